@@ -1,6 +1,8 @@
 package v1alpha1
 
 import (
+	"slices"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -59,12 +61,12 @@ func (w *Workflow) getTaskActionInfo() taskInfo {
 		}
 	INNER:
 		for ai, action := range task.Actions {
-			// Find the first non-successful action
+			// Find the first action that hasn't succeeded or been skipped
 			switch action.Status { //nolint:exhaustive // WorkflowStateWaiting is only used in Workflows not Actions.
-			case WorkflowStateSuccess:
+			case WorkflowStateSuccess, WorkflowStateSkipped:
 				actionIndex++
 				continue
-			case WorkflowStatePending, WorkflowStateRunning, WorkflowStateFailed, WorkflowStateTimeout:
+			case WorkflowStatePending, WorkflowStateRunning, WorkflowStateFailed, WorkflowStateTimeout, WorkflowStateCanceled:
 				taskIndex = ti
 				actionTaskIndex = ai
 				found = true
@@ -117,3 +119,26 @@ func (w *Workflow) GetCurrentActionState() WorkflowState {
 func (w *Workflow) GetTotalNumberOfActions() int {
 	return w.getTaskActionInfo().TotalNumberOfActions
 }
+
+// IsPaused determines if workers should be prevented from starting the next action because
+// Spec.Paused is set or the next action is one of Spec.Breakpoints. Running actions aren't
+// affected.
+func (w *Workflow) IsPaused() bool {
+	switch w.Status.State { //nolint:exhaustive // Only Workflows that may start actions can be paused.
+	case WorkflowStatePending, WorkflowStateRunning:
+	default:
+		return false
+	}
+
+	info := w.getTaskActionInfo()
+	if info.CurrentActionState != WorkflowStatePending {
+		return false
+	}
+	return w.Spec.Paused || slices.Contains(w.Spec.Breakpoints, BreakpointKey(info.CurrentTask, info.CurrentAction))
+}
+
+// BreakpointKey returns the key identifying the action named action of the task named task in
+// Spec.Breakpoints.
+func BreakpointKey(task, action string) string {
+	return task + "/" + action
+}
@@ -399,6 +399,34 @@ func TestWorkflowMethods(t *testing.T) {
 				CurrentActionIndex:   0,
 			},
 		},
+		{
+			"Skipped action",
+			&Workflow{
+				Status: WorkflowStatus{
+					State:         WorkflowStateRunning,
+					GlobalTimeout: 600,
+					Tasks: []Task{
+						{
+							Name:       "os-installation",
+							WorkerAddr: "3c:ec:ef:4c:4f:54",
+							Actions: []Action{
+								{Name: "wipe-disks", Status: WorkflowStateSkipped},
+								{Name: "stream-debian-image", Status: WorkflowStatePending},
+							},
+						},
+					},
+				},
+			},
+			taskInfo{
+				TotalNumberOfActions: 2,
+				CurrentTaskIndex:     0,
+				CurrentTask:          "os-installation",
+				CurrentWorker:        "3c:ec:ef:4c:4f:54",
+				CurrentAction:        "stream-debian-image",
+				CurrentActionState:   WorkflowStatePending,
+				CurrentActionIndex:   1,
+			},
+		},
 	}
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -410,6 +438,76 @@ func TestWorkflowMethods(t *testing.T) {
 	}
 }
 
+func TestIsPaused(t *testing.T) {
+	newWorkflow := func(spec WorkflowSpec, state WorkflowState, actionStates ...WorkflowState) *Workflow {
+		wf := &Workflow{
+			Spec:   spec,
+			Status: WorkflowStatus{State: state, Tasks: []Task{{Name: "task"}}},
+		}
+		for i, s := range actionStates {
+			wf.Status.Tasks[0].Actions = append(wf.Status.Tasks[0].Actions, Action{
+				Name:   []string{"first", "second"}[i],
+				Status: s,
+			})
+		}
+		return wf
+	}
+
+	cases := []struct {
+		name string
+		wf   *Workflow
+		want bool
+	}{
+		{
+			"Not paused",
+			newWorkflow(WorkflowSpec{}, WorkflowStatePending, WorkflowStatePending, WorkflowStatePending),
+			false,
+		},
+		{
+			"Paused pending workflow",
+			newWorkflow(WorkflowSpec{Paused: true}, WorkflowStatePending, WorkflowStatePending, WorkflowStatePending),
+			true,
+		},
+		{
+			"Paused with running action",
+			newWorkflow(WorkflowSpec{Paused: true}, WorkflowStateRunning, WorkflowStateRunning, WorkflowStatePending),
+			false,
+		},
+		{
+			"Paused after action",
+			newWorkflow(WorkflowSpec{Paused: true}, WorkflowStateRunning, WorkflowStateSuccess, WorkflowStatePending),
+			true,
+		},
+		{
+			"Paused completed workflow",
+			newWorkflow(WorkflowSpec{Paused: true}, WorkflowStateSuccess, WorkflowStateSuccess, WorkflowStateSuccess),
+			false,
+		},
+		{
+			"Breakpoint reached",
+			newWorkflow(WorkflowSpec{Breakpoints: []string{"task/second"}}, WorkflowStateRunning, WorkflowStateSuccess, WorkflowStatePending),
+			true,
+		},
+		{
+			"Breakpoint not reached",
+			newWorkflow(WorkflowSpec{Breakpoints: []string{"task/second"}}, WorkflowStatePending, WorkflowStatePending, WorkflowStatePending),
+			false,
+		},
+		{
+			"Breakpoint in other task",
+			newWorkflow(WorkflowSpec{Breakpoints: []string{"other/second"}}, WorkflowStateRunning, WorkflowStateSuccess, WorkflowStatePending),
+			false,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.wf.IsPaused(); got != tc.want {
+				t.Errorf("Got %v, wanted %v", got, tc.want)
+			}
+		})
+	}
+}
+
 func TestSetCondition(t *testing.T) {
 	tests := map[string]struct {
 		ExistingConditions []WorkflowCondition
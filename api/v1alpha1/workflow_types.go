@@ -1,6 +1,7 @@
 package v1alpha1
 
 import (
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -24,6 +25,8 @@ const (
 	WorkflowStateSuccess   = WorkflowState("STATE_SUCCESS")
 	WorkflowStateFailed    = WorkflowState("STATE_FAILED")
 	WorkflowStateTimeout   = WorkflowState("STATE_TIMEOUT")
+	WorkflowStateCanceled  = WorkflowState("STATE_CANCELED")
+	WorkflowStateSkipped   = WorkflowState("STATE_SKIPPED")
 
 	NetbootJobFailed        WorkflowConditionType = "NetbootJobFailed"
 	NetbootJobComplete      WorkflowConditionType = "NetbootJobComplete"
@@ -33,6 +36,8 @@ const (
 	ToggleAllowNetbootTrue  WorkflowConditionType = "AllowNetbootTrue"
 	ToggleAllowNetbootFalse WorkflowConditionType = "AllowNetbootFalse"
 	TemplateRenderedSuccess WorkflowConditionType = "TemplateRenderedSuccess"
+	WorkflowCanceled        WorkflowConditionType = "WorkflowCanceled"
+	WorkflowPaused          WorkflowConditionType = "Paused"
 
 	TemplateRenderingSuccessful TemplateRendering = "successful"
 	TemplateRenderingFailed     TemplateRendering = "failed"
@@ -82,6 +87,23 @@ type WorkflowSpec struct {
 
 	// BootOptions are options that control the booting of Hardware.
 	BootOptions BootOptions `json:"bootOptions,omitempty"`
+
+	// Cancel requests the Workflow be canceled. Any running action is stopped, post actions such as
+	// toggling allowPXE and ejecting ISOs are performed, and the Workflow enters the STATE_CANCELED state.
+	// Cancel has no effect on Workflows that have already completed.
+	// +optional
+	Cancel bool `json:"cancel,omitempty"`
+
+	// Paused stops workers from starting further actions until it's cleared. A running action
+	// is allowed to complete. Paused has no effect on Workflows that have already completed.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+
+	// Breakpoints lists the actions the Workflow pauses before. Action names are only unique
+	// within a task so actions are identified as "<task name>/<action name>". The Workflow
+	// resumes once the action is removed from Breakpoints.
+	// +optional
+	Breakpoints []string `json:"breakpoints,omitempty"`
 }
 
 // BootOptions are options that control the booting of Hardware.
@@ -204,6 +226,70 @@ type Action struct {
 	StartedAt   *metav1.Time      `json:"startedAt,omitempty"`
 	Seconds     int64             `json:"seconds,omitempty"`
 	Message     string            `json:"message,omitempty"`
+
+	// Retries is the maximum number of times the action is retried after the initial attempt fails.
+	// +optional
+	Retries int64 `json:"retries,omitempty"`
+
+	// Backoff is the number of seconds to wait before the first retry. The delay doubles for each
+	// subsequent retry.
+	// +optional
+	Backoff int64 `json:"backoff,omitempty"`
+
+	// RetryOn restricts retries to failures with one of the listed failure reasons: Timeout,
+	// RuntimeError or NonZeroExitCode. When empty, all failures are retried.
+	// +optional
+	RetryOn []string `json:"retryOn,omitempty"`
+
+	// DependsOn lists the names of actions in the same task that must succeed before this action
+	// is run. Actions are ordered so they follow their dependencies and are executed sequentially
+	// by tink-worker.
+	// +optional
+	DependsOn []string `json:"dependsOn,omitempty"`
+
+	// When is a condition evaluated by tink-worker immediately before the action runs. When it
+	// evaluates to false the action is skipped. Conditions are Go template pipelines without the
+	// enclosing delimiters, for example `eq (len .Hardware.Disks) 2`, and have access to the same
+	// data as the template using the field names of its JSON representation.
+	// +optional
+	When string `json:"when,omitempty"`
+
+	// Resources are the resource limits of the action's container, combining the limits of the
+	// action with those of the template.
+	// +optional
+	Resources *ResourceLimits `json:"resources,omitempty"`
+
+	// Attempts records each attempt at executing the action.
+	// +optional
+	Attempts []ActionAttempt `json:"attempts,omitempty"`
+
+	// Logs is the most recent output of the action captured when the action failed.
+	// +optional
+	Logs string `json:"logs,omitempty"`
+}
+
+// ResourceLimits defines the maximum resources available to an action's container. Unspecified
+// resources are unlimited.
+type ResourceLimits struct {
+	// Memory is the maximum memory, for example 512Mi.
+	// +optional
+	Memory *resource.Quantity `json:"memory,omitempty"`
+
+	// CPU is the maximum CPU time as a number of CPUs, for example 500m or 2.
+	// +optional
+	CPU *resource.Quantity `json:"cpu,omitempty"`
+
+	// PIDs is the maximum number of processes.
+	// +optional
+	PIDs int64 `json:"pids,omitempty"`
+}
+
+// ActionAttempt describes an attempt at executing an action.
+type ActionAttempt struct {
+	Status    WorkflowState `json:"status,omitempty"`
+	StartedAt *metav1.Time  `json:"startedAt,omitempty"`
+	Seconds   int64         `json:"seconds,omitempty"`
+	Message   string        `json:"message,omitempty"`
 }
 
 // HasCondition checks if the cType condition is present with status cStatus on a bmj.
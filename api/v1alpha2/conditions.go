@@ -43,3 +43,36 @@ type Condition struct {
 
 // Conditions define a list of observations of a particular resource.
 type Conditions []Condition
+
+// Get retrieves the condition of type t. The second return value indicates whether the condition
+// was found.
+func (c Conditions) Get(t ConditionType) (Condition, bool) {
+	for _, cond := range c {
+		if cond.Type == t {
+			return cond, true
+		}
+	}
+	return Condition{}, false
+}
+
+// IsTrue checks if the condition of type t exists and has a status of ConditionStatusTrue.
+func (c Conditions) IsTrue(t ConditionType) bool {
+	cond, ok := c.Get(t)
+	return ok && cond.Status == ConditionStatusTrue
+}
+
+// Set adds cond or replaces an existing condition of the same type. LastTransition is preserved
+// when the status of an existing condition doesn't change.
+func (c *Conditions) Set(cond Condition) {
+	for i, existing := range *c {
+		if existing.Type != cond.Type {
+			continue
+		}
+		if existing.Status == cond.Status {
+			cond.LastTransition = existing.LastTransition
+		}
+		(*c)[i] = cond
+		return
+	}
+	*c = append(*c, cond)
+}
@@ -1,16 +1,34 @@
 package v1alpha2
 
 import (
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type TemplateSpec struct {
-	// Actions defines the set of actions to be run on a target machine. Actions are run sequentially
-	// in the order they are specified. At least 1 action must be specified. Names of actions
-	// must be unique within a Template.
+	// Actions defines the set of actions to be run on a target machine. At least 1 action must be
+	// specified. Names of actions must be unique within a Template.
+	//
+	// When no action specifies DependsOn, actions are run sequentially in the order they are
+	// specified. Otherwise, actions form a graph where each action runs once the actions it
+	// depends on have succeeded and independent actions run concurrently. The graph must not
+	// contain cycles.
 	// +kubebuilder:validation:MinItems=1
 	Actions []Action `json:"actions,omitempty"`
 
+	// OnFailure defines actions run sequentially when an action in Actions fails, for example to
+	// collect diagnostics. Names must be unique across Actions, OnFailure and Finally and the
+	// actions cannot specify DependsOn.
+	// +optional
+	OnFailure []Action `json:"onFailure,omitempty"`
+
+	// Finally defines actions run sequentially once Actions, and OnFailure if applicable, have
+	// finished regardless of whether they succeeded, for example to reset the boot order. Names
+	// must be unique across Actions, OnFailure and Finally and the actions cannot specify
+	// DependsOn. The Workflow fails if an action in Finally fails.
+	// +optional
+	Finally []Action `json:"finally,omitempty"`
+
 	// Volumes to be mounted on all actions. If an action specifies the same volume it will take
 	// precedence.
 	// +optional
@@ -20,6 +38,11 @@ type TemplateSpec struct {
 	// the same environment variable it will take precedence.
 	// +optional
 	Env map[string]string `json:"env,omitempty"`
+
+	// Resources defines resource limits applied to all actions. Limits specified by an action take
+	// precedence.
+	// +optional
+	Resources *ResourceLimits `json:"resources,omitempty"`
 }
 
 // Action defines an individual action to be run on a target machine.
@@ -27,8 +50,22 @@ type Action struct {
 	// Name is a name for the action.
 	Name string `json:"name"`
 
-	// Image is an OCI image.
-	Image string `json:"image"`
+	// Type is the type of the action. Container actions run Image. Reboot and Kexec actions are
+	// performed by the agent and restart the machine; the Workflow continues with the next action
+	// once the agent reconnects. Actions running concurrently with a Reboot or Kexec action are
+	// interrupted and run again when the Workflow continues.
+	// +kubebuilder:validation:Enum=Container;Reboot;Kexec
+	// +kubebuilder:default=Container
+	// +optional
+	Type ActionType `json:"type,omitempty"`
+
+	// Image is an OCI image. It's required for Container actions.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Kexec defines the kernel booted by Kexec actions.
+	// +optional
+	Kexec *Kexec `json:"kexec,omitempty"`
 
 	// Cmd defines the command to use when launching the image. It overrides the default command
 	// of the action. It must be a unix path to an executable program.
@@ -52,6 +89,71 @@ type Action struct {
 	// Namespace defines the Linux namespaces this container should execute in.
 	// +optional
 	Namespace *Namespace `json:"namespaces,omitempty"`
+
+	// Privileged runs the container with all capabilities and access to all host devices.
+	// +optional
+	Privileged bool `json:"privileged,omitempty"`
+
+	// Capabilities defines the Linux capabilities added to, or dropped from, the container's
+	// default set.
+	// +optional
+	Capabilities *Capabilities `json:"capabilities,omitempty"`
+
+	// Devices defines the host devices made available to the container.
+	// +optional
+	Devices []Device `json:"devices,omitempty"`
+
+	// Resources defines the resource limits of the container.
+	// +optional
+	Resources *ResourceLimits `json:"resources,omitempty"`
+
+	// Retry defines how the action is retried when it fails. When unspecified the action is not
+	// retried.
+	// +optional
+	Retry *RetryPolicy `json:"retry,omitempty"`
+
+	// DependsOn lists the names of actions that must succeed before this action is run.
+	// +optional
+	DependsOn []string `json:"dependsOn,omitempty"`
+
+	// TimeoutSeconds is the maximum time the action may run for, including retries. When 0, the
+	// action is only bound by the Workflow timeout.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	TimeoutSeconds int64 `json:"timeout,omitempty"`
+
+	// Idempotent indicates the action can safely be re-run from the start when it's interrupted,
+	// for example because the agent restarted. Interrupted actions that aren't idempotent fail
+	// with the reason AgentRestarted.
+	// +optional
+	Idempotent bool `json:"idempotent,omitempty"`
+
+	// When is a condition evaluated immediately before the action runs. When it evaluates to
+	// false the action is skipped. Conditions are Go template pipelines without the enclosing
+	// delimiters, for example `eq .Hardware.metadata.instance.operating_system.distro "ubuntu"`,
+	// and have access to the Hardware and template parameters (.Param) using the field names of
+	// their YAML representation. Skipped actions satisfy the dependencies of other actions.
+	// +optional
+	When string `json:"when,omitempty"`
+}
+
+// RetryPolicy defines how an action is retried when it fails.
+type RetryPolicy struct {
+	// Retries is the maximum number of times the action is retried after the initial attempt.
+	// +kubebuilder:validation:Minimum=0
+	Retries int64 `json:"retries,omitempty"`
+
+	// BackoffSeconds is the delay before the first retry. The delay doubles for each subsequent
+	// retry.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	BackoffSeconds int64 `json:"backoffSeconds,omitempty"`
+
+	// Reasons restricts retries to failures with one of the listed failure reasons. Failure reasons
+	// are UpperCamelCase words typically provided by the action itself. When empty, all failures
+	// are retried.
+	// +optional
+	Reasons []string `json:"reasons,omitempty"`
 }
 
 // Volume is a specification for mounting a volume in an action. Volumes take the form
@@ -76,11 +178,101 @@ type Namespace struct {
 	// +optional
 	Network *string `json:"network,omitempty"`
 
-	// PID defines the PID namespace
+	// PID defines the PID namespace. The only supported value is 1, which joins the host PID
+	// namespace. Other values fail the Workflow when the Template is rendered.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=1
 	// +optional
 	PID *int `json:"pid,omitempty"`
 }
 
+// Capabilities defines modifications to the default Linux capabilities of a container. Capabilities
+// are named with or without the CAP_ prefix, for example NET_ADMIN. See
+// https://man7.org/linux/man-pages/man7/capabilities.7.html.
+type Capabilities struct {
+	// Add lists capabilities added to the default set.
+	// +optional
+	Add []string `json:"add,omitempty"`
+
+	// Drop lists capabilities dropped from the default set.
+	// +optional
+	Drop []string `json:"drop,omitempty"`
+}
+
+// ActionType describes how an action is performed.
+type ActionType string
+
+const (
+	// ActionTypeContainer actions run their image as a container. It's the default.
+	ActionTypeContainer ActionType = "Container"
+
+	// ActionTypeReboot actions reboot the machine.
+	ActionTypeReboot ActionType = "Reboot"
+
+	// ActionTypeKexec actions boot a new kernel without returning to the firmware.
+	ActionTypeKexec ActionType = "Kexec"
+)
+
+// IsReboot determines if actions of type t restart the machine.
+func (t ActionType) IsReboot() bool {
+	return t == ActionTypeReboot || t == ActionTypeKexec
+}
+
+// Kexec defines the kernel booted by a Kexec action.
+type Kexec struct {
+	// BlockDevice is the device containing the kernel, for example /dev/sda1. When empty, paths
+	// are resolved on the agent's filesystem.
+	// +optional
+	BlockDevice string `json:"blockDevice,omitempty"`
+
+	// FSType is the filesystem type of BlockDevice, for example ext4.
+	// +optional
+	FSType string `json:"fsType,omitempty"`
+
+	// KernelPath is the path to the kernel.
+	KernelPath string `json:"kernelPath"`
+
+	// InitrdPath is the path to the initial ramdisk.
+	// +optional
+	InitrdPath string `json:"initrdPath,omitempty"`
+
+	// Cmdline is the kernel command line.
+	// +optional
+	Cmdline string `json:"cmdline,omitempty"`
+}
+
+// Device is a specification for making a host device available to an action. Devices take the
+// form HOST-PATH[:CONTAINER-PATH[:PERMISSIONS]] where PERMISSIONS is a combination of r (read), w
+// (write) and m (mknod) defaulting to rwm. When CONTAINER-PATH is omitted the device is available
+// at HOST-PATH. Examples:
+//
+// Read-write access to /dev/sda
+//
+//	/dev/sda
+//
+// Read-only access to /dev/sdb available as /dev/disk
+//
+//	/dev/sdb:/dev/disk:r
+type Device string
+
+// ResourceLimits defines the maximum resources available to an action's container. Unspecified
+// resources are unlimited.
+type ResourceLimits struct {
+	// Memory is the maximum memory, for example 512Mi. The container is killed when it exceeds
+	// the limit.
+	// +optional
+	Memory *resource.Quantity `json:"memory,omitempty"`
+
+	// CPU is the maximum CPU time as a number of CPUs, for example 500m or 2.
+	// +optional
+	CPU *resource.Quantity `json:"cpu,omitempty"`
+
+	// PIDs is the maximum number of processes.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	PIDs *int64 `json:"pids,omitempty"`
+}
+
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:categories=tinkerbell,shortName=tpl
 // +kubebuilder:unservedversion
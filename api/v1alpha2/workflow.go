@@ -24,12 +24,30 @@ type WorkflowSpec struct {
 	// +kubebuilder:default=0
 	// +kubebuilder:validation:Minimum=0
 	TimeoutSeconds int64 `json:"timeout,omitempty"`
+
+	// Paused prevents further actions from starting. Actions already running are unaffected.
+	// Clearing Paused resumes the Workflow.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+
+	// Breakpoints is a list of action names. The Workflow pauses before starting an action in
+	// Breakpoints and resumes once the action is removed.
+	// +optional
+	Breakpoints []string `json:"breakpoints,omitempty"`
 }
 
 type WorkflowStatus struct {
 	// Actions is a list of action states.
 	Actions []ActionStatus `json:"actions"`
 
+	// OnFailure is a list of action states for the Template's OnFailure actions.
+	// +optional
+	OnFailure []ActionStatus `json:"onFailure,omitempty"`
+
+	// Finally is a list of action states for the Template's Finally actions.
+	// +optional
+	Finally []ActionStatus `json:"finally,omitempty"`
+
 	// StartedAt is the time the first action was requested. Nil indicates the Workflow has not
 	// started.
 	// +optional
@@ -44,6 +62,21 @@ type WorkflowStatus struct {
 	// Conditions details a set of observations about the Workflow.
 	// +optional
 	Conditions Conditions `json:"conditions"`
+
+	// LastEvent identifies the last event published by an agent that was applied to the Workflow.
+	// Agents publish events until they're acknowledged so an event may be received more than
+	// once; events at or before LastEvent in the same session are not applied again.
+	// +optional
+	LastEvent *EventSequence `json:"lastEvent,omitempty"`
+}
+
+// EventSequence identifies an event published by an agent.
+type EventSequence struct {
+	// Session identifies the agent's event outbox.
+	Session string `json:"session"`
+
+	// Sequence is the position of the event in Session starting from 1.
+	Sequence uint64 `json:"sequence"`
 }
 
 // ActionStatus describes status information about an action.
@@ -71,6 +104,38 @@ type ActionStatus struct {
 	// FailureMessage is a free-form user friendly message describing why the Action entered the
 	// ActionStateFailed state. Typically, this is an elaboration on the Reason.
 	FailureMessage string `json:"failureMessage,omitempty"`
+
+	// Attempts records each attempt at executing the action. Actions with a retry policy may have
+	// multiple attempts.
+	// +optional
+	Attempts []ActionAttempt `json:"attempts,omitempty"`
+
+	// Logs is the most recent output of the action captured when the action failed.
+	// +optional
+	Logs string `json:"logs,omitempty"`
+
+	// Outputs are the key/value pairs written by the action when it succeeded. Outputs are made
+	// available to the actions that depend on it.
+	// +optional
+	Outputs map[string]string `json:"outputs,omitempty"`
+}
+
+// ActionAttempt describes a single attempt at executing an action.
+type ActionAttempt struct {
+	// StartedAt is the time the attempt was started as reported by the client.
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+
+	// LastTransition is the observed time when State transitioned last.
+	LastTransition *metav1.Time `json:"lastTransitioned,omitempty"`
+
+	// State describes the state of the attempt.
+	State ActionState `json:"state,omitempty"`
+
+	// FailureReason is a short CamelCase word or phrase describing why the attempt failed.
+	FailureReason string `json:"failureReason,omitempty"`
+
+	// FailureMessage is a free-form user friendly message describing why the attempt failed.
+	FailureMessage string `json:"failureMessage,omitempty"`
 }
 
 // State describes the point in time state of a Workflow.
@@ -101,6 +166,24 @@ const (
 	WorkflowStateCanceled WorkflowState = "Canceled"
 )
 
+const (
+	// WorkflowConditionTemplateRendered indicates whether the referenced Template has been
+	// rendered into the Workflow's Actions.
+	WorkflowConditionTemplateRendered ConditionType = "TemplateRendered"
+
+	// WorkflowConditionTimedOut indicates the Workflow exceeded its Spec.TimeoutSeconds.
+	WorkflowConditionTimedOut ConditionType = "TimedOut"
+
+	// WorkflowConditionPaused indicates the agent is holding an action because the Workflow is
+	// paused.
+	WorkflowConditionPaused ConditionType = "Paused"
+
+	// WorkflowConditionImagesPulled indicates whether the agent has pulled the images used by the
+	// Workflow's actions. The agent pulls images before running any action and fails the Workflow
+	// if an image can't be pulled.
+	WorkflowConditionImagesPulled ConditionType = "ImagesPulled"
+)
+
 // ActionState describes a point in time state of an Action.
 type ActionState string
 
@@ -108,6 +191,10 @@ const (
 	// ActionStatePending indicates an Action is awaiting execution.
 	ActionStatePending ActionState = "Pending"
 
+	// ActionStatePaused indicates an Action is being held by the agent because the Workflow is
+	// paused.
+	ActionStatePaused ActionState = "Paused"
+
 	// ActionStateRunning indicates an Action has begun execution.
 	ActionStateRunning ActionState = "Running"
 
@@ -117,6 +204,10 @@ const (
 	// ActionStatFailed indicates an Action failed to execute. Users may inspect the associated
 	// Workflow resource to gain deeper insights into why the action failed.
 	ActionStateFailed ActionState = "Failed"
+
+	// ActionStateSkipped indicates an Action was not executed because its When condition was
+	// false.
+	ActionStateSkipped ActionState = "Skipped"
 )
 
 // +kubebuilder:object:root=true
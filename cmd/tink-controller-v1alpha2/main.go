@@ -11,6 +11,7 @@ import (
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 	tinkv1 "github.com/tinkerbell/tink/api/v1alpha2"
+	"github.com/tinkerbell/tink/internal/registry"
 	"github.com/tinkerbell/tink/internal/workflow"
 	"go.uber.org/zap"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -40,6 +41,9 @@ type Config struct {
 	MetricsAddr          string
 	ProbeAddr            string
 	EnableLeaderElection bool
+	PinImageDigests      bool
+	RegistryConfig       string
+	RegistryMirrors      []string
 }
 
 func (c *Config) AddFlags(fs *pflag.FlagSet) {
@@ -53,6 +57,12 @@ func (c *Config) AddFlags(fs *pflag.FlagSet) {
 	fs.BoolVar(&c.EnableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	fs.BoolVar(&c.PinImageDigests, "pin-image-digests", false,
+		"Resolve action image tags to digests when rendering templates.")
+	fs.StringVar(&c.RegistryConfig, "registry-config", "",
+		"Path to a Docker CLI config file containing registry credentials used to resolve image digests.")
+	fs.StringSliceVar(&c.RegistryMirrors, "registry-mirror", nil,
+		"Registry mirror used to resolve image digests in the form 'registry=mirror'. May be repeated.")
 }
 
 func main() {
@@ -122,7 +132,16 @@ func NewRootCommand() *cobra.Command {
 				return err
 			}
 
-			if err := workflow.NewReconciler(mgr.GetClient()).SetupWithManager(mgr); err != nil {
+			var reconcilerOpts []workflow.ReconcilerOption
+			if config.PinImageDigests {
+				reg, err := registry.Load(config.RegistryConfig, config.RegistryMirrors)
+				if err != nil {
+					return err
+				}
+				reconcilerOpts = append(reconcilerOpts, workflow.WithImagePinner(reg))
+			}
+
+			if err := workflow.NewReconciler(mgr.GetClient(), reconcilerOpts...).SetupWithManager(mgr); err != nil {
 				return err
 			}
 
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
@@ -17,6 +18,7 @@ import (
 	"github.com/tinkerbell/tink/internal/grpcserver"
 	"github.com/tinkerbell/tink/internal/httpserver"
 	"github.com/tinkerbell/tink/internal/server"
+	"github.com/tinkerbell/tink/internal/tlsconfig"
 	"go.uber.org/zap"
 )
 
@@ -33,6 +35,19 @@ type Config struct {
 	KubeconfigPath string
 	KubeAPI        string
 	KubeNamespace  string
+	KubeNamespaces []string
+
+	EnableWorkflowV2 bool
+
+	ActionLogBytes     int
+	ActionLogWorkflows int
+
+	TLSCertFile         string
+	TLSKeyFile          string
+	TLSClientCAFile     string
+	HTTPTLS             bool
+	HTTPTLSClientCAFile string
+	TLSOperatorNames    []string
 }
 
 const backendKubernetes = "kubernetes"
@@ -48,6 +63,72 @@ func (c *Config) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&c.KubeconfigPath, "kubeconfig", "", "The path to the Kubeconfig. Only takes effect if `--backend=kubernetes`")
 	fs.StringVar(&c.KubeAPI, "kubernetes", "", "The Kubernetes API URL, used for in-cluster client construction. Only takes effect if `--backend=kubernetes`")
 	fs.StringVar(&c.KubeNamespace, "kube-namespace", "", "The Kubernetes namespace to target")
+	fs.StringSliceVar(&c.KubeNamespaces, "kube-namespaces", nil, "Additional Kubernetes namespaces to target. All namespaces are targeted when neither `--kube-namespace` nor `--kube-namespaces` is set. When targeting more than one namespace, workers are served workflows from the namespace of the Hardware matching their ID")
+	fs.BoolVar(&c.EnableWorkflowV2, "enable-workflow-v2", false, "Serve the v2 workflow API used by tink-agent. Requires the v1alpha2 API version to be served. Only takes effect if `--backend=kubernetes`")
+	fs.IntVar(&c.ActionLogBytes, "action-log-bytes", server.DefaultActionLogBytes, "The number of bytes of the most recent output retained per action. Output is retained in memory so it's lost on restart and isn't shared between replicas")
+	fs.IntVar(&c.ActionLogWorkflows, "action-log-workflows", server.DefaultActionLogWorkflows, "The number of workflows action output is retained for")
+	fs.StringVar(&c.TLSCertFile, "tls-cert-file", "", "A PEM encoded certificate the gRPC server is served with; reloaded when it changes. Serves plaintext when empty")
+	fs.StringVar(&c.TLSKeyFile, "tls-key-file", "", "The PEM encoded private key for `--tls-cert-file`; reloaded when it changes")
+	fs.StringVar(&c.TLSClientCAFile, "tls-client-ca-file", "", "A PEM file of CA certificates gRPC client certificates are verified against; reloaded when it changes. When set, clients must present a certificate whose common name identifies the worker or agent and may only access their own workflows. Requires `--tls-cert-file`")
+	fs.StringSliceVar(&c.TLSOperatorNames, "tls-operator-names", nil, "Common names of gRPC client certificates that identify operators. Operators may read the action logs of any workflow; workers and agents may not. Only takes effect with `--tls-client-ca-file`")
+	fs.BoolVar(&c.HTTPTLS, "http-tls", false, "Serve the HTTP server over TLS with `--tls-cert-file`")
+	fs.StringVar(&c.HTTPTLSClientCAFile, "http-tls-client-ca-file", "", "A PEM file of CA certificates HTTP client certificates are verified against; reloaded when it changes. When set, clients must present a verified certificate. Requires `--http-tls`")
+}
+
+// serverOptions returns the gRPC and HTTP server options for c. Certificates are reloaded as
+// they change until ctx is cancelled. gRPC requests are authorized by a using the client
+// certificate's identity when client certificates are verified.
+func (c *Config) serverOptions(ctx context.Context, logger logr.Logger, a grpcserver.Authorizer) ([]grpcserver.Option, []httpserver.Option, error) {
+	if c.TLSCertFile == "" {
+		switch {
+		case c.TLSClientCAFile != "":
+			return nil, nil, errors.New("--tls-client-ca-file requires --tls-cert-file")
+		case c.HTTPTLS:
+			return nil, nil, errors.New("--http-tls requires --tls-cert-file")
+		}
+		return nil, nil, nil
+	}
+	if c.HTTPTLSClientCAFile != "" && !c.HTTPTLS {
+		return nil, nil, errors.New("--http-tls-client-ca-file requires --http-tls")
+	}
+
+	start := func(clientCAFile string) (*tlsconfig.Reloader, error) {
+		r, err := tlsconfig.New(
+			c.TLSCertFile,
+			c.TLSKeyFile,
+			tlsconfig.WithClientCAFile(clientCAFile),
+			tlsconfig.WithLogger(logger),
+		)
+		if err != nil {
+			return nil, err
+		}
+		go func() {
+			if err := r.Start(ctx); err != nil {
+				logger.Error(err, "Watching TLS certificates; certificate changes will not be reloaded")
+			}
+		}()
+		return r, nil
+	}
+
+	grpcTLS, err := start(c.TLSClientCAFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("grpc tls: %w", err)
+	}
+	grpcOpts := []grpcserver.Option{grpcserver.WithTLS(grpcTLS.Config())}
+	if grpcTLS.ClientAuth() {
+		grpcOpts = append(grpcOpts, grpcserver.WithAuthorizer(a))
+	}
+
+	var httpOpts []httpserver.Option
+	if c.HTTPTLS {
+		httpTLS, err := start(c.HTTPTLSClientCAFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("http tls: %w", err)
+		}
+		httpOpts = append(httpOpts, httpserver.WithTLS(httpTLS.Config()))
+	}
+
+	return grpcOpts, httpOpts, nil
 }
 
 func (c *Config) PopulateFromLegacyEnvVar() {
@@ -106,7 +187,10 @@ func NewRootCommand() *cobra.Command {
 			// graceful shutdown and error management but I want to
 			// figure this out in another PR
 			errCh := make(chan error, 2)
-			var registrar grpcserver.Registrar
+			var registrar interface {
+				grpcserver.Registrar
+				grpcserver.Authorizer
+			}
 
 			switch config.Backend {
 			case backendKubernetes:
@@ -116,6 +200,10 @@ func NewRootCommand() *cobra.Command {
 					config.KubeconfigPath,
 					config.KubeAPI,
 					config.KubeNamespace,
+					server.WithNamespaces(config.KubeNamespaces...),
+					server.WithWorkflowV2(config.EnableWorkflowV2),
+					server.WithActionLogRetention(config.ActionLogBytes, config.ActionLogWorkflows),
+					server.WithOperators(config.TLSOperatorNames...),
 				)
 				if err != nil {
 					return err
@@ -124,19 +212,25 @@ func NewRootCommand() *cobra.Command {
 				return fmt.Errorf("invalid backend: %s", config.Backend)
 			}
 
+			grpcOpts, httpOpts, err := config.serverOptions(ctx, logger, registrar)
+			if err != nil {
+				return err
+			}
+
 			// Start the gRPC server in the background
 			addr, err := grpcserver.SetupGRPC(
 				ctx,
 				registrar,
 				config.GRPCAuthority,
 				errCh,
+				grpcOpts...,
 			)
 			if err != nil {
 				return err
 			}
 			logger.Info("started listener", "address", addr)
 
-			httpserver.SetupHTTP(ctx, logger, config.HTTPAuthority, errCh)
+			httpserver.SetupHTTP(ctx, logger, config.HTTPAuthority, errCh, httpOpts...)
 
 			select {
 			case err := <-errCh:
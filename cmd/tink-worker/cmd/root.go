@@ -51,6 +51,7 @@ func NewRootCommand(version string) *cobra.Command {
 			pwd := viper.GetString("registry-password")
 			registry := viper.GetString("docker-registry")
 			captureActionLogs := viper.GetBool("capture-action-logs")
+			shipActionLogs := viper.GetBool("ship-action-logs")
 
 			logger.Info("starting", "version", version)
 
@@ -58,6 +59,8 @@ func NewRootCommand(version string) *cobra.Command {
 				viper.GetString("tinkerbell-grpc-authority"),
 				viper.GetBool("tinkerbell-tls"),
 				viper.GetBool("tinkerbell-insecure-tls"),
+				client.WithClientCertificate(viper.GetString("tinkerbell-tls-cert"), viper.GetString("tinkerbell-tls-key")),
+				client.WithCAFile(viper.GetString("tinkerbell-tls-ca")),
 			)
 			if err != nil {
 				return err
@@ -88,6 +91,7 @@ func NewRootCommand(version string) *cobra.Command {
 				worker.WithMaxFileSize(maxFileSize),
 				worker.WithRetries(retryInterval, retries),
 				worker.WithLogCapture(captureActionLogs),
+				worker.WithLogShipping(shipActionLogs),
 				worker.WithPrivileged(true))
 
 			logger.Info("starting to process workflow actions", "workerID", workerID)
@@ -104,8 +108,12 @@ func NewRootCommand(version string) *cobra.Command {
 	rootCmd.Flags().Int("max-retry", defaultRetryCount, "Maximum number of retries to attempt (MAX_RETRY)")
 	rootCmd.Flags().Int64("max-file-size", defaultMaxFileSize, "Maximum file size in bytes (MAX_FILE_SIZE)")
 	rootCmd.Flags().Bool("capture-action-logs", true, "Capture action container output as part of worker logs")
+	rootCmd.Flags().Bool("ship-action-logs", true, "Ship captured action container output to the server. Only takes effect if `--capture-action-logs` is set")
 	rootCmd.Flags().Bool("tinkerbell-tls", true, "Connect to server via TLS or not (TINKERBELL_TLS)")
 	rootCmd.Flags().Bool("tinkerbell-insecure-tls", false, "When connecting via TLS, enable insecure TLS via InsecureSkipVerify (TINKERBELL_INSECURE_TLS)")
+	rootCmd.Flags().String("tinkerbell-tls-cert", "", "When connecting via TLS, the client certificate presented to the server; its common name must be the worker id (TINKERBELL_TLS_CERT)")
+	rootCmd.Flags().String("tinkerbell-tls-key", "", "When connecting via TLS, the private key for the client certificate (TINKERBELL_TLS_KEY)")
+	rootCmd.Flags().String("tinkerbell-tls-ca", "", "When connecting via TLS, a PEM file of CA certificates used to verify the server instead of the system's (TINKERBELL_TLS_CA)")
 	rootCmd.Flags().StringP("docker-registry", "r", "", "Sets the Docker registry (DOCKER_REGISTRY)")
 	rootCmd.Flags().StringP("registry-username", "u", "", "Sets the registry username (REGISTRY_USERNAME)")
 	rootCmd.Flags().StringP("registry-password", "p", "", "Sets the registry-password (REGISTRY_PASSWORD)")
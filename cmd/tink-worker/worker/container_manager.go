@@ -73,6 +73,16 @@ func (m *containerManager) CreateContainer(ctx context.Context, cmd []string, wf
 		hostConfig.PidMode = container.PidMode(pidConfig)
 	}
 
+	if limits := action.GetResources(); limits != nil {
+		hostConfig.Resources = container.Resources{
+			Memory:   limits.GetMemoryBytes(),
+			NanoCPUs: limits.GetNanoCpus(),
+		}
+		if pids := limits.GetPids(); pids > 0 {
+			hostConfig.PidsLimit = &pids
+		}
+	}
+
 	hostConfig.Binds = append(hostConfig.Binds, action.GetVolumes()...)
 	l.Info("creating container", "command", cmd)
 	name := makeValidContainerName(action.GetName())
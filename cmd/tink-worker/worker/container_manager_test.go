@@ -27,6 +27,9 @@ type fakeDockerClient struct {
 	err              error
 	waitErr          error
 	imageInspectErr  error
+
+	// hostConfig is the host configuration of the last created container.
+	hostConfig *container.HostConfig
 }
 
 type dockerClientOpt func(*fakeDockerClient)
@@ -55,8 +58,9 @@ func newFakeDockerClient(containerID, imagePullContent string, delay time.Durati
 }
 
 func (c *fakeDockerClient) ContainerCreate(
-	context.Context, *container.Config, *container.HostConfig, *network.NetworkingConfig, *specs.Platform, string,
+	_ context.Context, _ *container.Config, hostConfig *container.HostConfig, _ *network.NetworkingConfig, _ *specs.Platform, _ string,
 ) (container.CreateResponse, error) {
+	c.hostConfig = hostConfig
 	if c.err != nil {
 		return container.CreateResponse{}, c.err
 	}
@@ -112,6 +116,8 @@ func TestContainerManagerCreate(t *testing.T) {
 		registry     string
 		clientErr    error
 		wantErr      error
+		wantPids     int64
+		wantLimits   container.Resources
 	}{
 		{
 			name:         "Happy Path",
@@ -127,6 +133,24 @@ func TestContainerManagerCreate(t *testing.T) {
 			containerID: "nomedalforchewie",
 			registry:    "rebelba.se",
 		},
+		{
+			name:         "resource limits",
+			workflowName: "saveTheRebelBase",
+			action: &proto.WorkflowAction{
+				TaskName: "UseTheForce",
+				Name:     "blow up the death star",
+				Image:    "yav.in/4/forestmoon",
+				Resources: &proto.ResourceLimits{
+					MemoryBytes: 512 << 20,
+					NanoCpus:    500_000_000,
+					Pids:        100,
+				},
+			},
+			containerID: "nomedalforchewie",
+			registry:    "rebelba.se",
+			wantPids:    100,
+			wantLimits:  container.Resources{Memory: 512 << 20, NanoCPUs: 500_000_000},
+		},
 		{
 			name:         "create failure",
 			workflowName: "saveTheRebelBase",
@@ -148,7 +172,8 @@ func TestContainerManagerCreate(t *testing.T) {
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
 			logger := zapr.NewLogger(zap.Must(zap.NewDevelopment()))
-			mgr := NewContainerManager(logger, newFakeDockerClient(tc.containerID, "", 0, 0, tc.clientErr, nil), RegistryConnDetails{Registry: tc.registry})
+			clnt := newFakeDockerClient(tc.containerID, "", 0, 0, tc.clientErr, nil)
+			mgr := NewContainerManager(logger, clnt, RegistryConnDetails{Registry: tc.registry})
 
 			ctx := context.Background()
 			got, gotErr := mgr.CreateContainer(ctx, []string{}, tc.workflowName, tc.action, false, true)
@@ -168,6 +193,16 @@ func TestContainerManagerCreate(t *testing.T) {
 			if got != tc.containerID {
 				t.Errorf("Unexpected response: got '%s', expected '%s'", got, tc.containerID)
 			}
+
+			if tc.wantPids != 0 {
+				pids := clnt.hostConfig.PidsLimit
+				if pids == nil || *pids != tc.wantPids {
+					t.Errorf("Unexpected pids limit: got %v, expected %v", pids, tc.wantPids)
+				}
+			}
+			if clnt.hostConfig.Resources.Memory != tc.wantLimits.Memory || clnt.hostConfig.Resources.NanoCPUs != tc.wantLimits.NanoCPUs {
+				t.Errorf("Unexpected resources: got %+v, expected %+v", clnt.hostConfig.Resources, tc.wantLimits)
+			}
 		})
 	}
 }
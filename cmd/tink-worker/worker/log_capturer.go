@@ -37,8 +37,8 @@ func NewDockerLogCapturer(cli client.ContainerAPIClient, logger logr.Logger, wri
 	}
 }
 
-// CaptureLogs streams container logs to the capturer's writer.
-func (l *DockerLogCapturer) CaptureLogs(ctx context.Context, id string) {
+// CaptureLogs streams container logs to the capturer's writer and any additional sinks.
+func (l *DockerLogCapturer) CaptureLogs(ctx context.Context, id string, sinks ...io.Writer) {
 	reader, err := l.dockerClient.ContainerLogs(ctx, id, container.LogsOptions{
 		ShowStdout: true,
 		ShowStderr: true,
@@ -51,8 +51,9 @@ func (l *DockerLogCapturer) CaptureLogs(ctx context.Context, id string) {
 	}
 	defer reader.Close()
 
+	writer := io.MultiWriter(append([]io.Writer{l.writer}, sinks...)...)
 	scanner := bufio.NewScanner(reader)
 	for scanner.Scan() {
-		fmt.Fprintln(l.writer, scanner.Text())
+		fmt.Fprintln(writer, scanner.Text())
 	}
 }
@@ -0,0 +1,78 @@
+package worker
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"github.com/tinkerbell/tink/internal/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// actionLogShipper is an io.Writer that ships action output to the server. Shipping is best
+// effort; failures are logged and further output is discarded so log capture isn't interrupted.
+type actionLogShipper struct {
+	logger logr.Logger
+	stream proto.WorkflowService_StreamActionLogsClient
+	// base describes the action the shipped output belongs to.
+	base   *proto.ActionLog
+	failed bool
+}
+
+// newActionLogShipper opens a stream for shipping the output of action to the server.
+func (w *Worker) newActionLogShipper(ctx context.Context, wfID string, action *proto.WorkflowAction) *actionLogShipper {
+	l := w.getLogger(ctx)
+	shipper := &actionLogShipper{
+		logger: l,
+		base: &proto.ActionLog{
+			WorkflowId: wfID,
+			TaskName:   action.GetTaskName(),
+			ActionName: action.GetName(),
+			WorkerId:   action.GetWorkerId(),
+			Stream:     proto.LogStream_LOG_STREAM_STDOUT,
+		},
+	}
+
+	stream, err := w.tinkClient.StreamActionLogs(ctx)
+	if err != nil {
+		l.Error(err, "open action log stream")
+		shipper.failed = true
+		return shipper
+	}
+	shipper.stream = stream
+
+	return shipper
+}
+
+// Write satisfies io.Writer. It always succeeds.
+func (s *actionLogShipper) Write(p []byte) (int, error) {
+	if s.failed {
+		return len(p), nil
+	}
+
+	msg := &proto.ActionLog{
+		WorkflowId: s.base.GetWorkflowId(),
+		TaskName:   s.base.GetTaskName(),
+		ActionName: s.base.GetActionName(),
+		WorkerId:   s.base.GetWorkerId(),
+		Stream:     s.base.GetStream(),
+		Data:       append([]byte(nil), p...),
+		CreatedAt:  timestamppb.Now(),
+	}
+	if err := s.stream.Send(msg); err != nil {
+		s.logger.Error(err, "ship action logs")
+		s.failed = true
+	}
+	return len(p), nil
+}
+
+// Close flushes the stream and waits for the server to acknowledge the shipped output.
+func (s *actionLogShipper) Close() error {
+	if s.stream == nil {
+		return nil
+	}
+	if _, err := s.stream.CloseAndRecv(); err != nil && !s.failed {
+		s.logger.Error(err, "close action log stream")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-logr/zapr"
+	"github.com/tinkerbell/tink/internal/proto"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+type fakeActionLogsClient struct {
+	proto.WorkflowServiceClient
+	stream *fakeActionLogsStream
+	err    error
+}
+
+func (c *fakeActionLogsClient) StreamActionLogs(context.Context, ...grpc.CallOption) (proto.WorkflowService_StreamActionLogsClient, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return c.stream, nil
+}
+
+type fakeActionLogsStream struct {
+	proto.WorkflowService_StreamActionLogsClient
+	sent    []*proto.ActionLog
+	sendErr error
+	closed  bool
+}
+
+func (s *fakeActionLogsStream) Send(l *proto.ActionLog) error {
+	if s.sendErr != nil {
+		return s.sendErr
+	}
+	s.sent = append(s.sent, l)
+	return nil
+}
+
+func (s *fakeActionLogsStream) CloseAndRecv() (*proto.Empty, error) {
+	s.closed = true
+	return &proto.Empty{}, nil
+}
+
+func TestActionLogShipper(t *testing.T) {
+	cases := []struct {
+		name      string
+		client    *fakeActionLogsClient
+		wantLines int
+	}{
+		{
+			name:      "ships output",
+			client:    &fakeActionLogsClient{stream: &fakeActionLogsStream{}},
+			wantLines: 2,
+		},
+		{
+			name:   "stream fails to open",
+			client: &fakeActionLogsClient{err: errors.New("unavailable")},
+		},
+		{
+			name:   "send fails",
+			client: &fakeActionLogsClient{stream: &fakeActionLogsStream{sendErr: errors.New("reset")}},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := NewWorker("worker", tc.client, nil, nil, zapr.NewLogger(zap.Must(zap.NewDevelopment())))
+			action := &proto.WorkflowAction{TaskName: "task", Name: "action", WorkerId: "worker"}
+
+			shipper := w.newActionLogShipper(context.Background(), "default/workflow", action)
+			for _, line := range []string{"line1\n", "line2\n"} {
+				// Shipping is best effort so writes always succeed.
+				if n, err := shipper.Write([]byte(line)); err != nil || n != len(line) {
+					t.Fatalf("Write() = %v, %v", n, err)
+				}
+			}
+			if err := shipper.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			if tc.client.stream == nil {
+				return
+			}
+			if !tc.client.stream.closed {
+				t.Fatal("expected the stream to be closed")
+			}
+			if len(tc.client.stream.sent) != tc.wantLines {
+				t.Fatalf("expected %v lines shipped, got %v", tc.wantLines, len(tc.client.stream.sent))
+			}
+			for _, l := range tc.client.stream.sent {
+				if l.GetWorkflowId() != "default/workflow" || l.GetTaskName() != "task" || l.GetActionName() != "action" {
+					t.Fatalf("unexpected log identity: %v", l)
+				}
+			}
+		})
+	}
+}
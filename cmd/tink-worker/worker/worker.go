@@ -10,6 +10,9 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	"github.com/tinkerbell/tink/internal/proto"
+	"github.com/tinkerbell/tink/internal/when"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 const (
@@ -20,8 +23,20 @@ const (
 	errReportActionStatus = "failed to report action status"
 
 	msgTurn = "it's turn for a different worker: %s"
+
+	// Failure reasons used to match an action's retry reasons.
+	reasonTimeout         = "Timeout"
+	reasonRuntimeError    = "RuntimeError"
+	reasonNonZeroExitCode = "NonZeroExitCode"
+
+	// maxRetryBackoff caps the delay between action retries.
+	maxRetryBackoff = 5 * time.Minute
 )
 
+// errWorkflowCanceled is the cause used to cancel an action's context when the server reports the
+// Workflow has been canceled.
+var errWorkflowCanceled = errors.New("workflow canceled")
+
 type loggingContext string
 
 var loggingContextKey loggingContext = "logger"
@@ -67,6 +82,14 @@ func WithLogCapture(capture bool) Option {
 	}
 }
 
+// WithLogShipping enables shipping of captured container logs to the server. It only takes
+// effect when log capture is enabled.
+func WithLogShipping(ship bool) Option {
+	return func(w *Worker) {
+		w.shipLogs = ship
+	}
+}
+
 // WithPrivileged enables containers to be privileged.
 func WithPrivileged(privileged bool) Option {
 	return func(w *Worker) {
@@ -74,9 +97,9 @@ func WithPrivileged(privileged bool) Option {
 	}
 }
 
-// LogCapturer emits container logs.
+// LogCapturer emits container logs. Logs are additionally written to sinks.
 type LogCapturer interface {
-	CaptureLogs(ctx context.Context, containerID string)
+	CaptureLogs(ctx context.Context, containerID string, sinks ...io.Writer)
 }
 
 // ContainerManager manages linux containers for Tinkerbell workers.
@@ -102,9 +125,12 @@ type Worker struct {
 
 	createPrivileged bool
 	captureLogs      bool
+	shipLogs         bool
 
 	retries       int
 	retryInterval time.Duration
+
+	running *runningActions
 }
 
 // NewWorker creates a new Worker, creating a new Docker registry client.
@@ -128,6 +154,7 @@ func NewWorker(
 		retries:          3,
 		retryInterval:    time.Second * 3,
 		maxSize:          1 << 20,
+		running:          &runningActions{},
 	}
 	for _, opt := range opts {
 		opt(w)
@@ -176,8 +203,21 @@ func (w *Worker) execute(ctx context.Context, wfID string, action *proto.Workflo
 		return proto.State_STATE_RUNNING, errors.Wrap(err, "start container")
 	}
 
+	// Logs are captured until the container is removed. The action status is reported after
+	// capturing completes so shipped logs are available to the server when it's reported.
+	var captured chan struct{}
 	if w.captureLogs {
-		go w.logCapturer.CaptureLogs(ctx, id)
+		captured = make(chan struct{})
+		go func() {
+			defer close(captured)
+			if !w.shipLogs {
+				w.logCapturer.CaptureLogs(ctx, id)
+				return
+			}
+			shipper := w.newActionLogShipper(ctx, wfID, action)
+			defer shipper.Close()
+			w.logCapturer.CaptureLogs(ctx, id, shipper)
+		}()
 	}
 
 	st, err := w.containerManager.WaitForContainer(timeCtx, id)
@@ -187,10 +227,15 @@ func (w *Worker) execute(ctx context.Context, wfID string, action *proto.Workflo
 	// Everything after this is just cleanup.
 
 	defer func() {
-		if err := w.containerManager.RemoveContainer(ctx, id); err != nil {
+		// The container must be removed even if ctx was canceled, for example, because the
+		// Workflow was canceled. Removal is forced so running containers are killed.
+		if err := w.containerManager.RemoveContainer(context.WithoutCancel(ctx), id); err != nil {
 			l.Error(err, "remove container", "containerID", id)
 		}
 		l.Info("container removed", "status", st.String())
+		if captured != nil {
+			<-captured
+		}
 	}()
 
 	if err != nil {
@@ -243,8 +288,10 @@ func (w *Worker) executeReaction(ctx context.Context, reaction string, cmd []str
 	return <-st
 }
 
-// ProcessWorkflowActions gets all Workflow contexts and processes their actions.
+// ProcessWorkflowActions watches the Workflow contexts served to the worker and processes their
+// actions.
 func (w *Worker) ProcessWorkflowActions(ctx context.Context) error {
+	progress := actionProgress{}
 	for {
 		l := w.logger.WithValues("workerID", w.workerID)
 		select {
@@ -252,28 +299,29 @@ func (w *Worker) ProcessWorkflowActions(ctx context.Context) error {
 			return nil
 		default:
 		}
-		res, err := w.tinkClient.GetWorkflowContexts(ctx, &proto.WorkflowContextRequest{WorkerId: w.workerID})
+		// Servers that don't support watching close the stream once the contexts are sent so
+		// the worker falls back to polling. Contexts are received while actions execute so
+		// cancellations pushed by the server stop running actions.
+		streamCtx, cancelStream := context.WithCancel(ctx)
+		res, err := w.tinkClient.GetWorkflowContexts(streamCtx, &proto.WorkflowContextRequest{WorkerId: w.workerID, Watch: true})
 		if err != nil {
+			cancelStream()
 			l.Error(err, errGetWfContext)
 			<-time.After(w.retryInterval)
 			continue
 		}
+		queue := newContextQueue()
+		go w.receiveWorkflowContexts(l, res, queue)
 		for {
-			select {
-			case <-ctx.Done():
-				return nil
-			default:
-			}
-			wfContext, err := res.Recv()
-			if err != nil || wfContext == nil {
-				if !errors.Is(err, io.EOF) {
-					l.Info(err.Error())
-				}
-				<-time.After(w.retryInterval)
+			wfContext, ok := queue.Next(ctx)
+			if !ok {
 				break
 			}
+			if progress.Stale(wfContext) {
+				continue
+			}
 			wfID := wfContext.GetWorkflowId()
-			l = l.WithValues("workflowID", wfID)
+			l := l.WithValues("workflowID", wfID)
 			ctx := context.WithValue(ctx, loggingContextKey, l)
 
 			actions, err := w.tinkClient.GetWorkflowActions(ctx, &proto.WorkflowActionsRequest{WorkflowId: wfID})
@@ -292,7 +340,7 @@ func (w *Worker) ProcessWorkflowActions(ctx context.Context) error {
 				}
 			} else {
 				switch wfContext.GetCurrentActionState() {
-				case proto.State_STATE_SUCCESS:
+				case proto.State_STATE_SUCCESS, proto.State_STATE_SKIPPED:
 					if isLastAction(wfContext, actions) {
 						continue
 					}
@@ -302,6 +350,8 @@ func (w *Worker) ProcessWorkflowActions(ctx context.Context) error {
 					continue
 				case proto.State_STATE_TIMEOUT:
 					continue
+				case proto.State_STATE_CANCELED:
+					continue
 				default:
 					nextAction = actions.GetActionList()[wfContext.GetCurrentActionIndex()]
 					actionIndex = int(wfContext.GetCurrentActionIndex())
@@ -319,49 +369,37 @@ func (w *Worker) ProcessWorkflowActions(ctx context.Context) error {
 					"taskName", action.GetTaskName(),
 				)
 				ctx := context.WithValue(ctx, loggingContextKey, l)
-				if wfContext.GetCurrentActionState() != proto.State_STATE_RUNNING {
-					actionStatus := &proto.WorkflowActionStatus{
+
+				run, err := when.Evaluate(action.GetWhen(), actions.GetConditionData().AsMap())
+				if err != nil {
+					l.Error(err, "evaluate action condition")
+					w.reportActionStatus(ctx, l, &proto.WorkflowActionStatus{
 						WorkflowId:   wfID,
 						TaskName:     action.GetTaskName(),
 						ActionName:   action.GetName(),
-						ActionStatus: proto.State_STATE_RUNNING,
-						Seconds:      0,
-						Message:      "Started execution",
+						ActionStatus: proto.State_STATE_FAILED,
+						Message:      fmt.Sprintf("invalid condition: %v", err),
 						WorkerId:     action.GetWorkerId(),
-					}
-					w.reportActionStatus(ctx, l, actionStatus)
-					l.Info("sent action status", "status", actionStatus.ActionStatus, "duration", strconv.FormatInt(actionStatus.Seconds, 10))
-				}
-
-				// start executing the action
-				start := time.Now()
-				st, err := w.execute(ctx, wfID, action)
-				elapsed := time.Since(start)
-
-				actionStatus := &proto.WorkflowActionStatus{
-					WorkflowId: wfID,
-					TaskName:   action.GetTaskName(),
-					ActionName: action.GetName(),
-					Seconds:    int64(elapsed.Seconds()),
-					WorkerId:   action.GetWorkerId(),
+					})
+					progress.Finished(wfID, actionIndex)
+					break
 				}
 
-				if err != nil || st != proto.State_STATE_SUCCESS {
-					if st == proto.State_STATE_TIMEOUT {
-						actionStatus.ActionStatus = proto.State_STATE_TIMEOUT
-					} else {
-						actionStatus.ActionStatus = proto.State_STATE_FAILED
-					}
-					l = l.WithValues("actionStatus", actionStatus.ActionStatus.String())
-					l.Error(err, "execute workflow")
-					w.reportActionStatus(ctx, l, actionStatus)
+				if !run {
+					l.Info("skipping action; condition is false", "condition", action.GetWhen())
+					w.reportActionStatus(ctx, l, &proto.WorkflowActionStatus{
+						WorkflowId:   wfID,
+						TaskName:     action.GetTaskName(),
+						ActionName:   action.GetName(),
+						ActionStatus: proto.State_STATE_SKIPPED,
+						Message:      "condition is false",
+						WorkerId:     action.GetWorkerId(),
+					})
+				} else if !w.runAction(ctx, l, wfID, wfContext, action) {
+					progress.Finished(wfID, actionIndex)
 					break
 				}
-
-				actionStatus.ActionStatus = proto.State_STATE_SUCCESS
-				actionStatus.Message = "finished execution successfully"
-				w.reportActionStatus(ctx, l, actionStatus)
-				l.Info("sent action status")
+				progress.Finished(wfID, actionIndex)
 
 				if len(actions.GetActionList()) == actionIndex+1 {
 					l.Info("reached to end of workflow")
@@ -372,31 +410,276 @@ func (w *Worker) ProcessWorkflowActions(ctx context.Context) error {
 				if nextAction.GetWorkerId() != w.workerID {
 					l.Info(fmt.Sprintf(msgTurn, nextAction.GetWorkerId()))
 					turn = false
-				} else {
-					actionIndex++
+					break
 				}
+
+				// The workflow may have been paused before the next action. If so, it will be
+				// served again once it's resumed.
+				served, err := w.isWorkflowServed(ctx, wfID)
+				if err != nil {
+					l.Error(err, errGetWfContext)
+				}
+				if err == nil && !served {
+					l.Info("workflow paused, waiting for it to be resumed", "nextAction", nextAction.GetName())
+					turn = false
+					break
+				}
+				actionIndex++
 			}
 		}
+		cancelStream()
 		// sleep before asking for new workflows
 		<-time.After(w.retryInterval)
 	}
 }
 
+// isWorkflowServed checks the workflow with ID wfID is still served to the worker. Workflows that
+// are paused, or have finished, are not served.
+func (w *Worker) isWorkflowServed(ctx context.Context, wfID string) (bool, error) {
+	wfContext, err := w.getWorkflowContext(ctx, wfID)
+	if err != nil {
+		return false, err
+	}
+	return wfContext != nil, nil
+}
+
+// getWorkflowContext retrieves the workflow context with ID wfID from the workflow contexts
+// served for the worker. It returns nil if the workflow isn't served.
+func (w *Worker) getWorkflowContext(ctx context.Context, wfID string) (*proto.WorkflowContext, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	res, err := w.tinkClient.GetWorkflowContexts(ctx, &proto.WorkflowContextRequest{WorkerId: w.workerID})
+	if err != nil {
+		return nil, err
+	}
+	for {
+		wfContext, err := res.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if wfContext.GetWorkflowId() == wfID {
+			return wfContext, nil
+		}
+	}
+}
+
+// failureReason classifies a failed action execution for matching against an action's retry
+// reasons.
+func failureReason(st proto.State, err error) string {
+	switch {
+	case st == proto.State_STATE_TIMEOUT:
+		return reasonTimeout
+	case err != nil:
+		return reasonRuntimeError
+	default:
+		return reasonNonZeroExitCode
+	}
+}
+
+// shouldRetry determines if action should be retried after the attempt failed with reason.
+func shouldRetry(action *proto.WorkflowAction, attempt int64, reason string) bool {
+	if attempt > action.GetRetries() {
+		return false
+	}
+	if len(action.GetRetryOn()) == 0 {
+		return true
+	}
+	for _, r := range action.GetRetryOn() {
+		if r == reason {
+			return true
+		}
+	}
+	return false
+}
+
+// retryDelay returns the delay before retrying action after attempt failed. The delay doubles with
+// each attempt and is capped at maxRetryBackoff.
+func retryDelay(action *proto.WorkflowAction, attempt int64) time.Duration {
+	delay := time.Duration(action.GetBackoff()) * time.Second
+	for i := int64(1); i < attempt && delay < maxRetryBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryBackoff {
+		return maxRetryBackoff
+	}
+	return delay
+}
+
+// runAction executes action until it completes or the workflow is canceled, retrying failed
+// attempts as permitted by the action's retry policy. It returns true if the action succeeded and
+// the worker should continue with the next action.
+func (w *Worker) runAction(ctx context.Context, l logr.Logger, wfID string, wfContext *proto.WorkflowContext, action *proto.WorkflowAction) bool {
+	if wfContext.GetCurrentActionState() != proto.State_STATE_RUNNING {
+		actionStatus := &proto.WorkflowActionStatus{
+			WorkflowId:   wfID,
+			TaskName:     action.GetTaskName(),
+			ActionName:   action.GetName(),
+			ActionStatus: proto.State_STATE_RUNNING,
+			Seconds:      0,
+			Message:      "Started execution",
+			WorkerId:     action.GetWorkerId(),
+			Attempt:      1,
+		}
+		w.reportActionStatus(ctx, l, actionStatus)
+		l.Info("sent action status", "status", actionStatus.ActionStatus, "duration", strconv.FormatInt(actionStatus.Seconds, 10))
+	}
+
+	var (
+		actionStatus *proto.WorkflowActionStatus
+		canceled     bool
+	)
+	for attempt := int64(1); ; attempt++ {
+		start := time.Now()
+		actionCtx, cancelAction := context.WithCancelCause(ctx)
+		untrack := w.running.Add(wfID, cancelAction)
+		st, err := w.execute(actionCtx, wfID, action)
+		canceled = errors.Is(context.Cause(actionCtx), errWorkflowCanceled)
+		untrack()
+		cancelAction(nil)
+		elapsed := time.Since(start)
+
+		if canceled {
+			break
+		}
+
+		actionStatus = &proto.WorkflowActionStatus{
+			WorkflowId: wfID,
+			TaskName:   action.GetTaskName(),
+			ActionName: action.GetName(),
+			Seconds:    int64(elapsed.Seconds()),
+			WorkerId:   action.GetWorkerId(),
+			Attempt:    attempt,
+		}
+
+		if err == nil && st == proto.State_STATE_SUCCESS {
+			actionStatus.ActionStatus = proto.State_STATE_SUCCESS
+			actionStatus.Message = "finished execution successfully"
+			break
+		}
+
+		if st == proto.State_STATE_TIMEOUT {
+			actionStatus.ActionStatus = proto.State_STATE_TIMEOUT
+		} else {
+			actionStatus.ActionStatus = proto.State_STATE_FAILED
+		}
+		l.Error(err, "execute workflow", "actionStatus", actionStatus.ActionStatus.String(), "attempt", attempt)
+
+		reason := failureReason(st, err)
+		if !shouldRetry(action, attempt, reason) {
+			break
+		}
+
+		delay := retryDelay(action, attempt)
+		actionStatus.WillRetry = true
+		actionStatus.Message = fmt.Sprintf("retrying in %v: %v", delay, reason)
+		w.reportActionStatus(ctx, l, actionStatus)
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(delay):
+		}
+
+		w.reportActionStatus(ctx, l, &proto.WorkflowActionStatus{
+			WorkflowId:   wfID,
+			TaskName:     action.GetTaskName(),
+			ActionName:   action.GetName(),
+			ActionStatus: proto.State_STATE_RUNNING,
+			Message:      "Started execution",
+			WorkerId:     action.GetWorkerId(),
+			Attempt:      attempt + 1,
+		})
+	}
+
+	if canceled {
+		// The server no longer accepts action status for canceled workflows.
+		l.Info("workflow canceled, action stopped")
+		return false
+	}
+
+	w.reportActionStatus(ctx, l, actionStatus)
+	if actionStatus.ActionStatus != proto.State_STATE_SUCCESS {
+		return false
+	}
+	l.Info("sent action status")
+	return true
+}
+
+// progressTTL is the duration the worker ignores workflow contexts describing progress it has
+// already made. It bounds how long the server may take to observe the worker's reports.
+const progressTTL = time.Minute
+
+// actionProgress tracks the last action the worker finished for each Workflow. Contexts are
+// pushed to the worker as Workflows change so contexts sent before the server observed the
+// worker's reports may be received after the worker has moved on. Acting on them would run
+// finished actions again.
+type actionProgress map[string]finishedAction
+
+type finishedAction struct {
+	Index int
+	At    time.Time
+}
+
+// Finished records the action at actionIndex of the Workflow identified by wfID as finished.
+func (p actionProgress) Finished(wfID string, actionIndex int) {
+	p[wfID] = finishedAction{Index: actionIndex, At: time.Now()}
+}
+
+// Stale determines if wfContext describes a Workflow before the last action the worker finished.
+func (p actionProgress) Stale(wfContext *proto.WorkflowContext) bool {
+	finished, ok := p[wfContext.GetWorkflowId()]
+	if !ok {
+		return false
+	}
+
+	index := int(wfContext.GetCurrentActionIndex())
+	if time.Since(finished.At) < progressTTL {
+		switch {
+		case index < finished.Index:
+			return true
+		case index == finished.Index:
+			switch wfContext.GetCurrentActionState() { //nolint:exhaustive // Remaining states indicate the action finished.
+			case proto.State_STATE_PENDING, proto.State_STATE_RUNNING:
+				return true
+			}
+		}
+	}
+
+	// The server has observed the worker's progress, or has had ample time to.
+	delete(p, wfContext.GetWorkflowId())
+	return false
+}
+
 func isLastAction(wfContext *proto.WorkflowContext, actions *proto.WorkflowActionList) bool {
 	return int(wfContext.GetCurrentActionIndex()) == len(actions.GetActionList())-1
 }
 
-// reportActionStatus reports the status of an action to the Tinkerbell server and retries forever on error.
+// reportActionStatus reports the status of an action to the Tinkerbell server and retries on
+// error until the server accepts or rejects the report.
 func (w *Worker) reportActionStatus(ctx context.Context, l logr.Logger, actionStatus *proto.WorkflowActionStatus) {
 	for {
 		l.Info("reporting Action Status")
 		_, err := w.tinkClient.ReportActionStatus(ctx, actionStatus)
-		if err != nil {
-			l.Error(err, errReportActionStatus)
-			<-time.After(w.retryInterval)
+		if err == nil {
+			return
+		}
+		l.Error(err, errReportActionStatus)
 
-			continue
+		// The server rejects reports that don't match the workflow or that the worker isn't
+		// authorized to make; retrying won't change that.
+		switch status.Code(err) {
+		case codes.InvalidArgument, codes.NotFound, codes.PermissionDenied:
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(w.retryInterval):
 		}
-		return
 	}
 }
@@ -0,0 +1,99 @@
+package worker
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tinkerbell/tink/internal/proto"
+)
+
+func TestShouldRetry(t *testing.T) {
+	cases := []struct {
+		name    string
+		action  *proto.WorkflowAction
+		attempt int64
+		reason  string
+		want    bool
+	}{
+		{"NoRetries", &proto.WorkflowAction{}, 1, reasonNonZeroExitCode, false},
+		{"RetriesRemaining", &proto.WorkflowAction{Retries: 2}, 2, reasonNonZeroExitCode, true},
+		{"RetriesExhausted", &proto.WorkflowAction{Retries: 2}, 3, reasonNonZeroExitCode, false},
+		{"MatchingReason", &proto.WorkflowAction{Retries: 1, RetryOn: []string{reasonTimeout}}, 1, reasonTimeout, true},
+		{"UnmatchedReason", &proto.WorkflowAction{Retries: 1, RetryOn: []string{reasonTimeout}}, 1, reasonRuntimeError, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := shouldRetry(tc.action, tc.attempt, tc.reason); got != tc.want {
+				t.Fatalf("shouldRetry() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	action := &proto.WorkflowAction{Backoff: 10}
+	cases := map[int64]time.Duration{
+		1:  10 * time.Second,
+		2:  20 * time.Second,
+		3:  40 * time.Second,
+		10: maxRetryBackoff,
+	}
+	for attempt, want := range cases {
+		if got := retryDelay(action, attempt); got != want {
+			t.Fatalf("retryDelay(%v) = %v, want %v", attempt, got, want)
+		}
+	}
+}
+
+func TestFailureReason(t *testing.T) {
+	if got := failureReason(proto.State_STATE_TIMEOUT, nil); got != reasonTimeout {
+		t.Fatalf("got %v, want %v", got, reasonTimeout)
+	}
+	if got := failureReason(proto.State_STATE_RUNNING, errors.New("pull image")); got != reasonRuntimeError {
+		t.Fatalf("got %v, want %v", got, reasonRuntimeError)
+	}
+	if got := failureReason(proto.State_STATE_FAILED, nil); got != reasonNonZeroExitCode {
+		t.Fatalf("got %v, want %v", got, reasonNonZeroExitCode)
+	}
+}
+
+func TestActionProgressStale(t *testing.T) {
+	newContext := func(index int64, state proto.State) *proto.WorkflowContext {
+		return &proto.WorkflowContext{WorkflowId: "default/workflow", CurrentActionIndex: index, CurrentActionState: state}
+	}
+
+	cases := []struct {
+		name    string
+		context *proto.WorkflowContext
+		want    bool
+	}{
+		{"EarlierAction", newContext(0, proto.State_STATE_SUCCESS), true},
+		{"FinishedActionRunning", newContext(1, proto.State_STATE_RUNNING), true},
+		{"FinishedActionPending", newContext(1, proto.State_STATE_PENDING), true},
+		{"FinishedActionFailed", newContext(1, proto.State_STATE_FAILED), false},
+		{"NextAction", newContext(2, proto.State_STATE_PENDING), false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			progress := actionProgress{}
+			progress.Finished("default/workflow", 1)
+			if got := progress.Stale(tc.context); got != tc.want {
+				t.Fatalf("Stale() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+
+	t.Run("Untracked", func(t *testing.T) {
+		if (actionProgress{}).Stale(newContext(0, proto.State_STATE_RUNNING)) {
+			t.Fatal("Expected contexts for untracked workflows to be current")
+		}
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		progress := actionProgress{"default/workflow": {Index: 1, At: time.Now().Add(-progressTTL)}}
+		if progress.Stale(newContext(0, proto.State_STATE_PENDING)) {
+			t.Fatal("Expected contexts to be current once progress expires")
+		}
+	})
+}
@@ -0,0 +1,161 @@
+package worker
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"github.com/tinkerbell/tink/internal/proto"
+)
+
+// contextQueue holds the Workflow contexts received from the server that are yet to be processed.
+// Only the latest context for each Workflow is of interest so a context replaces any queued
+// context for the same Workflow, retaining its position.
+type contextQueue struct {
+	mtx     sync.Mutex
+	pending []*proto.WorkflowContext
+	closed  bool
+
+	// ready is signaled when a context is pushed or the queue is closed.
+	ready chan struct{}
+}
+
+func newContextQueue() *contextQueue {
+	return &contextQueue{ready: make(chan struct{}, 1)}
+}
+
+// Push queues wfContext for processing.
+func (q *contextQueue) Push(wfContext *proto.WorkflowContext) {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	replaced := false
+	for i, queued := range q.pending {
+		if queued.GetWorkflowId() == wfContext.GetWorkflowId() {
+			q.pending[i] = wfContext
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		q.pending = append(q.pending, wfContext)
+	}
+	q.signal()
+}
+
+// Close marks the queue as receiving no more contexts. Queued contexts remain available to Next.
+func (q *contextQueue) Close() {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+	q.closed = true
+	q.signal()
+}
+
+// Next blocks until a context is queued and returns it. It returns false once the queue is closed
+// and drained, or when ctx is done.
+func (q *contextQueue) Next(ctx context.Context) (*proto.WorkflowContext, bool) {
+	for {
+		q.mtx.Lock()
+		if len(q.pending) > 0 {
+			wfContext := q.pending[0]
+			q.pending = q.pending[1:]
+			q.mtx.Unlock()
+			return wfContext, true
+		}
+		closed := q.closed
+		q.mtx.Unlock()
+
+		if closed {
+			return nil, false
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, false
+		case <-q.ready:
+		}
+	}
+}
+
+func (q *contextQueue) signal() {
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
+
+// runningActions tracks the cancel functions of the actions being executed by Workflow ID, and
+// the Workflows the server reported as canceled, so actions of canceled Workflows are stopped.
+type runningActions struct {
+	mtx      sync.Mutex
+	cancels  map[string]context.CancelCauseFunc
+	canceled map[string]bool
+}
+
+// Add tracks cancel as the cancel function of the action executing for wfID. If the Workflow was
+// reported as canceled, cancel is called immediately. The returned function stops tracking it.
+func (r *runningActions) Add(wfID string, cancel context.CancelCauseFunc) func() {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if r.cancels == nil {
+		r.cancels = map[string]context.CancelCauseFunc{}
+	}
+	r.cancels[wfID] = cancel
+	if r.canceled[wfID] {
+		cancel(errWorkflowCanceled)
+	}
+
+	return func() {
+		r.mtx.Lock()
+		defer r.mtx.Unlock()
+		delete(r.cancels, wfID)
+	}
+}
+
+// Observe records whether wfContext reports its Workflow as canceled and, if so, cancels the
+// action executing for it. It reports whether an action was canceled.
+func (r *runningActions) Observe(wfContext *proto.WorkflowContext) bool {
+	wfID := wfContext.GetWorkflowId()
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	// Workflows may be re-created with the same ID so the record is cleared when the Workflow is
+	// reported as anything but canceled.
+	if wfContext.GetCurrentActionState() != proto.State_STATE_CANCELED {
+		delete(r.canceled, wfID)
+		return false
+	}
+	if r.canceled == nil {
+		r.canceled = map[string]bool{}
+	}
+	r.canceled[wfID] = true
+
+	cancel, ok := r.cancels[wfID]
+	if ok {
+		cancel(errWorkflowCanceled)
+	}
+	return ok
+}
+
+// receiveWorkflowContexts queues the contexts received on res until the stream ends. Actions are
+// stopped as soon as the context of their Workflow reports it was canceled, rather than when the
+// context is processed, because contexts are processed between actions.
+func (w *Worker) receiveWorkflowContexts(l logr.Logger, res proto.WorkflowService_GetWorkflowContextsClient, queue *contextQueue) {
+	defer queue.Close()
+	for {
+		wfContext, err := res.Recv()
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				l.Info(err.Error())
+			}
+			return
+		}
+
+		if w.running.Observe(wfContext) {
+			l.Info("workflow canceled, stopping action", "workflowID", wfContext.GetWorkflowId())
+		}
+		queue.Push(wfContext)
+	}
+}
@@ -0,0 +1,85 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/tinkerbell/tink/internal/proto"
+	"google.golang.org/grpc"
+)
+
+type workflowContextsStream struct {
+	grpc.ClientStream
+	contexts []*proto.WorkflowContext
+}
+
+func (s *workflowContextsStream) Recv() (*proto.WorkflowContext, error) {
+	if len(s.contexts) == 0 {
+		return nil, io.EOF
+	}
+	wfContext := s.contexts[0]
+	s.contexts = s.contexts[1:]
+	return wfContext, nil
+}
+
+func TestReceiveWorkflowContexts(t *testing.T) {
+	w := NewWorker("worker", nil, nil, nil, logr.Discard())
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+	untrack := w.running.Add("default/running", cancel)
+	defer untrack()
+
+	stream := &workflowContextsStream{contexts: []*proto.WorkflowContext{
+		{WorkflowId: "default/running", CurrentActionState: proto.State_STATE_RUNNING},
+		{WorkflowId: "default/pending", CurrentActionState: proto.State_STATE_PENDING},
+		{WorkflowId: "default/running", CurrentActionState: proto.State_STATE_CANCELED},
+	}}
+	queue := newContextQueue()
+	w.receiveWorkflowContexts(logr.Discard(), stream, queue)
+
+	if !errors.Is(context.Cause(ctx), errWorkflowCanceled) {
+		t.Fatalf("Expected running action to be canceled, got cause %v", context.Cause(ctx))
+	}
+
+	// Contexts replace queued contexts for the same Workflow.
+	want := []*proto.WorkflowContext{
+		{WorkflowId: "default/running", CurrentActionState: proto.State_STATE_CANCELED},
+		{WorkflowId: "default/pending", CurrentActionState: proto.State_STATE_PENDING},
+	}
+	for _, wantCtx := range want {
+		got, ok := queue.Next(context.Background())
+		if !ok {
+			t.Fatal("Expected a queued context")
+		}
+		if got.GetWorkflowId() != wantCtx.GetWorkflowId() || got.GetCurrentActionState() != wantCtx.GetCurrentActionState() {
+			t.Fatalf("Unexpected context: got %v, want %v", got, wantCtx)
+		}
+	}
+	if _, ok := queue.Next(context.Background()); ok {
+		t.Fatal("Expected closed queue to be drained")
+	}
+}
+
+func TestRunningActionsCanceledBeforeStart(t *testing.T) {
+	var running runningActions
+	running.Observe(&proto.WorkflowContext{WorkflowId: "default/workflow", CurrentActionState: proto.State_STATE_CANCELED})
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	running.Add("default/workflow", cancel)()
+	if !errors.Is(context.Cause(ctx), errWorkflowCanceled) {
+		t.Fatalf("Expected action of canceled workflow to be canceled, got cause %v", context.Cause(ctx))
+	}
+
+	// A re-created Workflow with the same ID isn't canceled.
+	running.Observe(&proto.WorkflowContext{WorkflowId: "default/workflow", CurrentActionState: proto.State_STATE_PENDING})
+	ctx, cancel = context.WithCancelCause(context.Background())
+	defer cancel(nil)
+	running.Add("default/workflow", cancel)()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("Unexpected cancellation: %v", err)
+	}
+}
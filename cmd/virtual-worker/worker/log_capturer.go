@@ -2,13 +2,14 @@ package worker
 
 import (
 	"context"
+	"io"
 
 	"github.com/tinkerbell/tink/cmd/tink-worker/worker"
 )
 
 type emptyLogger struct{}
 
-func (l *emptyLogger) CaptureLogs(context.Context, string) {}
+func (l *emptyLogger) CaptureLogs(context.Context, string, ...io.Writer) {}
 
 // NewEmptyLogCapturer returns an no-op log capturer.
 func NewEmptyLogCapturer() worker.LogCapturer {
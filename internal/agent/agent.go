@@ -28,17 +28,41 @@ type Agent struct {
 	// Runtime is the container runtime used to execute workflow actions.
 	Runtime ContainerRuntime
 
+	// Logs ships the output of workflow actions. When nil, action output is discarded.
+	Logs LogShipper
+
+	// Verifier verifies action images before they're run. When nil, images aren't verified.
+	Verifier ImageVerifier
+
+	// Rebooter performs reboot and kexec actions. When nil, reboot and kexec actions fail.
+	Rebooter Rebooter
+
+	// PullConcurrency is the maximum number of images pulled concurrently when the Runtime is an
+	// ImagePuller. Defaults to DefaultPullConcurrency.
+	PullConcurrency int
+
+	// State persists the executing workflow and its events so the workflow can be recovered if
+	// the agent restarts. The Transport must implement event.Recorder so events that weren't sent
+	// can be recorded on start. When the Transport implements event.AckRecorder, events are
+	// retained until they're acknowledged. When nil, state isn't persisted.
+	State StateStore
+
 	// sem ensure we handle a single workflow at a time.
 	sem chan struct{}
 
 	// executionContext tracks the currently executing workflow.
 	executionContext *executionContext
 	mtx              sync.RWMutex
+
+	// journal persists state when State is set.
+	journal *journal
 }
 
 // Start finalizes the Agent configuration and starts the configured Transport so it is ready
 // to receive workflows. On receiving a workflow, it will leverage the configured Runtime to
-// execute workflow actions.
+// execute workflow actions. When State is set, a workflow interrupted by the agent restarting is
+// resumed before the Transport is started; Transports that implement EventPublisher start
+// publishing events first.
 func (agent *Agent) Start(ctx context.Context) error {
 	if agent.ID == "" {
 		return errors.New("ID field must be set before calling Start()")
@@ -57,11 +81,38 @@ func (agent *Agent) Start(ctx context.Context) error {
 		agent.Log = logr.Discard()
 	}
 
+	if agent.Logs == nil {
+		agent.Logs = discardLogs{}
+	}
+
+	if agent.PullConcurrency <= 0 {
+		agent.PullConcurrency = DefaultPullConcurrency
+	}
+
+	var recorder event.Recorder
+	if agent.State != nil {
+		var ok bool
+		if recorder, ok = agent.Transport.(event.Recorder); !ok {
+			return errors.New("Transport field must implement event.Recorder when State is set")
+		}
+	}
+
 	agent.Log = agent.Log.WithValues("agent_id", agent.ID)
 
+	// Events recorded while recovering are published before workflows are retrieved so recording
+	// them doesn't block on a full outbox.
+	if p, ok := agent.Transport.(EventPublisher); ok {
+		p.StartPublishing(ctx)
+	}
+
 	// Initialize the semaphore and add a resource to it ensuring we can run 1 workflow at a time.
+	// When recovering, the resource is held by the resumed workflow.
 	agent.sem = make(chan struct{}, 1)
-	agent.sem <- struct{}{}
+	if agent.State != nil {
+		agent.recover(ctx, recorder)
+	} else {
+		agent.sem <- struct{}{}
+	}
 
 	return agent.Transport.Start(ctx, agent.ID, agent)
 }
@@ -74,32 +125,21 @@ func (agent *Agent) HandleWorkflow(ctx context.Context, wflw workflow.Workflow,
 
 	select {
 	case <-agent.sem:
-		// Ensure we configure the current workflow and cancellation func before we launch the
-		// goroutine to avoid a race with CancelWorkflow.
-		agent.mtx.Lock()
-		defer agent.mtx.Unlock()
-
-		ctx, cancel := context.WithCancel(ctx)
-		agent.executionContext = &executionContext{
-			Workflow: wflw,
-			Cancel:   cancel,
-		}
-
-		go func() {
-			// Replenish the semaphore on exit so we can pick up another workflow.
-			defer func() { agent.sem <- struct{}{} }()
-
-			agent.run(ctx, wflw, events)
-
-			// Nilify the execution context after running so cancellation requests are ignored.
-			agent.mtx.Lock()
-			defer agent.mtx.Unlock()
-			agent.executionContext = nil
-		}()
+		agent.execute(ctx, wflw, events, nil)
 
 	default:
 		log := agent.Log.WithValues("workflow_id", wflw.ID)
 
+		// The server re-sends workflows it hasn't seen progress on when the transport reconnects
+		// so requests for the executing workflow are duplicates rather than conflicts.
+		agent.mtx.RLock()
+		duplicate := agent.executionContext != nil && agent.executionContext.Workflow.ID == wflw.ID
+		agent.mtx.RUnlock()
+		if duplicate {
+			log.Info("Workflow already executing; ignoring duplicate request")
+			return
+		}
+
 		reject := event.WorkflowRejected{
 			ID:      wflw.ID,
 			Message: "workflow already in progress",
@@ -114,6 +154,48 @@ func (agent *Agent) HandleWorkflow(ctx context.Context, wflw workflow.Workflow,
 	}
 }
 
+// execute runs wflw in a new goroutine. rec is the progress recovered for wflw when it's resumed
+// after the agent restarted; when nil, progress is taken from the workflow's Results. The caller
+// must hold the semaphore; it's replenished when the workflow finishes.
+func (agent *Agent) execute(ctx context.Context, wflw workflow.Workflow, events event.Recorder, rec *recovery) {
+	// Ensure we configure the current workflow and cancellation func before we launch the
+	// goroutine to avoid a race with CancelWorkflow.
+	agent.mtx.Lock()
+	defer agent.mtx.Unlock()
+
+	if agent.journal != nil {
+		agent.journal.begin(wflw)
+		events = agent.journal.recorder(events)
+	}
+
+	if rec == nil && len(wflw.Results) > 0 {
+		rec = newRecovery(wflw, nil)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	agent.executionContext = &executionContext{
+		Workflow: wflw,
+		Cancel:   cancel,
+		Pause:    newPauseGate(wflw.Pause),
+		Recovery: rec,
+	}
+
+	go func() {
+		// Replenish the semaphore on exit so we can pick up another workflow.
+		defer func() { agent.sem <- struct{}{} }()
+
+		agent.run(ctx, wflw, events)
+
+		// Nilify the execution context after running so cancellation requests are ignored.
+		agent.mtx.Lock()
+		defer agent.mtx.Unlock()
+		agent.executionContext = nil
+		if agent.journal != nil {
+			agent.journal.end()
+		}
+	}()
+}
+
 func (agent *Agent) CancelWorkflow(workflowID string) {
 	agent.mtx.RLock()
 	defer agent.mtx.RUnlock()
@@ -136,7 +218,32 @@ func (agent *Agent) CancelWorkflow(workflowID string) {
 	agent.executionContext.Cancel()
 }
 
+// PauseWorkflow satisfies transport.
+func (agent *Agent) PauseWorkflow(workflowID string, pause workflow.Pause) {
+	agent.mtx.RLock()
+	defer agent.mtx.RUnlock()
+
+	if agent.executionContext == nil || agent.executionContext.Workflow.ID != workflowID {
+		agent.Log.Info("Workflow not running; ignoring pause request", "workflow_id", workflowID)
+		return
+	}
+
+	agent.Log.Info("Update workflow pause",
+		"workflow_id", workflowID,
+		"paused", pause.Paused,
+		"breakpoints", pause.Breakpoints,
+	)
+	agent.executionContext.Pause.Set(pause)
+}
+
 type executionContext struct {
 	Workflow workflow.Workflow
 	Cancel   context.CancelFunc
+
+	// Pause holds actions while the workflow is paused.
+	Pause *pauseGate
+
+	// Recovery is the progress made through the workflow before it was received, for example
+	// before the agent restarted.
+	Recovery *recovery
 }
@@ -1,8 +1,15 @@
 package agent_test
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"io"
+	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -13,6 +20,7 @@ import (
 	"github.com/tinkerbell/tink/internal/agent/event"
 	"github.com/tinkerbell/tink/internal/agent/failure"
 	"github.com/tinkerbell/tink/internal/agent/runtime"
+	"github.com/tinkerbell/tink/internal/agent/state"
 	"github.com/tinkerbell/tink/internal/agent/transport"
 	"github.com/tinkerbell/tink/internal/agent/workflow"
 	"go.uber.org/zap"
@@ -105,10 +113,10 @@ func TestAgent_ConcurrentWorkflows(t *testing.T) {
 	// Started is used to indicate the runtime has received the workflow.
 	started := make(chan struct{})
 	rntime := agent.ContainerRuntimeMock{
-		RunFunc: func(ctx context.Context, _ workflow.Action) error {
+		RunFunc: func(ctx context.Context, _ workflow.Action, _, _ io.Writer) (map[string]string, error) {
 			started <- struct{}{}
 			<-ctx.Done()
-			return nil
+			return nil, nil
 		},
 	}
 
@@ -139,8 +147,18 @@ func TestAgent_ConcurrentWorkflows(t *testing.T) {
 		t.Fatal(ctx.Err())
 	}
 
-	// Attempt to fire off a second workflow.
+	// Requests for the executing workflow are duplicates so are ignored.
 	agnt.HandleWorkflow(ctx, wflw, recorder)
+	if calls := recorder.RecordEventCalls(); len(calls) > 0 {
+		if _, ok := calls[len(calls)-1].Event.(event.WorkflowRejected); ok {
+			t.Fatal("Unexpected rejection of the executing workflow")
+		}
+	}
+
+	// Attempt to fire off a second workflow.
+	second := wflw
+	second.ID = "5678"
+	agnt.HandleWorkflow(ctx, second, recorder)
 
 	// Ensure the latest event recorded is a event.WorkflowRejected.
 	calls := recorder.RecordEventCalls()
@@ -155,7 +173,7 @@ func TestAgent_ConcurrentWorkflows(t *testing.T) {
 	}
 
 	expectEvent := event.WorkflowRejected{
-		ID:      wflw.ID,
+		ID:      second.ID,
 		Message: "workflow already in progress",
 	}
 	if !cmp.Equal(expectEvent, ev) {
@@ -174,7 +192,10 @@ func TestAgent_HandlingWorkflows(t *testing.T) {
 		Name     string
 		Workflow workflow.Workflow
 		Errors   map[string]ReasonAndMessage
-		Events   []event.Event
+		// FailedAttempts limits the number of attempts that return the action's error. When
+		// unspecified for an action, all attempts fail.
+		FailedAttempts map[string]int
+		Events         []event.Event
 	}{
 		{
 			Name: "SuccessfulWorkflow",
@@ -198,18 +219,22 @@ func TestAgent_HandlingWorkflows(t *testing.T) {
 				event.ActionStarted{
 					WorkflowID: "1234",
 					ActionID:   "1",
+					Attempt:    1,
 				},
 				event.ActionSucceeded{
 					WorkflowID: "1234",
 					ActionID:   "1",
+					Attempt:    1,
 				},
 				event.ActionStarted{
 					WorkflowID: "1234",
 					ActionID:   "2",
+					Attempt:    1,
 				},
 				event.ActionSucceeded{
 					WorkflowID: "1234",
 					ActionID:   "2",
+					Attempt:    1,
 				},
 			},
 		},
@@ -240,20 +265,24 @@ func TestAgent_HandlingWorkflows(t *testing.T) {
 				event.ActionStarted{
 					WorkflowID: "1234",
 					ActionID:   "1",
+					Attempt:    1,
 				},
 				event.ActionSucceeded{
 					WorkflowID: "1234",
 					ActionID:   "1",
+					Attempt:    1,
 				},
 				event.ActionStarted{
 					WorkflowID: "1234",
 					ActionID:   "2",
+					Attempt:    1,
 				},
 				event.ActionFailed{
 					WorkflowID: "1234",
 					ActionID:   "2",
 					Reason:     "TestReason",
 					Message:    "test message",
+					Attempt:    1,
 				},
 			},
 		},
@@ -284,12 +313,14 @@ func TestAgent_HandlingWorkflows(t *testing.T) {
 				event.ActionStarted{
 					WorkflowID: "1234",
 					ActionID:   "1",
+					Attempt:    1,
 				},
 				event.ActionFailed{
 					WorkflowID: "1234",
 					ActionID:   "1",
 					Reason:     "TestReason",
 					Message:    "test message",
+					Attempt:    1,
 				},
 			},
 		},
@@ -325,20 +356,24 @@ func TestAgent_HandlingWorkflows(t *testing.T) {
 				event.ActionStarted{
 					WorkflowID: "1234",
 					ActionID:   "1",
+					Attempt:    1,
 				},
 				event.ActionSucceeded{
 					WorkflowID: "1234",
 					ActionID:   "1",
+					Attempt:    1,
 				},
 				event.ActionStarted{
 					WorkflowID: "1234",
 					ActionID:   "2",
+					Attempt:    1,
 				},
 				event.ActionFailed{
 					WorkflowID: "1234",
 					ActionID:   "2",
 					Reason:     "TestReason",
 					Message:    "test message",
+					Attempt:    1,
 				},
 			},
 		},
@@ -364,12 +399,14 @@ func TestAgent_HandlingWorkflows(t *testing.T) {
 				event.ActionStarted{
 					WorkflowID: "1234",
 					ActionID:   "1",
+					Attempt:    1,
 				},
 				event.ActionFailed{
 					WorkflowID: "1234",
 					ActionID:   "1",
 					Reason:     "InvalidReason",
 					Message:    "test message",
+					Attempt:    1,
 				},
 			},
 		},
@@ -396,13 +433,260 @@ message`,
 				event.ActionStarted{
 					WorkflowID: "1234",
 					ActionID:   "1",
+					Attempt:    1,
 				},
 				event.ActionFailed{
 					WorkflowID: "1234",
 					ActionID:   "1",
 					Reason:     "TestReason",
 					Message:    `invalid \nmessage`,
+					Attempt:    1,
+				},
+			},
+		},
+		{
+			Name: "RetriedActionSucceeds",
+			Workflow: workflow.Workflow{
+				ID: "1234",
+				Actions: []workflow.Action{
+					{
+						ID:    "1",
+						Name:  "action_1",
+						Image: "image_1",
+						Retry: workflow.RetryPolicy{Retries: 2},
+					},
+				},
+			},
+			Errors: map[string]ReasonAndMessage{
+				"1": {
+					Reason:  "TestReason",
+					Message: "test message",
+				},
+			},
+			FailedAttempts: map[string]int{"1": 1},
+			Events: []event.Event{
+				event.ActionStarted{
+					WorkflowID: "1234",
+					ActionID:   "1",
+					Attempt:    1,
+				},
+				event.ActionFailed{
+					WorkflowID: "1234",
+					ActionID:   "1",
+					Reason:     "TestReason",
+					Message:    "test message",
+					Attempt:    1,
+					WillRetry:  true,
+				},
+				event.ActionStarted{
+					WorkflowID: "1234",
+					ActionID:   "1",
+					Attempt:    2,
+				},
+				event.ActionSucceeded{
+					WorkflowID: "1234",
+					ActionID:   "1",
+					Attempt:    2,
+				},
+			},
+		},
+		{
+			Name: "RetriesExhausted",
+			Workflow: workflow.Workflow{
+				ID: "1234",
+				Actions: []workflow.Action{
+					{
+						ID:    "1",
+						Name:  "action_1",
+						Image: "image_1",
+						Retry: workflow.RetryPolicy{Retries: 1},
+					},
+				},
+			},
+			Errors: map[string]ReasonAndMessage{
+				"1": {
+					Reason:  "TestReason",
+					Message: "test message",
+				},
+			},
+			Events: []event.Event{
+				event.ActionStarted{
+					WorkflowID: "1234",
+					ActionID:   "1",
+					Attempt:    1,
+				},
+				event.ActionFailed{
+					WorkflowID: "1234",
+					ActionID:   "1",
+					Reason:     "TestReason",
+					Message:    "test message",
+					Attempt:    1,
+					WillRetry:  true,
+				},
+				event.ActionStarted{
+					WorkflowID: "1234",
+					ActionID:   "1",
+					Attempt:    2,
+				},
+				event.ActionFailed{
+					WorkflowID: "1234",
+					ActionID:   "1",
+					Reason:     "TestReason",
+					Message:    "test message",
+					Attempt:    2,
+				},
+			},
+		},
+		{
+			Name: "ReasonNotRetried",
+			Workflow: workflow.Workflow{
+				ID: "1234",
+				Actions: []workflow.Action{
+					{
+						ID:    "1",
+						Name:  "action_1",
+						Image: "image_1",
+						Retry: workflow.RetryPolicy{Retries: 2, Reasons: []string{"OtherReason"}},
+					},
+				},
+			},
+			Errors: map[string]ReasonAndMessage{
+				"1": {
+					Reason:  "TestReason",
+					Message: "test message",
+				},
+			},
+			Events: []event.Event{
+				event.ActionStarted{
+					WorkflowID: "1234",
+					ActionID:   "1",
+					Attempt:    1,
+				},
+				event.ActionFailed{
+					WorkflowID: "1234",
+					ActionID:   "1",
+					Reason:     "TestReason",
+					Message:    "test message",
+					Attempt:    1,
+				},
+			},
+		},
+		{
+			Name: "SkippedAction",
+			Workflow: workflow.Workflow{
+				ID: "1234",
+				Actions: []workflow.Action{
+					{
+						ID:    "1",
+						Name:  "action_1",
+						Image: "image_1",
+						When:  `eq .Param.wipe "true"`,
+					},
+					{
+						ID:    "2",
+						Name:  "action_2",
+						Image: "image_2",
+					},
+				},
+				ConditionData: map[string]any{
+					"Param": map[string]any{"wipe": "false"},
+				},
+			},
+			Events: []event.Event{
+				event.ActionSkipped{
+					WorkflowID: "1234",
+					ActionID:   "1",
+				},
+				event.ActionStarted{
+					WorkflowID: "1234",
+					ActionID:   "2",
+					Attempt:    1,
+				},
+				event.ActionSucceeded{
+					WorkflowID: "1234",
+					ActionID:   "2",
+					Attempt:    1,
+				},
+			},
+		},
+		{
+			Name: "InvalidCondition",
+			Workflow: workflow.Workflow{
+				ID: "1234",
+				Actions: []workflow.Action{
+					{
+						ID:    "1",
+						Name:  "action_1",
+						Image: "image_1",
+						When:  `.Param.wipe`,
+					},
+				},
+			},
+			Events: []event.Event{
+				event.ActionFailed{
+					WorkflowID: "1234",
+					ActionID:   "1",
+					Reason:     agent.ReasonInvalidCondition,
+					Message:    `evaluate condition: template: when:1:9: executing "when" at <.Param.wipe>: map has no entry for key "Param"`,
+					Attempt:    1,
+				},
+			},
+		},
+		{
+			Name: "FailureHandlers",
+			Workflow: workflow.Workflow{
+				ID: "1234",
+				Actions: []workflow.Action{
+					{ID: "1", Name: "action_1", Image: "image_1"},
+					{ID: "2", Name: "action_2", Image: "image_2"},
+				},
+				OnFailure: []workflow.Action{
+					{ID: "3", Name: "diagnostics", Image: "image_3"},
+				},
+				Finally: []workflow.Action{
+					{ID: "4", Name: "reset", Image: "image_4"},
+				},
+			},
+			Errors: map[string]ReasonAndMessage{
+				"1": {
+					Reason:  "TestReason",
+					Message: "test message",
+				},
+			},
+			Events: []event.Event{
+				event.ActionStarted{WorkflowID: "1234", ActionID: "1", Attempt: 1},
+				event.ActionFailed{
+					WorkflowID: "1234",
+					ActionID:   "1",
+					Reason:     "TestReason",
+					Message:    "test message",
+					Attempt:    1,
+				},
+				event.ActionStarted{WorkflowID: "1234", ActionID: "3", Attempt: 1},
+				event.ActionSucceeded{WorkflowID: "1234", ActionID: "3", Attempt: 1},
+				event.ActionStarted{WorkflowID: "1234", ActionID: "4", Attempt: 1},
+				event.ActionSucceeded{WorkflowID: "1234", ActionID: "4", Attempt: 1},
+			},
+		},
+		{
+			Name: "FinallyAfterSuccess",
+			Workflow: workflow.Workflow{
+				ID: "1234",
+				Actions: []workflow.Action{
+					{ID: "1", Name: "action_1", Image: "image_1"},
 				},
+				OnFailure: []workflow.Action{
+					{ID: "2", Name: "diagnostics", Image: "image_2"},
+				},
+				Finally: []workflow.Action{
+					{ID: "3", Name: "reset", Image: "image_3"},
+				},
+			},
+			Events: []event.Event{
+				event.ActionStarted{WorkflowID: "1234", ActionID: "1", Attempt: 1},
+				event.ActionSucceeded{WorkflowID: "1234", ActionID: "1", Attempt: 1},
+				event.ActionStarted{WorkflowID: "1234", ActionID: "3", Attempt: 1},
+				event.ActionSucceeded{WorkflowID: "1234", ActionID: "3", Attempt: 1},
 			},
 		},
 	}
@@ -411,12 +695,17 @@ message`,
 		t.Run(tc.Name, func(t *testing.T) {
 			trnport := transport.Noop()
 
+			attempts := map[string]int{}
 			rntime := agent.ContainerRuntimeMock{
-				RunFunc: func(_ context.Context, action workflow.Action) error {
+				RunFunc: func(_ context.Context, action workflow.Action, _, _ io.Writer) (map[string]string, error) {
+					attempts[action.ID]++
 					if res, ok := tc.Errors[action.ID]; ok {
-						return failure.NewReason(res.Message, res.Reason)
+						if limit, ok := tc.FailedAttempts[action.ID]; ok && attempts[action.ID] > limit {
+							return nil, nil
+						}
+						return nil, failure.NewReason(res.Message, res.Reason)
 					}
-					return nil
+					return nil, nil
 				},
 			}
 
@@ -474,3 +763,1244 @@ message`,
 		})
 	}
 }
+
+type closerFunc func() error
+
+func (fn closerFunc) Close() error { return fn() }
+
+func TestAgent_ShipsActionLogs(t *testing.T) {
+	logger := zapr.NewLogger(zap.Must(zap.NewDevelopment()))
+	trnport := transport.Noop()
+
+	rntime := agent.ContainerRuntimeMock{
+		RunFunc: func(_ context.Context, _ workflow.Action, stdout, stderr io.Writer) (map[string]string, error) {
+			_, _ = io.WriteString(stdout, "out")
+			_, _ = io.WriteString(stderr, "err")
+			return nil, failure.NewReason("test message", "TestReason")
+		},
+	}
+
+	// The logs must be flushed before the failure is recorded so the output is available to
+	// consumers of the failure event.
+	var stdout, stderr bytes.Buffer
+	closed := make(chan struct{})
+	logs := agent.LogShipperMock{
+		ShipActionLogsFunc: func(_ context.Context, _, _ string) (io.Writer, io.Writer, io.Closer) {
+			return &stdout, &stderr, closerFunc(func() error {
+				close(closed)
+				return nil
+			})
+		},
+	}
+
+	failed := make(chan bool)
+	recorder := event.RecorderMock{
+		RecordEventFunc: func(_ context.Context, e event.Event) error {
+			if _, ok := e.(event.ActionFailed); ok {
+				select {
+				case <-closed:
+					failed <- true
+				default:
+					failed <- false
+				}
+			}
+			return nil
+		},
+	}
+
+	agnt := agent.Agent{
+		Log:       logger,
+		Transport: &trnport,
+		Runtime:   &rntime,
+		Logs:      &logs,
+		ID:        "1234",
+	}
+	if err := agnt.Start(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	agnt.HandleWorkflow(ctx, workflow.Workflow{
+		ID:      "1234",
+		Actions: []workflow.Action{{ID: "1", Name: "name", Image: "image"}},
+	}, &recorder)
+
+	select {
+	case flushed := <-failed:
+		if !flushed {
+			t.Fatal("Action failure recorded before logs were flushed")
+		}
+	case <-ctx.Done():
+		t.Fatal(ctx.Err())
+	}
+
+	calls := logs.ShipActionLogsCalls()
+	if len(calls) != 1 || calls[0].WorkflowID != "1234" || calls[0].ActionID != "1" {
+		t.Fatalf("Unexpected ShipActionLogs calls: %+v", calls)
+	}
+	if stdout.String() != "out" || stderr.String() != "err" {
+		t.Fatalf("Unexpected output: stdout=%q stderr=%q", stdout.String(), stderr.String())
+	}
+}
+
+func TestAgent_RunsActionGraph(t *testing.T) {
+	logger := zapr.NewLogger(zap.Must(zap.NewDevelopment()))
+	trnport := transport.Noop()
+
+	// Actions 1 and 2 are independent so each waits for the other to start, proving they run
+	// concurrently. Action 3 depends on both.
+	started := map[string]chan struct{}{
+		"1": make(chan struct{}),
+		"2": make(chan struct{}),
+	}
+	rntime := agent.ContainerRuntimeMock{
+		RunFunc: func(ctx context.Context, action workflow.Action, _, _ io.Writer) (map[string]string, error) {
+			switch action.ID {
+			case "1", "2":
+				close(started[action.ID])
+				other := map[string]string{"1": "2", "2": "1"}[action.ID]
+				select {
+				case <-started[other]:
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				}
+			}
+			return nil, nil
+		},
+	}
+
+	done := make(chan struct{})
+	recorder := event.RecorderMock{
+		RecordEventFunc: func(_ context.Context, e event.Event) error {
+			if s, ok := e.(event.ActionSucceeded); ok && s.ActionID == "3" {
+				close(done)
+			}
+			return nil
+		},
+	}
+
+	agnt := agent.Agent{
+		Log:       logger,
+		Transport: &trnport,
+		Runtime:   &rntime,
+		ID:        "1234",
+	}
+	if err := agnt.Start(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	agnt.HandleWorkflow(ctx, workflow.Workflow{
+		ID: "1234",
+		Actions: []workflow.Action{
+			{ID: "3", Name: "action_3", DependsOn: []string{"1", "2"}},
+			{ID: "1", Name: "action_1"},
+			{ID: "2", Name: "action_2"},
+		},
+	}, &recorder)
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		t.Fatal(ctx.Err())
+	}
+
+	// Action 3 must only start once both of its dependencies have succeeded.
+	succeeded := map[string]bool{}
+	for _, call := range recorder.RecordEventCalls() {
+		switch e := call.Event.(type) {
+		case event.ActionSucceeded:
+			succeeded[e.ActionID] = true
+		case event.ActionStarted:
+			if e.ActionID == "3" && (!succeeded["1"] || !succeeded["2"]) {
+				t.Fatalf("Action 3 started before its dependencies succeeded: %v", succeeded)
+			}
+		}
+	}
+}
+
+func TestAgent_PausesAtBreakpoint(t *testing.T) {
+	logger := zapr.NewLogger(zap.Must(zap.NewDevelopment()))
+	trnport := transport.Noop()
+	rntime := agent.ContainerRuntimeMock{
+		RunFunc: func(context.Context, workflow.Action, io.Writer, io.Writer) (map[string]string, error) {
+			return nil, nil
+		},
+	}
+
+	paused := make(chan struct{})
+	done := make(chan struct{})
+	recorder := event.RecorderMock{
+		RecordEventFunc: func(_ context.Context, e event.Event) error {
+			switch e := e.(type) {
+			case event.ActionPaused:
+				close(paused)
+			case event.ActionSucceeded:
+				if e.ActionID == "2" {
+					close(done)
+				}
+			}
+			return nil
+		},
+	}
+
+	agnt := agent.Agent{
+		Log:       logger,
+		Transport: &trnport,
+		Runtime:   &rntime,
+		ID:        "1234",
+	}
+	if err := agnt.Start(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	agnt.HandleWorkflow(ctx, workflow.Workflow{
+		ID: "1234",
+		Actions: []workflow.Action{
+			{ID: "1", Name: "action_1"},
+			{ID: "2", Name: "action_2"},
+		},
+		Pause: workflow.Pause{Breakpoints: []string{"2"}},
+	}, &recorder)
+
+	select {
+	case <-paused:
+	case <-ctx.Done():
+		t.Fatal(ctx.Err())
+	}
+
+	// Only the action before the breakpoint should have run.
+	if calls := rntime.RunCalls(); len(calls) != 1 || calls[0].Action.ID != "1" {
+		t.Fatalf("Expected only action 1 to run; received %v calls", len(calls))
+	}
+
+	agnt.PauseWorkflow("1234", workflow.Pause{})
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		t.Fatal(ctx.Err())
+	}
+
+	want := []event.Event{
+		event.ActionStarted{ActionID: "1", WorkflowID: "1234", Attempt: 1},
+		event.ActionSucceeded{ActionID: "1", WorkflowID: "1234", Attempt: 1},
+		event.ActionPaused{ActionID: "2", WorkflowID: "1234"},
+		event.ActionStarted{ActionID: "2", WorkflowID: "1234", Attempt: 1},
+		event.ActionSucceeded{ActionID: "2", WorkflowID: "1234", Attempt: 1},
+	}
+	var got []event.Event
+	for _, call := range recorder.RecordEventCalls() {
+		got = append(got, call.Event)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestAgent_PauseDoesNotCountTowardsTimeout(t *testing.T) {
+	logger := zapr.NewLogger(zap.Must(zap.NewDevelopment()))
+	trnport := transport.Noop()
+	rntime := agent.ContainerRuntimeMock{
+		RunFunc: func(ctx context.Context, _ workflow.Action, _, _ io.Writer) (map[string]string, error) {
+			return nil, ctx.Err()
+		},
+	}
+
+	paused := make(chan struct{})
+	finished := make(chan event.Event, 1)
+	recorder := event.RecorderMock{
+		RecordEventFunc: func(_ context.Context, e event.Event) error {
+			switch e.(type) {
+			case event.ActionPaused:
+				close(paused)
+			case event.ActionSucceeded, event.ActionFailed:
+				finished <- e
+			}
+			return nil
+		},
+	}
+
+	agnt := agent.Agent{
+		Log:       logger,
+		Transport: &trnport,
+		Runtime:   &rntime,
+		ID:        "1234",
+	}
+	if err := agnt.Start(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	timeout := 50 * time.Millisecond
+	agnt.HandleWorkflow(ctx, workflow.Workflow{
+		ID:      "1234",
+		Actions: []workflow.Action{{ID: "1", Name: "action", Timeout: timeout}},
+		Pause:   workflow.Pause{Breakpoints: []string{"1"}},
+	}, &recorder)
+
+	select {
+	case <-paused:
+	case <-ctx.Done():
+		t.Fatal(ctx.Err())
+	}
+
+	// Hold the action at the breakpoint for longer than its timeout.
+	time.Sleep(3 * timeout)
+	agnt.PauseWorkflow("1234", workflow.Pause{})
+
+	select {
+	case e := <-finished:
+		want := event.ActionSucceeded{ActionID: "1", WorkflowID: "1234", Attempt: 1}
+		if diff := cmp.Diff(want, e); diff != "" {
+			t.Fatal(diff)
+		}
+	case <-ctx.Done():
+		t.Fatal(ctx.Err())
+	}
+}
+
+func TestAgent_ActionTimeout(t *testing.T) {
+	logger := zapr.NewLogger(zap.Must(zap.NewDevelopment()))
+	trnport := transport.Noop()
+
+	rntime := agent.ContainerRuntimeMock{
+		RunFunc: func(ctx context.Context, _ workflow.Action, _, _ io.Writer) (map[string]string, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	}
+
+	failed := make(chan event.ActionFailed, 1)
+	recorder := event.RecorderMock{
+		RecordEventFunc: func(_ context.Context, e event.Event) error {
+			if f, ok := e.(event.ActionFailed); ok {
+				failed <- f
+			}
+			return nil
+		},
+	}
+
+	agnt := agent.Agent{
+		Log:       logger,
+		Transport: &trnport,
+		Runtime:   &rntime,
+		ID:        "1234",
+	}
+	if err := agnt.Start(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	agnt.HandleWorkflow(ctx, workflow.Workflow{
+		ID: "1234",
+		Actions: []workflow.Action{
+			{
+				ID:      "1",
+				Name:    "action_1",
+				Timeout: 10 * time.Millisecond,
+				// The action would time out before the retry starts so it shouldn't be retried.
+				Retry: workflow.RetryPolicy{Retries: 1, Backoff: time.Minute},
+			},
+		},
+	}, &recorder)
+
+	select {
+	case f := <-failed:
+		if f.Reason != agent.ReasonTimeout || f.WillRetry {
+			t.Fatalf("Unexpected failure: %+v", f)
+		}
+	case <-ctx.Done():
+		t.Fatal(ctx.Err())
+	}
+}
+
+func TestAgent_PassesActionOutputs(t *testing.T) {
+	logger := zapr.NewLogger(zap.Must(zap.NewDevelopment()))
+	trnport := transport.Noop()
+
+	env := make(chan map[string]string, 1)
+	rntime := agent.ContainerRuntimeMock{
+		RunFunc: func(_ context.Context, action workflow.Action, _, _ io.Writer) (map[string]string, error) {
+			if action.ID == "1" {
+				return map[string]string{"disk": "/dev/sda"}, nil
+			}
+			env <- action.Env
+			return nil, nil
+		},
+	}
+
+	done := make(chan struct{})
+	recorder := event.RecorderMock{
+		RecordEventFunc: func(_ context.Context, e event.Event) error {
+			if s, ok := e.(event.ActionSucceeded); ok && s.ActionID == "2" {
+				close(done)
+			}
+			return nil
+		},
+	}
+
+	agnt := agent.Agent{
+		Log:       logger,
+		Transport: &trnport,
+		Runtime:   &rntime,
+		ID:        "1234",
+	}
+	if err := agnt.Start(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	agnt.HandleWorkflow(ctx, workflow.Workflow{
+		ID: "1234",
+		Actions: []workflow.Action{
+			{ID: "1", Name: "find-disk"},
+			{
+				ID:   "2",
+				Name: "wipe",
+				Env:  map[string]string{"FORCE": "true"},
+				When: `eq (index .Outputs "find-disk" "disk") "/dev/sda"`,
+			},
+		},
+	}, &recorder)
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		t.Fatal(ctx.Err())
+	}
+
+	expect := map[string]string{
+		"FORCE":                            "true",
+		"TINKERBELL_OUTPUT_FIND_DISK_DISK": "/dev/sda",
+	}
+	if diff := cmp.Diff(expect, <-env); diff != "" {
+		t.Fatalf("Unexpected action environment:\n%v", diff)
+	}
+
+	expectEvent := event.ActionSucceeded{
+		WorkflowID: "1234",
+		ActionID:   "1",
+		Attempt:    1,
+		Outputs:    map[string]string{"disk": "/dev/sda"},
+	}
+	for _, call := range recorder.RecordEventCalls() {
+		if s, ok := call.Event.(event.ActionSucceeded); ok && s.ActionID == "1" {
+			if diff := cmp.Diff(expectEvent, s); diff != "" {
+				t.Fatalf("Unexpected event:\n%v", diff)
+			}
+		}
+	}
+}
+
+func TestAgent_PullsImages(t *testing.T) {
+	logger := zapr.NewLogger(zap.Must(zap.NewDevelopment()))
+	trnport := transport.Noop()
+
+	var running, maxRunning atomic.Int32
+	rntime := pullingRuntime{
+		ContainerRuntimeMock: agent.ContainerRuntimeMock{
+			RunFunc: func(context.Context, workflow.Action, io.Writer, io.Writer) (map[string]string, error) {
+				return nil, nil
+			},
+		},
+		PullImageFunc: func(context.Context, string) error {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				m := maxRunning.Load()
+				if n <= m || maxRunning.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			return nil
+		},
+	}
+
+	done := make(chan struct{})
+	recorder := event.RecorderMock{
+		RecordEventFunc: func(_ context.Context, e event.Event) error {
+			if s, ok := e.(event.ActionSucceeded); ok && s.ActionID == "finally" {
+				close(done)
+			}
+			return nil
+		},
+	}
+
+	agnt := agent.Agent{
+		Log:             logger,
+		Transport:       &trnport,
+		Runtime:         &rntime,
+		ID:              "1234",
+		PullConcurrency: 2,
+	}
+	if err := agnt.Start(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	agnt.HandleWorkflow(ctx, workflow.Workflow{
+		ID: "1234",
+		Actions: []workflow.Action{
+			{ID: "1", Name: "action_1", Image: "image_1"},
+			{ID: "2", Name: "action_2", Image: "image_2"},
+			{ID: "3", Name: "action_3", Image: "image_1"},
+			{ID: "4", Name: "action_4", Image: "image_3"},
+		},
+		Finally: []workflow.Action{{ID: "finally", Name: "finally", Image: "image_4"}},
+	}, &recorder)
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		t.Fatal(ctx.Err())
+	}
+
+	var images []string
+	for _, call := range rntime.PullImageCalls() {
+		images = append(images, call.Image)
+	}
+	slices.Sort(images)
+	if diff := cmp.Diff([]string{"image_1", "image_2", "image_3", "image_4"}, images); diff != "" {
+		t.Fatalf("Unexpected images pulled:\n%v", diff)
+	}
+	if m := maxRunning.Load(); m > 2 {
+		t.Fatalf("Expected at most 2 concurrent pulls; received %v", m)
+	}
+
+	// Progress is reported for each image before any action starts.
+	var pulled []int
+	for i, call := range recorder.RecordEventCalls() {
+		switch e := call.Event.(type) {
+		case event.ImagePulled:
+			if e.Total != 4 {
+				t.Fatalf("Expected 4 images in total; received %v", e.Total)
+			}
+			pulled = append(pulled, e.Pulled)
+		case event.ActionStarted:
+			if i < 4 {
+				t.Fatalf("Action started before images were pulled: %v", e)
+			}
+		}
+	}
+	if diff := cmp.Diff([]int{1, 2, 3, 4}, pulled); diff != "" {
+		t.Fatalf("Unexpected pull progress:\n%v", diff)
+	}
+}
+
+func TestAgent_ImagePullFailure(t *testing.T) {
+	logger := zapr.NewLogger(zap.Must(zap.NewDevelopment()))
+	trnport := transport.Noop()
+
+	rntime := pullingRuntime{
+		ContainerRuntimeMock: agent.ContainerRuntimeMock{
+			RunFunc: func(context.Context, workflow.Action, io.Writer, io.Writer) (map[string]string, error) {
+				return nil, nil
+			},
+		},
+		PullImageFunc: func(ctx context.Context, image string) error {
+			if image == "missing" {
+				return errors.New("manifest unknown")
+			}
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}
+
+	done := make(chan struct{})
+	recorder := event.RecorderMock{
+		RecordEventFunc: func(_ context.Context, e event.Event) error {
+			if _, ok := e.(event.ImagePullFailed); ok {
+				close(done)
+			}
+			return nil
+		},
+	}
+
+	agnt := agent.Agent{
+		Log:       logger,
+		Transport: &trnport,
+		Runtime:   &rntime,
+		ID:        "1234",
+	}
+	if err := agnt.Start(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// The pull of the first image blocks until the failure of the second image cancels it.
+	agnt.HandleWorkflow(ctx, workflow.Workflow{
+		ID: "1234",
+		Actions: []workflow.Action{
+			{ID: "1", Name: "action_1", Image: "slow"},
+			{ID: "2", Name: "action_2", Image: "missing"},
+		},
+		Finally: []workflow.Action{{ID: "finally", Name: "finally", Image: "slow"}},
+	}, &recorder)
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		t.Fatal(ctx.Err())
+	}
+
+	want := []event.Event{
+		event.ImagePullFailed{WorkflowID: "1234", Image: "missing", Message: "manifest unknown"},
+	}
+	var got []event.Event
+	for _, call := range recorder.RecordEventCalls() {
+		got = append(got, call.Event)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatal(diff)
+	}
+	if calls := rntime.RunCalls(); len(calls) != 0 {
+		t.Fatalf("Expected no actions to run; received %v", len(calls))
+	}
+}
+
+func TestAgent_VerifiesImages(t *testing.T) {
+	logger := zapr.NewLogger(zap.Must(zap.NewDevelopment()))
+	trnport := transport.Noop()
+
+	rntime := pullingRuntime{
+		ContainerRuntimeMock: agent.ContainerRuntimeMock{
+			RunFunc: func(context.Context, workflow.Action, io.Writer, io.Writer) (map[string]string, error) {
+				return nil, nil
+			},
+		},
+		PullImageFunc: func(context.Context, string) error { return nil },
+	}
+
+	done := make(chan struct{})
+	recorder := event.RecorderMock{
+		RecordEventFunc: func(_ context.Context, e event.Event) error {
+			if _, ok := e.(event.ActionFailed); ok {
+				close(done)
+			}
+			return nil
+		},
+	}
+
+	agnt := agent.Agent{
+		Log:       logger,
+		Transport: &trnport,
+		Runtime:   &rntime,
+		ID:        "1234",
+		Verifier: imageVerifierFunc(func(_ context.Context, image string) (string, error) {
+			if image == "unsigned" {
+				return "", errors.New("no valid signature")
+			}
+			return image + "@sha256:digest", nil
+		}),
+	}
+	if err := agnt.Start(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	agnt.HandleWorkflow(ctx, workflow.Workflow{
+		ID: "1234",
+		Actions: []workflow.Action{
+			{ID: "1", Name: "signed", Image: "signed"},
+			{ID: "2", Name: "unsigned", Image: "unsigned"},
+		},
+	}, &recorder)
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		t.Fatal(ctx.Err())
+	}
+
+	// Only the verified image runs and it runs pinned to the verified digest.
+	calls := rntime.RunCalls()
+	if len(calls) != 1 {
+		t.Fatalf("Expected 1 action to run; received %v", len(calls))
+	}
+	if calls[0].Action.Image != "signed@sha256:digest" {
+		t.Fatalf("Expected pinned image; received %v", calls[0].Action.Image)
+	}
+
+	// Images are pulled once, by the verified digest, and unverified images aren't pulled.
+	if diff := cmp.Diff([]struct{ Image string }{{"signed@sha256:digest"}}, rntime.PullImageCalls()); diff != "" {
+		t.Fatalf("Unexpected images pulled:\n%v", diff)
+	}
+
+	expect := event.ActionFailed{
+		ActionID:   "2",
+		WorkflowID: "1234",
+		Reason:     agent.ReasonImageVerificationFailed,
+		Message:    "no valid signature",
+		Attempt:    1,
+	}
+	for _, call := range recorder.RecordEventCalls() {
+		if f, ok := call.Event.(event.ActionFailed); ok {
+			if diff := cmp.Diff(expect, f); diff != "" {
+				t.Fatalf("Unexpected event:\n%v", diff)
+			}
+		}
+	}
+}
+
+// imageVerifierFunc is a func that satisfies agent.ImageVerifier.
+type imageVerifierFunc func(context.Context, string) (string, error)
+
+func (fn imageVerifierFunc) VerifyImage(ctx context.Context, image string) (string, error) {
+	return fn(ctx, image)
+}
+
+// pullingRuntime is a ContainerRuntime that implements agent.ImagePuller.
+type pullingRuntime struct {
+	agent.ContainerRuntimeMock
+
+	PullImageFunc func(ctx context.Context, image string) error
+
+	mtx   sync.Mutex
+	calls []struct{ Image string }
+}
+
+func (r *pullingRuntime) PullImage(ctx context.Context, image string) error {
+	r.mtx.Lock()
+	r.calls = append(r.calls, struct{ Image string }{image})
+	r.mtx.Unlock()
+	return r.PullImageFunc(ctx, image)
+}
+
+func (r *pullingRuntime) PullImageCalls() []struct{ Image string } {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return slices.Clone(r.calls)
+}
+
+// recordingTransport is a transport that records events with Recorder.
+type recordingTransport struct {
+	transport.Fake
+	Recorder *event.RecorderMock
+}
+
+func (t recordingTransport) RecordEvent(ctx context.Context, e event.Event) error {
+	return t.Recorder.RecordEvent(ctx, e)
+}
+
+func TestAgent_RecoversInterruptedWorkflow(t *testing.T) {
+	cases := []struct {
+		Name       string
+		Idempotent bool
+
+		// Ran are the IDs of the actions expected to run.
+		Ran []string
+
+		// Expect are the events expected to be recorded after the unsent event.
+		Expect []event.Event
+	}{
+		{
+			Name: "NotIdempotent",
+			Ran:  []string{"finally"},
+			Expect: []event.Event{
+				event.ActionFailed{
+					ActionID:   "interrupted",
+					WorkflowID: "workflow",
+					Reason:     agent.ReasonAgentRestarted,
+					Message:    "the agent restarted while the action was running",
+					Attempt:    1,
+				},
+				event.ActionStarted{ActionID: "finally", WorkflowID: "workflow", Attempt: 1},
+				event.ActionSucceeded{ActionID: "finally", WorkflowID: "workflow", Attempt: 1},
+			},
+		},
+		{
+			Name:       "Idempotent",
+			Idempotent: true,
+			Ran:        []string{"interrupted", "next", "finally"},
+			Expect: []event.Event{
+				event.ActionStarted{ActionID: "interrupted", WorkflowID: "workflow", Attempt: 2},
+				event.ActionSucceeded{ActionID: "interrupted", WorkflowID: "workflow", Attempt: 2},
+				event.ActionStarted{ActionID: "next", WorkflowID: "workflow", Attempt: 1},
+				event.ActionSucceeded{ActionID: "next", WorkflowID: "workflow", Attempt: 1},
+				event.ActionStarted{ActionID: "finally", WorkflowID: "workflow", Attempt: 1},
+				event.ActionSucceeded{ActionID: "finally", WorkflowID: "workflow", Attempt: 1},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			logger := zapr.NewLogger(zap.Must(zap.NewDevelopment()))
+
+			wflw := workflow.Workflow{
+				ID: "workflow",
+				Actions: []workflow.Action{
+					{ID: "done", Name: "done", Image: "image"},
+					{ID: "interrupted", Name: "interrupted", Image: "image", DependsOn: []string{"done"}, Idempotent: tc.Idempotent},
+					{ID: "next", Name: "next", Image: "image", DependsOn: []string{"interrupted"}},
+				},
+				Finally: []workflow.Action{
+					{ID: "finally", Name: "finally", Image: "image"},
+				},
+			}
+			unsent := event.ActionStarted{ActionID: "interrupted", WorkflowID: "workflow", Attempt: 1}
+
+			store := state.NewFile(filepath.Join(t.TempDir(), "state.json"))
+			err := store.Save(state.State{
+				Workflow: &wflw,
+				Events: []state.Record{
+					{Event: event.ActionStarted{ActionID: "done", WorkflowID: "workflow", Attempt: 1}, Sent: true},
+					{Event: event.ActionSucceeded{ActionID: "done", WorkflowID: "workflow", Attempt: 1, Outputs: map[string]string{"key": "value"}}, Sent: true},
+					{Event: unsent},
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			done := make(chan struct{})
+			recorder := event.RecorderMock{
+				RecordEventFunc: func(_ context.Context, e event.Event) error {
+					if s, ok := e.(event.ActionSucceeded); ok && s.ActionID == "finally" {
+						close(done)
+					}
+					return nil
+				},
+			}
+
+			rntime := agent.ContainerRuntimeMock{
+				RunFunc: func(context.Context, workflow.Action, io.Writer, io.Writer) (map[string]string, error) {
+					return nil, nil
+				},
+			}
+
+			agnt := agent.Agent{
+				Log:       logger,
+				Transport: recordingTransport{Fake: transport.Noop(), Recorder: &recorder},
+				Runtime:   &rntime,
+				ID:        "1234",
+				State:     store,
+			}
+			if err := agnt.Start(context.Background()); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			select {
+			case <-done:
+			case <-ctx.Done():
+				t.Fatal(ctx.Err())
+			}
+
+			var events []event.Event
+			for _, call := range recorder.RecordEventCalls() {
+				events = append(events, call.Event)
+			}
+			if diff := cmp.Diff(append([]event.Event{unsent}, tc.Expect...), events); diff != "" {
+				t.Fatalf("Unexpected events:\n%v", diff)
+			}
+
+			var ran []string
+			for _, call := range rntime.RunCalls() {
+				ran = append(ran, call.Action.ID)
+				// Outputs of actions that finished before the restart are still passed on.
+				if call.Action.ID == "next" && call.Action.Env["TINKERBELL_OUTPUT_DONE_KEY"] != "value" {
+					t.Fatalf("Expected outputs of finished action; received env %v", call.Action.Env)
+				}
+			}
+			if diff := cmp.Diff(tc.Ran, ran); diff != "" {
+				t.Fatalf("Unexpected actions run:\n%v", diff)
+			}
+
+			// The workflow is cleared from the state once it finishes.
+			for {
+				st, err := store.Load()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if st.Workflow == nil {
+					if unsent := st.Unsent(); len(unsent) != 0 {
+						t.Fatalf("Expected all events to be sent; received %v", unsent)
+					}
+					break
+				}
+				select {
+				case <-ctx.Done():
+					t.Fatal("Workflow wasn't cleared from state")
+				case <-time.After(10 * time.Millisecond):
+				}
+			}
+		})
+	}
+}
+
+func TestAgent_StateRequiresRecordingTransport(t *testing.T) {
+	agnt := agent.Agent{
+		Transport: &agent.TransportMock{},
+		Runtime:   &agent.ContainerRuntimeMock{},
+		ID:        "1234",
+		State:     state.NewFile(filepath.Join(t.TempDir(), "state.json")),
+	}
+	if err := agnt.Start(context.Background()); err == nil {
+		t.Fatal("Expected error")
+	}
+}
+
+// ackingTransport is a transport that holds recorded events until they're acknowledged with Ack.
+type ackingTransport struct {
+	transport.Fake
+
+	mtx        sync.Mutex
+	publishing bool
+	acks       []func()
+	early      []event.Event
+	recorded   []event.Event
+}
+
+func (t *ackingTransport) StartPublishing(context.Context) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.publishing = true
+}
+
+func (t *ackingTransport) RecordEvent(ctx context.Context, e event.Event) error {
+	return t.RecordEventWithAck(ctx, e, func() {})
+}
+
+func (t *ackingTransport) RecordEventWithAck(_ context.Context, e event.Event, ack func()) error {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if !t.publishing {
+		t.early = append(t.early, e)
+	}
+	t.recorded = append(t.recorded, e)
+	t.acks = append(t.acks, ack)
+	return nil
+}
+
+// Recorded returns the events recorded so far.
+func (t *ackingTransport) Recorded() []event.Event {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	return slices.Clone(t.recorded)
+}
+
+// Ack acknowledges the events recorded so far.
+func (t *ackingTransport) Ack() {
+	t.mtx.Lock()
+	acks := t.acks
+	t.acks = nil
+	t.mtx.Unlock()
+	for _, ack := range acks {
+		ack()
+	}
+}
+
+func TestAgent_RecoveryMarksEventsSentWhenAcknowledged(t *testing.T) {
+	store := state.NewFile(filepath.Join(t.TempDir(), "state.json"))
+	unsent := event.ActionSucceeded{ActionID: "action", WorkflowID: "workflow", Attempt: 1}
+	if err := store.Save(state.State{Events: []state.Record{{Event: unsent}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	trnsport := &ackingTransport{Fake: transport.Noop()}
+	agnt := agent.Agent{
+		Transport: trnsport,
+		Runtime:   &agent.ContainerRuntimeMock{},
+		ID:        "1234",
+		State:     store,
+	}
+	if err := agnt.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(trnsport.early) != 0 {
+		t.Fatalf("Events recorded before publishing started: %v", trnsport.early)
+	}
+
+	// Recorded events are retained until they're acknowledged.
+	st, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]state.Record{{Event: unsent}}, st.Unsent()); diff != "" {
+		t.Fatalf("Unexpected unsent events:\n%v", diff)
+	}
+
+	trnsport.Ack()
+	st, err = store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unsent := st.Unsent(); len(unsent) != 0 {
+		t.Fatalf("Expected acknowledged events to be sent; received %v", unsent)
+	}
+}
+
+// rebooterMock records the reboot and kexec requests it receives.
+type rebooterMock struct {
+	Err error
+
+	mtx   sync.Mutex
+	calls []workflow.Kexec
+}
+
+func (r *rebooterMock) Reboot(ctx context.Context) error {
+	return r.Kexec(ctx, workflow.Kexec{})
+}
+
+func (r *rebooterMock) Kexec(_ context.Context, k workflow.Kexec) error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.calls = append(r.calls, k)
+	return r.Err
+}
+
+func (r *rebooterMock) Calls() []workflow.Kexec {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return slices.Clone(r.calls)
+}
+
+func TestAgent_Reboots(t *testing.T) {
+	kexec := workflow.Kexec{BlockDevice: "/dev/sda1", FSType: "ext4", KernelPath: "/boot/vmlinuz", Cmdline: "console=ttyS0"}
+
+	cases := []struct {
+		Name   string
+		Action workflow.Action
+		Err    error
+		Kexec  workflow.Kexec
+		Expect event.Event
+	}{
+		{
+			Name:   "Reboot",
+			Action: workflow.Action{ID: "2", Name: "reboot", Type: workflow.ActionTypeReboot},
+			Expect: event.ActionStarted{ActionID: "2", WorkflowID: "1234", Attempt: 1},
+		},
+		{
+			Name:   "Kexec",
+			Action: workflow.Action{ID: "2", Name: "kexec", Type: workflow.ActionTypeKexec, Kexec: kexec},
+			Kexec:  kexec,
+			Expect: event.ActionStarted{ActionID: "2", WorkflowID: "1234", Attempt: 1},
+		},
+		{
+			Name:   "Failure",
+			Action: workflow.Action{ID: "2", Name: "reboot", Type: workflow.ActionTypeReboot},
+			Err:    errors.New("operation not permitted"),
+			Expect: event.ActionFailed{
+				ActionID:   "2",
+				WorkflowID: "1234",
+				Reason:     agent.ReasonRebootFailed,
+				Message:    "operation not permitted",
+				Attempt:    1,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			logger := zapr.NewLogger(zap.Must(zap.NewDevelopment()))
+			trnport := transport.Noop()
+
+			rntime := agent.ContainerRuntimeMock{
+				RunFunc: func(context.Context, workflow.Action, io.Writer, io.Writer) (map[string]string, error) {
+					return nil, nil
+				},
+			}
+
+			// The last event recorded for the reboot action signals the agent has finished with it.
+			done := make(chan struct{})
+			recorder := event.RecorderMock{
+				RecordEventFunc: func(_ context.Context, e event.Event) error {
+					if cmp.Equal(tc.Expect, e) {
+						close(done)
+					}
+					return nil
+				},
+			}
+
+			rebooter := rebooterMock{Err: tc.Err}
+			agnt := agent.Agent{
+				Log:       logger,
+				Transport: &trnport,
+				Runtime:   &rntime,
+				ID:        "1234",
+				Rebooter:  &rebooter,
+			}
+			if err := agnt.Start(context.Background()); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			agnt.HandleWorkflow(ctx, workflow.Workflow{
+				ID: "1234",
+				Actions: []workflow.Action{
+					{ID: "1", Name: "install", Image: "image"},
+					tc.Action,
+					{ID: "3", Name: "configure", Image: "image"},
+				},
+			}, &recorder)
+
+			select {
+			case <-done:
+			case <-ctx.Done():
+				t.Fatal(ctx.Err())
+			}
+
+			// The workflow is held after the machine starts restarting so no further action runs.
+			agnt.CancelWorkflow("1234")
+
+			if diff := cmp.Diff([]workflow.Kexec{tc.Kexec}, rebooter.Calls()); diff != "" {
+				t.Fatalf("Unexpected restarts:\n%v", diff)
+			}
+			calls := rntime.RunCalls()
+			if len(calls) != 1 || calls[0].Action.ID != "1" {
+				t.Fatalf("Expected only the first action to run; received %v", calls)
+			}
+		})
+	}
+}
+
+func TestAgent_RebootWaitsForEventDelivery(t *testing.T) {
+	logger := zapr.NewLogger(zap.Must(zap.NewDevelopment()))
+	trnsport := &ackingTransport{Fake: transport.Noop()}
+	rntime := agent.ContainerRuntimeMock{
+		RunFunc: func(context.Context, workflow.Action, io.Writer, io.Writer) (map[string]string, error) {
+			return nil, nil
+		},
+	}
+	rebooter := rebooterMock{}
+	agnt := agent.Agent{
+		Log:       logger,
+		Transport: trnsport,
+		Runtime:   &rntime,
+		ID:        "1234",
+		Rebooter:  &rebooter,
+	}
+	if err := agnt.Start(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	agnt.HandleWorkflow(ctx, workflow.Workflow{
+		ID: "1234",
+		Actions: []workflow.Action{
+			{ID: "1", Name: "install", Image: "image"},
+			{ID: "2", Name: "reboot", Type: workflow.ActionTypeReboot},
+		},
+	}, trnsport)
+	defer agnt.CancelWorkflow("1234")
+
+	wait := func(cond func() bool) {
+		t.Helper()
+		for !cond() {
+			select {
+			case <-ctx.Done():
+				t.Fatal(ctx.Err())
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+	}
+
+	// The machine isn't restarted while the events recorded before it are being published.
+	started := event.ActionStarted{ActionID: "2", WorkflowID: "1234", Attempt: 1}
+	wait(func() bool {
+		return slices.ContainsFunc(trnsport.Recorded(), func(e event.Event) bool { return cmp.Equal(started, e) })
+	})
+	time.Sleep(50 * time.Millisecond)
+	if calls := rebooter.Calls(); len(calls) != 0 {
+		t.Fatalf("Machine restarted before events were delivered: %v", calls)
+	}
+
+	trnsport.Ack()
+	wait(func() bool { return len(rebooter.Calls()) == 1 })
+}
+
+func TestAgent_SkipsActionsWithResults(t *testing.T) {
+	logger := zapr.NewLogger(zap.Must(zap.NewDevelopment()))
+	trnport := transport.Noop()
+
+	rntime := agent.ContainerRuntimeMock{
+		RunFunc: func(context.Context, workflow.Action, io.Writer, io.Writer) (map[string]string, error) {
+			return nil, nil
+		},
+	}
+
+	done := make(chan struct{})
+	recorder := event.RecorderMock{
+		RecordEventFunc: func(_ context.Context, e event.Event) error {
+			if s, ok := e.(event.ActionSucceeded); ok && s.ActionID == "3" {
+				close(done)
+			}
+			return nil
+		},
+	}
+
+	agnt := agent.Agent{
+		Log:       logger,
+		Transport: &trnport,
+		Runtime:   &rntime,
+		ID:        "1234",
+	}
+	if err := agnt.Start(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// The workflow is dispatched again after the reboot action restarted the machine.
+	agnt.HandleWorkflow(ctx, workflow.Workflow{
+		ID: "1234",
+		Actions: []workflow.Action{
+			{ID: "1", Name: "install", Image: "image"},
+			{ID: "2", Name: "reboot", Type: workflow.ActionTypeReboot},
+			{ID: "3", Name: "configure", Image: "image"},
+		},
+		Results: map[string]workflow.Result{
+			"1": {Succeeded: true, Outputs: map[string]string{"disk": "/dev/sda"}},
+			"2": {Succeeded: true},
+		},
+	}, &recorder)
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		t.Fatal(ctx.Err())
+	}
+
+	calls := rntime.RunCalls()
+	if len(calls) != 1 || calls[0].Action.ID != "3" {
+		t.Fatalf("Expected only the action without a result to run; received %v", calls)
+	}
+	if disk := calls[0].Action.Env["TINKERBELL_OUTPUT_INSTALL_DISK"]; disk != "/dev/sda" {
+		t.Fatalf("Expected outputs of finished action; received %q", disk)
+	}
+	for _, call := range recorder.RecordEventCalls() {
+		if s, ok := call.Event.(event.ActionStarted); ok && s.ActionID != "3" {
+			t.Fatalf("Unexpected event for finished action: %v", s)
+		}
+	}
+}
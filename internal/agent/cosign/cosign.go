@@ -0,0 +1,258 @@
+// Package cosign verifies action images are signed by cosign using a set of trusted public keys.
+//
+// Only key-based signatures stored alongside the image as the '<digest>.sig' tag are supported.
+// Keyless signatures, which rely on transparency logs and certificate authorities, and notation
+// signatures are not supported.
+package cosign
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/remotes"
+	"github.com/distribution/reference"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/tinkerbell/tink/internal/agent"
+	"github.com/tinkerbell/tink/internal/registry"
+)
+
+// SignatureAnnotation is the layer annotation containing the base64 encoded signature of the
+// layer's payload.
+const SignatureAnnotation = "dev.cosignproject.cosign/signature"
+
+// maxContentSize is the maximum size of signature manifests and payloads.
+const maxContentSize = 4 << 20
+
+var _ agent.ImageVerifier = &Verifier{}
+
+// Verifier verifies images are signed by any of a set of public keys. It satisfies
+// agent.ImageVerifier.
+type Verifier struct {
+	keys     []crypto.PublicKey
+	registry *registry.Registry
+}
+
+// NewVerifier creates a Verifier trusting signatures made by keys.
+func NewVerifier(keys []crypto.PublicKey, opts ...Option) *Verifier {
+	v := &Verifier{
+		keys:     keys,
+		registry: registry.New(),
+	}
+
+	for _, fn := range opts {
+		fn(v)
+	}
+
+	return v
+}
+
+// Option defines optional configuration for a Verifier instance.
+type Option func(*Verifier)
+
+// WithRegistry returns an option to configure the credentials and mirrors used to retrieve images
+// and their signatures.
+func WithRegistry(r *registry.Registry) Option {
+	return func(v *Verifier) {
+		if r == nil {
+			return
+		}
+		v.registry = r
+	}
+}
+
+// payload is the subset of the cosign simple signing payload used for verification.
+type payload struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+	} `json:"critical"`
+}
+
+// VerifyImage satisfies agent.ImageVerifier. It resolves image to a digest and verifies a
+// signature made by a trusted key exists for the digest. It returns image pinned to the digest.
+func (v *Verifier) VerifyImage(ctx context.Context, image string) (string, error) {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return "", fmt.Errorf("parse image reference: %w", err)
+	}
+
+	ref, err := v.registry.Resolve(image)
+	if err != nil {
+		return "", err
+	}
+	resolved, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return "", fmt.Errorf("parse image reference: %w", err)
+	}
+
+	resolver := v.registry.Resolver()
+	_, desc, err := resolver.Resolve(ctx, reference.TagNameOnly(resolved).String())
+	if err != nil {
+		return "", fmt.Errorf("resolve image: %w", err)
+	}
+
+	if err := v.verifyDigest(ctx, resolver, reference.TrimNamed(resolved), desc.Digest); err != nil {
+		return "", fmt.Errorf("verify image %v: %w", image, err)
+	}
+
+	if _, ok := named.(reference.Digested); ok {
+		return image, nil
+	}
+	pinned, err := reference.WithDigest(reference.TagNameOnly(named), desc.Digest)
+	if err != nil {
+		return "", fmt.Errorf("pin image: %w", err)
+	}
+	return reference.FamiliarString(pinned), nil
+}
+
+// verifyDigest verifies the signature manifest of dgst in repo contains a payload for dgst
+// signed by a trusted key.
+func (v *Verifier) verifyDigest(ctx context.Context, resolver remotes.Resolver, repo reference.Named, dgst digest.Digest) error {
+	sigRef := fmt.Sprintf("%v:%v-%v.sig", repo, dgst.Algorithm(), dgst.Encoded())
+	name, desc, err := resolver.Resolve(ctx, sigRef)
+	if errdefs.IsNotFound(err) {
+		return errors.New("no signatures found")
+	}
+	if err != nil {
+		return fmt.Errorf("resolve signatures: %w", err)
+	}
+
+	fetcher, err := resolver.Fetcher(ctx, name)
+	if err != nil {
+		return fmt.Errorf("fetch signatures: %w", err)
+	}
+
+	data, err := fetch(ctx, fetcher, desc)
+	if err != nil {
+		return fmt.Errorf("fetch signatures: %w", err)
+	}
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("decode signatures: %w", err)
+	}
+
+	for _, layer := range manifest.Layers {
+		encoded, ok := layer.Annotations[SignatureAnnotation]
+		if !ok {
+			continue
+		}
+		sig, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			continue
+		}
+
+		data, err := fetch(ctx, fetcher, layer)
+		if err != nil {
+			return fmt.Errorf("fetch signature payload: %w", err)
+		}
+		if !v.verifySignature(data, sig) {
+			continue
+		}
+
+		var p payload
+		if err := json.Unmarshal(data, &p); err != nil {
+			continue
+		}
+		if p.Critical.Image.DockerManifestDigest == dgst.String() {
+			return nil
+		}
+	}
+
+	return errors.New("no signatures made by a trusted key")
+}
+
+// verifySignature reports whether sig is a signature of data made by any trusted key.
+func (v *Verifier) verifySignature(data, sig []byte) bool {
+	hash := sha256.Sum256(data)
+	for _, key := range v.keys {
+		switch k := key.(type) {
+		case *ecdsa.PublicKey:
+			if ecdsa.VerifyASN1(k, hash[:], sig) {
+				return true
+			}
+		case *rsa.PublicKey:
+			if rsa.VerifyPKCS1v15(k, crypto.SHA256, hash[:], sig) == nil {
+				return true
+			}
+		case ed25519.PublicKey:
+			if ed25519.Verify(k, data, sig) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// fetch retrieves the content described by desc ensuring it matches the descriptor's digest.
+func fetch(ctx context.Context, fetcher remotes.Fetcher, desc ocispec.Descriptor) ([]byte, error) {
+	if desc.Size > maxContentSize {
+		return nil, fmt.Errorf("content exceeds %v bytes", maxContentSize)
+	}
+
+	rc, err := fetcher.Fetch(ctx, desc)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, maxContentSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxContentSize {
+		return nil, fmt.Errorf("content exceeds %v bytes", maxContentSize)
+	}
+	if digest.FromBytes(data) != desc.Digest {
+		return nil, fmt.Errorf("content doesn't match digest %v", desc.Digest)
+	}
+	return data, nil
+}
+
+// LoadPublicKeys reads PEM encoded ECDSA, RSA and Ed25519 public keys from paths. Each file may
+// contain multiple keys.
+func LoadPublicKeys(paths ...string) ([]crypto.PublicKey, error) {
+	var keys []crypto.PublicKey
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read public keys: %w", err)
+		}
+
+		var found bool
+		for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+			if block.Type != "PUBLIC KEY" {
+				continue
+			}
+			key, err := x509.ParsePKIXPublicKey(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("parse public key in %v: %w", path, err)
+			}
+			switch key.(type) {
+			case *ecdsa.PublicKey, *rsa.PublicKey, ed25519.PublicKey:
+			default:
+				return nil, fmt.Errorf("unsupported public key type in %v: %T", path, key)
+			}
+			keys = append(keys, key)
+			found = true
+		}
+		if !found {
+			return nil, fmt.Errorf("no public keys found in %v", path)
+		}
+	}
+	return keys, nil
+}
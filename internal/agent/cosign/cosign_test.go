@@ -0,0 +1,187 @@
+package cosign_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/tinkerbell/tink/internal/agent/cosign"
+	"github.com/tinkerbell/tink/internal/registry"
+	"github.com/tinkerbell/tink/internal/registry/registrytest"
+)
+
+func TestVerifyImage(t *testing.T) {
+	trusted, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	untrusted, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fake := registrytest.New(t)
+	signed := putImage(fake, "signed")
+	sign(t, fake, "signed", signed, signed, trusted)
+	putImage(fake, "unsigned")
+	untrustedDigest := putImage(fake, "untrusted")
+	sign(t, fake, "untrusted", untrustedDigest, untrustedDigest, untrusted)
+	mismatchDigest := putImage(fake, "mismatch")
+	sign(t, fake, "mismatch", mismatchDigest, signed, trusted)
+
+	verifier := cosign.NewVerifier(
+		[]crypto.PublicKey{&trusted.PublicKey},
+		cosign.WithRegistry(registry.New(registry.WithMirror("quay.io", fake.Host()))),
+	)
+
+	cases := []struct {
+		Name   string
+		Image  string
+		Expect string
+		Error  bool
+	}{
+		{
+			Name:   "Signed",
+			Image:  fake.Host() + "/signed:v1",
+			Expect: fake.Host() + "/signed:v1@" + signed.String(),
+		},
+		{
+			Name:   "SignedDigest",
+			Image:  fake.Host() + "/signed@" + signed.String(),
+			Expect: fake.Host() + "/signed@" + signed.String(),
+		},
+		{
+			Name:   "Mirror",
+			Image:  "quay.io/signed:v1",
+			Expect: "quay.io/signed:v1@" + signed.String(),
+		},
+		{
+			Name:  "Unsigned",
+			Image: fake.Host() + "/unsigned:v1",
+			Error: true,
+		},
+		{
+			Name:  "UntrustedKey",
+			Image: fake.Host() + "/untrusted:v1",
+			Error: true,
+		},
+		{
+			Name:  "DigestMismatch",
+			Image: fake.Host() + "/mismatch:v1",
+			Error: true,
+		},
+		{
+			Name:  "MissingImage",
+			Image: fake.Host() + "/missing:v1",
+			Error: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			pinned, err := verifier.VerifyImage(context.Background(), tc.Image)
+			if tc.Error != (err != nil) {
+				t.Fatalf("Expected error: %v; Received: %v", tc.Error, err)
+			}
+			if pinned != tc.Expect {
+				t.Fatalf("Expected: %v; Received: %v", tc.Expect, pinned)
+			}
+		})
+	}
+}
+
+func TestLoadPublicKeys(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edKey, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	keys := filepath.Join(dir, "keys.pem")
+	if err := os.WriteFile(keys, append(encodePublicKey(t, &ecKey.PublicKey), encodePublicKey(t, edKey)...), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	empty := filepath.Join(dir, "empty.pem")
+	if err := os.WriteFile(empty, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := cosign.LoadPublicKeys(keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded) != 2 {
+		t.Fatalf("Expected 2 keys; Received: %v", len(loaded))
+	}
+
+	if _, err := cosign.LoadPublicKeys(empty); err == nil {
+		t.Fatal("Expected error for file without keys")
+	}
+	if _, err := cosign.LoadPublicKeys(filepath.Join(dir, "missing.pem")); err == nil {
+		t.Fatal("Expected error for missing file")
+	}
+}
+
+// putImage stores an image manifest tagged v1 in repo and returns its digest.
+func putImage(fake *registrytest.Registry, repo string) digest.Digest {
+	manifest := fmt.Sprintf(`{"schemaVersion":2,"annotations":{"repo":%q}}`, repo)
+	return fake.PutManifest(repo, "v1", ocispec.MediaTypeImageManifest, []byte(manifest))
+}
+
+// sign stores a cosign signature for the image with digest dgst in repo. The signed payload
+// references payloadDigest.
+func sign(t *testing.T, fake *registrytest.Registry, repo string, dgst, payloadDigest digest.Digest, key *ecdsa.PrivateKey) {
+	t.Helper()
+
+	payload := fmt.Appendf(nil,
+		`{"critical":{"identity":{"docker-reference":%q},"image":{"docker-manifest-digest":%q},"type":"cosign container image signature"},"optional":null}`,
+		repo, payloadDigest)
+	hash := sha256.Sum256(payload)
+	sig, err := ecdsa.SignASN1(rand.Reader, key, hash[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manifest, err := json.Marshal(ocispec.Manifest{
+		MediaType: ocispec.MediaTypeImageManifest,
+		Layers: []ocispec.Descriptor{
+			{
+				MediaType:   "application/vnd.dev.cosign.simplesigning.v1+json",
+				Digest:      fake.PutBlob(repo, payload),
+				Size:        int64(len(payload)),
+				Annotations: map[string]string{cosign.SignatureAnnotation: base64.StdEncoding.EncodeToString(sig)},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake.PutManifest(repo, fmt.Sprintf("%v-%v.sig", dgst.Algorithm(), dgst.Encoded()), ocispec.MediaTypeImageManifest, manifest)
+}
+
+func encodePublicKey(t *testing.T, key crypto.PublicKey) []byte {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
@@ -6,12 +6,17 @@ const (
 	ActionStartedName   Name = "ActionStarted"
 	ActionSucceededName Name = "ActionSucceeded"
 	ActionFailedName    Name = "ActionFailed"
+	ActionSkippedName   Name = "ActionSkipped"
+	ActionPausedName    Name = "ActionPaused"
 )
 
 // ActionStarted occurs when an action begins running.
 type ActionStarted struct {
 	ActionID   string
 	WorkflowID string
+
+	// Attempt is the attempt number starting from 1.
+	Attempt int
 }
 
 func (ActionStarted) GetName() Name {
@@ -26,6 +31,12 @@ func (e ActionStarted) String() string {
 type ActionSucceeded struct {
 	ActionID   string
 	WorkflowID string
+
+	// Attempt is the attempt number starting from 1.
+	Attempt int
+
+	// Outputs are the key/value pairs written by the action.
+	Outputs map[string]string
 }
 
 func (ActionSucceeded) GetName() Name {
@@ -42,6 +53,12 @@ type ActionFailed struct {
 	WorkflowID string
 	Reason     string
 	Message    string
+
+	// Attempt is the attempt number starting from 1.
+	Attempt int
+
+	// WillRetry indicates the action will be retried according to its retry policy.
+	WillRetry bool
 }
 
 func (ActionFailed) GetName() Name {
@@ -51,3 +68,31 @@ func (ActionFailed) GetName() Name {
 func (e ActionFailed) String() string {
 	return fmt.Sprintf("workflow='%v' action='%v' reason='%v'", e.WorkflowID, e.ActionID, e.Reason)
 }
+
+// ActionSkipped occurs when an action isn't run because its condition is false.
+type ActionSkipped struct {
+	ActionID   string
+	WorkflowID string
+}
+
+func (ActionSkipped) GetName() Name {
+	return ActionSkippedName
+}
+
+func (e ActionSkipped) String() string {
+	return fmt.Sprintf("workflow=%v action=%v", e.WorkflowID, e.ActionID)
+}
+
+// ActionPaused occurs when an action is held because the workflow is paused.
+type ActionPaused struct {
+	ActionID   string
+	WorkflowID string
+}
+
+func (ActionPaused) GetName() Name {
+	return ActionPausedName
+}
+
+func (e ActionPaused) String() string {
+	return fmt.Sprintf("workflow=%v action=%v", e.WorkflowID, e.ActionID)
+}
@@ -31,3 +31,11 @@ type Event interface {
 type Recorder interface {
 	RecordEvent(context.Context, Event) error
 }
+
+// AckRecorder is implemented by Recorders that deliver events asynchronously so callers can
+// observe delivery.
+type AckRecorder interface {
+	// RecordEventWithAck records e like RecordEvent and calls ack once the event is delivered or
+	// rejected as invalid by its destination.
+	RecordEventWithAck(ctx context.Context, e Event, ack func()) error
+}
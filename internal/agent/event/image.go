@@ -0,0 +1,45 @@
+package event
+
+import "fmt"
+
+const (
+	ImagePulledName     Name = "ImagePulled"
+	ImagePullFailedName Name = "ImagePullFailed"
+)
+
+// ImagePulled occurs when an image used by the workflow's actions has been pulled ahead of running
+// the actions.
+type ImagePulled struct {
+	WorkflowID string
+	Image      string
+
+	// Pulled is the number of distinct images pulled so far.
+	Pulled int
+
+	// Total is the number of distinct images used by the workflow.
+	Total int
+}
+
+func (ImagePulled) GetName() Name {
+	return ImagePulledName
+}
+
+func (e ImagePulled) String() string {
+	return fmt.Sprintf("workflow=%v image=%v pulled=%v/%v", e.WorkflowID, e.Image, e.Pulled, e.Total)
+}
+
+// ImagePullFailed occurs when an image used by the workflow's actions couldn't be pulled. No
+// action is run.
+type ImagePullFailed struct {
+	WorkflowID string
+	Image      string
+	Message    string
+}
+
+func (ImagePullFailed) GetName() Name {
+	return ImagePullFailedName
+}
+
+func (e ImagePullFailed) String() string {
+	return fmt.Sprintf("workflow='%v' image='%v'", e.WorkflowID, e.Image)
+}
@@ -12,5 +12,10 @@ package event
 func (ActionStarted) isEventFromThisPackage()   {}
 func (ActionSucceeded) isEventFromThisPackage() {}
 func (ActionFailed) isEventFromThisPackage()    {}
+func (ActionSkipped) isEventFromThisPackage()   {}
+func (ActionPaused) isEventFromThisPackage()    {}
+
+func (ImagePulled) isEventFromThisPackage()     {}
+func (ImagePullFailed) isEventFromThisPackage() {}
 
 func (WorkflowRejected) isEventFromThisPackage() {}
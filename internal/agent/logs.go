@@ -0,0 +1,21 @@
+package agent
+
+import (
+	"context"
+	"io"
+)
+
+// LogShipper ships action output off the node so it can be inspected after the action completes.
+type LogShipper interface {
+	// ShipActionLogs returns writers for the standard output and error of an attempt at executing
+	// the action identified by workflowID and actionID. The returned io.Closer is closed when the
+	// attempt completes and should flush any buffered output.
+	ShipActionLogs(_ context.Context, workflowID, actionID string) (stdout, stderr io.Writer, _ io.Closer)
+}
+
+// discardLogs is the LogShipper used when the agent isn't configured with one.
+type discardLogs struct{}
+
+func (discardLogs) ShipActionLogs(context.Context, string, string) (io.Writer, io.Writer, io.Closer) {
+	return io.Discard, io.Discard, io.NopCloser(nil)
+}
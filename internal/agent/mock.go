@@ -5,6 +5,7 @@ package agent
 
 import (
 	"context"
+	"io"
 	"sync"
 
 	"github.com/tinkerbell/tink/internal/agent/transport"
@@ -99,7 +100,7 @@ var _ ContainerRuntime = &ContainerRuntimeMock{}
 //
 //		// make and configure a mocked ContainerRuntime
 //		mockedContainerRuntime := &ContainerRuntimeMock{
-//			RunFunc: func(contextMoqParam context.Context, action workflow.Action) error {
+//			RunFunc: func(contextMoqParam context.Context, action workflow.Action, stdout io.Writer, stderr io.Writer) (map[string]string, error) {
 //				panic("mock out the Run method")
 //			},
 //		}
@@ -110,7 +111,7 @@ var _ ContainerRuntime = &ContainerRuntimeMock{}
 //	}
 type ContainerRuntimeMock struct {
 	// RunFunc mocks the Run method.
-	RunFunc func(contextMoqParam context.Context, action workflow.Action) error
+	RunFunc func(contextMoqParam context.Context, action workflow.Action, stdout io.Writer, stderr io.Writer) (map[string]string, error)
 
 	// calls tracks calls to the methods.
 	calls struct {
@@ -120,27 +121,35 @@ type ContainerRuntimeMock struct {
 			ContextMoqParam context.Context
 			// Action is the action argument value.
 			Action workflow.Action
+			// Stdout is the stdout argument value.
+			Stdout io.Writer
+			// Stderr is the stderr argument value.
+			Stderr io.Writer
 		}
 	}
 	lockRun sync.RWMutex
 }
 
 // Run calls RunFunc.
-func (mock *ContainerRuntimeMock) Run(contextMoqParam context.Context, action workflow.Action) error {
+func (mock *ContainerRuntimeMock) Run(contextMoqParam context.Context, action workflow.Action, stdout io.Writer, stderr io.Writer) (map[string]string, error) {
 	if mock.RunFunc == nil {
 		panic("ContainerRuntimeMock.RunFunc: method is nil but ContainerRuntime.Run was just called")
 	}
 	callInfo := struct {
 		ContextMoqParam context.Context
 		Action          workflow.Action
+		Stdout          io.Writer
+		Stderr          io.Writer
 	}{
 		ContextMoqParam: contextMoqParam,
 		Action:          action,
+		Stdout:          stdout,
+		Stderr:          stderr,
 	}
 	mock.lockRun.Lock()
 	mock.calls.Run = append(mock.calls.Run, callInfo)
 	mock.lockRun.Unlock()
-	return mock.RunFunc(contextMoqParam, action)
+	return mock.RunFunc(contextMoqParam, action, stdout, stderr)
 }
 
 // RunCalls gets all the calls that were made to Run.
@@ -150,13 +159,95 @@ func (mock *ContainerRuntimeMock) Run(contextMoqParam context.Context, action wo
 func (mock *ContainerRuntimeMock) RunCalls() []struct {
 	ContextMoqParam context.Context
 	Action          workflow.Action
+	Stdout          io.Writer
+	Stderr          io.Writer
 } {
 	var calls []struct {
 		ContextMoqParam context.Context
 		Action          workflow.Action
+		Stdout          io.Writer
+		Stderr          io.Writer
 	}
 	mock.lockRun.RLock()
 	calls = mock.calls.Run
 	mock.lockRun.RUnlock()
 	return calls
 }
+
+// Ensure, that LogShipperMock does implement LogShipper.
+// If this is not the case, regenerate this file with moq.
+var _ LogShipper = &LogShipperMock{}
+
+// LogShipperMock is a mock implementation of LogShipper.
+//
+//	func TestSomethingThatUsesLogShipper(t *testing.T) {
+//
+//		// make and configure a mocked LogShipper
+//		mockedLogShipper := &LogShipperMock{
+//			ShipActionLogsFunc: func(contextMoqParam context.Context, workflowID string, actionID string) (io.Writer, io.Writer, io.Closer) {
+//				panic("mock out the ShipActionLogs method")
+//			},
+//		}
+//
+//		// use mockedLogShipper in code that requires LogShipper
+//		// and then make assertions.
+//
+//	}
+type LogShipperMock struct {
+	// ShipActionLogsFunc mocks the ShipActionLogs method.
+	ShipActionLogsFunc func(contextMoqParam context.Context, workflowID string, actionID string) (io.Writer, io.Writer, io.Closer)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// ShipActionLogs holds details about calls to the ShipActionLogs method.
+		ShipActionLogs []struct {
+			// ContextMoqParam is the contextMoqParam argument value.
+			ContextMoqParam context.Context
+			// WorkflowID is the workflowID argument value.
+			WorkflowID string
+			// ActionID is the actionID argument value.
+			ActionID string
+		}
+	}
+	lockShipActionLogs sync.RWMutex
+}
+
+// ShipActionLogs calls ShipActionLogsFunc.
+func (mock *LogShipperMock) ShipActionLogs(contextMoqParam context.Context, workflowID string, actionID string) (io.Writer, io.Writer, io.Closer) {
+	if mock.ShipActionLogsFunc == nil {
+		panic("LogShipperMock.ShipActionLogsFunc: method is nil but LogShipper.ShipActionLogs was just called")
+	}
+	callInfo := struct {
+		ContextMoqParam context.Context
+		WorkflowID      string
+		ActionID        string
+	}{
+		ContextMoqParam: contextMoqParam,
+		WorkflowID:      workflowID,
+		ActionID:        actionID,
+	}
+	mock.lockShipActionLogs.Lock()
+	mock.calls.ShipActionLogs = append(mock.calls.ShipActionLogs, callInfo)
+	mock.lockShipActionLogs.Unlock()
+	return mock.ShipActionLogsFunc(contextMoqParam, workflowID, actionID)
+}
+
+// ShipActionLogsCalls gets all the calls that were made to ShipActionLogs.
+// Check the length with:
+//
+//	len(mockedLogShipper.ShipActionLogsCalls())
+func (mock *LogShipperMock) ShipActionLogsCalls() []struct {
+	ContextMoqParam context.Context
+	WorkflowID      string
+	ActionID        string
+} {
+	var calls []struct {
+		ContextMoqParam context.Context
+		WorkflowID      string
+		ActionID        string
+	}
+	mock.lockShipActionLogs.RLock()
+	calls = mock.calls.ShipActionLogs
+	mock.lockShipActionLogs.RUnlock()
+	return calls
+}
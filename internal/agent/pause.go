@@ -0,0 +1,61 @@
+package agent
+
+import (
+	"context"
+	"sync"
+
+	"github.com/tinkerbell/tink/internal/agent/workflow"
+)
+
+// pauseGate holds actions while the pause configuration of a workflow prevents them from
+// starting. It is safe for concurrent use.
+type pauseGate struct {
+	mtx   sync.Mutex
+	pause workflow.Pause
+
+	// changed is closed, and replaced, when pause is updated.
+	changed chan struct{}
+}
+
+func newPauseGate(pause workflow.Pause) *pauseGate {
+	return &pauseGate{
+		pause:   pause,
+		changed: make(chan struct{}),
+	}
+}
+
+// Set replaces the pause configuration and re-evaluates held actions.
+func (g *pauseGate) Set(pause workflow.Pause) {
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+
+	g.pause = pause
+	close(g.changed)
+	g.changed = make(chan struct{})
+}
+
+// Wait blocks while the action identified by id is held. onHold is called once, when the action
+// is first held. It returns ctx.Err() if ctx is done before the action is released.
+func (g *pauseGate) Wait(ctx context.Context, id string, onHold func()) error {
+	held := false
+	for {
+		g.mtx.Lock()
+		holds, changed := g.pause.Holds(id), g.changed
+		g.mtx.Unlock()
+
+		if !holds {
+			return nil
+		}
+
+		if !held {
+			held = true
+			onHold()
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
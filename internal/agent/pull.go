@@ -0,0 +1,138 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/tinkerbell/tink/internal/agent/event"
+	"github.com/tinkerbell/tink/internal/agent/workflow"
+	"golang.org/x/sync/errgroup"
+)
+
+// DefaultPullConcurrency is the default number of images the agent pulls concurrently.
+const DefaultPullConcurrency = 4
+
+// pullError identifies the image that couldn't be pulled.
+type pullError struct {
+	Image string
+	Err   error
+}
+
+func (e pullError) Error() string {
+	return e.Err.Error()
+}
+
+// pullImages pulls the distinct images used by the actions of wflw concurrently so actions don't
+// wait for their images to download, recording progress as each image is pulled. It returns false
+// if an image couldn't be pulled in which case no action should run. Images are pulled as
+// actions run when the runtime isn't an ImagePuller.
+//
+// When a Verifier is configured, images are pulled by the digest they're verified at so actions
+// run with the image that was pulled. Images that fail verification aren't pulled; the actions
+// using them fail when they run.
+func (agent *Agent) pullImages(ctx context.Context, log logr.Logger, wflw workflow.Workflow, events event.Recorder) bool {
+	puller, ok := agent.Runtime.(ImagePuller)
+	if !ok {
+		return true
+	}
+
+	images := workflowImages(wflw)
+	if len(images) == 0 {
+		return true
+	}
+
+	start := time.Now()
+	log.Info("Pulling images", "images", len(images))
+
+	var (
+		grp, grpCtx = errgroup.WithContext(ctx)
+		pulled      int
+		mtx         sync.Mutex
+	)
+	grp.SetLimit(agent.PullConcurrency)
+
+	for _, img := range images {
+		grp.Go(func() error {
+			ref := img
+			if agent.Verifier != nil {
+				verified, err := agent.Verifier.VerifyImage(grpCtx, img)
+				if err != nil {
+					log.Info("Image verification failed; not pulling image", "image", img, "error", err)
+					return nil
+				}
+				ref = verified
+			}
+
+			if err := puller.PullImage(grpCtx, ref); err != nil {
+				return pullError{Image: img, Err: err}
+			}
+
+			// Events are recorded under the lock so progress is reported in order.
+			mtx.Lock()
+			defer mtx.Unlock()
+			pulled++
+			log.Info("Pulled image", "image", ref, "pulled", pulled, "total", len(images))
+			progress := event.ImagePulled{
+				WorkflowID: wflw.ID,
+				Image:      img,
+				Pulled:     pulled,
+				Total:      len(images),
+			}
+			if err := events.RecordEvent(ctx, progress); err != nil {
+				log.Error(err, "Record image pulled event")
+			}
+			return nil
+		})
+	}
+
+	err := grp.Wait()
+	if err == nil {
+		log.Info("Pulled images", "duration", time.Since(start).String())
+		return true
+	}
+
+	// Cancelled workflows don't report pull failures.
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var perr pullError
+	if !errors.As(err, &perr) {
+		perr.Err = err
+	}
+
+	log.Info("Image pull failed; terminating workflow", "image", perr.Image, "error", perr.Err)
+	failed := event.ImagePullFailed{
+		WorkflowID: wflw.ID,
+		Image:      perr.Image,
+		Message:    strings.ReplaceAll(perr.Err.Error(), "\n", `\n`),
+	}
+	if err := events.RecordEvent(ctx, failed); err != nil {
+		log.Error(err, "Record image pull failed event", "event", failed)
+	}
+	return false
+}
+
+// workflowImages returns the distinct images used by the actions of wflw in the order they're
+// first used.
+func workflowImages(wflw workflow.Workflow) []string {
+	var images []string
+	for _, a := range slices.Concat(wflw.Actions, wflw.OnFailure, wflw.Finally) {
+		// Actions performed by the agent have no image and finished actions aren't run again.
+		if a.Type != workflow.ActionTypeContainer {
+			continue
+		}
+		if _, ok := wflw.Results[a.ID]; ok {
+			continue
+		}
+		if !slices.Contains(images, a.Image) {
+			images = append(images, a.Image)
+		}
+	}
+	return images
+}
@@ -0,0 +1,80 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/tinkerbell/tink/internal/agent/event"
+	"github.com/tinkerbell/tink/internal/agent/workflow"
+)
+
+// ReasonRebootFailed indicates the machine could not be restarted by a reboot or kexec action.
+const ReasonRebootFailed = "RebootFailed"
+
+// reboot performs a reboot or kexec action using the configured Rebooter. The action is reported
+// as started and completes once the agent reconnects after the machine restarts so the workflow
+// is held until the agent is stopped. The machine is only restarted once the server has the
+// action's start, and every event before it, as the server completes the action when it sees it
+// running after the restart. It returns false if the machine couldn't be restarted.
+func (agent *Agent) reboot(ctx context.Context, log logr.Logger, wflw workflow.Workflow, action workflow.Action, events event.Recorder) bool {
+	started := event.ActionStarted{
+		ActionID:   action.ID,
+		WorkflowID: wflw.ID,
+		Attempt:    1,
+	}
+	if err := recordDelivered(ctx, events, started); err != nil {
+		log.Error(err, "Record action start event")
+		return false
+	}
+
+	err := errors.New("agent isn't configured to restart the machine")
+	if agent.Rebooter != nil {
+		log.Info("Restarting machine", "type", action.Type)
+		switch action.Type {
+		case workflow.ActionTypeKexec:
+			err = agent.Rebooter.Kexec(ctx, action.Kexec)
+		default:
+			err = agent.Rebooter.Reboot(ctx)
+		}
+	}
+
+	if err == nil {
+		<-ctx.Done()
+		return false
+	}
+
+	log.Info("Restarting machine failed; terminating workflow", "error", err)
+	failed := event.ActionFailed{
+		ActionID:   action.ID,
+		WorkflowID: wflw.ID,
+		Reason:     ReasonRebootFailed,
+		Message:    strings.ReplaceAll(err.Error(), "\n", `\n`),
+		Attempt:    1,
+	}
+	if err := events.RecordEvent(ctx, failed); err != nil {
+		log.Error(err, "Record failed action event", "event", failed)
+	}
+	return false
+}
+
+// recordDelivered records e with events and, when events delivers asynchronously, waits until e
+// is acknowledged. Events are delivered in order so events recorded earlier are delivered too.
+func recordDelivered(ctx context.Context, events event.Recorder, e event.Event) error {
+	ar, ok := events.(event.AckRecorder)
+	if !ok {
+		return events.RecordEvent(ctx, e)
+	}
+
+	acked := make(chan struct{})
+	if err := ar.RecordEventWithAck(ctx, e, func() { close(acked) }); err != nil {
+		return err
+	}
+	select {
+	case <-acked:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
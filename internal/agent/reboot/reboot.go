@@ -0,0 +1,11 @@
+// Package reboot restarts the machine the agent runs on.
+package reboot
+
+import "github.com/tinkerbell/tink/internal/agent"
+
+var _ agent.Rebooter = System{}
+
+// System satisfies agent.Rebooter by restarting the machine using the Linux reboot and
+// kexec_file_load system calls. Filesystems are synced before the machine restarts. It requires
+// the CAP_SYS_BOOT capability, and CAP_SYS_ADMIN to mount the device containing a kexec kernel.
+type System struct{}
@@ -0,0 +1,72 @@
+//go:build linux && (amd64 || arm64)
+
+package reboot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/tinkerbell/tink/internal/agent/workflow"
+	"golang.org/x/sys/unix"
+)
+
+// Reboot satisfies agent.Rebooter.
+func (System) Reboot(context.Context) error {
+	unix.Sync()
+	if err := unix.Reboot(unix.LINUX_REBOOT_CMD_RESTART); err != nil {
+		return fmt.Errorf("reboot: %w", err)
+	}
+	return nil
+}
+
+// Kexec satisfies agent.Rebooter. When k specifies a block device, it's mounted read-only to
+// load the kernel.
+func (System) Kexec(_ context.Context, k workflow.Kexec) error {
+	if k.KernelPath == "" {
+		return errors.New("kexec: missing kernel path")
+	}
+
+	root := "/"
+	if k.BlockDevice != "" {
+		dir, err := os.MkdirTemp("", "kexec-")
+		if err != nil {
+			return fmt.Errorf("kexec: %w", err)
+		}
+		defer os.Remove(dir)
+
+		if err := unix.Mount(k.BlockDevice, dir, k.FSType, unix.MS_RDONLY, ""); err != nil {
+			return fmt.Errorf("kexec: mount %v: %w", k.BlockDevice, err)
+		}
+		defer unix.Unmount(dir, 0) //nolint:errcheck // Best effort; the kernel is already loaded.
+		root = dir
+	}
+
+	kernel, err := os.Open(filepath.Join(root, k.KernelPath))
+	if err != nil {
+		return fmt.Errorf("kexec: %w", err)
+	}
+	defer kernel.Close()
+
+	initrd, flags := -1, unix.KEXEC_FILE_NO_INITRAMFS
+	if k.InitrdPath != "" {
+		f, err := os.Open(filepath.Join(root, k.InitrdPath))
+		if err != nil {
+			return fmt.Errorf("kexec: %w", err)
+		}
+		defer f.Close()
+		initrd, flags = int(f.Fd()), 0
+	}
+
+	if err := unix.KexecFileLoad(int(kernel.Fd()), initrd, k.Cmdline, flags); err != nil {
+		return fmt.Errorf("kexec: load kernel: %w", err)
+	}
+
+	unix.Sync()
+	if err := unix.Reboot(unix.LINUX_REBOOT_CMD_KEXEC); err != nil {
+		return fmt.Errorf("kexec: %w", err)
+	}
+	return nil
+}
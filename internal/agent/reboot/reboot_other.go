@@ -0,0 +1,22 @@
+//go:build !(linux && (amd64 || arm64))
+
+package reboot
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tinkerbell/tink/internal/agent/workflow"
+)
+
+var errUnsupported = errors.New("restarting the machine is unsupported on this platform")
+
+// Reboot satisfies agent.Rebooter.
+func (System) Reboot(context.Context) error {
+	return errUnsupported
+}
+
+// Kexec satisfies agent.Rebooter.
+func (System) Kexec(context.Context, workflow.Kexec) error {
+	return errUnsupported
+}
@@ -0,0 +1,291 @@
+package agent
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"github.com/tinkerbell/tink/internal/agent/event"
+	"github.com/tinkerbell/tink/internal/agent/state"
+	"github.com/tinkerbell/tink/internal/agent/workflow"
+)
+
+// StateStore persists agent state so the executing workflow can be recovered when the agent
+// restarts.
+type StateStore interface {
+	Load() (state.State, error)
+	Save(state.State) error
+}
+
+// recover loads the persisted state, re-records events that weren't sent before the agent
+// stopped and resumes the workflow that was executing. The caller must hold the semaphore; it's
+// released by the resumed workflow or before returning if there's nothing to resume.
+func (agent *Agent) recover(ctx context.Context, recorder event.Recorder) {
+	st, err := agent.State.Load()
+	if err != nil {
+		agent.Log.Error(err, "Discarding unreadable agent state")
+		st = state.State{}
+	}
+	agent.journal = newJournal(agent.Log, agent.State, st)
+
+	for _, r := range agent.journal.unsent() {
+		if err := agent.journal.deliver(ctx, recorder, r, nil); err != nil {
+			agent.Log.Error(err, "Record unsent event", "event", r.Event)
+		}
+	}
+
+	if st.Workflow == nil {
+		agent.journal.end()
+		agent.sem <- struct{}{}
+		return
+	}
+
+	wflw := *st.Workflow
+	rec := newRecovery(wflw, st.Events)
+	if rec.aborted {
+		agent.Log.Info("Workflow ended before agent restarted", "workflow_id", wflw.ID)
+		agent.journal.end()
+		agent.sem <- struct{}{}
+		return
+	}
+
+	agent.Log.Info("Resuming workflow interrupted by agent restart", "workflow_id", wflw.ID)
+	agent.execute(ctx, wflw, recorder, rec)
+}
+
+// recovered returns the outcome of action when it finished before the agent restarted. When the
+// action was interrupted by the restart and isn't idempotent it's failed with
+// ReasonAgentRestarted. Otherwise, it returns the attempt the action should start from.
+func (agent *Agent) recovered(ctx context.Context, log logr.Logger, wflw workflow.Workflow, action workflow.Action, events event.Recorder) (outputs map[string]string, ok, done bool, attempt int) {
+	agent.mtx.RLock()
+	var rec *recovery
+	if agent.executionContext != nil {
+		rec = agent.executionContext.Recovery
+	}
+	agent.mtx.RUnlock()
+
+	if rec == nil {
+		return nil, false, false, 1
+	}
+
+	if r, finished := rec.Finished[action.ID]; finished {
+		log.Info("Action finished previously", "succeeded", r.Succeeded)
+		return r.Outputs, r.Succeeded, true, 0
+	}
+
+	interrupted, ok := rec.Interrupted[action.ID]
+	if !ok {
+		return nil, false, false, 1
+	}
+
+	// Interrupting the agent is the purpose of reboot and kexec actions.
+	if action.Type != workflow.ActionTypeContainer {
+		log.Info("Machine restarted by action")
+		succeeded := event.ActionSucceeded{
+			ActionID:   action.ID,
+			WorkflowID: wflw.ID,
+			Attempt:    interrupted,
+		}
+		if err := events.RecordEvent(ctx, succeeded); err != nil {
+			log.Error(err, "Record succeeded action event")
+			return nil, false, true, 0
+		}
+		return nil, true, true, 0
+	}
+
+	if action.Idempotent {
+		log.Info("Re-running idempotent action interrupted by agent restart")
+		return nil, false, false, interrupted + 1
+	}
+
+	log.Info("Action interrupted by agent restart; terminating workflow")
+	failed := event.ActionFailed{
+		ActionID:   action.ID,
+		WorkflowID: wflw.ID,
+		Reason:     ReasonAgentRestarted,
+		Message:    "the agent restarted while the action was running",
+		Attempt:    interrupted,
+	}
+	if err := events.RecordEvent(ctx, failed); err != nil {
+		log.Error(err, "Record failed action event", "event", failed)
+	}
+	return nil, false, true, 0
+}
+
+// recovery is the progress through a workflow made before the agent received it, either recovered
+// from the events recorded before the agent restarted or provided with the workflow.
+type recovery struct {
+	// Finished contains the outcome of actions that finished keyed by action ID.
+	Finished map[string]workflow.Result
+
+	// Interrupted contains the last attempt of actions that were running, or waiting to be
+	// retried, keyed by action ID.
+	Interrupted map[string]int
+
+	// aborted indicates the workflow ended before any action ran.
+	aborted bool
+}
+
+// newRecovery builds the progress through wflw from its Results and records.
+func newRecovery(wflw workflow.Workflow, records []state.Record) *recovery {
+	workflowID := wflw.ID
+	rec := &recovery{
+		Finished:    maps.Clone(wflw.Results),
+		Interrupted: map[string]int{},
+	}
+	if rec.Finished == nil {
+		rec.Finished = map[string]workflow.Result{}
+	}
+
+	for _, r := range records {
+		switch e := r.Event.(type) {
+		case event.ActionStarted:
+			if e.WorkflowID == workflowID {
+				rec.Interrupted[e.ActionID] = e.Attempt
+			}
+		case event.ActionFailed:
+			if e.WorkflowID != workflowID || e.WillRetry {
+				continue
+			}
+			delete(rec.Interrupted, e.ActionID)
+			rec.Finished[e.ActionID] = workflow.Result{}
+		case event.ActionSucceeded:
+			if e.WorkflowID == workflowID {
+				delete(rec.Interrupted, e.ActionID)
+				rec.Finished[e.ActionID] = workflow.Result{Succeeded: true, Outputs: e.Outputs}
+			}
+		case event.ActionSkipped:
+			if e.WorkflowID == workflowID {
+				delete(rec.Interrupted, e.ActionID)
+				rec.Finished[e.ActionID] = workflow.Result{Succeeded: true}
+			}
+		case event.ImagePullFailed:
+			if e.WorkflowID == workflowID {
+				rec.aborted = true
+			}
+		}
+	}
+
+	return rec
+}
+
+// journal persists the executing workflow and the events recorded for it. Events are marked sent
+// once they're acknowledged so events lost by the transport, for example because the agent
+// restarted before they were published, are recorded again when the agent restarts.
+type journal struct {
+	log   logr.Logger
+	store StateStore
+
+	mtx    sync.Mutex
+	state  state.State
+	nextID uint64
+}
+
+func newJournal(log logr.Logger, store StateStore, st state.State) *journal {
+	j := &journal{log: log, store: store, state: st}
+	for i := range j.state.Events {
+		j.nextID++
+		j.state.Events[i].ID = j.nextID
+	}
+	return j
+}
+
+// begin records wflw as the executing workflow. Events already sent are discarded unless wflw is
+// being resumed so its progress survives the agent restarting again.
+func (j *journal) begin(wflw workflow.Workflow) {
+	j.mtx.Lock()
+	defer j.mtx.Unlock()
+	events := j.state.Events
+	if j.state.Workflow == nil || j.state.Workflow.ID != wflw.ID {
+		events = j.state.Unsent()
+	}
+	j.state = state.State{Workflow: &wflw, Events: events}
+	j.save()
+}
+
+// end records that no workflow is executing. Events that weren't sent are retained so they can be
+// recorded when the agent restarts.
+func (j *journal) end() {
+	j.mtx.Lock()
+	defer j.mtx.Unlock()
+	j.state = state.State{Events: j.state.Unsent()}
+	j.save()
+}
+
+// unsent returns the events that haven't been acknowledged.
+func (j *journal) unsent() []state.Record {
+	j.mtx.Lock()
+	defer j.mtx.Unlock()
+	return j.state.Unsent()
+}
+
+// append persists e as an unsent event and returns its record.
+func (j *journal) append(e event.Event) state.Record {
+	j.mtx.Lock()
+	defer j.mtx.Unlock()
+	j.nextID++
+	r := state.Record{Event: e, ID: j.nextID}
+	j.state.Events = append(slices.Clip(j.state.Events), r)
+	j.save()
+	return r
+}
+
+// deliver records the event of r with next and marks r sent once next acknowledges it, then calls
+// ack when not nil. Recorders that don't implement event.AckRecorder deliver events before
+// returning.
+func (j *journal) deliver(ctx context.Context, next event.Recorder, r state.Record, ack func()) error {
+	sent := func() {
+		j.markSent(r.ID)
+		if ack != nil {
+			ack()
+		}
+	}
+	if ar, ok := next.(event.AckRecorder); ok {
+		return ar.RecordEventWithAck(ctx, r.Event, sent)
+	}
+	if err := next.RecordEvent(ctx, r.Event); err != nil {
+		return err
+	}
+	sent()
+	return nil
+}
+
+// markSent marks the event recorded with id as sent. Sent events may already have been
+// discarded.
+func (j *journal) markSent(id uint64) {
+	j.mtx.Lock()
+	defer j.mtx.Unlock()
+	for i := range j.state.Events {
+		if j.state.Events[i].ID == id {
+			j.state.Events[i].Sent = true
+			j.save()
+			return
+		}
+	}
+}
+
+// recorder returns a recorder that persists events before recording them with next.
+func (j *journal) recorder(next event.Recorder) event.Recorder {
+	return journalRecorder{journal: j, next: next}
+}
+
+func (j *journal) save() {
+	if err := j.store.Save(j.state); err != nil {
+		j.log.Error(err, "Save agent state")
+	}
+}
+
+type journalRecorder struct {
+	journal *journal
+	next    event.Recorder
+}
+
+func (r journalRecorder) RecordEvent(ctx context.Context, e event.Event) error {
+	return r.journal.deliver(ctx, r.next, r.journal.append(e), nil)
+}
+
+func (r journalRecorder) RecordEventWithAck(ctx context.Context, e event.Event, ack func()) error {
+	return r.journal.deliver(ctx, r.next, r.journal.append(e), ack)
+}
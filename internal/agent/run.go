@@ -2,7 +2,11 @@ package agent
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"maps"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 
@@ -10,26 +14,269 @@ import (
 	"github.com/tinkerbell/tink/internal/agent/event"
 	"github.com/tinkerbell/tink/internal/agent/failure"
 	"github.com/tinkerbell/tink/internal/agent/workflow"
+	"github.com/tinkerbell/tink/internal/when"
 )
 
 // ReasonRuntimeError is the default reason used when no reason is provided by the runtime.
 const ReasonRuntimeError = "RuntimeError"
 
+// ReasonTimeout indicates an action exceeded its timeout.
+const ReasonTimeout = "Timeout"
+
+// ReasonInvalidCondition indicates an action's condition could not be evaluated.
+const ReasonInvalidCondition = "InvalidCondition"
+
+// ReasonImageVerificationFailed indicates an action's image could not be verified.
+const ReasonImageVerificationFailed = "ImageVerificationFailed"
+
+// ReasonAgentRestarted indicates an action was interrupted by the agent restarting.
+const ReasonAgentRestarted = "AgentRestarted"
+
 // ReasonInvalid indicates a reason provided by the runtime was invalid.
 const ReasonInvalid = "InvalidReason"
 
 // validReasonRegex defines the regex for a valid action failure reason.
 var validReasonRegex = regexp.MustCompile(`^[a-zA-Z]+$`)
 
-// run executes the workflow using the runtime configured on agent.
+// run executes the workflow using the runtime configured on agent. The images used by the workflow
+// are pulled before any action runs when the runtime supports it. Actions are started once the
+// actions they depend on have succeeded so independent actions run concurrently. When an action
+// fails no further actions are started but actions already running are allowed to finish. The
+// OnFailure actions are then run if an action failed, followed by the Finally actions.
 func (agent *Agent) run(ctx context.Context, wflw workflow.Workflow, events event.Recorder) {
 	log := agent.Log.WithValues("workflow_id", wflw.ID)
 
 	workflowStart := time.Now()
 	log.Info("Starting workflow")
 
-	for _, action := range wflw.Actions {
+	if !agent.pullImages(ctx, log, wflw, events) {
+		return
+	}
+
+	outputs := map[string]map[string]string{}
+	ok := agent.runActions(ctx, log, wflw, outputs, events)
+
+	// Cancelled workflows can't run further actions.
+	if ctx.Err() == nil {
+		if !ok && len(wflw.OnFailure) > 0 {
+			log.Info("Running on failure actions")
+			agent.runSequence(ctx, log, wflw, wflw.OnFailure, outputs, events)
+		}
+		if len(wflw.Finally) > 0 {
+			log.Info("Running finally actions")
+			ok = agent.runSequence(ctx, log, wflw, wflw.Finally, outputs, events) && ok
+		}
+	}
+
+	if !ok {
+		return
+	}
+
+	log.Info("Finished workflow", "duration", time.Since(workflowStart).String())
+}
+
+// runActions executes the workflow's Actions according to their dependencies and records the
+// outputs of successful actions in outputs. It returns true if all actions succeeded.
+func (agent *Agent) runActions(ctx context.Context, log logr.Logger, wflw workflow.Workflow, outputs map[string]map[string]string, events event.Recorder) bool {
+	type result struct {
+		ActionID  string
+		Succeeded bool
+		Outputs   map[string]string
+	}
+
+	var (
+		deps      = wflw.Dependencies()
+		pending   = wflw.Actions
+		succeeded = map[string]bool{}
+		results   = make(chan result)
+		running   int
+		failed    bool
+	)
+
+	for {
+		if !failed {
+			var blocked []workflow.Action
+			for _, action := range pending {
+				if !allSucceeded(deps[action.ID], succeeded) {
+					blocked = append(blocked, action)
+					continue
+				}
+
+				running++
+				inputs := make(map[string]map[string]string)
+				for _, id := range ancestors(action.ID, deps) {
+					inputs[id] = outputs[id]
+				}
+				action, wflw := withInputs(wflw, action, inputs)
+				go func() {
+					log := log.WithValues("action_id", action.ID, "action_name", action.Name)
+					outputs, ok := agent.runAction(ctx, log, wflw, action, events)
+					results <- result{action.ID, ok, outputs}
+				}()
+			}
+			pending = blocked
+		}
+
+		if running == 0 {
+			break
+		}
+
+		r := <-results
+		running--
+		if r.Succeeded {
+			succeeded[r.ActionID] = true
+			outputs[r.ActionID] = r.Outputs
+		} else {
+			failed = true
+		}
+	}
+
+	if failed {
+		return false
+	}
+
+	if len(pending) > 0 {
+		// Transports validate dependencies so this indicates a transport bug.
+		log.Info("Workflow has actions with unsatisfiable dependencies", "actions", len(pending))
+		return false
+	}
+
+	return true
+}
+
+// runSequence executes actions sequentially, stopping at the first action that fails. Actions
+// have access to all outputs recorded so far and their outputs are added to outputs. It returns
+// true if all actions succeeded.
+func (agent *Agent) runSequence(ctx context.Context, log logr.Logger, wflw workflow.Workflow, actions []workflow.Action, outputs map[string]map[string]string, events event.Recorder) bool {
+	for _, action := range actions {
+		action, wflw := withInputs(wflw, action, outputs)
 		log := log.WithValues("action_id", action.ID, "action_name", action.Name)
+		out, ok := agent.runAction(ctx, log, wflw, action, events)
+		if !ok {
+			return false
+		}
+		outputs[action.ID] = out
+	}
+	return true
+}
+
+// ancestors returns the IDs of the actions the action identified by id transitively depends on.
+func ancestors(id string, deps map[string][]string) []string {
+	var ids []string
+	seen := map[string]bool{}
+	queue := slices.Clone(deps[id])
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+			queue = append(queue, deps[id]...)
+		}
+	}
+	return ids
+}
+
+// allSucceeded determines if every action identified by ids has succeeded.
+func allSucceeded(ids []string, succeeded map[string]bool) bool {
+	for _, id := range ids {
+		if !succeeded[id] {
+			return false
+		}
+	}
+	return true
+}
+
+// withInputs returns copies of wflw and action that expose inputs, the outputs of other actions
+// keyed by action ID, to action. Outputs are available to the action as environment variables
+// named TINKERBELL_OUTPUT_<ACTION NAME>_<KEY>, unless the action defines the variable itself, and
+// to its condition as .Outputs.<action name>.<key>.
+func withInputs(wflw workflow.Workflow, action workflow.Action, inputs map[string]map[string]string) (workflow.Action, workflow.Workflow) {
+	env := maps.Clone(action.Env)
+	if env == nil {
+		env = map[string]string{}
+	}
+	data := maps.Clone(wflw.ConditionData)
+	if data == nil {
+		data = map[string]any{}
+	}
+	byName := map[string]any{}
+
+	// Iterate the workflow actions so inputs are deterministic when environment variable names
+	// collide.
+	for _, a := range slices.Concat(wflw.Actions, wflw.OnFailure, wflw.Finally) {
+		outputs, ok := inputs[a.ID]
+		if !ok {
+			continue
+		}
+
+		values := map[string]any{}
+		for k, v := range outputs {
+			name := outputEnvName(a.Name, k)
+			if _, ok := action.Env[name]; !ok {
+				env[name] = v
+			}
+			values[k] = v
+		}
+		byName[a.Name] = values
+	}
+	data["Outputs"] = byName
+
+	action.Env = env
+	wflw.ConditionData = data
+	return action, wflw
+}
+
+var invalidEnvNameChars = regexp.MustCompile(`[^A-Z0-9_]`)
+
+// outputEnvName returns the environment variable name used to pass an output of the action
+// named actionName to other actions.
+func outputEnvName(actionName, key string) string {
+	return invalidEnvNameChars.ReplaceAllString(
+		strings.ToUpper(fmt.Sprintf("TINKERBELL_OUTPUT_%v_%v", actionName, key)),
+		"_",
+	)
+}
+
+// runAction executes action, retrying according to its retry policy until the action's timeout
+// elapses. It returns the action's outputs and true if the action succeeded and the workflow
+// should continue.
+func (agent *Agent) runAction(ctx context.Context, log logr.Logger, wflw workflow.Workflow, action workflow.Action, events event.Recorder) (map[string]string, bool) {
+	outputs, ok, done, first := agent.recovered(ctx, log, wflw, action, events)
+	if done {
+		return outputs, ok
+	}
+
+	if !agent.waitWhilePaused(ctx, log, wflw, action, events) {
+		return nil, false
+	}
+
+	if skip, ok := agent.skipAction(ctx, log, wflw, action, events); skip {
+		return nil, ok
+	}
+
+	if action.Type != workflow.ActionTypeContainer {
+		return nil, agent.reboot(ctx, log, wflw, action, events)
+	}
+
+	image, ok := agent.verifyImage(ctx, log, wflw, action, events)
+	if !ok {
+		return nil, false
+	}
+	action.Image = image
+
+	// The timeout starts once the action is ready to run so time held at a pause doesn't count
+	// against it. Events are recorded with ctx so the outcome of an action that timed out is still
+	// recorded.
+	runCtx := ctx
+	if action.Timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, action.Timeout)
+		defer cancel()
+	}
+
+	for attempt := first; ; attempt++ {
+		log := log.WithValues("attempt", attempt)
 
 		actionStart := time.Now()
 		log.Info("Starting action")
@@ -37,52 +284,191 @@ func (agent *Agent) run(ctx context.Context, wflw workflow.Workflow, events even
 		started := event.ActionStarted{
 			ActionID:   action.ID,
 			WorkflowID: wflw.ID,
+			Attempt:    attempt,
 		}
 		if err := events.RecordEvent(ctx, started); err != nil {
 			log.Error(err, "Record action start event")
-			return
+			return nil, false
 		}
 
-		if err := agent.Runtime.Run(ctx, action); err != nil {
-			reason := extractReason(log, err)
+		stdout, stderr, logs := agent.Logs.ShipActionLogs(ctx, wflw.ID, action.ID)
+		outputs, err := agent.Runtime.Run(runCtx, action, stdout, stderr)
+		if err != nil && ctx.Err() == nil && errors.Is(runCtx.Err(), context.DeadlineExceeded) {
+			err = failure.WithReason(err, ReasonTimeout)
+		}
 
-			// We consider newlines in the failure message invalid because it upsets formatting.
-			// The failure message is vital to easy debugability so we force the string into
-			// something we're happy with and communicate that.
-			message := strings.ReplaceAll(err.Error(), "\n", `\n`)
+		// Flush the output before recording the outcome so the output is available to consumers
+		// of the event.
+		if cerr := logs.Close(); cerr != nil {
+			log.Error(cerr, "Ship action logs")
+		}
 
+		if err == nil {
+			succeed := event.ActionSucceeded{
+				ActionID:   action.ID,
+				WorkflowID: wflw.ID,
+				Attempt:    attempt,
+				Outputs:    outputs,
+			}
+			if err := events.RecordEvent(ctx, succeed); err != nil {
+				log.Error(err, "Record succeeded action event")
+				return nil, false
+			}
+
+			log.Info("Finished action", "duration", time.Since(actionStart).String())
+			return outputs, true
+		}
+
+		reason := extractReason(log, err)
+
+		// We consider newlines in the failure message invalid because it upsets formatting.
+		// The failure message is vital to easy debugability so we force the string into
+		// something we're happy with and communicate that.
+		message := strings.ReplaceAll(err.Error(), "\n", `\n`)
+
+		// Don't retry if the context is done as the workflow has been cancelled or the action has
+		// timed out, or if the action would time out before the retry starts.
+		delay := action.Retry.Delay(attempt)
+		retry := runCtx.Err() == nil && action.Retry.ShouldRetry(attempt, reason)
+		if deadline, ok := runCtx.Deadline(); ok && time.Until(deadline) <= delay {
+			retry = false
+		}
+
+		failed := event.ActionFailed{
+			ActionID:   action.ID,
+			WorkflowID: wflw.ID,
+			Reason:     reason,
+			Message:    message,
+			Attempt:    attempt,
+			WillRetry:  retry,
+		}
+
+		if !retry {
 			log.Info("Action failed; terminating workflow",
 				"error", err,
 				"reason", reason,
 				"duration", time.Since(actionStart).String(),
 			)
-
-			failed := event.ActionFailed{
-				ActionID:   action.ID,
-				WorkflowID: wflw.ID,
-				Reason:     reason,
-				Message:    message,
-			}
 			if err := events.RecordEvent(ctx, failed); err != nil {
 				log.Error(err, "Record failed action event", "event", failed)
 			}
+			return nil, false
+		}
+
+		log.Info("Action failed; retrying",
+			"error", err,
+			"reason", reason,
+			"duration", time.Since(actionStart).String(),
+			"delay", delay.String(),
+		)
+		if err := events.RecordEvent(ctx, failed); err != nil {
+			log.Error(err, "Record failed action event", "event", failed)
+			return nil, false
+		}
 
-			return
+		select {
+		case <-runCtx.Done():
+			return nil, false
+		case <-time.After(delay):
 		}
+	}
+}
+
+// waitWhilePaused blocks while the executing workflow is paused before action, recording when
+// the action is held. It returns false if the workflow was cancelled while waiting.
+func (agent *Agent) waitWhilePaused(ctx context.Context, log logr.Logger, wflw workflow.Workflow, action workflow.Action, events event.Recorder) bool {
+	agent.mtx.RLock()
+	ec := agent.executionContext
+	agent.mtx.RUnlock()
+
+	if ec == nil {
+		return true
+	}
 
-		succeed := event.ActionSucceeded{
+	var held bool
+	err := ec.Pause.Wait(ctx, action.ID, func() {
+		held = true
+		log.Info("Workflow paused; holding action")
+		paused := event.ActionPaused{
 			ActionID:   action.ID,
 			WorkflowID: wflw.ID,
 		}
-		if err := events.RecordEvent(ctx, succeed); err != nil {
-			log.Error(err, "Record succeeded action event")
-			return
+		if err := events.RecordEvent(ctx, paused); err != nil {
+			log.Error(err, "Record paused action event")
 		}
+	})
+	if err != nil {
+		return false
+	}
 
-		log.Info("Finished action", "duration", time.Since(actionStart).String())
+	if held {
+		log.Info("Workflow resumed; releasing action")
 	}
+	return true
+}
 
-	log.Info("Finished workflow", "duration", time.Since(workflowStart).String())
+// verifyImage verifies the image of action using the configured Verifier, recording a failure
+// when verification fails. It returns the image the action should be run with and false if the
+// image couldn't be verified.
+func (agent *Agent) verifyImage(ctx context.Context, log logr.Logger, wflw workflow.Workflow, action workflow.Action, events event.Recorder) (string, bool) {
+	if agent.Verifier == nil {
+		return action.Image, true
+	}
+
+	image, err := agent.Verifier.VerifyImage(ctx, action.Image)
+	if err != nil {
+		log.Info("Image verification failed; terminating workflow", "image", action.Image, "error", err)
+		failed := event.ActionFailed{
+			ActionID:   action.ID,
+			WorkflowID: wflw.ID,
+			Reason:     ReasonImageVerificationFailed,
+			Message:    strings.ReplaceAll(err.Error(), "\n", `\n`),
+			Attempt:    1,
+		}
+		if err := events.RecordEvent(ctx, failed); err != nil {
+			log.Error(err, "Record failed action event", "event", failed)
+		}
+		return "", false
+	}
+
+	log.Info("Verified image", "image", image)
+	return image, true
+}
+
+// skipAction evaluates the condition of action and records the outcome when the action shouldn't
+// run. It returns true if the action should not run and, in that case, whether the workflow
+// should continue.
+func (agent *Agent) skipAction(ctx context.Context, log logr.Logger, wflw workflow.Workflow, action workflow.Action, events event.Recorder) (skip, ok bool) {
+	run, err := when.Evaluate(action.When, wflw.ConditionData)
+	if err != nil {
+		log.Info("Invalid action condition; terminating workflow", "error", err)
+		failed := event.ActionFailed{
+			ActionID:   action.ID,
+			WorkflowID: wflw.ID,
+			Reason:     ReasonInvalidCondition,
+			Message:    strings.ReplaceAll(err.Error(), "\n", `\n`),
+			Attempt:    1,
+		}
+		if err := events.RecordEvent(ctx, failed); err != nil {
+			log.Error(err, "Record failed action event", "event", failed)
+		}
+		return true, false
+	}
+
+	if run {
+		return false, false
+	}
+
+	log.Info("Skipping action; condition is false", "condition", action.When)
+	skipped := event.ActionSkipped{
+		ActionID:   action.ID,
+		WorkflowID: wflw.ID,
+	}
+	if err := events.RecordEvent(ctx, skipped); err != nil {
+		log.Error(err, "Record skipped action event")
+		return true, false
+	}
+	return true, true
 }
 
 func extractReason(log logr.Logger, err error) string {
@@ -2,6 +2,7 @@ package agent
 
 import (
 	"context"
+	"io"
 
 	"github.com/tinkerbell/tink/internal/agent/workflow"
 )
@@ -16,5 +17,39 @@ type ContainerRuntime interface {
 	//
 	// The reason and message should be communicataed via the returned error. The message should
 	// be the error message and the reason should be provided as defined in failure.Reason().
-	Run(context.Context, workflow.Action) error
+	//
+	// The runtime should also mount the following file for the action implementation to
+	// communicate outputs as KEY=VALUE pairs, one per line. Outputs of successful actions are
+	// returned by Run.
+	//
+	//	/tinkerbell/outputs
+	//
+	// The standard output and error of the action should be written to stdout and stderr
+	// respectively. Run should not return until all output has been written.
+	Run(_ context.Context, _ workflow.Action, stdout, stderr io.Writer) (outputs map[string]string, err error)
+}
+
+// ImagePuller is implemented by container runtimes that can pull images ahead of running the
+// actions that use them. When the runtime configured on the agent is an ImagePuller, the agent
+// pulls the images used by a workflow before running any of its actions.
+type ImagePuller interface {
+	// PullImage makes image available to subsequently run actions.
+	PullImage(_ context.Context, image string) error
+}
+
+// ImageVerifier verifies action images before they're run.
+type ImageVerifier interface {
+	// VerifyImage verifies image and returns a reference to the verified image pinned by digest
+	// so the runtime runs the image that was verified.
+	VerifyImage(_ context.Context, image string) (string, error)
+}
+
+// Rebooter restarts the machine the agent runs on for reboot and kexec actions. A nil error
+// indicates the machine is restarting.
+type Rebooter interface {
+	// Reboot reboots the machine.
+	Reboot(context.Context) error
+
+	// Kexec boots the kernel described by k without returning to the firmware.
+	Kexec(_ context.Context, k workflow.Kexec) error
 }
@@ -0,0 +1,10 @@
+package runtime
+
+const (
+	// OutputsMountPath is the path used by Actions to write their outputs as KEY=VALUE pairs, one
+	// per line.
+	OutputsMountPath = "/tinkerbell/outputs"
+
+	// ReasonInvalidOutputs indicates an action wrote outputs that couldn't be parsed.
+	ReasonInvalidOutputs = "InvalidOutputs"
+)
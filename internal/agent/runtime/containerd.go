@@ -0,0 +1,446 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	retry "github.com/avast/retry-go"
+	"github.com/containerd/containerd"
+	"github.com/containerd/containerd/cio"
+	"github.com/containerd/containerd/namespaces"
+	"github.com/containerd/containerd/oci"
+	"github.com/distribution/reference"
+	"github.com/go-logr/logr"
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/tinkerbell/tink/internal/agent"
+	"github.com/tinkerbell/tink/internal/agent/failure"
+	"github.com/tinkerbell/tink/internal/agent/runtime/internal"
+	"github.com/tinkerbell/tink/internal/agent/workflow"
+	"github.com/tinkerbell/tink/internal/registry"
+	"k8s.io/apimachinery/pkg/util/rand"
+)
+
+const (
+	// DefaultContainerdAddress is the default address of the containerd socket.
+	DefaultContainerdAddress = "/run/containerd/containerd.sock"
+
+	// DefaultContainerdNamespace is the default containerd namespace actions are run in.
+	DefaultContainerdNamespace = "tinkerbell"
+)
+
+// containerdStopTimeout is the time a task has to exit after being sent SIGTERM before it is
+// killed.
+const containerdStopTimeout = 5 * time.Second
+
+var (
+	_ agent.ContainerRuntime = &Containerd{}
+	_ agent.ImagePuller      = &Containerd{}
+)
+
+// Containerd is a containerd runtime that satisfies agent.ContainerRuntime.
+//
+// containerd doesn't configure container networking so actions share the host network namespace
+// unless the action specifies a network namespace. A network namespace of 'none' runs the action
+// in an isolated network namespace and an absolute path joins the network namespace at the path.
+//
+// Images are pulled from the mirror of their registry, if any, using the registry's credentials.
+// Images pinned by digest are pulled once and reused by subsequent actions unless pulled again
+// with PullImage. Other images are pulled for every action so tags that move, such as latest, are
+// refreshed.
+type Containerd struct {
+	log       logr.Logger
+	client    *containerd.Client
+	address   string
+	namespace string
+	registry  *registry.Registry
+
+	// pulled is the set of image references pinned by digest that have been pulled.
+	pulled sync.Map
+}
+
+// Run satisfies agent.ContainerRuntime.
+func (c *Containerd) Run(ctx context.Context, a workflow.Action, stdout, stderr io.Writer) (map[string]string, error) {
+	ctx = namespaces.WithNamespace(ctx, c.namespace)
+
+	img, err := c.image(ctx, a.Image)
+	if err != nil {
+		return nil, err
+	}
+
+	failureFiles, err := internal.NewFailureFiles()
+	if err != nil {
+		return nil, fmt.Errorf("create action failure files: %w", err)
+	}
+	defer failureFiles.Close()
+
+	outputFile, err := internal.NewOutputFile()
+	if err != nil {
+		return nil, fmt.Errorf("create action output file: %w", err)
+	}
+	defer outputFile.Close()
+
+	volumes, err := toContainerdMounts(a.Volumes)
+	if err != nil {
+		return nil, fmt.Errorf("containerd: %w", err)
+	}
+	mounts := append([]specs.Mount{
+		bindMount(failureFiles.ReasonPath(), ReasonMountPath, false),
+		bindMount(failureFiles.MessagePath(), MessageMountPath, false),
+		bindMount(outputFile.Path(), OutputsMountPath, false),
+	}, volumes...)
+
+	netns, err := toContainerdNetworkNamespace(a.NetworkNamespace)
+	if err != nil {
+		return nil, fmt.Errorf("containerd: %w", err)
+	}
+
+	specOpts := []oci.SpecOpts{
+		toContainerdProcessArgs(img, a.Cmd, a.Args),
+		oci.WithEnv(toEnv(a.Env)),
+		oci.WithMounts(mounts),
+		netns,
+	}
+	specOpts = append(specOpts, toContainerdResources(a.Resources)...)
+
+	id := toContainerdID(a.ID)
+	cntr, err := c.client.NewContainer(
+		ctx,
+		id,
+		containerd.WithImage(img),
+		containerd.WithNewSnapshot(id, img),
+		containerd.WithNewSpec(specOpts...),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("containerd: %w", err)
+	}
+
+	// We can't use the context passed to Run() for cleanup as it may have been cancelled so we
+	// detach from its cancellation instead.
+	cleanupCtx := context.WithoutCancel(ctx)
+
+	// Always try to remove the container, and its snapshot, on exit.
+	defer func() {
+		if err := cntr.Delete(cleanupCtx, containerd.WithSnapshotCleanup); err != nil {
+			c.log.Info("Couldn't remove container", "container_id", id, "error", err)
+		}
+	}()
+
+	task, err := cntr.NewTask(ctx, cio.NewCreator(cio.WithStreams(nil, stdout, stderr)))
+	if err != nil {
+		return nil, fmt.Errorf("containerd: %w", err)
+	}
+
+	// Deleting the task waits for its output to be written so it must be deleted before the
+	// container.
+	defer func() {
+		if _, err := task.Delete(cleanupCtx, containerd.WithProcessKill); err != nil {
+			c.log.Info("Couldn't delete task", "container_id", id, "error", err)
+		}
+	}()
+
+	// Wait must be called before Start to guarantee the exit status is received.
+	exit, err := task.Wait(cleanupCtx)
+	if err != nil {
+		return nil, fmt.Errorf("containerd: %w", err)
+	}
+
+	if err := task.Start(ctx); err != nil {
+		return nil, fmt.Errorf("containerd: %w", err)
+	}
+
+	select {
+	case status := <-exit:
+		code, _, err := status.Result()
+		if err != nil {
+			return nil, fmt.Errorf("containerd: %w", err)
+		}
+		if code != 0 {
+			return nil, failureFiles.ToError()
+		}
+		outputs, err := outputFile.Outputs()
+		if err != nil {
+			return nil, failure.WithReason(fmt.Errorf("read action outputs: %w", err), ReasonInvalidOutputs)
+		}
+		return outputs, nil
+
+	case <-ctx.Done():
+		if err := task.Kill(cleanupCtx, syscall.SIGTERM); err != nil {
+			c.log.Info("Failed to gracefully stop task", "error", err)
+		}
+		select {
+		case <-exit:
+		case <-time.After(containerdStopTimeout):
+			// The task is killed when it's deleted if this fails.
+			if err := task.Kill(cleanupCtx, syscall.SIGKILL); err != nil {
+				c.log.Info("Failed to kill task", "error", err)
+			}
+		}
+		return nil, fmt.Errorf("containerd: %w", ctx.Err())
+	}
+}
+
+// PullImage satisfies agent.ImagePuller. Actions using image don't pull it again.
+func (c *Containerd) PullImage(ctx context.Context, image string) error {
+	ctx = namespaces.WithNamespace(ctx, c.namespace)
+
+	ref, err := c.imageRef(image)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.pull(ctx, ref)
+	return err
+}
+
+// image retrieves the image for an action, pulling it unless it has already been pulled.
+func (c *Containerd) image(ctx context.Context, image string) (containerd.Image, error) {
+	ref, err := c.imageRef(image)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := c.pulled.Load(ref); ok {
+		img, err := c.client.GetImage(ctx, ref)
+		if err == nil {
+			return img, nil
+		}
+		c.log.Info("Couldn't retrieve pulled image; pulling again", "image", ref, "error", err)
+	}
+
+	return c.pull(ctx, ref)
+}
+
+// imageRef resolves image against the registry mirrors and normalizes it.
+func (c *Containerd) imageRef(image string) (string, error) {
+	resolved, err := c.registry.Resolve(image)
+	if err != nil {
+		return "", fmt.Errorf("containerd: %w", err)
+	}
+
+	ref, err := normalizeImageRef(resolved)
+	if err != nil {
+		return "", fmt.Errorf("containerd: %w", err)
+	}
+	return ref, nil
+}
+
+// pull pulls ref, retrying on failure, and records it as pulled when it's pinned by digest.
+func (c *Containerd) pull(ctx context.Context, ref string) (containerd.Image, error) {
+	resolver := c.registry.Resolver()
+
+	var img containerd.Image
+	pullImage := func() error {
+		// Images must be unpacked into the snapshotter before they can be used to create a
+		// container.
+		var err error
+		img, err = c.client.Pull(ctx, ref, containerd.WithPullUnpack, containerd.WithResolver(resolver))
+		if err != nil {
+			return fmt.Errorf("containerd: %w", err)
+		}
+		return nil
+	}
+
+	err := retry.Do(pullImage, retry.Attempts(5), retry.DelayType(retry.BackOffDelay), retry.Context(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	if registry.Pinned(ref) {
+		c.pulled.Store(ref, struct{}{})
+	}
+	return img, nil
+}
+
+// normalizeImageRef converts image to the fully qualified reference required by containerd. For
+// example, 'alpine' is converted to 'docker.io/library/alpine:latest'.
+func normalizeImageRef(image string) (string, error) {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return "", fmt.Errorf("parse image reference: %w", err)
+	}
+	return reference.TagNameOnly(named).String(), nil
+}
+
+// toContainerdProcessArgs returns an option that configures the process launched in the
+// container. The Tink Action Cmd property is modeled as being the command launched in the
+// container so, consistent with Docker, it replaces the image entrypoint and Args replace the
+// image command.
+func toContainerdProcessArgs(img containerd.Image, cmd string, args []string) oci.SpecOpts {
+	switch {
+	case cmd != "":
+		return oci.Compose(oci.WithImageConfig(img), oci.WithProcessArgs(append([]string{cmd}, args...)...))
+	case len(args) > 0:
+		return oci.WithImageConfigArgs(img, args)
+	default:
+		return oci.WithImageConfig(img)
+	}
+}
+
+// toContainerdNetworkNamespace returns an option that configures the network namespace of the
+// container from an action's network namespace.
+func toContainerdNetworkNamespace(ns string) (oci.SpecOpts, error) {
+	switch {
+	case ns == "" || ns == "host":
+		return oci.Compose(
+			oci.WithHostNamespace(specs.NetworkNamespace),
+			oci.WithHostHostsFile,
+			oci.WithHostResolvconf,
+		), nil
+	case ns == "none":
+		return oci.WithLinuxNamespace(specs.LinuxNamespace{Type: specs.NetworkNamespace}), nil
+	case strings.HasPrefix(ns, "/"):
+		return oci.WithLinuxNamespace(specs.LinuxNamespace{Type: specs.NetworkNamespace, Path: ns}), nil
+	default:
+		return nil, fmt.Errorf("unsupported network namespace: %v", ns)
+	}
+}
+
+// cpuCFSPeriod is the CFS scheduling period, in microseconds, used to enforce CPU limits. It
+// matches the period used by Docker.
+const cpuCFSPeriod = 100000
+
+// toContainerdResources converts the resource limits of an action to spec options.
+func toContainerdResources(r workflow.Resources) []oci.SpecOpts {
+	var opts []oci.SpecOpts
+	if r.Memory > 0 {
+		opts = append(opts, oci.WithMemoryLimit(uint64(r.Memory)))
+	}
+	if r.NanoCPUs > 0 {
+		opts = append(opts, oci.WithCPUCFS(r.NanoCPUs*cpuCFSPeriod/1e9, cpuCFSPeriod))
+	}
+	if r.PIDs > 0 {
+		opts = append(opts, oci.WithPidsLimit(r.PIDs))
+	}
+	return opts
+}
+
+// toContainerdMounts converts action volumes of the form '/source:/destination[:ro|rw]' to bind
+// mounts. Named volumes aren't supported by containerd.
+func toContainerdMounts(volumes []string) ([]specs.Mount, error) {
+	var mounts []specs.Mount
+	for _, v := range volumes {
+		vol, err := parseVolume(v)
+		if err != nil {
+			return nil, err
+		}
+		if !vol.IsBind() {
+			return nil, fmt.Errorf("invalid volume: %v: named volumes are not supported", v)
+		}
+		mounts = append(mounts, bindMount(vol.Source, vol.Destination, vol.ReadOnly))
+	}
+	return mounts, nil
+}
+
+func bindMount(source, destination string, readOnly bool) specs.Mount {
+	mode := "rw"
+	if readOnly {
+		mode = "ro"
+	}
+	return specs.Mount{
+		Type:        "bind",
+		Source:      source,
+		Destination: destination,
+		Options:     []string{"rbind", mode},
+	}
+}
+
+var invalidContainerdIDChars = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+
+// toContainerdID converts an action ID into a usable container ID. containerd IDs must separate
+// alphanumeric components with a single separator.
+func toContainerdID(actionID string) string {
+	id := strings.Trim(invalidContainerdIDChars.ReplaceAllString(actionID, "-"), "-")
+	if id == "" {
+		return fmt.Sprintf("tinkerbell-%s", rand.String(6))
+	}
+	return fmt.Sprintf("tinkerbell-%s-%s", id, rand.String(6))
+}
+
+// NewContainerd creates a new Containerd instance.
+func NewContainerd(opts ...ContainerdOption) (*Containerd, error) {
+	o := &Containerd{
+		log:       logr.Discard(),
+		address:   DefaultContainerdAddress,
+		namespace: DefaultContainerdNamespace,
+		registry:  registry.New(),
+	}
+
+	for _, fn := range opts {
+		fn(o)
+	}
+
+	if o.namespace == "" {
+		return nil, errors.New("containerd namespace must not be empty")
+	}
+
+	if o.client == nil {
+		var err error
+		o.client, err = containerd.New(o.address)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return o, nil
+}
+
+// ContainerdOption defines optional configuration for a Containerd instance.
+type ContainerdOption func(*Containerd)
+
+// WithContainerdLogger returns an option to configure the logger on a Containerd instance.
+func WithContainerdLogger(log logr.Logger) ContainerdOption {
+	return func(o *Containerd) {
+		if log.GetSink() == nil {
+			return
+		}
+		o.log = log
+	}
+}
+
+// WithContainerdAddress returns an option to configure the address of the containerd socket a
+// Containerd instance connects to. It has no effect when a client is configured.
+func WithContainerdAddress(address string) ContainerdOption {
+	return func(o *Containerd) {
+		if address == "" {
+			return
+		}
+		o.address = address
+	}
+}
+
+// WithContainerdNamespace returns an option to configure the containerd namespace actions are
+// run in.
+func WithContainerdNamespace(namespace string) ContainerdOption {
+	return func(o *Containerd) {
+		o.namespace = namespace
+	}
+}
+
+// WithContainerdRegistry returns an option to configure the registry mirrors and credentials
+// used to pull images on a Containerd instance.
+func WithContainerdRegistry(r *registry.Registry) ContainerdOption {
+	return func(o *Containerd) {
+		if r == nil {
+			return
+		}
+		o.registry = r
+	}
+}
+
+// WithContainerdClient returns an option to configure a containerd client on a Containerd
+// instance.
+func WithContainerdClient(clnt *containerd.Client) ContainerdOption {
+	return func(o *Containerd) {
+		if clnt == nil {
+			return
+		}
+		o.client = clnt
+	}
+}
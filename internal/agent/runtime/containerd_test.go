@@ -0,0 +1,178 @@
+package runtime
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/containerd/containerd/containers"
+	"github.com/containerd/containerd/oci"
+	"github.com/google/go-cmp/cmp"
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/tinkerbell/tink/internal/agent/workflow"
+	"github.com/tinkerbell/tink/internal/ptr"
+)
+
+func TestToContainerdMounts(t *testing.T) {
+	cases := []struct {
+		Name    string
+		Volumes []string
+		Mounts  []specs.Mount
+		Error   bool
+	}{
+		{
+			Name: "NoVolumes",
+		},
+		{
+			Name:    "Volumes",
+			Volumes: []string{"/dev:/dev", "/etc/ssl:/etc/ssl:ro", "/tmp:/tmp:rw"},
+			Mounts: []specs.Mount{
+				{Type: "bind", Source: "/dev", Destination: "/dev", Options: []string{"rbind", "rw"}},
+				{Type: "bind", Source: "/etc/ssl", Destination: "/etc/ssl", Options: []string{"rbind", "ro"}},
+				{Type: "bind", Source: "/tmp", Destination: "/tmp", Options: []string{"rbind", "rw"}},
+			},
+		},
+		{
+			Name:    "MissingDestination",
+			Volumes: []string{"/dev"},
+			Error:   true,
+		},
+		{
+			Name:    "NamedVolume",
+			Volumes: []string{"data:/data"},
+			Error:   true,
+		},
+		{
+			Name:    "InvalidMode",
+			Volumes: []string{"/dev:/dev:z"},
+			Error:   true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			mounts, err := toContainerdMounts(tc.Volumes)
+			if tc.Error != (err != nil) {
+				t.Fatalf("Expected error: %v; Received: %v", tc.Error, err)
+			}
+			if diff := cmp.Diff(tc.Mounts, mounts); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestToContainerdNetworkNamespace(t *testing.T) {
+	cases := []struct {
+		Name      string
+		Namespace string
+		Expect    []specs.LinuxNamespace
+		Error     bool
+	}{
+		{
+			Name:   "Default",
+			Expect: []specs.LinuxNamespace{{Type: specs.PIDNamespace}},
+		},
+		{
+			Name:      "Host",
+			Namespace: "host",
+			Expect:    []specs.LinuxNamespace{{Type: specs.PIDNamespace}},
+		},
+		{
+			Name:      "None",
+			Namespace: "none",
+			Expect:    []specs.LinuxNamespace{{Type: specs.PIDNamespace}, {Type: specs.NetworkNamespace}},
+		},
+		{
+			Name:      "Path",
+			Namespace: "/var/run/netns/provisioning",
+			Expect: []specs.LinuxNamespace{
+				{Type: specs.PIDNamespace},
+				{Type: specs.NetworkNamespace, Path: "/var/run/netns/provisioning"},
+			},
+		},
+		{
+			Name:      "Unsupported",
+			Namespace: "bridge",
+			Error:     true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			opt, err := toContainerdNetworkNamespace(tc.Namespace)
+			if tc.Error != (err != nil) {
+				t.Fatalf("Expected error: %v; Received: %v", tc.Error, err)
+			}
+			if err != nil {
+				return
+			}
+
+			spec := oci.Spec{
+				Linux: &specs.Linux{
+					Namespaces: []specs.LinuxNamespace{
+						{Type: specs.PIDNamespace},
+						{Type: specs.NetworkNamespace},
+					},
+				},
+			}
+			if err := opt(context.Background(), nil, &containers.Container{}, &spec); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.Expect, spec.Linux.Namespaces); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestToContainerdResources(t *testing.T) {
+	spec := oci.Spec{Linux: &specs.Linux{}}
+	for _, opt := range toContainerdResources(workflow.Resources{Memory: 512 << 20, NanoCPUs: 1_500_000_000, PIDs: 100}) {
+		if err := opt(context.Background(), nil, &containers.Container{}, &spec); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expect := &specs.LinuxResources{
+		Memory: &specs.LinuxMemory{Limit: ptr.Int64(512 << 20)},
+		CPU:    &specs.LinuxCPU{Quota: ptr.Int64(150000), Period: ptr.Uint64(100000)},
+		Pids:   &specs.LinuxPids{Limit: 100},
+	}
+	if diff := cmp.Diff(expect, spec.Linux.Resources); diff != "" {
+		t.Fatal(diff)
+	}
+
+	if opts := toContainerdResources(workflow.Resources{}); len(opts) != 0 {
+		t.Fatalf("Expected no options for unlimited resources; received %v", len(opts))
+	}
+}
+
+func TestNormalizeImageRef(t *testing.T) {
+	cases := map[string]string{
+		"alpine":                       "docker.io/library/alpine:latest",
+		"quay.io/tinkerbell/actions:1": "quay.io/tinkerbell/actions:1",
+		"localhost:5000/image@sha256:0123456789012345678901234567890123456789012345678901234567890123": "localhost:5000/image@sha256:0123456789012345678901234567890123456789012345678901234567890123",
+	}
+
+	for image, expect := range cases {
+		ref, err := normalizeImageRef(image)
+		if err != nil {
+			t.Fatalf("%v: %v", image, err)
+		}
+		if ref != expect {
+			t.Fatalf("%v: expected %v; received %v", image, expect, ref)
+		}
+	}
+}
+
+func TestToContainerdID(t *testing.T) {
+	// containerd requires alphanumeric components separated by a single separator.
+	valid := regexp.MustCompile(`^[A-Za-z0-9]+(?:[._-][A-Za-z0-9]+)*$`)
+
+	for _, actionID := range []string{"action", "my__action", "-action/1-", "!!"} {
+		if id := toContainerdID(actionID); !valid.MatchString(id) {
+			t.Fatalf("Invalid containerd ID for action %q: %v", actionID, id)
+		}
+	}
+}
@@ -5,78 +5,119 @@ import (
 	"fmt"
 	"io"
 	"regexp"
+	"strings"
+	"sync"
 
 	retry "github.com/avast/retry-go"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/image"
 	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	dockerregistry "github.com/docker/docker/api/types/registry"
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/go-logr/logr"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/tinkerbell/tink/internal/agent"
+	"github.com/tinkerbell/tink/internal/agent/failure"
 	"github.com/tinkerbell/tink/internal/agent/runtime/internal"
 	"github.com/tinkerbell/tink/internal/agent/workflow"
 	"github.com/tinkerbell/tink/internal/ptr"
+	"github.com/tinkerbell/tink/internal/registry"
 	"k8s.io/apimachinery/pkg/util/rand"
 )
 
-var _ agent.ContainerRuntime = &Docker{}
+var (
+	_ agent.ContainerRuntime = &Docker{}
+	_ agent.ImagePuller      = &Docker{}
+)
 
 // Docker is a docker runtime that satisfies agent.ContainerRuntime.
+//
+// Action volumes with an absolute source are bind mounted and other volumes are named volumes that
+// are created if they don't exist. Network and PID namespaces are Docker network and PID modes
+// such as 'host'; Docker can't join namespaces by path.
+//
+// Images are pulled from the mirror of their registry, if any, using the registry's credentials.
+// Images pinned by digest are pulled once and reused by subsequent actions unless pulled again
+// with PullImage. Other images are pulled for every action so tags that move, such as latest, are
+// refreshed.
 type Docker struct {
-	log    logr.Logger
-	client *client.Client
+	log      logr.Logger
+	client   DockerClient
+	registry *registry.Registry
+
+	// pulled is the set of image references pinned by digest that have been pulled.
+	pulled sync.Map
 }
 
-// Run satisfies agent.ContainerRuntime.
-func (d *Docker) Run(ctx context.Context, a workflow.Action) error {
-	pullImage := func() error {
-		// We need the image to be available before we can create a container.
-		img, err := d.client.ImagePull(ctx, a.Image, image.PullOptions{})
-		if err != nil {
-			return fmt.Errorf("docker: %w", err)
-		}
-		defer img.Close()
+// DockerClient is the subset of the Docker API used by Docker. It's satisfied by *client.Client.
+type DockerClient interface {
+	ImagePull(ctx context.Context, ref string, options image.PullOptions) (io.ReadCloser, error)
+	ContainerCreate(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, platform *ocispec.Platform, containerName string) (container.CreateResponse, error)
+	ContainerLogs(ctx context.Context, container string, options container.LogsOptions) (io.ReadCloser, error)
+	ContainerWait(ctx context.Context, container string, condition container.WaitCondition) (<-chan container.WaitResponse, <-chan error)
+	ContainerStart(ctx context.Context, container string, options container.StartOptions) error
+	ContainerStop(ctx context.Context, container string, options container.StopOptions) error
+	ContainerRemove(ctx context.Context, container string, options container.RemoveOptions) error
+}
 
-		// Docker requires everything to be read from the images ReadCloser for the image to actually
-		// be pulled. We may want to log image pulls in a circular buffer somewhere for debugability.
-		if _, err = io.Copy(io.Discard, img); err != nil {
-			return fmt.Errorf("docker: %w", err)
-		}
+var _ DockerClient = &client.Client{}
 
-		return nil
+// Run satisfies agent.ContainerRuntime.
+func (d *Docker) Run(ctx context.Context, a workflow.Action, stdout, stderr io.Writer) (map[string]string, error) {
+	// Validate the action before pulling its image.
+	hostCfg, err := toDockerHostConfig(a)
+	if err != nil {
+		return nil, fmt.Errorf("docker: %w", err)
 	}
 
-	err := retry.Do(pullImage, retry.Attempts(5), retry.DelayType(retry.BackOffDelay))
+	ref, pullOpts, err := d.pullOptions(a.Image)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("docker: %w", err)
+	}
+
+	// We need the image to be available before we can create a container.
+	if _, ok := d.pulled.Load(ref); !ok {
+		if err := d.pull(ctx, ref, pullOpts); err != nil {
+			return nil, err
+		}
 	}
 
-	// TODO: Support all the other things on the action such as volumes.
 	cfg := container.Config{
-		Image: a.Image,
-		Env:   toDockerEnv(a.Env),
+		Image: ref,
+		Env:   toEnv(a.Env),
 	}
 
 	failureFiles, err := internal.NewFailureFiles()
 	if err != nil {
-		return fmt.Errorf("create action failure files: %w", err)
+		return nil, fmt.Errorf("create action failure files: %w", err)
 	}
 	defer failureFiles.Close()
 
-	hostCfg := container.HostConfig{
-		Mounts: []mount.Mount{
-			{
-				Type:   mount.TypeBind,
-				Source: failureFiles.ReasonPath(),
-				Target: ReasonMountPath,
-			},
-			{
-				Type:   mount.TypeBind,
-				Source: failureFiles.MessagePath(),
-				Target: MessageMountPath,
-			},
-		},
+	outputFile, err := internal.NewOutputFile()
+	if err != nil {
+		return nil, fmt.Errorf("create action output file: %w", err)
 	}
+	defer outputFile.Close()
+
+	hostCfg.Mounts = append([]mount.Mount{
+		{
+			Type:   mount.TypeBind,
+			Source: failureFiles.ReasonPath(),
+			Target: ReasonMountPath,
+		},
+		{
+			Type:   mount.TypeBind,
+			Source: failureFiles.MessagePath(),
+			Target: MessageMountPath,
+		},
+		{
+			Type:   mount.TypeBind,
+			Source: outputFile.Path(),
+			Target: OutputsMountPath,
+		},
+	}, hostCfg.Mounts...)
 
 	containerName := toContainerName(a.ID)
 
@@ -90,13 +131,14 @@ func (d *Docker) Run(ctx context.Context, a workflow.Action) error {
 		cfg.Cmd = append(cfg.Cmd, a.Args...)
 	}
 
-	// TODO: Figure out container logging. We probably want to save it somewhere for debugability.
-
 	create, err := d.client.ContainerCreate(ctx, &cfg, &hostCfg, nil, nil, containerName)
 	if err != nil {
-		return fmt.Errorf("docker: %w", err)
+		return nil, fmt.Errorf("docker: %w", err)
 	}
 
+	// logsDone is closed once the container's output has been written.
+	var logsDone chan struct{}
+
 	// Always try to remove the container on exit.
 	defer func() {
 		// Force remove containers in an attempt to preserve space in memory constraints environments.
@@ -109,6 +151,30 @@ func (d *Docker) Run(ctx context.Context, a workflow.Action) error {
 		if err != nil {
 			d.log.Info("Couldn't remove container", "container_name", containerName, "error", err)
 		}
+
+		// The output ends when the container exits so we wait for it to be written only once the
+		// container has been removed.
+		if logsDone != nil {
+			<-logsDone
+		}
+	}()
+
+	// Follow the container's output before starting it so no output is missed.
+	logs, err := d.client.ContainerLogs(ctx, create.ID, container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("docker: %w", err)
+	}
+	logsDone = make(chan struct{})
+	go func() {
+		defer close(logsDone)
+		defer logs.Close()
+		if _, err := stdcopy.StdCopy(stdout, stderr, logs); err != nil {
+			d.log.Info("Failed to copy container output", "container_name", containerName, "error", err)
+		}
 	}()
 
 	// Issue the wait with a 'next-exit' condition so we can await a response originating from
@@ -116,18 +182,22 @@ func (d *Docker) Run(ctx context.Context, a workflow.Action) error {
 	waitBody, waitErr := d.client.ContainerWait(ctx, create.ID, container.WaitConditionNextExit)
 
 	if err := d.client.ContainerStart(ctx, create.ID, container.StartOptions{}); err != nil {
-		return fmt.Errorf("docker: %w", err)
+		return nil, fmt.Errorf("docker: %w", err)
 	}
 
 	select {
 	case result := <-waitBody:
-		if result.StatusCode == 0 {
-			return nil
+		if result.StatusCode != 0 {
+			return nil, failureFiles.ToError()
 		}
-		return failureFiles.ToError()
+		outputs, err := outputFile.Outputs()
+		if err != nil {
+			return nil, failure.WithReason(fmt.Errorf("read action outputs: %w", err), ReasonInvalidOutputs)
+		}
+		return outputs, nil
 
 	case err := <-waitErr:
-		return fmt.Errorf("docker: %w", err)
+		return nil, fmt.Errorf("docker: %w", err)
 
 	case <-ctx.Done():
 		// We can't use the context passed to Run() as its been cancelled.
@@ -137,8 +207,158 @@ func (d *Docker) Run(ctx context.Context, a workflow.Action) error {
 		if err != nil {
 			d.log.Info("Failed to gracefully stop container", "error", err)
 		}
-		return fmt.Errorf("docker: %w", ctx.Err())
+		return nil, fmt.Errorf("docker: %w", ctx.Err())
+	}
+}
+
+// PullImage satisfies agent.ImagePuller. Actions using image don't pull it again.
+func (d *Docker) PullImage(ctx context.Context, img string) error {
+	ref, pullOpts, err := d.pullOptions(img)
+	if err != nil {
+		return fmt.Errorf("docker: %w", err)
+	}
+	return d.pull(ctx, ref, pullOpts)
+}
+
+// pull pulls ref, retrying on failure, and records it as pulled when it's pinned by digest.
+func (d *Docker) pull(ctx context.Context, ref string, opts image.PullOptions) error {
+	pullImage := func() error {
+		img, err := d.client.ImagePull(ctx, ref, opts)
+		if err != nil {
+			return fmt.Errorf("docker: %w", err)
+		}
+		defer img.Close()
+
+		// Docker requires everything to be read from the images ReadCloser for the image to actually
+		// be pulled. We may want to log image pulls in a circular buffer somewhere for debugability.
+		if _, err = io.Copy(io.Discard, img); err != nil {
+			return fmt.Errorf("docker: %w", err)
+		}
+
+		return nil
+	}
+
+	err := retry.Do(pullImage, retry.Attempts(5), retry.DelayType(retry.BackOffDelay), retry.Context(ctx))
+	if err != nil {
+		return err
+	}
+
+	if registry.Pinned(ref) {
+		d.pulled.Store(ref, struct{}{})
+	}
+	return nil
+}
+
+// pullOptions resolves image against the registry mirrors and builds the options used to pull it
+// with the credentials of the registry it's pulled from.
+func (d *Docker) pullOptions(img string) (string, image.PullOptions, error) {
+	ref, err := d.registry.Resolve(img)
+	if err != nil {
+		return "", image.PullOptions{}, err
+	}
+
+	host, err := registry.Host(ref)
+	if err != nil {
+		return "", image.PullOptions{}, err
+	}
+
+	auth, ok := d.registry.Auth(host)
+	if !ok {
+		return ref, image.PullOptions{}, nil
+	}
+
+	encoded, err := dockerregistry.EncodeAuthConfig(dockerregistry.AuthConfig{
+		Username:      auth.Username,
+		Password:      auth.Password,
+		IdentityToken: auth.IdentityToken,
+		ServerAddress: host,
+	})
+	if err != nil {
+		return "", image.PullOptions{}, err
 	}
+
+	return ref, image.PullOptions{RegistryAuth: encoded}, nil
+}
+
+// toDockerHostConfig converts the host related properties of a to a Docker host configuration.
+func toDockerHostConfig(a workflow.Action) (container.HostConfig, error) {
+	var cfg container.HostConfig
+
+	for _, v := range a.Volumes {
+		vol, err := parseVolume(v)
+		if err != nil {
+			return container.HostConfig{}, err
+		}
+		typ := mount.TypeVolume
+		if vol.IsBind() {
+			typ = mount.TypeBind
+		}
+		cfg.Mounts = append(cfg.Mounts, mount.Mount{
+			Type:     typ,
+			Source:   vol.Source,
+			Target:   vol.Destination,
+			ReadOnly: vol.ReadOnly,
+		})
+	}
+
+	if strings.HasPrefix(a.NetworkNamespace, "/") {
+		return container.HostConfig{}, fmt.Errorf("unsupported network namespace: %v", a.NetworkNamespace)
+	}
+	cfg.NetworkMode = container.NetworkMode(a.NetworkNamespace)
+
+	if strings.HasPrefix(a.PIDNamespace, "/") {
+		return container.HostConfig{}, fmt.Errorf("unsupported pid namespace: %v", a.PIDNamespace)
+	}
+	cfg.PidMode = container.PidMode(a.PIDNamespace)
+
+	cfg.Privileged = a.Privileged
+	cfg.CapAdd = a.Capabilities.Add
+	cfg.CapDrop = a.Capabilities.Drop
+
+	for _, d := range a.Devices {
+		device, err := parseDevice(d)
+		if err != nil {
+			return container.HostConfig{}, err
+		}
+		cfg.Devices = append(cfg.Devices, device)
+	}
+
+	cfg.Memory = a.Resources.Memory
+	cfg.NanoCPUs = a.Resources.NanoCPUs
+	if a.Resources.PIDs > 0 {
+		cfg.PidsLimit = &a.Resources.PIDs
+	}
+
+	return cfg, nil
+}
+
+// parseDevice parses a device of the form '/host-path[:/container-path[:permissions]]'.
+func parseDevice(d string) (container.DeviceMapping, error) {
+	parts := strings.Split(d, ":")
+	if len(parts) > 3 {
+		return container.DeviceMapping{}, fmt.Errorf("invalid device: %v", d)
+	}
+
+	device := container.DeviceMapping{
+		PathOnHost:        parts[0],
+		PathInContainer:   parts[0],
+		CgroupPermissions: "rwm",
+	}
+	if len(parts) > 1 {
+		device.PathInContainer = parts[1]
+	}
+	if len(parts) > 2 {
+		device.CgroupPermissions = parts[2]
+	}
+
+	if !strings.HasPrefix(device.PathOnHost, "/") || !strings.HasPrefix(device.PathInContainer, "/") {
+		return container.DeviceMapping{}, fmt.Errorf("invalid device: %v: paths must be absolute", d)
+	}
+	if device.CgroupPermissions == "" || strings.Trim(device.CgroupPermissions, "rwm") != "" {
+		return container.DeviceMapping{}, fmt.Errorf("invalid device: %v: unsupported permissions %v", d, device.CgroupPermissions)
+	}
+
+	return device, nil
 }
 
 var validContainerName = regexp.MustCompile(`[^a-zA-Z0-9_.-]`)
@@ -153,7 +373,7 @@ func toContainerName(actionID string) string {
 	)
 }
 
-func toDockerEnv(env map[string]string) []string {
+func toEnv(env map[string]string) []string {
 	var de []string
 	for k, v := range env {
 		de = append(de, fmt.Sprintf("%v=%v", k, v))
@@ -164,7 +384,8 @@ func toDockerEnv(env map[string]string) []string {
 // NewDocker creates a new Docker instance.
 func NewDocker(opts ...DockerOption) (*Docker, error) {
 	o := &Docker{
-		log: logr.Discard(),
+		log:      logr.Discard(),
+		registry: registry.New(),
 	}
 
 	var err error
@@ -193,8 +414,19 @@ func WithLogger(log logr.Logger) DockerOption {
 	}
 }
 
+// WithRegistry returns an option to configure the registry mirrors and credentials used to pull
+// images on a Docker instance.
+func WithRegistry(r *registry.Registry) DockerOption {
+	return func(o *Docker) {
+		if r == nil {
+			return
+		}
+		o.registry = r
+	}
+}
+
 // WithClient returns an option to configure a Docker client on a Docker instance.
-func WithClient(clnt *client.Client) DockerOption {
+func WithClient(clnt DockerClient) DockerOption {
 	return func(o *Docker) {
 		if clnt == nil {
 			return
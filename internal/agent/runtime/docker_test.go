@@ -4,20 +4,29 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/go-logr/zerologr"
+	"github.com/google/go-cmp/cmp"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/rs/zerolog"
 	"github.com/tinkerbell/tink/internal/agent/failure"
 	"github.com/tinkerbell/tink/internal/agent/runtime"
 	"github.com/tinkerbell/tink/internal/agent/workflow"
+	"github.com/tinkerbell/tink/internal/ptr"
+	"github.com/tinkerbell/tink/internal/registry"
 	"go.uber.org/multierr"
 
+	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	dockerregistry "github.com/docker/docker/api/types/registry"
 	"github.com/docker/docker/client"
 )
 
@@ -58,7 +67,7 @@ func TestDockerImageNotPresent(t *testing.T) {
 		Image: img,
 	}
 
-	err = rt.Run(context.Background(), action)
+	_, err = rt.Run(context.Background(), action, io.Discard, io.Discard)
 	if err != nil {
 		t.Fatalf("Received unexpected error: %v", err)
 	}
@@ -106,7 +115,7 @@ func TestDockerFailureMessages(t *testing.T) {
 				t.Fatal(err.Error())
 			}
 
-			err = rt.Run(context.Background(), tc.Action)
+			_, err = rt.Run(context.Background(), tc.Action, io.Discard, io.Discard)
 			reason, ok := failure.Reason(err)
 
 			switch {
@@ -151,7 +160,7 @@ func TestDockerContextTimeout(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 	defer cancel()
 
-	err = rt.Run(ctx, action)
+	_, err = rt.Run(ctx, action, io.Discard, io.Discard)
 
 	if err == nil {
 		t.Fatal("Expected error but received none")
@@ -161,6 +170,324 @@ func TestDockerContextTimeout(t *testing.T) {
 	}
 }
 
+func TestDockerHostConfig(t *testing.T) {
+	cases := []struct {
+		Name   string
+		Action workflow.Action
+		Expect container.HostConfig
+		Error  bool
+	}{
+		{
+			Name:   "Defaults",
+			Action: workflow.Action{},
+		},
+		{
+			Name: "Volumes",
+			Action: workflow.Action{
+				Volumes: []string{"/dev:/dev", "/etc/ssl:/etc/ssl:ro", "shared:/data:rw"},
+			},
+			Expect: container.HostConfig{
+				Mounts: []mount.Mount{
+					{Type: mount.TypeBind, Source: "/dev", Target: "/dev"},
+					{Type: mount.TypeBind, Source: "/etc/ssl", Target: "/etc/ssl", ReadOnly: true},
+					{Type: mount.TypeVolume, Source: "shared", Target: "/data"},
+				},
+			},
+		},
+		{
+			Name: "Namespaces",
+			Action: workflow.Action{
+				NetworkNamespace: "host",
+				PIDNamespace:     "host",
+			},
+			Expect: container.HostConfig{
+				NetworkMode: "host",
+				PidMode:     "host",
+			},
+		},
+		{
+			Name: "PrivilegedAndCapabilities",
+			Action: workflow.Action{
+				Privileged: true,
+				Capabilities: workflow.Capabilities{
+					Add:  []string{"SYS_ADMIN"},
+					Drop: []string{"NET_RAW"},
+				},
+			},
+			Expect: container.HostConfig{
+				Privileged: true,
+				CapAdd:     []string{"SYS_ADMIN"},
+				CapDrop:    []string{"NET_RAW"},
+			},
+		},
+		{
+			Name: "Devices",
+			Action: workflow.Action{
+				Devices: []string{"/dev/sda", "/dev/sdb:/dev/disk", "/dev/sdc:/dev/sdc:r"},
+			},
+			Expect: container.HostConfig{
+				Resources: container.Resources{
+					Devices: []container.DeviceMapping{
+						{PathOnHost: "/dev/sda", PathInContainer: "/dev/sda", CgroupPermissions: "rwm"},
+						{PathOnHost: "/dev/sdb", PathInContainer: "/dev/disk", CgroupPermissions: "rwm"},
+						{PathOnHost: "/dev/sdc", PathInContainer: "/dev/sdc", CgroupPermissions: "r"},
+					},
+				},
+			},
+		},
+		{
+			Name: "Resources",
+			Action: workflow.Action{
+				Resources: workflow.Resources{Memory: 512 << 20, NanoCPUs: 500_000_000, PIDs: 100},
+			},
+			Expect: container.HostConfig{
+				Resources: container.Resources{
+					Memory:    512 << 20,
+					NanoCPUs:  500_000_000,
+					PidsLimit: ptr.Int64(100),
+				},
+			},
+		},
+		{
+			Name:   "InvalidVolume",
+			Action: workflow.Action{Volumes: []string{"/dev"}},
+			Error:  true,
+		},
+		{
+			Name:   "InvalidVolumeMode",
+			Action: workflow.Action{Volumes: []string{"/dev:/dev:z"}},
+			Error:  true,
+		},
+		{
+			Name:   "NetworkNamespacePath",
+			Action: workflow.Action{NetworkNamespace: "/var/run/netns/provisioning"},
+			Error:  true,
+		},
+		{
+			Name:   "PIDNamespacePath",
+			Action: workflow.Action{PIDNamespace: "/proc/10/ns/pid"},
+			Error:  true,
+		},
+		{
+			Name:   "RelativeDevice",
+			Action: workflow.Action{Devices: []string{"sda"}},
+			Error:  true,
+		},
+		{
+			Name:   "InvalidDevicePermissions",
+			Action: workflow.Action{Devices: []string{"/dev/sda:/dev/sda:x"}},
+			Error:  true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			clnt := &fakeDockerClient{}
+			rt, err := runtime.NewDocker(runtime.WithClient(clnt))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tc.Action.ID = "action"
+			tc.Action.Image = "alpine"
+
+			_, err = rt.Run(context.Background(), tc.Action, io.Discard, io.Discard)
+			if tc.Error != (err != nil) {
+				t.Fatalf("Expected error: %v; Received: %v", tc.Error, err)
+			}
+
+			if err != nil {
+				if clnt.Pulled {
+					t.Fatal("Image pulled for invalid action")
+				}
+				return
+			}
+
+			// The failure and output files are always mounted first.
+			hostCfg := clnt.HostConfig
+			if len(hostCfg.Mounts) < 3 {
+				t.Fatalf("Missing failure and output file mounts: %v", hostCfg.Mounts)
+			}
+			var targets []string
+			for _, m := range hostCfg.Mounts[:3] {
+				targets = append(targets, m.Target)
+			}
+			expectTargets := []string{runtime.ReasonMountPath, runtime.MessageMountPath, runtime.OutputsMountPath}
+			if diff := cmp.Diff(expectTargets, targets); diff != "" {
+				t.Fatal(diff)
+			}
+
+			hostCfg.Mounts = hostCfg.Mounts[3:]
+			if len(hostCfg.Mounts) == 0 {
+				hostCfg.Mounts = nil
+			}
+			if diff := cmp.Diff(tc.Expect, *hostCfg); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestDockerRegistry(t *testing.T) {
+	reg := registry.New(
+		registry.WithMirror("docker.io", "harbor.example.com/dockerhub"),
+		registry.WithAuths(map[string]registry.Auth{
+			"harbor.example.com": {Username: "robot", Password: "secret"},
+		}),
+	)
+
+	cases := []struct {
+		Name  string
+		Image string
+		Ref   string
+		Auth  *dockerregistry.AuthConfig
+	}{
+		{
+			Name:  "Mirror",
+			Image: "alpine:3",
+			Ref:   "harbor.example.com/dockerhub/library/alpine:3",
+			Auth: &dockerregistry.AuthConfig{
+				Username:      "robot",
+				Password:      "secret",
+				ServerAddress: "harbor.example.com",
+			},
+		},
+		{
+			Name:  "NoCredentials",
+			Image: "quay.io/tinkerbell/action",
+			Ref:   "quay.io/tinkerbell/action",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			clnt := &fakeDockerClient{}
+			rt, err := runtime.NewDocker(runtime.WithClient(clnt), runtime.WithRegistry(reg))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = rt.Run(context.Background(), workflow.Action{ID: "action", Image: tc.Image}, io.Discard, io.Discard)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if clnt.PullRef != tc.Ref {
+				t.Fatalf("Expected pull of %v; Received: %v", tc.Ref, clnt.PullRef)
+			}
+			if clnt.Config.Image != tc.Ref {
+				t.Fatalf("Expected container image %v; Received: %v", tc.Ref, clnt.Config.Image)
+			}
+
+			var auth *dockerregistry.AuthConfig
+			if clnt.PullOptions.RegistryAuth != "" {
+				decoded, err := dockerregistry.DecodeAuthConfig(clnt.PullOptions.RegistryAuth)
+				if err != nil {
+					t.Fatal(err)
+				}
+				auth = decoded
+			}
+			if diff := cmp.Diff(tc.Auth, auth); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestDockerPullImage(t *testing.T) {
+	const pinned = "alpine@sha256:1e42bbe2508154c9126d48c2b8a75420c3544343bf86fd041fb7527e017a4b4a"
+
+	clnt := &fakeDockerClient{}
+	rt, err := runtime.NewDocker(runtime.WithClient(clnt))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := rt.PullImage(context.Background(), pinned); err != nil {
+		t.Fatal(err)
+	}
+
+	// Actions reuse pulled images pinned by digest.
+	for range 2 {
+		_, err := rt.Run(context.Background(), workflow.Action{ID: "action", Image: pinned}, io.Discard, io.Discard)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if clnt.Pulls != 1 {
+		t.Fatalf("Expected 1 pull; Received: %v", clnt.Pulls)
+	}
+
+	// Explicit pulls refresh the image.
+	if err := rt.PullImage(context.Background(), pinned); err != nil {
+		t.Fatal(err)
+	}
+	if clnt.Pulls != 2 {
+		t.Fatalf("Expected 2 pulls; Received: %v", clnt.Pulls)
+	}
+
+	// Tags may move so images that aren't pinned are pulled for every action.
+	if err := rt.PullImage(context.Background(), "alpine:latest"); err != nil {
+		t.Fatal(err)
+	}
+	for range 2 {
+		_, err := rt.Run(context.Background(), workflow.Action{ID: "action", Image: "alpine:latest"}, io.Discard, io.Discard)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if clnt.Pulls != 5 {
+		t.Fatalf("Expected 5 pulls; Received: %v", clnt.Pulls)
+	}
+}
+
+// fakeDockerClient is a runtime.DockerClient that records the image pull and configuration of the
+// created container and reports containers as exiting successfully.
+type fakeDockerClient struct {
+	Pulled      bool
+	Pulls       int
+	PullRef     string
+	PullOptions image.PullOptions
+	Config      *container.Config
+	HostConfig  *container.HostConfig
+}
+
+func (f *fakeDockerClient) ImagePull(_ context.Context, ref string, opts image.PullOptions) (io.ReadCloser, error) {
+	f.Pulled = true
+	f.Pulls++
+	f.PullRef = ref
+	f.PullOptions = opts
+	return io.NopCloser(strings.NewReader("")), nil
+}
+
+func (f *fakeDockerClient) ContainerCreate(_ context.Context, config *container.Config, hostConfig *container.HostConfig, _ *network.NetworkingConfig, _ *ocispec.Platform, name string) (container.CreateResponse, error) {
+	f.Config = config
+	f.HostConfig = hostConfig
+	return container.CreateResponse{ID: name}, nil
+}
+
+func (f *fakeDockerClient) ContainerLogs(context.Context, string, container.LogsOptions) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader("")), nil
+}
+
+func (f *fakeDockerClient) ContainerWait(context.Context, string, container.WaitCondition) (<-chan container.WaitResponse, <-chan error) {
+	wait := make(chan container.WaitResponse, 1)
+	wait <- container.WaitResponse{}
+	return wait, make(chan error)
+}
+
+func (f *fakeDockerClient) ContainerStart(context.Context, string, container.StartOptions) error {
+	return nil
+}
+
+func (f *fakeDockerClient) ContainerStop(context.Context, string, container.StopOptions) error {
+	return nil
+}
+
+func (f *fakeDockerClient) ContainerRemove(context.Context, string, container.RemoveOptions) error {
+	return nil
+}
+
 type command struct {
 	// Reason is the reason to write to /tinkerbell/failure-reason
 	Reason string
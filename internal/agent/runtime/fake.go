@@ -2,6 +2,7 @@ package runtime
 
 import (
 	"context"
+	"io"
 
 	"github.com/go-logr/logr"
 	"github.com/tinkerbell/tink/internal/agent"
@@ -22,7 +23,7 @@ type Fake struct {
 }
 
 // Run satisfies agent.ContainerRuntime.
-func (f Fake) Run(_ context.Context, a workflow.Action) error {
+func (f Fake) Run(_ context.Context, a workflow.Action, _, _ io.Writer) (map[string]string, error) {
 	f.Log.Info("Starting fake container", "action", a)
-	return nil
+	return nil, nil
 }
@@ -28,6 +28,27 @@ func NewFailureFiles() (*FailureFiles, error) {
 	}, nil
 }
 
+// NewFailureFilesAt creates a new FailureFiles instance with underlying files at reasonPath and
+// messagePath. Existing files are truncated. Consumers are responsible for calling
+// FailureFiles.Close().
+func NewFailureFilesAt(reasonPath, messagePath string) (*FailureFiles, error) {
+	reason, err := os.OpenFile(reasonPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	message, err := os.OpenFile(messagePath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		os.Remove(reason.Name())
+		return nil, err
+	}
+
+	return &FailureFiles{
+		reason:  reason,
+		message: message,
+	}, nil
+}
+
 // FailureFiles provides mountable files for runtimes that can be used to extract
 // a reason and message from actions.
 type FailureFiles struct {
@@ -0,0 +1,101 @@
+package internal
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// MaxOutputSize is the maximum size, in bytes, of an output file.
+const MaxOutputSize = 4096
+
+// validOutputKey defines the regex for a valid output key. Keys are restricted to characters
+// valid in environment variable names so outputs can be passed to other actions as environment
+// variables.
+var validOutputKey = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// NewOutputFile creates a new OutputFile instance with an isolated underlying file. Consumers
+// are responsible for calling OutputFile.Close().
+func NewOutputFile() (*OutputFile, error) {
+	f, err := os.CreateTemp("", "outputs-*")
+	if err != nil {
+		return nil, err
+	}
+
+	return &OutputFile{file: f}, nil
+}
+
+// NewOutputFileAt creates a new OutputFile instance with an underlying file at path. An existing
+// file is truncated. Consumers are responsible for calling OutputFile.Close().
+func NewOutputFileAt(path string) (*OutputFile, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OutputFile{file: f}, nil
+}
+
+// OutputFile provides a mountable file for runtimes that can be used to extract outputs from
+// actions. Outputs are written as KEY=VALUE pairs, one per line.
+type OutputFile struct {
+	file *os.File
+}
+
+// Close closes the file tracked by f.
+func (f *OutputFile) Close() error {
+	os.Remove(f.file.Name())
+	return nil
+}
+
+// Path returns the path for the output file.
+func (f *OutputFile) Path() string {
+	return f.file.Name()
+}
+
+// Outputs parses the outputs from the output file. Blank lines are ignored and, when a key is
+// written more than once, the last value is used.
+func (f *OutputFile) Outputs() (map[string]string, error) {
+	// Always seek back to the original point. If this fails, assume the file is missing and so
+	// any further interactions will also receive errors.
+	defer func() {
+		_, _ = f.file.Seek(0, 0)
+	}()
+
+	var content bytes.Buffer
+	if _, err := content.ReadFrom(io.LimitReader(f.file, MaxOutputSize+1)); err != nil {
+		return nil, err
+	}
+	if content.Len() > MaxOutputSize {
+		return nil, fmt.Errorf("outputs exceed %v bytes", MaxOutputSize)
+	}
+
+	outputs := map[string]string{}
+	scanner := bufio.NewScanner(&content)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if !ok {
+			return nil, fmt.Errorf("line %v: expected KEY=VALUE", line)
+		}
+		if !validOutputKey.MatchString(key) {
+			return nil, fmt.Errorf("line %v: invalid key: %q", line, key)
+		}
+		outputs[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(outputs) == 0 {
+		return nil, nil
+	}
+	return outputs, nil
+}
@@ -0,0 +1,74 @@
+package internal_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tinkerbell/tink/internal/agent/runtime/internal"
+)
+
+func TestOutputFile(t *testing.T) {
+	cases := []struct {
+		Name    string
+		Content string
+		Outputs map[string]string
+		Error   bool
+	}{
+		{
+			Name: "Empty",
+		},
+		{
+			Name:    "Outputs",
+			Content: "disk=/dev/sda\n\nPARTITION_1=/dev/sda1\nuuid=a=b\n",
+			Outputs: map[string]string{
+				"disk":        "/dev/sda",
+				"PARTITION_1": "/dev/sda1",
+				"uuid":        "a=b",
+			},
+		},
+		{
+			Name:    "LastValueWins",
+			Content: "disk=/dev/sda\ndisk=/dev/sdb",
+			Outputs: map[string]string{"disk": "/dev/sdb"},
+		},
+		{
+			Name:    "MissingSeparator",
+			Content: "disk",
+			Error:   true,
+		},
+		{
+			Name:    "InvalidKey",
+			Content: "1disk=/dev/sda",
+			Error:   true,
+		},
+		{
+			Name:    "TooLarge",
+			Content: "disk=" + strings.Repeat("a", internal.MaxOutputSize),
+			Error:   true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			of, err := internal.NewOutputFile()
+			if err != nil {
+				t.Fatalf("Could not create output file: %v", err)
+			}
+			defer of.Close()
+
+			if err := os.WriteFile(of.Path(), []byte(tc.Content), 0o600); err != nil {
+				t.Fatalf("Couldn't write to output file: %v", err)
+			}
+
+			outputs, err := of.Outputs()
+			if tc.Error != (err != nil) {
+				t.Fatalf("Expected error: %v; Received: %v", tc.Error, err)
+			}
+			if diff := cmp.Diff(tc.Outputs, outputs); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,321 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/tinkerbell/tink/internal/agent"
+	"github.com/tinkerbell/tink/internal/agent/failure"
+	"github.com/tinkerbell/tink/internal/agent/runtime/internal"
+	"github.com/tinkerbell/tink/internal/agent/workflow"
+)
+
+// DefaultRootfsDir is the default directory containing pre-extracted root filesystems for the
+// Process runtime.
+const DefaultRootfsDir = "/var/lib/tinkerbell/rootfs"
+
+const (
+	// ReasonPathEnv is the environment variable containing the path Actions run by the Process
+	// runtime can write their failure reason to.
+	ReasonPathEnv = "TINKERBELL_FAILURE_REASON_PATH"
+
+	// MessagePathEnv is the environment variable containing the path Actions run by the Process
+	// runtime can write their failure message to.
+	MessagePathEnv = "TINKERBELL_FAILURE_MESSAGE_PATH"
+
+	// OutputsPathEnv is the environment variable containing the path Actions run by the Process
+	// runtime can write their outputs to.
+	OutputsPathEnv = "TINKERBELL_OUTPUTS_PATH"
+)
+
+// processStopTimeout is the time a process has to exit after being sent SIGTERM before it is
+// killed.
+const processStopTimeout = 5 * time.Second
+
+// defaultRootfsPath is the PATH used to find commands in a root filesystem when the action
+// doesn't define one.
+const defaultRootfsPath = "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
+
+var _ agent.ContainerRuntime = &Process{}
+
+// Process is a runtime that satisfies agent.ContainerRuntime by executing actions as processes
+// so no container engine is required. An action's image is resolved to a path; absolute images
+// are used as is and relative images are resolved against the root filesystem directory.
+//
+// When the path is a directory it's considered a pre-extracted root filesystem. The action's Cmd,
+// or first Arg when Cmd is empty, is run chrooted into the root filesystem in new mount, PID, UTS
+// and IPC namespaces which requires root privileges. Failure and output files are created at
+// their usual paths in the root filesystem so actions sharing a root filesystem are run one at a
+// time.
+//
+// When the path is an executable file it's run directly on the host with the action's Args,
+// unless the action's Cmd is set in which case Cmd is run instead. The failure and output files
+// can't be created at their usual paths on the host so actions should use the paths provided in
+// ReasonPathEnv, MessagePathEnv and OutputsPathEnv.
+//
+// Processes share the host network and actions with volumes, resource limits, or network
+// namespaces other than 'host', are rejected.
+type Process struct {
+	log       logr.Logger
+	rootfsDir string
+
+	// rootfsLocks serializes actions sharing a root filesystem.
+	rootfsLocks map[string]*sync.Mutex
+	mtx         sync.Mutex
+}
+
+// Run satisfies agent.ContainerRuntime.
+func (p *Process) Run(ctx context.Context, a workflow.Action, stdout, stderr io.Writer) (map[string]string, error) {
+	if len(a.Volumes) > 0 {
+		return nil, errors.New("process: volumes are not supported")
+	}
+	if a.NetworkNamespace != "" && a.NetworkNamespace != "host" {
+		return nil, fmt.Errorf("process: unsupported network namespace: %v", a.NetworkNamespace)
+	}
+	if !a.Resources.IsZero() {
+		return nil, errors.New("process: resource limits are not supported")
+	}
+
+	path, err := p.resolveImage(a.Image)
+	if err != nil {
+		return nil, fmt.Errorf("process: %w", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("process: %w", err)
+	}
+
+	switch {
+	case info.IsDir():
+		return p.runRootfs(ctx, path, a, stdout, stderr)
+	case info.Mode()&0o111 != 0:
+		return p.runBinary(ctx, path, a, stdout, stderr)
+	default:
+		return nil, fmt.Errorf("process: image is neither a directory nor an executable: %v", path)
+	}
+}
+
+// resolveImage resolves image to a path.
+func (p *Process) resolveImage(image string) (string, error) {
+	if filepath.IsAbs(image) {
+		return image, nil
+	}
+	if !filepath.IsLocal(image) {
+		return "", fmt.Errorf("invalid image: %v", image)
+	}
+	return filepath.Join(p.rootfsDir, image), nil
+}
+
+// runBinary runs the action as a host process using binary unless the action's Cmd is set.
+func (p *Process) runBinary(ctx context.Context, binary string, a workflow.Action, stdout, stderr io.Writer) (map[string]string, error) {
+	failureFiles, err := internal.NewFailureFiles()
+	if err != nil {
+		return nil, fmt.Errorf("create action failure files: %w", err)
+	}
+	defer failureFiles.Close()
+
+	outputFile, err := internal.NewOutputFile()
+	if err != nil {
+		return nil, fmt.Errorf("create action output file: %w", err)
+	}
+	defer outputFile.Close()
+
+	if a.Cmd != "" {
+		binary = a.Cmd
+	}
+
+	cmd := exec.CommandContext(ctx, binary, a.Args...)
+	cmd.Env = append(
+		toEnv(a.Env),
+		fmt.Sprintf("%v=%v", ReasonPathEnv, failureFiles.ReasonPath()),
+		fmt.Sprintf("%v=%v", MessagePathEnv, failureFiles.MessagePath()),
+		fmt.Sprintf("%v=%v", OutputsPathEnv, outputFile.Path()),
+	)
+	cmd.SysProcAttr = hostProcAttr()
+
+	return p.exec(ctx, cmd, stdout, stderr, failureFiles, outputFile)
+}
+
+// runRootfs runs the action chrooted into rootfs.
+func (p *Process) runRootfs(ctx context.Context, rootfs string, a workflow.Action, stdout, stderr io.Writer) (map[string]string, error) {
+	args := a.Args
+	if a.Cmd != "" {
+		args = append([]string{a.Cmd}, args...)
+	}
+	if len(args) == 0 {
+		return nil, errors.New("process: actions using a root filesystem must specify a command")
+	}
+
+	env := maps.Clone(a.Env)
+	if env == nil {
+		env = map[string]string{}
+	}
+	if _, ok := env["PATH"]; !ok {
+		env["PATH"] = defaultRootfsPath
+	}
+
+	name, err := lookPathIn(rootfs, args[0], env["PATH"])
+	if err != nil {
+		return nil, fmt.Errorf("process: %w", err)
+	}
+
+	attr, err := rootfsProcAttr(rootfs)
+	if err != nil {
+		return nil, fmt.Errorf("process: %w", err)
+	}
+
+	unlock := p.lockRootfs(rootfs, a)
+	defer unlock()
+
+	if err := os.MkdirAll(filepath.Join(rootfs, filepath.Dir(ReasonMountPath)), 0o755); err != nil {
+		return nil, fmt.Errorf("create action failure files: %w", err)
+	}
+
+	failureFiles, err := internal.NewFailureFilesAt(
+		filepath.Join(rootfs, ReasonMountPath),
+		filepath.Join(rootfs, MessageMountPath),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create action failure files: %w", err)
+	}
+	defer failureFiles.Close()
+
+	outputFile, err := internal.NewOutputFileAt(filepath.Join(rootfs, OutputsMountPath))
+	if err != nil {
+		return nil, fmt.Errorf("create action output file: %w", err)
+	}
+	defer outputFile.Close()
+
+	// name is absolute so it isn't resolved on the host; the kernel resolves it after changing
+	// root.
+	cmd := exec.CommandContext(ctx, name, args[1:]...)
+	cmd.Dir = "/"
+	cmd.Env = append(
+		toEnv(env),
+		fmt.Sprintf("%v=%v", ReasonPathEnv, ReasonMountPath),
+		fmt.Sprintf("%v=%v", MessagePathEnv, MessageMountPath),
+		fmt.Sprintf("%v=%v", OutputsPathEnv, OutputsMountPath),
+	)
+	cmd.SysProcAttr = attr
+
+	return p.exec(ctx, cmd, stdout, stderr, failureFiles, outputFile)
+}
+
+// exec runs cmd and extracts the outcome from failureFiles and outputFile. When ctx is done, the
+// process is sent SIGTERM and killed if it doesn't exit within processStopTimeout.
+func (p *Process) exec(
+	ctx context.Context,
+	cmd *exec.Cmd,
+	stdout, stderr io.Writer,
+	failureFiles *internal.FailureFiles,
+	outputFile *internal.OutputFile,
+) (map[string]string, error) {
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.Cancel = func() error {
+		return signalProcess(cmd.Process, syscall.SIGTERM)
+	}
+	cmd.WaitDelay = processStopTimeout
+
+	err := cmd.Run()
+
+	var exitErr *exec.ExitError
+	switch {
+	case ctx.Err() != nil:
+		return nil, fmt.Errorf("process: %w", ctx.Err())
+	case errors.As(err, &exitErr):
+		return nil, failureFiles.ToError()
+	case err != nil:
+		return nil, fmt.Errorf("process: %w", err)
+	}
+
+	outputs, err := outputFile.Outputs()
+	if err != nil {
+		return nil, failure.WithReason(fmt.Errorf("read action outputs: %w", err), ReasonInvalidOutputs)
+	}
+	return outputs, nil
+}
+
+// lockRootfs locks rootfs for exclusive use by a. It returns a function that unlocks rootfs.
+func (p *Process) lockRootfs(rootfs string, a workflow.Action) func() {
+	p.mtx.Lock()
+	lock, ok := p.rootfsLocks[rootfs]
+	if !ok {
+		lock = &sync.Mutex{}
+		p.rootfsLocks[rootfs] = lock
+	}
+	p.mtx.Unlock()
+
+	if !lock.TryLock() {
+		p.log.Info("Waiting for root filesystem used by another action", "action_id", a.ID, "rootfs", rootfs)
+		lock.Lock()
+	}
+	return lock.Unlock
+}
+
+// lookPathIn searches for the executable name in the directories of path inside rootfs. Names
+// containing a slash are not searched for. The returned path is relative to rootfs.
+func lookPathIn(rootfs, name, path string) (string, error) {
+	if strings.Contains(name, "/") {
+		return filepath.Join("/", name), nil
+	}
+	for _, dir := range filepath.SplitList(path) {
+		candidate := filepath.Join("/", dir, name)
+		info, err := os.Stat(filepath.Join(rootfs, candidate))
+		if err == nil && !info.IsDir() && info.Mode()&0o111 != 0 {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("executable not found in root filesystem: %v", name)
+}
+
+// NewProcess creates a new Process instance.
+func NewProcess(opts ...ProcessOption) *Process {
+	o := &Process{
+		log:         logr.Discard(),
+		rootfsDir:   DefaultRootfsDir,
+		rootfsLocks: map[string]*sync.Mutex{},
+	}
+
+	for _, fn := range opts {
+		fn(o)
+	}
+
+	return o
+}
+
+// ProcessOption defines optional configuration for a Process instance.
+type ProcessOption func(*Process)
+
+// WithProcessLogger returns an option to configure the logger on a Process instance.
+func WithProcessLogger(log logr.Logger) ProcessOption {
+	return func(o *Process) {
+		if log.GetSink() == nil {
+			return
+		}
+		o.log = log
+	}
+}
+
+// WithRootfsDir returns an option to configure the directory relative images are resolved
+// against.
+func WithRootfsDir(dir string) ProcessOption {
+	return func(o *Process) {
+		if dir == "" {
+			return
+		}
+		o.rootfsDir = dir
+	}
+}
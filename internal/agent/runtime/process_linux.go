@@ -0,0 +1,28 @@
+package runtime
+
+import (
+	"os"
+	"syscall"
+)
+
+// hostProcAttr returns the attributes of processes run on the host. Processes are started in
+// their own process group so the whole group can be signalled.
+func hostProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Setpgid: true}
+}
+
+// rootfsProcAttr returns the attributes of processes run chrooted into rootfs.
+func rootfsProcAttr(rootfs string) (*syscall.SysProcAttr, error) {
+	return &syscall.SysProcAttr{
+		Chroot:     rootfs,
+		Cloneflags: syscall.CLONE_NEWNS | syscall.CLONE_NEWPID | syscall.CLONE_NEWUTS | syscall.CLONE_NEWIPC,
+		Setpgid:    true,
+		// Ensure the process doesn't outlive the agent.
+		Pdeathsig: syscall.SIGKILL,
+	}, nil
+}
+
+// signalProcess sends sig to the process group of p.
+func signalProcess(p *os.Process, sig syscall.Signal) error {
+	return syscall.Kill(-p.Pid, sig)
+}
@@ -0,0 +1,24 @@
+//go:build !linux
+
+package runtime
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// hostProcAttr returns the attributes of processes run on the host.
+func hostProcAttr() *syscall.SysProcAttr {
+	return nil
+}
+
+// rootfsProcAttr returns the attributes of processes run chrooted into rootfs.
+func rootfsProcAttr(string) (*syscall.SysProcAttr, error) {
+	return nil, errors.New("root filesystems are only supported on Linux")
+}
+
+// signalProcess sends sig to p.
+func signalProcess(p *os.Process, sig syscall.Signal) error {
+	return p.Signal(sig)
+}
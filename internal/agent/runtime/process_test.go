@@ -0,0 +1,181 @@
+package runtime_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tinkerbell/tink/internal/agent/failure"
+	"github.com/tinkerbell/tink/internal/agent/runtime"
+	"github.com/tinkerbell/tink/internal/agent/workflow"
+)
+
+func TestProcess(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skipf("sh not available: %v", err)
+	}
+
+	cases := []struct {
+		Name    string
+		Action  workflow.Action
+		Stdout  string
+		Outputs map[string]string
+		Reason  string
+		Message string
+		Error   bool
+	}{
+		{
+			Name: "Success",
+			Action: workflow.Action{
+				Image: sh,
+				Args:  []string{"-c", `echo "$GREETING"; echo disk=/dev/sda > "$TINKERBELL_OUTPUTS_PATH"`},
+				Env:   map[string]string{"GREETING": "hello"},
+			},
+			Stdout:  "hello\n",
+			Outputs: map[string]string{"disk": "/dev/sda"},
+		},
+		{
+			Name: "CmdReplacesImage",
+			Action: workflow.Action{
+				Image: sh,
+				Cmd:   sh,
+				Args:  []string{"-c", "echo cmd"},
+			},
+			Stdout: "cmd\n",
+		},
+		{
+			Name: "FailureReasonAndMessage",
+			Action: workflow.Action{
+				Image: sh,
+				Args: []string{"-c", `
+					echo -n "MyReason" > "$TINKERBELL_FAILURE_REASON_PATH"
+					echo -n "my message" > "$TINKERBELL_FAILURE_MESSAGE_PATH"
+					exit 1
+				`},
+			},
+			Reason:  "MyReason",
+			Message: "my message",
+			Error:   true,
+		},
+		{
+			Name: "InvalidOutputs",
+			Action: workflow.Action{
+				Image: sh,
+				Args:  []string{"-c", `echo invalid > "$TINKERBELL_OUTPUTS_PATH"`},
+			},
+			Reason: runtime.ReasonInvalidOutputs,
+			Error:  true,
+		},
+		{
+			Name: "MissingImage",
+			Action: workflow.Action{
+				Image: "missing",
+			},
+			Error: true,
+		},
+		{
+			Name: "EscapingImage",
+			Action: workflow.Action{
+				Image: "../image",
+			},
+			Error: true,
+		},
+		{
+			Name: "RootfsMissingCommand",
+			Action: workflow.Action{
+				Image: "rootfs",
+			},
+			Error: true,
+		},
+		{
+			Name: "RootfsCommandNotFound",
+			Action: workflow.Action{
+				Image: "rootfs",
+				Cmd:   "sh",
+			},
+			Error: true,
+		},
+		{
+			Name: "Volumes",
+			Action: workflow.Action{
+				Image:   sh,
+				Volumes: []string{"/dev:/dev"},
+			},
+			Error: true,
+		},
+		{
+			Name: "NetworkNamespace",
+			Action: workflow.Action{
+				Image:            sh,
+				NetworkNamespace: "/var/run/netns/provisioning",
+			},
+			Error: true,
+		},
+		{
+			Name: "Resources",
+			Action: workflow.Action{
+				Image:     sh,
+				Resources: workflow.Resources{Memory: 512 << 20},
+			},
+			Error: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			// Provide an empty root filesystem.
+			dir := t.TempDir()
+			if err := os.Mkdir(filepath.Join(dir, "rootfs"), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			rt := runtime.NewProcess(runtime.WithRootfsDir(dir))
+
+			var stdout bytes.Buffer
+			outputs, err := rt.Run(context.Background(), tc.Action, &stdout, &bytes.Buffer{})
+			if tc.Error != (err != nil) {
+				t.Fatalf("Expected error: %v; Received: %v", tc.Error, err)
+			}
+
+			if reason, _ := failure.Reason(err); reason != tc.Reason {
+				t.Fatalf("Expected reason: %q; Received: %q", tc.Reason, reason)
+			}
+			if tc.Message != "" && err.Error() != tc.Message {
+				t.Fatalf("Expected message: %q; Received: %q", tc.Message, err.Error())
+			}
+			if diff := cmp.Diff(tc.Outputs, outputs); diff != "" {
+				t.Fatal(diff)
+			}
+			if stdout.String() != tc.Stdout {
+				t.Fatalf("Expected stdout: %q; Received: %q", tc.Stdout, stdout.String())
+			}
+		})
+	}
+}
+
+func TestProcessContextTimeout(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skipf("sh not available: %v", err)
+	}
+
+	rt := runtime.NewProcess()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err = rt.Run(ctx, workflow.Action{Image: sh, Args: []string{"-c", "sleep 30"}}, &bytes.Buffer{}, &bytes.Buffer{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expect: %v; Received: %v", context.DeadlineExceeded, err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Fatalf("Process wasn't stopped promptly; took %v", elapsed)
+	}
+}
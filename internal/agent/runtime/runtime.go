@@ -4,4 +4,11 @@
 //
 //	/tinkerbell/failure-reason
 //	/tinkerbell/failure-message
+//
+// They are also responsible for extracting the outputs of successful actions from:
+//
+//	/tinkerbell/outputs
+//
+// Runtimes that can't provide the files at these locations, such as the Process runtime when
+// running actions on the host, provide their locations to actions in environment variables.
 package runtime
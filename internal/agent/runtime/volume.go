@@ -0,0 +1,45 @@
+package runtime
+
+import (
+	"fmt"
+	"strings"
+)
+
+// volume is an action volume of the form 'source:/destination[:ro|rw]'. The source is either an
+// absolute host path or a volume name.
+type volume struct {
+	Source      string
+	Destination string
+	ReadOnly    bool
+}
+
+// IsBind determines if the volume's source is a host path.
+func (v volume) IsBind() bool {
+	return strings.HasPrefix(v.Source, "/")
+}
+
+// parseVolume parses an action volume.
+func parseVolume(v string) (volume, error) {
+	parts := strings.Split(v, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return volume{}, fmt.Errorf("invalid volume: %v", v)
+	}
+	if parts[0] == "" {
+		return volume{}, fmt.Errorf("invalid volume: %v: missing source", v)
+	}
+	if !strings.HasPrefix(parts[1], "/") {
+		return volume{}, fmt.Errorf("invalid volume: %v: destination must be an absolute path", v)
+	}
+
+	vol := volume{Source: parts[0], Destination: parts[1]}
+	if len(parts) == 3 {
+		switch parts[2] {
+		case "ro":
+			vol.ReadOnly = true
+		case "rw":
+		default:
+			return volume{}, fmt.Errorf("invalid volume: %v: unsupported mode %v", v, parts[2])
+		}
+	}
+	return vol, nil
+}
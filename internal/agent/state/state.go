@@ -0,0 +1,170 @@
+// Package state persists the agent's progress through a workflow so it can be recovered when the
+// agent restarts.
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/tinkerbell/tink/internal/agent/event"
+	"github.com/tinkerbell/tink/internal/agent/workflow"
+)
+
+// State is the persisted agent state.
+type State struct {
+	// Workflow is the workflow being executed. It's nil when the agent isn't executing a
+	// workflow.
+	Workflow *workflow.Workflow `json:"workflow,omitempty"`
+
+	// Events are the events recorded for Workflow in the order they were recorded, and events
+	// of previous workflows that haven't been sent.
+	Events []Record `json:"events,omitempty"`
+}
+
+// Unsent returns the events that haven't been sent.
+func (s State) Unsent() []Record {
+	var unsent []Record
+	for _, r := range s.Events {
+		if !r.Sent {
+			unsent = append(unsent, r)
+		}
+	}
+	return unsent
+}
+
+// Record is an event recorded by the agent.
+type Record struct {
+	Event event.Event
+
+	// Sent indicates the server acknowledged the event.
+	Sent bool
+
+	// ID identifies the record while the agent runs. It isn't persisted.
+	ID uint64
+}
+
+// record is the persisted form of a Record.
+type record struct {
+	Name  event.Name      `json:"name"`
+	Event json.RawMessage `json:"event"`
+	Sent  bool            `json:"sent,omitempty"`
+}
+
+// MarshalJSON satisfies json.Marshaler.
+func (r Record) MarshalJSON() ([]byte, error) {
+	if r.Event == nil {
+		return nil, errors.New("record has no event")
+	}
+	data, err := json.Marshal(r.Event)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(record{Name: r.Event.GetName(), Event: data, Sent: r.Sent})
+}
+
+// UnmarshalJSON satisfies json.Unmarshaler.
+func (r *Record) UnmarshalJSON(data []byte) error {
+	var rec record
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return err
+	}
+
+	var err error
+	switch rec.Name {
+	case event.ActionStartedName:
+		r.Event, err = decode[event.ActionStarted](rec.Event)
+	case event.ActionSucceededName:
+		r.Event, err = decode[event.ActionSucceeded](rec.Event)
+	case event.ActionFailedName:
+		r.Event, err = decode[event.ActionFailed](rec.Event)
+	case event.ActionSkippedName:
+		r.Event, err = decode[event.ActionSkipped](rec.Event)
+	case event.ActionPausedName:
+		r.Event, err = decode[event.ActionPaused](rec.Event)
+	case event.ImagePulledName:
+		r.Event, err = decode[event.ImagePulled](rec.Event)
+	case event.ImagePullFailedName:
+		r.Event, err = decode[event.ImagePullFailed](rec.Event)
+	case event.WorkflowRejectedName:
+		r.Event, err = decode[event.WorkflowRejected](rec.Event)
+	default:
+		return fmt.Errorf("unknown event: %v", rec.Name)
+	}
+	r.Sent = rec.Sent
+	return err
+}
+
+func decode[T event.Event](data []byte) (event.Event, error) {
+	var e T
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// File stores State as JSON in a file.
+type File struct {
+	path string
+}
+
+// NewFile creates a File that stores state at path.
+func NewFile(path string) *File {
+	return &File{path: path}
+}
+
+// Load reads the state from the file. When the file doesn't exist the state is empty.
+func (f *File) Load() (State, error) {
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return State{}, nil
+	}
+	if err != nil {
+		return State{}, fmt.Errorf("read state: %w", err)
+	}
+
+	var s State
+	if err := json.Unmarshal(data, &s); err != nil {
+		return State{}, fmt.Errorf("decode state: %w", err)
+	}
+	return s, nil
+}
+
+// Save writes s to the file. The file is replaced atomically so a crash during Save leaves the
+// previous state intact.
+func (f *File) Save(s State) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("encode state: %w", err)
+	}
+
+	dir := filepath.Dir(f.path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("create state directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(f.path)+".*")
+	if err != nil {
+		return fmt.Errorf("create state file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write state: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write state: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write state: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), f.path); err != nil {
+		return fmt.Errorf("replace state: %w", err)
+	}
+	return nil
+}
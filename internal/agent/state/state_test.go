@@ -0,0 +1,76 @@
+package state_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tinkerbell/tink/internal/agent/event"
+	"github.com/tinkerbell/tink/internal/agent/state"
+	"github.com/tinkerbell/tink/internal/agent/workflow"
+)
+
+func TestFile(t *testing.T) {
+	store := state.NewFile(filepath.Join(t.TempDir(), "agent", "state.json"))
+
+	expect := state.State{
+		Workflow: &workflow.Workflow{
+			ID: "workflow",
+			Actions: []workflow.Action{
+				{
+					ID:         "action",
+					Name:       "action",
+					Image:      "image",
+					Idempotent: true,
+					Timeout:    time.Minute,
+					Resources:  workflow.Resources{Memory: 512 << 20},
+				},
+			},
+		},
+		Events: []state.Record{
+			{Event: event.ActionStarted{ActionID: "action", WorkflowID: "workflow", Attempt: 1}, Sent: true},
+			{Event: event.ActionSucceeded{ActionID: "action", WorkflowID: "workflow", Attempt: 1, Outputs: map[string]string{"disk": "/dev/sda"}}},
+			{Event: event.ActionFailed{ActionID: "action", WorkflowID: "workflow", Reason: "Reason", Attempt: 2, WillRetry: true}},
+			{Event: event.ImagePullFailed{WorkflowID: "workflow", Image: "image", Message: "message"}},
+		},
+	}
+	if err := store.Save(expect); err != nil {
+		t.Fatal(err)
+	}
+
+	received, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(expect, received); diff != "" {
+		t.Fatal(diff)
+	}
+
+	unsent := received.Unsent()
+	if len(unsent) != 3 || unsent[0].Event.GetName() != event.ActionSucceededName {
+		t.Fatalf("Unexpected unsent events: %v", unsent)
+	}
+}
+
+func TestFileMissing(t *testing.T) {
+	received, err := state.NewFile(filepath.Join(t.TempDir(), "state.json")).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(state.State{}, received); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestFileCorrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := os.WriteFile(path, []byte(`{"events":[{"name":"Unknown","event":{}}]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := state.NewFile(path).Load(); err == nil {
+		t.Fatal("Expected error")
+	}
+}
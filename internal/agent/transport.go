@@ -13,3 +13,11 @@ type Transport interface {
 	// should block until its told to cancel via the context.
 	Start(_ context.Context, agentID string, _ transport.WorkflowHandler) error
 }
+
+// EventPublisher is implemented by Transports that publish recorded events independently of
+// retrieving workflows.
+type EventPublisher interface {
+	// StartPublishing publishes recorded events until ctx is done. It doesn't block and calls
+	// after the first have no effect.
+	StartPublishing(ctx context.Context)
+}
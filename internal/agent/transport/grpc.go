@@ -3,45 +3,130 @@ package transport
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
+	"slices"
+	"sync"
+	"time"
 
-	"github.com/avast/retry-go"
 	"github.com/go-logr/logr"
 	"github.com/tinkerbell/tink/internal/agent/event"
 	"github.com/tinkerbell/tink/internal/agent/workflow"
+	"github.com/tinkerbell/tink/internal/dag"
 	workflowproto "github.com/tinkerbell/tink/internal/proto/workflow/v2"
 )
 
-var _ event.Recorder = &GRPC{}
+var (
+	_ event.Recorder    = &GRPC{}
+	_ event.AckRecorder = &GRPC{}
+)
+
+const (
+	// reconnectInitialBackoff is the delay before the workflow stream is re-opened after ending.
+	reconnectInitialBackoff = 500 * time.Millisecond
+
+	// reconnectMaxBackoff is the maximum delay between attempts to open the workflow stream.
+	reconnectMaxBackoff = 30 * time.Second
+)
 
-func NewGRPC(log logr.Logger, client workflowproto.WorkflowServiceClient) *GRPC {
-	return &GRPC{
+func NewGRPC(log logr.Logger, client workflowproto.WorkflowServiceClient, opts ...GRPCOption) *GRPC {
+	g := &GRPC{
 		log:    log,
 		client: client,
 	}
+
+	for _, fn := range opts {
+		fn(g)
+	}
+
+	if g.outbox == nil {
+		g.outbox = NewOutbox(WithOutboxLogger(log))
+	}
+
+	return g
+}
+
+// GRPCOption defines optional configuration for a GRPC instance.
+type GRPCOption func(*GRPC)
+
+// WithOutbox returns an option to configure the outbox events are published from. An outbox
+// with default options is used when none is configured.
+func WithOutbox(o *Outbox) GRPCOption {
+	return func(g *GRPC) {
+		if o == nil {
+			return
+		}
+		g.outbox = o
+	}
 }
 
 type GRPC struct {
 	log    logr.Logger
 	client workflowproto.WorkflowServiceClient
+	outbox *Outbox
+
+	publish sync.Once
+}
+
+// StartPublishing publishes events added to the outbox until ctx is done. Events are published
+// for the lifetime of the first ctx it's called with; subsequent calls have no effect.
+func (g *GRPC) StartPublishing(ctx context.Context) {
+	g.publish.Do(func() {
+		go g.outbox.Run(ctx, func(ctx context.Context, r *workflowproto.PublishEventRequest) error {
+			_, err := g.client.PublishEvent(ctx, r)
+			return err
+		})
+	})
 }
 
+// Start passes the workflow commands received from the server for agentID to handler until ctx is
+// done. The workflow stream is re-opened with an exponential backoff whenever it ends, for example
+// because the server restarted, so Start only returns once ctx is done. Events are published for
+// the lifetime of ctx.
 func (g *GRPC) Start(ctx context.Context, agentID string, handler WorkflowHandler) error {
+	g.StartPublishing(ctx)
+
+	backoff := reconnectInitialBackoff
+	for {
+		received, err := g.receive(ctx, agentID, handler)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		// Streams that delivered commands were healthy so the next attempt is made promptly.
+		if received {
+			backoff = reconnectInitialBackoff
+		}
+		g.log.Info("Workflow stream ended; reconnecting", "error", err, "delay", backoff.String())
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, reconnectMaxBackoff)
+	}
+}
+
+// receive opens a workflow stream for agentID and passes the commands received on it to handler
+// until the stream ends. It reports whether any commands were received.
+func (g *GRPC) receive(ctx context.Context, agentID string, handler WorkflowHandler) (bool, error) {
 	stream, err := g.client.GetWorkflows(ctx, &workflowproto.GetWorkflowsRequest{
 		AgentId: agentID,
 	})
 	if err != nil {
-		return err
+		return false, err
 	}
 
+	received := false
 	for {
 		request, err := stream.Recv()
 		switch {
 		case errors.Is(err, io.EOF):
-			return nil
+			return received, errors.New("stream closed by server")
 		case err != nil:
-			return err
+			return received, err
 		}
+		received = true
 
 		switch request.GetCmd().(type) {
 		case *workflowproto.GetWorkflowsResponse_StartWorkflow_:
@@ -65,25 +150,36 @@ func (g *GRPC) Start(ctx context.Context, agentID string, handler WorkflowHandle
 			}
 
 			handler.CancelWorkflow(request.GetStopWorkflow().WorkflowId)
+
+		case *workflowproto.GetWorkflowsResponse_PauseWorkflow_:
+			if request.GetPauseWorkflow().WorkflowId == "" {
+				g.log.Info("Dropping request to pause workflow; missing workflow ID")
+				continue
+			}
+
+			handler.PauseWorkflow(
+				request.GetPauseWorkflow().WorkflowId,
+				toPause(request.GetPauseWorkflow().GetPause()),
+			)
 		}
 	}
 }
 
+// RecordEvent adds e to the outbox. The event is published to the server once Start or
+// StartPublishing has been called and is retried until the server acknowledges it.
 func (g *GRPC) RecordEvent(ctx context.Context, e event.Event) error {
+	return g.RecordEventWithAck(ctx, e, nil)
+}
+
+// RecordEventWithAck adds e to the outbox like RecordEvent and calls ack once the server
+// acknowledges e or rejects it as invalid.
+func (g *GRPC) RecordEventWithAck(ctx context.Context, e event.Event, ack func()) error {
 	evnt, err := toGRPC(e)
 	if err != nil {
 		return err
 	}
 
-	publish := func() error {
-		payload := workflowproto.PublishEventRequest{Event: evnt}
-		if _, err := g.client.PublishEvent(ctx, &payload); err != nil {
-			return err
-		}
-		return nil
-	}
-
-	return retry.Do(publish, retry.Attempts(5), retry.DelayType(retry.BackOffDelay))
+	return g.outbox.AddWithAck(ctx, evnt, ack)
 }
 
 func validateGRPCWorkflow(wflw *workflowproto.Workflow) error {
@@ -91,19 +187,75 @@ func validateGRPCWorkflow(wflw *workflowproto.Workflow) error {
 		return errors.New("workflow must not be nil")
 	}
 
-	for _, action := range wflw.Actions {
+	for _, action := range slices.Concat(wflw.Actions, wflw.OnFailure, wflw.Finally) {
 		if action == nil {
 			return errors.New("workflow actions must not be nil")
 		}
 	}
 
+	wrkflw := toWorkflow(wflw)
+	ids := make([]string, 0, len(wrkflw.Actions))
+	for _, action := range wrkflw.Actions {
+		ids = append(ids, action.ID)
+	}
+	if _, err := dag.Sort(ids, wrkflw.Dependencies()); err != nil {
+		return fmt.Errorf("invalid action dependencies: %w", err)
+	}
+
+	for _, action := range slices.Concat(wrkflw.OnFailure, wrkflw.Finally) {
+		if slices.Contains(ids, action.ID) {
+			return fmt.Errorf("duplicate action id: %v", action.ID)
+		}
+		if len(action.DependsOn) > 0 {
+			return fmt.Errorf("on failure and finally actions must not have dependencies: %v", action.ID)
+		}
+		ids = append(ids, action.ID)
+	}
+
 	return nil
 }
 
 func toWorkflow(wflw *workflowproto.Workflow) workflow.Workflow {
 	return workflow.Workflow{
-		ID:      wflw.WorkflowId,
-		Actions: toActions(wflw.GetActions()),
+		ID:            wflw.WorkflowId,
+		Actions:       toActions(wflw.GetActions()),
+		ConditionData: wflw.GetConditionData().AsMap(),
+		OnFailure:     toActions(wflw.GetOnFailure()),
+		Finally:       toActions(wflw.GetFinally()),
+		Pause:         toPause(wflw.GetPause()),
+		Results:       toResults(wflw.GetResults()),
+	}
+}
+
+func toResults(r map[string]*workflowproto.Workflow_ActionResult) map[string]workflow.Result {
+	if len(r) == 0 {
+		return nil
+	}
+	results := make(map[string]workflow.Result, len(r))
+	for id, result := range r {
+		results[id] = workflow.Result{
+			Succeeded: result.GetSucceeded(),
+			Outputs:   result.GetOutputs(),
+		}
+	}
+	return results
+}
+
+func toActionType(t workflowproto.Workflow_Action_Type) workflow.ActionType {
+	switch t {
+	case workflowproto.Workflow_Action_TYPE_REBOOT:
+		return workflow.ActionTypeReboot
+	case workflowproto.Workflow_Action_TYPE_KEXEC:
+		return workflow.ActionTypeKexec
+	default:
+		return workflow.ActionTypeContainer
+	}
+}
+
+func toPause(p *workflowproto.Workflow_Pause) workflow.Pause {
+	return workflow.Pause{
+		Paused:      p.GetPaused(),
+		Breakpoints: p.GetBreakpoints(),
 	}
 }
 
@@ -113,17 +265,53 @@ func toActions(a []*workflowproto.Workflow_Action) []workflow.Action {
 		actions = append(actions, workflow.Action{
 			ID:               action.GetId(),
 			Name:             action.GetName(),
+			Type:             toActionType(action.GetType()),
 			Image:            action.GetImage(),
 			Cmd:              action.GetCmd(),
 			Args:             action.GetArgs(),
 			Env:              action.GetEnv(),
 			Volumes:          action.GetVolumes(),
 			NetworkNamespace: action.GetNetworkNamespace(),
+			Retry:            toRetryPolicy(action.GetRetryPolicy()),
+			DependsOn:        action.GetDependsOn(),
+			Timeout:          time.Duration(action.GetTimeoutSeconds()) * time.Second,
+			When:             action.GetWhen(),
+			PIDNamespace:     action.GetPidNamespace(),
+			Privileged:       action.GetPrivileged(),
+			Capabilities: workflow.Capabilities{
+				Add:  action.GetCapabilities().GetAdd(),
+				Drop: action.GetCapabilities().GetDrop(),
+			},
+			Devices: action.GetDevices(),
+			Resources: workflow.Resources{
+				Memory:   action.GetResources().GetMemoryBytes(),
+				NanoCPUs: action.GetResources().GetNanoCpus(),
+				PIDs:     action.GetResources().GetPids(),
+			},
+			Idempotent: action.GetIdempotent(),
+			Kexec: workflow.Kexec{
+				BlockDevice: action.GetKexec().GetBlockDevice(),
+				FSType:      action.GetKexec().GetFsType(),
+				KernelPath:  action.GetKexec().GetKernelPath(),
+				InitrdPath:  action.GetKexec().GetInitrdPath(),
+				Cmdline:     action.GetKexec().GetCmdline(),
+			},
 		})
 	}
 	return actions
 }
 
+func toRetryPolicy(p *workflowproto.Workflow_RetryPolicy) workflow.RetryPolicy {
+	if p == nil {
+		return workflow.RetryPolicy{}
+	}
+	return workflow.RetryPolicy{
+		Retries: int(p.GetRetries()),
+		Backoff: time.Duration(p.GetBackoffSeconds()) * time.Second,
+		Reasons: p.GetReasons(),
+	}
+}
+
 func toGRPC(e event.Event) (*workflowproto.Event, error) {
 	switch v := e.(type) {
 	case event.ActionStarted:
@@ -132,6 +320,7 @@ func toGRPC(e event.Event) (*workflowproto.Event, error) {
 			Event: &workflowproto.Event_ActionStarted_{
 				ActionStarted: &workflowproto.Event_ActionStarted{
 					ActionId: v.ActionID,
+					Attempt:  int64(v.Attempt),
 				},
 			},
 		}, nil
@@ -141,6 +330,8 @@ func toGRPC(e event.Event) (*workflowproto.Event, error) {
 			Event: &workflowproto.Event_ActionSucceeded_{
 				ActionSucceeded: &workflowproto.Event_ActionSucceeded{
 					ActionId: v.ActionID,
+					Attempt:  int64(v.Attempt),
+					Outputs:  v.Outputs,
 				},
 			},
 		}, nil
@@ -152,6 +343,47 @@ func toGRPC(e event.Event) (*workflowproto.Event, error) {
 					ActionId:       v.ActionID,
 					FailureReason:  &v.Reason,
 					FailureMessage: &v.Message,
+					Attempt:        int64(v.Attempt),
+					WillRetry:      v.WillRetry,
+				},
+			},
+		}, nil
+	case event.ActionSkipped:
+		return &workflowproto.Event{
+			WorkflowId: v.WorkflowID,
+			Event: &workflowproto.Event_ActionSkipped_{
+				ActionSkipped: &workflowproto.Event_ActionSkipped{
+					ActionId: v.ActionID,
+				},
+			},
+		}, nil
+	case event.ActionPaused:
+		return &workflowproto.Event{
+			WorkflowId: v.WorkflowID,
+			Event: &workflowproto.Event_ActionPaused_{
+				ActionPaused: &workflowproto.Event_ActionPaused{
+					ActionId: v.ActionID,
+				},
+			},
+		}, nil
+	case event.ImagePulled:
+		return &workflowproto.Event{
+			WorkflowId: v.WorkflowID,
+			Event: &workflowproto.Event_ImagePulled_{
+				ImagePulled: &workflowproto.Event_ImagePulled{
+					Image:  v.Image,
+					Pulled: int64(v.Pulled),
+					Total:  int64(v.Total),
+				},
+			},
+		}, nil
+	case event.ImagePullFailed:
+		return &workflowproto.Event{
+			WorkflowId: v.WorkflowID,
+			Event: &workflowproto.Event_ImagePullFailed_{
+				ImagePullFailed: &workflowproto.Event_ImagePullFailed{
+					Image:   v.Image,
+					Message: v.Message,
 				},
 			},
 		}, nil
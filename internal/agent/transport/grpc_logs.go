@@ -0,0 +1,91 @@
+package transport
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	workflowproto "github.com/tinkerbell/tink/internal/proto/workflow/v2"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ShipActionLogs opens a stream publishing the output of the action identified by workflowID and
+// actionID to the server. Shipping is best effort; if publishing fails the failure is logged and
+// further output is discarded.
+func (g *GRPC) ShipActionLogs(ctx context.Context, workflowID, actionID string) (io.Writer, io.Writer, io.Closer) {
+	log := g.log.WithValues("workflow_id", workflowID, "action_id", actionID)
+
+	stream, err := g.client.PublishActionLogs(ctx)
+	if err != nil {
+		log.Info("Failed to open action log stream; discarding output", "error", err)
+		return io.Discard, io.Discard, io.NopCloser(nil)
+	}
+
+	ls := &logStream{
+		stream:     stream,
+		workflowID: workflowID,
+		actionID:   actionID,
+	}
+	ls.onFailure = func(err error) {
+		log.Info("Failed to publish action logs; discarding output", "error", err)
+	}
+
+	return logStreamWriter{ls, workflowproto.ActionLog_STREAM_STDOUT},
+		logStreamWriter{ls, workflowproto.ActionLog_STREAM_STDERR},
+		ls
+}
+
+// logStream publishes action output to the server. It is safe for concurrent use.
+type logStream struct {
+	stream     workflowproto.WorkflowService_PublishActionLogsClient
+	workflowID string
+	actionID   string
+	onFailure  func(error)
+
+	mtx    sync.Mutex
+	failed bool
+}
+
+func (s *logStream) send(kind workflowproto.ActionLog_Stream, p []byte) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.failed {
+		return
+	}
+
+	err := s.stream.Send(&workflowproto.PublishActionLogsRequest{
+		Log: &workflowproto.ActionLog{
+			WorkflowId: s.workflowID,
+			ActionId:   s.actionID,
+			Stream:     kind,
+			Data:       append([]byte(nil), p...),
+			CreatedAt:  timestamppb.Now(),
+		},
+	})
+	if err != nil {
+		s.failed = true
+		s.onFailure(err)
+	}
+}
+
+// Close closes the stream and waits for the server to acknowledge the published output.
+func (s *logStream) Close() error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	_, err := s.stream.CloseAndRecv()
+	return err
+}
+
+// logStreamWriter is an io.Writer that publishes output read from a single stream. Writes always
+// succeed so output consumers aren't interrupted by publishing failures.
+type logStreamWriter struct {
+	*logStream
+	kind workflowproto.ActionLog_Stream
+}
+
+func (w logStreamWriter) Write(p []byte) (int, error) {
+	w.send(w.kind, p)
+	return len(p), nil
+}
@@ -5,14 +5,19 @@ import (
 	"io"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/go-logr/zerologr"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/rs/zerolog"
 	"github.com/tinkerbell/tink/internal/agent/event"
 	"github.com/tinkerbell/tink/internal/agent/transport"
 	"github.com/tinkerbell/tink/internal/agent/workflow"
 	workflowproto "github.com/tinkerbell/tink/internal/proto/workflow/v2"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestGRPC(t *testing.T) {
@@ -21,7 +26,10 @@ func TestGRPC(t *testing.T) {
 		Workflow *workflowproto.GetWorkflowsResponse
 		Error    error
 	}
-	responses := make(chan streamResponse, 2)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	responses := make(chan streamResponse, 1)
 	responses <- streamResponse{
 		Workflow: &workflowproto.GetWorkflowsResponse{
 			Cmd: &workflowproto.GetWorkflowsResponse_StartWorkflow_{
@@ -31,14 +39,13 @@ func TestGRPC(t *testing.T) {
 			},
 		},
 	}
-	responses <- streamResponse{
-		Error: io.EOF,
-	}
 
 	stream := &workflowproto.WorkflowService_GetWorkflowsClientMock{
 		RecvFunc: func() (*workflowproto.GetWorkflowsResponse, error) {
 			r, ok := <-responses
 			if !ok {
+				// Start reconnects when the stream ends so stop it once the responses are consumed.
+				cancel()
 				return nil, io.EOF
 			}
 			return r.Workflow, r.Error
@@ -62,10 +69,327 @@ func TestGRPC(t *testing.T) {
 
 	g := transport.NewGRPC(zerologr.New(&logger), client)
 
-	err := g.Start(context.Background(), "id", handler)
+	err := g.Start(ctx, "id", handler)
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	wg.Wait()
 }
+
+func TestGRPCReconnects(t *testing.T) {
+	logger := zerolog.New(zerolog.NewConsoleWriter())
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// The first stream breaks as if the server restarted; the second delivers a workflow.
+	broken := &workflowproto.WorkflowService_GetWorkflowsClientMock{
+		RecvFunc: func() (*workflowproto.GetWorkflowsResponse, error) {
+			return nil, status.Error(codes.Unavailable, "server restarting")
+		},
+	}
+	delivered := false
+	healthy := &workflowproto.WorkflowService_GetWorkflowsClientMock{
+		RecvFunc: func() (*workflowproto.GetWorkflowsResponse, error) {
+			if delivered {
+				<-ctx.Done()
+				return nil, status.FromContextError(ctx.Err()).Err()
+			}
+			delivered = true
+			return &workflowproto.GetWorkflowsResponse{
+				Cmd: &workflowproto.GetWorkflowsResponse_StartWorkflow_{
+					StartWorkflow: &workflowproto.GetWorkflowsResponse_StartWorkflow{
+						Workflow: &workflowproto.Workflow{WorkflowId: "workflow"},
+					},
+				},
+			}, nil
+		},
+	}
+	streams := []workflowproto.WorkflowService_GetWorkflowsClient{broken, healthy}
+	client := &workflowproto.WorkflowServiceClientMock{
+		GetWorkflowsFunc: func(_ context.Context, _ *workflowproto.GetWorkflowsRequest, _ ...grpc.CallOption) (workflowproto.WorkflowService_GetWorkflowsClient, error) {
+			stream := streams[0]
+			streams = streams[1:]
+			return stream, nil
+		},
+	}
+	handler := &transport.WorkflowHandlerMock{
+		HandleWorkflowFunc: func(context.Context, workflow.Workflow, event.Recorder) {
+			cancel()
+		},
+	}
+
+	g := transport.NewGRPC(zerologr.New(&logger), client)
+	if err := g.Start(ctx, "id", handler); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := len(client.GetWorkflowsCalls()); got != 2 {
+		t.Fatalf("Expected 2 workflow streams; Received %v", got)
+	}
+	calls := handler.HandleWorkflowCalls()
+	if len(calls) != 1 || calls[0].WorkflowMoqParam.ID != "workflow" {
+		t.Fatalf("Expected the workflow to be handled after reconnecting; Received %v", calls)
+	}
+}
+
+func TestGRPCPauseWorkflow(t *testing.T) {
+	logger := zerolog.New(zerolog.NewConsoleWriter())
+	responses := []*workflowproto.GetWorkflowsResponse{
+		{
+			Cmd: &workflowproto.GetWorkflowsResponse_PauseWorkflow_{
+				PauseWorkflow: &workflowproto.GetWorkflowsResponse_PauseWorkflow{
+					Pause: &workflowproto.Workflow_Pause{Paused: true},
+				},
+			},
+		},
+		{
+			Cmd: &workflowproto.GetWorkflowsResponse_PauseWorkflow_{
+				PauseWorkflow: &workflowproto.GetWorkflowsResponse_PauseWorkflow{
+					WorkflowId: "workflow",
+					Pause:      &workflowproto.Workflow_Pause{Breakpoints: []string{"action"}},
+				},
+			},
+		},
+		{
+			Cmd: &workflowproto.GetWorkflowsResponse_PauseWorkflow_{
+				PauseWorkflow: &workflowproto.GetWorkflowsResponse_PauseWorkflow{
+					WorkflowId: "workflow",
+				},
+			},
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream := &workflowproto.WorkflowService_GetWorkflowsClientMock{
+		RecvFunc: func() (*workflowproto.GetWorkflowsResponse, error) {
+			if len(responses) == 0 {
+				cancel()
+				return nil, io.EOF
+			}
+			r := responses[0]
+			responses = responses[1:]
+			return r, nil
+		},
+		ContextFunc: context.Background,
+	}
+	client := &workflowproto.WorkflowServiceClientMock{
+		GetWorkflowsFunc: func(_ context.Context, _ *workflowproto.GetWorkflowsRequest, _ ...grpc.CallOption) (workflowproto.WorkflowService_GetWorkflowsClient, error) {
+			return stream, nil
+		},
+	}
+	handler := &transport.WorkflowHandlerMock{
+		PauseWorkflowFunc: func(string, workflow.Pause) {},
+	}
+
+	g := transport.NewGRPC(zerologr.New(&logger), client)
+	if err := g.Start(ctx, "id", handler); err != nil {
+		t.Fatal(err)
+	}
+
+	// The request missing a workflow ID should be dropped.
+	want := []struct {
+		WorkflowID string
+		Pause      workflow.Pause
+	}{
+		{WorkflowID: "workflow", Pause: workflow.Pause{Breakpoints: []string{"action"}}},
+		{WorkflowID: "workflow"},
+	}
+	if diff := cmp.Diff(want, handler.PauseWorkflowCalls()); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestGRPCShipActionLogs(t *testing.T) {
+	logger := zerolog.New(zerolog.NewConsoleWriter())
+
+	var sent []*workflowproto.ActionLog
+	stream := &workflowproto.WorkflowService_PublishActionLogsClientMock{
+		SendFunc: func(req *workflowproto.PublishActionLogsRequest) error {
+			sent = append(sent, req.GetLog())
+			return nil
+		},
+		CloseAndRecvFunc: func() (*workflowproto.PublishActionLogsResponse, error) {
+			return &workflowproto.PublishActionLogsResponse{}, nil
+		},
+	}
+	client := &workflowproto.WorkflowServiceClientMock{
+		PublishActionLogsFunc: func(_ context.Context, _ ...grpc.CallOption) (workflowproto.WorkflowService_PublishActionLogsClient, error) {
+			return stream, nil
+		},
+	}
+
+	g := transport.NewGRPC(zerologr.New(&logger), client)
+
+	stdout, stderr, closer := g.ShipActionLogs(context.Background(), "workflow", "action")
+	if _, err := io.WriteString(stdout, "out"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.WriteString(stderr, "err"); err != nil {
+		t.Fatal(err)
+	}
+	if err := closer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(stream.CloseAndRecvCalls()) != 1 {
+		t.Fatal("Expected the stream to be closed")
+	}
+
+	expect := []struct {
+		Stream workflowproto.ActionLog_Stream
+		Data   string
+	}{
+		{workflowproto.ActionLog_STREAM_STDOUT, "out"},
+		{workflowproto.ActionLog_STREAM_STDERR, "err"},
+	}
+	if len(sent) != len(expect) {
+		t.Fatalf("Expected %v logs; Received %v", len(expect), len(sent))
+	}
+	for i, e := range expect {
+		l := sent[i]
+		if l.GetWorkflowId() != "workflow" || l.GetActionId() != "action" {
+			t.Fatalf("Unexpected log identity: %v", l)
+		}
+		if l.GetStream() != e.Stream || string(l.GetData()) != e.Data {
+			t.Fatalf("Expected %v %q; Received %v %q", e.Stream, e.Data, l.GetStream(), l.GetData())
+		}
+	}
+}
+
+func TestGRPCRecordEvent(t *testing.T) {
+	logger := zerolog.New(zerolog.NewConsoleWriter())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	published := make(chan *workflowproto.PublishEventRequest, 1)
+	stream := &workflowproto.WorkflowService_GetWorkflowsClientMock{
+		RecvFunc: func() (*workflowproto.GetWorkflowsResponse, error) {
+			// Keep the stream open until the event is published.
+			<-published
+			cancel()
+			return nil, io.EOF
+		},
+	}
+	client := &workflowproto.WorkflowServiceClientMock{
+		GetWorkflowsFunc: func(_ context.Context, _ *workflowproto.GetWorkflowsRequest, _ ...grpc.CallOption) (workflowproto.WorkflowService_GetWorkflowsClient, error) {
+			return stream, nil
+		},
+		PublishEventFunc: func(_ context.Context, r *workflowproto.PublishEventRequest, _ ...grpc.CallOption) (*workflowproto.PublishEventResponse, error) {
+			published <- r
+			return &workflowproto.PublishEventResponse{}, nil
+		},
+	}
+
+	g := transport.NewGRPC(zerologr.New(&logger), client)
+
+	// Events recorded before the transport starts are published once it starts.
+	e := event.ActionStarted{WorkflowID: "workflow", ActionID: "action", Attempt: 1}
+	if err := g.RecordEvent(context.Background(), e); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := g.Start(ctx, "id", &transport.WorkflowHandlerMock{}); err != nil {
+		t.Fatal(err)
+	}
+
+	calls := client.PublishEventCalls()
+	if len(calls) != 1 {
+		t.Fatalf("Expected 1 published event; Received %v", len(calls))
+	}
+	r := calls[0].In
+	if r.GetEvent().GetActionStarted().GetActionId() != "action" || r.GetSequence() != 1 || r.GetSessionId() == "" {
+		t.Fatalf("Unexpected request: %v", r)
+	}
+}
+
+func TestGRPCActionDependencies(t *testing.T) {
+	logger := zerolog.New(zerolog.NewConsoleWriter())
+
+	start := func(wflw *workflowproto.Workflow) *workflowproto.GetWorkflowsResponse {
+		return &workflowproto.GetWorkflowsResponse{
+			Cmd: &workflowproto.GetWorkflowsResponse_StartWorkflow_{
+				StartWorkflow: &workflowproto.GetWorkflowsResponse_StartWorkflow{Workflow: wflw},
+			},
+		}
+	}
+	responses := []*workflowproto.GetWorkflowsResponse{
+		// Workflows with cyclic dependencies can't be run so should be dropped.
+		start(&workflowproto.Workflow{
+			WorkflowId: "cyclic",
+			Actions: []*workflowproto.Workflow_Action{
+				{Id: "1", DependsOn: []string{"2"}},
+				{Id: "2", DependsOn: []string{"1"}},
+			},
+		}),
+		// Handler actions run sequentially so can't declare dependencies.
+		start(&workflowproto.Workflow{
+			WorkflowId: "handler-dependencies",
+			Actions:    []*workflowproto.Workflow_Action{{Id: "1"}},
+			Finally:    []*workflowproto.Workflow_Action{{Id: "2", DependsOn: []string{"1"}}},
+		}),
+		start(&workflowproto.Workflow{
+			WorkflowId: "duplicate-handler",
+			Actions:    []*workflowproto.Workflow_Action{{Id: "1"}},
+			OnFailure:  []*workflowproto.Workflow_Action{{Id: "1"}},
+		}),
+		start(&workflowproto.Workflow{
+			WorkflowId: "graph",
+			Actions: []*workflowproto.Workflow_Action{
+				{Id: "1", TimeoutSeconds: 30},
+				{Id: "2", DependsOn: []string{"1"}},
+			},
+			OnFailure: []*workflowproto.Workflow_Action{{Id: "3"}},
+			Finally:   []*workflowproto.Workflow_Action{{Id: "4"}},
+		}),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream := &workflowproto.WorkflowService_GetWorkflowsClientMock{
+		RecvFunc: func() (*workflowproto.GetWorkflowsResponse, error) {
+			if len(responses) == 0 {
+				cancel()
+				return nil, io.EOF
+			}
+			r := responses[0]
+			responses = responses[1:]
+			return r, nil
+		},
+		ContextFunc: context.Background,
+	}
+	client := &workflowproto.WorkflowServiceClientMock{
+		GetWorkflowsFunc: func(_ context.Context, _ *workflowproto.GetWorkflowsRequest, _ ...grpc.CallOption) (workflowproto.WorkflowService_GetWorkflowsClient, error) {
+			return stream, nil
+		},
+	}
+
+	var handled []workflow.Workflow
+	handler := &transport.WorkflowHandlerMock{
+		HandleWorkflowFunc: func(_ context.Context, wflw workflow.Workflow, _ event.Recorder) {
+			handled = append(handled, wflw)
+		},
+	}
+
+	g := transport.NewGRPC(zerologr.New(&logger), client)
+	if err := g.Start(ctx, "id", handler); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := []workflow.Workflow{
+		{
+			ID: "graph",
+			Actions: []workflow.Action{
+				{ID: "1", Timeout: 30 * time.Second},
+				{ID: "2", DependsOn: []string{"1"}},
+			},
+			OnFailure: []workflow.Action{{ID: "3"}},
+			Finally:   []workflow.Action{{ID: "4"}},
+		},
+	}
+	if diff := cmp.Diff(expect, handled, cmpopts.EquateEmpty()); diff != "" {
+		t.Fatal(diff)
+	}
+}
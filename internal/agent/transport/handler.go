@@ -17,4 +17,8 @@ type WorkflowHandler interface {
 	// CancelWorkflow cancels a workflow identified by workflowID. It should not block and should
 	// be efficient in handing off the cancellation request.
 	CancelWorkflow(workflowID string)
+
+	// PauseWorkflow replaces the pause configuration of a workflow identified by workflowID. It
+	// should not block.
+	PauseWorkflow(workflowID string, pause workflow.Pause)
 }
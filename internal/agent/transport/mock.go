@@ -27,6 +27,9 @@ var _ WorkflowHandler = &WorkflowHandlerMock{}
 //			HandleWorkflowFunc: func(contextMoqParam context.Context, workflowMoqParam workflow.Workflow, recorder event.Recorder)  {
 //				panic("mock out the HandleWorkflow method")
 //			},
+//			PauseWorkflowFunc: func(workflowID string, pause workflow.Pause)  {
+//				panic("mock out the PauseWorkflow method")
+//			},
 //		}
 //
 //		// use mockedWorkflowHandler in code that requires WorkflowHandler
@@ -40,6 +43,9 @@ type WorkflowHandlerMock struct {
 	// HandleWorkflowFunc mocks the HandleWorkflow method.
 	HandleWorkflowFunc func(contextMoqParam context.Context, workflowMoqParam workflow.Workflow, recorder event.Recorder)
 
+	// PauseWorkflowFunc mocks the PauseWorkflow method.
+	PauseWorkflowFunc func(workflowID string, pause workflow.Pause)
+
 	// calls tracks calls to the methods.
 	calls struct {
 		// CancelWorkflow holds details about calls to the CancelWorkflow method.
@@ -56,9 +62,17 @@ type WorkflowHandlerMock struct {
 			// Recorder is the recorder argument value.
 			Recorder event.Recorder
 		}
+		// PauseWorkflow holds details about calls to the PauseWorkflow method.
+		PauseWorkflow []struct {
+			// WorkflowID is the workflowID argument value.
+			WorkflowID string
+			// Pause is the pause argument value.
+			Pause workflow.Pause
+		}
 	}
 	lockCancelWorkflow sync.RWMutex
 	lockHandleWorkflow sync.RWMutex
+	lockPauseWorkflow  sync.RWMutex
 }
 
 // CancelWorkflow calls CancelWorkflowFunc.
@@ -132,3 +146,39 @@ func (mock *WorkflowHandlerMock) HandleWorkflowCalls() []struct {
 	mock.lockHandleWorkflow.RUnlock()
 	return calls
 }
+
+// PauseWorkflow calls PauseWorkflowFunc.
+func (mock *WorkflowHandlerMock) PauseWorkflow(workflowID string, pause workflow.Pause) {
+	if mock.PauseWorkflowFunc == nil {
+		panic("WorkflowHandlerMock.PauseWorkflowFunc: method is nil but WorkflowHandler.PauseWorkflow was just called")
+	}
+	callInfo := struct {
+		WorkflowID string
+		Pause      workflow.Pause
+	}{
+		WorkflowID: workflowID,
+		Pause:      pause,
+	}
+	mock.lockPauseWorkflow.Lock()
+	mock.calls.PauseWorkflow = append(mock.calls.PauseWorkflow, callInfo)
+	mock.lockPauseWorkflow.Unlock()
+	mock.PauseWorkflowFunc(workflowID, pause)
+}
+
+// PauseWorkflowCalls gets all the calls that were made to PauseWorkflow.
+// Check the length with:
+//
+//	len(mockedWorkflowHandler.PauseWorkflowCalls())
+func (mock *WorkflowHandlerMock) PauseWorkflowCalls() []struct {
+	WorkflowID string
+	Pause      workflow.Pause
+} {
+	var calls []struct {
+		WorkflowID string
+		Pause      workflow.Pause
+	}
+	mock.lockPauseWorkflow.RLock()
+	calls = mock.calls.PauseWorkflow
+	mock.lockPauseWorkflow.RUnlock()
+	return calls
+}
@@ -0,0 +1,223 @@
+package transport
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	workflowproto "github.com/tinkerbell/tink/internal/proto/workflow/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultOutboxSize is the default number of events an Outbox holds.
+const DefaultOutboxSize = 1024
+
+const (
+	// outboxInitialBackoff is the delay before an event is published again after failing.
+	outboxInitialBackoff = 500 * time.Millisecond
+
+	// outboxMaxBackoff is the maximum delay between attempts to publish an event.
+	outboxMaxBackoff = 30 * time.Second
+)
+
+// Outbox holds events until the server acknowledges them. Events are published in the order they
+// were added and each event is retried until the server acknowledges it, or rejects it as
+// invalid. Events are numbered so the server can ignore events published more than once.
+//
+// An Outbox holds a bounded number of events in memory; adding an event to a full Outbox blocks
+// until an event is acknowledged. Events that must survive the agent restarting are retained by
+// the agent's state until they're acknowledged.
+type Outbox struct {
+	log  logr.Logger
+	size int
+
+	mtx     sync.Mutex
+	session string
+	seq     uint64
+	events  []outboxEvent
+
+	// ready is signaled when an event is added; space when an event is removed.
+	ready chan struct{}
+	space chan struct{}
+}
+
+type outboxEvent struct {
+	sequence uint64
+	event    *workflowproto.Event
+	ack      func()
+}
+
+// NewOutbox creates a new Outbox instance.
+func NewOutbox(opts ...OutboxOption) *Outbox {
+	o := &Outbox{
+		log:     logr.Discard(),
+		size:    DefaultOutboxSize,
+		session: newSession(),
+		ready:   make(chan struct{}, 1),
+		space:   make(chan struct{}, 1),
+	}
+
+	for _, fn := range opts {
+		fn(o)
+	}
+
+	return o
+}
+
+// newSession generates an ID that distinguishes the sequence numbers of one outbox from another.
+func newSession() string {
+	session := make([]byte, 16)
+	if _, err := rand.Read(session); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(session)
+}
+
+// OutboxOption defines optional configuration for an Outbox instance.
+type OutboxOption func(*Outbox)
+
+// WithOutboxLogger returns an option to configure the logger on an Outbox instance.
+func WithOutboxLogger(log logr.Logger) OutboxOption {
+	return func(o *Outbox) {
+		if log.GetSink() == nil {
+			return
+		}
+		o.log = log
+	}
+}
+
+// WithOutboxSize returns an option to configure the maximum number of events held by an Outbox
+// instance.
+func WithOutboxSize(size int) OutboxOption {
+	return func(o *Outbox) {
+		if size <= 0 {
+			return
+		}
+		o.size = size
+	}
+}
+
+// Add adds e to the outbox. It blocks while the outbox is full until ctx is done.
+func (o *Outbox) Add(ctx context.Context, e *workflowproto.Event) error {
+	return o.AddWithAck(ctx, e, nil)
+}
+
+// AddWithAck adds e to the outbox like Add and calls ack, when not nil, once the server
+// acknowledges e or rejects it as invalid.
+func (o *Outbox) AddWithAck(ctx context.Context, e *workflowproto.Event, ack func()) error {
+	for {
+		o.mtx.Lock()
+		if len(o.events) < o.size {
+			o.seq++
+			o.events = append(o.events, outboxEvent{sequence: o.seq, event: e, ack: ack})
+			o.mtx.Unlock()
+			signal(o.ready)
+			return nil
+		}
+		o.mtx.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("outbox full: %w", ctx.Err())
+		case <-o.space:
+		}
+	}
+}
+
+// Len returns the number of events waiting to be acknowledged.
+func (o *Outbox) Len() int {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	return len(o.events)
+}
+
+// Run publishes events with publish until ctx is done. Events publish fails to deliver are
+// retried with an exponential backoff unless the server rejected them as invalid.
+func (o *Outbox) Run(ctx context.Context, publish func(context.Context, *workflowproto.PublishEventRequest) error) {
+	backoff := outboxInitialBackoff
+	for {
+		o.mtx.Lock()
+		var next *outboxEvent
+		if len(o.events) > 0 {
+			next = &o.events[0]
+		}
+		request := &workflowproto.PublishEventRequest{SessionId: o.session}
+		o.mtx.Unlock()
+
+		if next == nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-o.ready:
+				continue
+			}
+		}
+
+		request.Event = next.event
+		request.Sequence = next.sequence
+		err := publish(ctx, request)
+		switch {
+		case err == nil:
+		case isPermanent(err):
+			o.log.Info("Dropping event rejected by server", "sequence", next.sequence, "error", err)
+		default:
+			if ctx.Err() != nil {
+				return
+			}
+			o.log.Info("Publish event failed; retrying", "sequence", next.sequence, "error", err, "delay", backoff.String())
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			backoff = min(backoff*2, outboxMaxBackoff)
+			continue
+		}
+
+		backoff = outboxInitialBackoff
+		o.remove(next.sequence)
+	}
+}
+
+// remove removes the event numbered seq from the head of the outbox and acknowledges it.
+func (o *Outbox) remove(seq uint64) {
+	o.mtx.Lock()
+	if len(o.events) == 0 || o.events[0].sequence != seq {
+		o.mtx.Unlock()
+		return
+	}
+	ack := o.events[0].ack
+	o.events = o.events[1:]
+	o.mtx.Unlock()
+	signal(o.space)
+
+	if ack != nil {
+		ack()
+	}
+}
+
+// isPermanent determines if err indicates the server will never accept an event. Events are
+// published in order so retrying such an event would hold back every event after it. Events the
+// agent isn't authorized to publish, for example for a Workflow that was deleted or reassigned,
+// are also dropped.
+func isPermanent(err error) bool {
+	switch status.Code(err) {
+	case codes.InvalidArgument, codes.NotFound, codes.PermissionDenied, codes.Unauthenticated:
+		return true
+	}
+	return false
+}
+
+// signal notifies a waiter on ch without blocking.
+func signal(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
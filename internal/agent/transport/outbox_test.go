@@ -0,0 +1,218 @@
+package transport_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tinkerbell/tink/internal/agent/transport"
+	workflowproto "github.com/tinkerbell/tink/internal/proto/workflow/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// publisher records the requests an outbox publishes. Requests are failed with the errors in
+// Errors, in order, before being acknowledged.
+type publisher struct {
+	mtx       sync.Mutex
+	Errors    []error
+	Requests  []*workflowproto.PublishEventRequest
+	published chan struct{}
+}
+
+func newPublisher(errs ...error) *publisher {
+	return &publisher{Errors: errs, published: make(chan struct{}, 100)}
+}
+
+func (p *publisher) Publish(_ context.Context, r *workflowproto.PublishEventRequest) error {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.Requests = append(p.Requests, r)
+	p.published <- struct{}{}
+	if len(p.Errors) > 0 {
+		err := p.Errors[0]
+		p.Errors = p.Errors[1:]
+		return err
+	}
+	return nil
+}
+
+// Wait waits for n publish attempts.
+func (p *publisher) Wait(t *testing.T, n int) {
+	t.Helper()
+	for range n {
+		select {
+		case <-p.published:
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for events to be published")
+		}
+	}
+}
+
+// Sequences returns the sequence numbers of the published requests.
+func (p *publisher) Sequences() []uint64 {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	var seqs []uint64
+	for _, r := range p.Requests {
+		seqs = append(seqs, r.GetSequence())
+	}
+	return seqs
+}
+
+func run(t *testing.T, o *transport.Outbox, p *publisher) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		o.Run(ctx, p.Publish)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+}
+
+// waitEmpty waits for all events in o to be acknowledged.
+func waitEmpty(t *testing.T, o *transport.Outbox) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for o.Len() > 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for events to be acknowledged")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func newEvent(workflowID string) *workflowproto.Event {
+	return &workflowproto.Event{
+		WorkflowId: workflowID,
+		Event: &workflowproto.Event_ActionStarted_{
+			ActionStarted: &workflowproto.Event_ActionStarted{ActionId: "action", Attempt: 1},
+		},
+	}
+}
+
+func TestOutbox(t *testing.T) {
+	o := transport.NewOutbox()
+
+	for _, id := range []string{"1", "2", "3"} {
+		if err := o.Add(context.Background(), newEvent(id)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	p := newPublisher(status.Error(codes.Unavailable, "unavailable"))
+	run(t, o, p)
+	p.Wait(t, 4)
+
+	if diff := cmp.Diff([]uint64{1, 1, 2, 3}, p.Sequences()); diff != "" {
+		t.Fatal(diff)
+	}
+
+	session := p.Requests[0].GetSessionId()
+	if session == "" {
+		t.Fatal("Expected a session ID")
+	}
+	for i, r := range p.Requests {
+		if r.GetSessionId() != session {
+			t.Fatalf("Request %v: session = %v; expected %v", i, r.GetSessionId(), session)
+		}
+	}
+
+	var workflows []string
+	for _, r := range p.Requests[1:] {
+		workflows = append(workflows, r.GetEvent().GetWorkflowId())
+	}
+	if diff := cmp.Diff([]string{"1", "2", "3"}, workflows); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestOutboxDropsRejectedEvents(t *testing.T) {
+	// Events the server rejects are dropped so they don't hold back the events after them.
+	for _, code := range []codes.Code{codes.InvalidArgument, codes.NotFound, codes.PermissionDenied, codes.Unauthenticated} {
+		t.Run(code.String(), func(t *testing.T) {
+			o := transport.NewOutbox()
+
+			for _, id := range []string{"1", "2"} {
+				if err := o.Add(context.Background(), newEvent(id)); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			p := newPublisher(status.Error(code, "rejected"))
+			run(t, o, p)
+			p.Wait(t, 2)
+			waitEmpty(t, o)
+
+			if diff := cmp.Diff([]uint64{1, 2}, p.Sequences()); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestOutboxAck(t *testing.T) {
+	o := transport.NewOutbox()
+
+	acked := make(chan string, 2)
+	for _, id := range []string{"1", "2"} {
+		if err := o.AddWithAck(context.Background(), newEvent(id), func() { acked <- id }); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Events aren't acknowledged until the server accepts them.
+	p := newPublisher(status.Error(codes.Unavailable, "unavailable"))
+	run(t, o, p)
+	p.Wait(t, 1)
+	select {
+	case id := <-acked:
+		t.Fatalf("Unexpected acknowledgement of event %v", id)
+	default:
+	}
+
+	var got []string
+	for range 2 {
+		select {
+		case id := <-acked:
+			got = append(got, id)
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for acknowledgement")
+		}
+	}
+	if diff := cmp.Diff([]string{"1", "2"}, got); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestOutboxFull(t *testing.T) {
+	o := transport.NewOutbox(transport.WithOutboxSize(1))
+
+	if err := o.Add(context.Background(), newEvent("1")); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := o.Add(ctx, newEvent("2")); err == nil {
+		t.Fatal("Expected error adding to a full outbox")
+	}
+
+	p := newPublisher()
+	run(t, o, p)
+
+	if err := o.Add(context.Background(), newEvent("2")); err != nil {
+		t.Fatal(err)
+	}
+	p.Wait(t, 2)
+
+	if diff := cmp.Diff([]uint64{1, 2}, p.Sequences()); diff != "" {
+		t.Fatal(diff)
+	}
+}
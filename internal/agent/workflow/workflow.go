@@ -2,27 +2,186 @@
 // /internal/workflow at a later date when they are required/we transition to the new codebase.
 package workflow
 
+import (
+	"slices"
+	"time"
+)
+
 // Workflow represents a runnable workflow for the Handler.
 type Workflow struct {
 	// Do we need a workflow name? Does that even come down in the proto definition?
 	ID      string   `yaml:"id"`
 	Actions []Action `yaml:"actions"`
+
+	// ConditionData is the data action conditions are evaluated against.
+	ConditionData map[string]any `yaml:"conditionData"`
+
+	// OnFailure are actions run sequentially when an action in Actions fails.
+	OnFailure []Action `yaml:"onFailure"`
+
+	// Finally are actions run sequentially once Actions, and OnFailure if applicable, have
+	// finished regardless of whether they succeeded.
+	Finally []Action `yaml:"finally"`
+
+	// Pause prevents actions from starting. Actions already running are unaffected.
+	Pause Pause `yaml:"pause"`
+
+	// Results are the outcomes of actions that finished before the workflow was received, for
+	// example before the machine rebooted, keyed by action ID. These actions aren't run again.
+	Results map[string]Result `yaml:"results"`
+}
+
+// Result is the outcome of an action that finished.
+type Result struct {
+	// Succeeded indicates the action succeeded or was skipped.
+	Succeeded bool `yaml:"succeeded"`
+
+	// Outputs are the outputs of the action.
+	Outputs map[string]string `yaml:"outputs"`
 }
 
 func (w Workflow) String() string {
 	return w.ID
 }
 
+// Dependencies returns the IDs of the actions each action depends on keyed by action ID. When no
+// action declares dependencies, each action depends on the action preceding it so actions are
+// run sequentially.
+func (w Workflow) Dependencies() map[string][]string {
+	deps := make(map[string][]string, len(w.Actions))
+
+	if !slices.ContainsFunc(w.Actions, func(a Action) bool { return len(a.DependsOn) > 0 }) {
+		for i := 1; i < len(w.Actions); i++ {
+			deps[w.Actions[i].ID] = []string{w.Actions[i-1].ID}
+		}
+		return deps
+	}
+
+	for _, a := range w.Actions {
+		deps[a.ID] = a.DependsOn
+	}
+	return deps
+}
+
+// Pause describes the actions of a workflow that must not start. The zero value doesn't hold any
+// action.
+type Pause struct {
+	// Paused holds every action.
+	Paused bool `yaml:"paused"`
+
+	// Breakpoints lists the IDs of actions that are held.
+	Breakpoints []string `yaml:"breakpoints"`
+}
+
+// Holds determines if the action identified by id must not start.
+func (p Pause) Holds(id string) bool {
+	return p.Paused || slices.Contains(p.Breakpoints, id)
+}
+
 // Action represents an individually runnable action.
 type Action struct {
 	ID               string            `yaml:"id"`
 	Name             string            `yaml:"name"`
+	Type             ActionType        `yaml:"type"`
 	Image            string            `yaml:"image"`
 	Cmd              string            `yaml:"cmd"`
 	Args             []string          `yaml:"args"`
 	Env              map[string]string `yaml:"env"`
 	Volumes          []string          `yaml:"volumes"`
 	NetworkNamespace string            `yaml:"networkNamespace"`
+	Retry            RetryPolicy       `yaml:"retry"`
+
+	// DependsOn lists the IDs of actions that must succeed before the action is run.
+	DependsOn []string `yaml:"dependsOn"`
+
+	// Timeout is the maximum time the action may run for, including retries. When 0, the action
+	// has no timeout.
+	Timeout time.Duration `yaml:"timeout"`
+
+	// When is a condition evaluated against the workflow's ConditionData immediately before the
+	// action runs. When false, the action is skipped. See the when package for details.
+	When string `yaml:"when"`
+
+	// PIDNamespace is the PID namespace the action is run in. When empty, the action is run in
+	// its own PID namespace.
+	PIDNamespace string `yaml:"pidNamespace"`
+
+	// Privileged runs the action with all capabilities and access to all host devices.
+	Privileged bool `yaml:"privileged"`
+
+	// Capabilities modifies the default Linux capabilities of the action.
+	Capabilities Capabilities `yaml:"capabilities"`
+
+	// Devices are host devices made available to the action of the form
+	// host-path[:container-path[:permissions]].
+	Devices []string `yaml:"devices"`
+
+	// Resources limits the resources available to the action.
+	Resources Resources `yaml:"resources"`
+
+	// Idempotent indicates the action can safely be re-run from the start when it's interrupted
+	// by an agent restart.
+	Idempotent bool `yaml:"idempotent"`
+
+	// Kexec is the kernel booted by ActionTypeKexec actions.
+	Kexec Kexec `yaml:"kexec"`
+}
+
+// ActionType describes how an action is performed.
+type ActionType string
+
+const (
+	// ActionTypeContainer actions run their image using the container runtime. It's the zero
+	// value.
+	ActionTypeContainer ActionType = ""
+
+	// ActionTypeReboot actions reboot the machine.
+	ActionTypeReboot ActionType = "Reboot"
+
+	// ActionTypeKexec actions boot a new kernel without returning to the firmware.
+	ActionTypeKexec ActionType = "Kexec"
+)
+
+// Kexec defines the kernel booted by a kexec action.
+type Kexec struct {
+	// BlockDevice is the device containing the kernel. When empty, paths are on the agent's
+	// filesystem.
+	BlockDevice string `yaml:"blockDevice"`
+
+	// FSType is the filesystem type of BlockDevice.
+	FSType string `yaml:"fsType"`
+
+	// KernelPath is the path to the kernel.
+	KernelPath string `yaml:"kernelPath"`
+
+	// InitrdPath is the path to the initial ramdisk. When empty, no ramdisk is loaded.
+	InitrdPath string `yaml:"initrdPath"`
+
+	// Cmdline is the kernel command line.
+	Cmdline string `yaml:"cmdline"`
+}
+
+// Capabilities defines modifications to the default Linux capabilities of an action.
+type Capabilities struct {
+	Add  []string `yaml:"add"`
+	Drop []string `yaml:"drop"`
+}
+
+// Resources defines the maximum resources available to an action. Zero values are unlimited.
+type Resources struct {
+	// Memory is the maximum memory in bytes.
+	Memory int64 `yaml:"memory"`
+
+	// NanoCPUs is the maximum CPU time in billionths of a CPU.
+	NanoCPUs int64 `yaml:"nanoCPUs"`
+
+	// PIDs is the maximum number of processes.
+	PIDs int64 `yaml:"pids"`
+}
+
+// IsZero reports whether r doesn't limit any resource.
+func (r Resources) IsZero() bool {
+	return r == Resources{}
 }
 
 func (a Action) String() string {
@@ -31,3 +190,41 @@ func (a Action) String() string {
 	// retrieving names.
 	return a.ID
 }
+
+// maxRetryBackoff caps the delay between action retries.
+const maxRetryBackoff = 5 * time.Minute
+
+// RetryPolicy defines how an action is retried when it fails. The zero value disables retries.
+type RetryPolicy struct {
+	// Retries is the maximum number of times the action is retried after the initial attempt.
+	Retries int `yaml:"retries"`
+
+	// Backoff is the delay before the first retry. The delay doubles for each subsequent retry.
+	Backoff time.Duration `yaml:"backoff"`
+
+	// Reasons restricts retries to failures with one of the listed reasons. When empty, all
+	// failures are retried.
+	Reasons []string `yaml:"reasons"`
+}
+
+// ShouldRetry determines if an action that failed with reason on attempt, starting from 1,
+// should be retried.
+func (p RetryPolicy) ShouldRetry(attempt int, reason string) bool {
+	if attempt > p.Retries {
+		return false
+	}
+	if len(p.Reasons) == 0 {
+		return true
+	}
+	return slices.Contains(p.Reasons, reason)
+}
+
+// Delay returns the time to wait before retrying an action that failed on attempt, starting
+// from 1.
+func (p RetryPolicy) Delay(attempt int) time.Duration {
+	delay := p.Backoff
+	for i := 1; i < attempt && delay < maxRetryBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryBackoff)
+}
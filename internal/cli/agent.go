@@ -6,18 +6,42 @@ import (
 	"github.com/go-logr/zapr"
 	"github.com/spf13/cobra"
 	"github.com/tinkerbell/tink/internal/agent"
+	"github.com/tinkerbell/tink/internal/agent/cosign"
+	"github.com/tinkerbell/tink/internal/agent/reboot"
 	"github.com/tinkerbell/tink/internal/agent/runtime"
+	"github.com/tinkerbell/tink/internal/agent/state"
 	"github.com/tinkerbell/tink/internal/agent/transport"
+	"github.com/tinkerbell/tink/internal/client"
 	"github.com/tinkerbell/tink/internal/proto/workflow/v2"
+	"github.com/tinkerbell/tink/internal/registry"
 	"go.uber.org/zap"
-	"google.golang.org/grpc"
+)
+
+// Container runtimes supported by the agent.
+const (
+	runtimeDocker     = "docker"
+	runtimeContainerd = "containerd"
+	runtimeProcess    = "process"
 )
 
 // NewAgent builds a command that launches the agent component.
 func NewAgent() *cobra.Command {
 	var opts struct {
-		AgentID        string
-		TinkServerAddr string
+		AgentID             string
+		TinkServerAddr      string
+		Runtime             string
+		ContainerdAddress   string
+		ContainerdNamespace string
+		RootfsDir           string
+		RegistryConfig      string
+		RegistryMirrors     []string
+		PullConcurrency     int
+		CosignPublicKeys    []string
+		StateFile           string
+		EventOutboxSize     int
+		TLSCertFile         string
+		TLSKeyFile          string
+		TLSCAFile           string
 	}
 
 	// TODO(chrisdoherty4) Handle signals
@@ -30,23 +54,79 @@ func NewAgent() *cobra.Command {
 			}
 			logger := zapr.NewLogger(zl)
 
-			rntime, err := runtime.NewDocker()
+			reg, err := registry.Load(opts.RegistryConfig, opts.RegistryMirrors)
+			if err != nil {
+				return err
+			}
+
+			var rntime agent.ContainerRuntime
+			switch opts.Runtime {
+			case runtimeDocker:
+				rntime, err = runtime.NewDocker(runtime.WithLogger(logger), runtime.WithRegistry(reg))
+			case runtimeContainerd:
+				rntime, err = runtime.NewContainerd(
+					runtime.WithContainerdLogger(logger),
+					runtime.WithContainerdRegistry(reg),
+					runtime.WithContainerdAddress(opts.ContainerdAddress),
+					runtime.WithContainerdNamespace(opts.ContainerdNamespace),
+				)
+			case runtimeProcess:
+				rntime = runtime.NewProcess(
+					runtime.WithProcessLogger(logger),
+					runtime.WithRootfsDir(opts.RootfsDir),
+				)
+			default:
+				err = fmt.Errorf("unknown runtime: %v", opts.Runtime)
+			}
 			if err != nil {
 				return fmt.Errorf("create runtime: %w", err)
 			}
 
-			conn, err := grpc.NewClient(opts.TinkServerAddr)
+			var verifier agent.ImageVerifier
+			if len(opts.CosignPublicKeys) > 0 {
+				if opts.Runtime == runtimeProcess {
+					return fmt.Errorf("image verification is not supported by the %v runtime", runtimeProcess)
+				}
+				keys, err := cosign.LoadPublicKeys(opts.CosignPublicKeys...)
+				if err != nil {
+					return err
+				}
+				verifier = cosign.NewVerifier(keys, cosign.WithRegistry(reg))
+			}
+
+			var store agent.StateStore
+			if opts.StateFile != "" {
+				store = state.NewFile(opts.StateFile)
+			}
+
+			conn, err := client.NewClientConn(
+				opts.TinkServerAddr,
+				opts.TLSCertFile != "" || opts.TLSCAFile != "",
+				false,
+				client.WithClientCertificate(opts.TLSCertFile, opts.TLSKeyFile),
+				client.WithCAFile(opts.TLSCAFile),
+			)
 			if err != nil {
 				return fmt.Errorf("dial tink server: %w", err)
 			}
 			defer conn.Close()
-			trnport := transport.NewGRPC(logger, workflow.NewWorkflowServiceClient(conn))
+
+			outbox := transport.NewOutbox(
+				transport.WithOutboxLogger(logger),
+				transport.WithOutboxSize(opts.EventOutboxSize),
+			)
+			trnport := transport.NewGRPC(logger, workflow.NewWorkflowServiceClient(conn), transport.WithOutbox(outbox))
 
 			return (&agent.Agent{
-				Log:       logger,
-				ID:        opts.AgentID,
-				Transport: trnport,
-				Runtime:   rntime,
+				Log:             logger,
+				ID:              opts.AgentID,
+				Transport:       trnport,
+				Runtime:         rntime,
+				Logs:            trnport,
+				PullConcurrency: opts.PullConcurrency,
+				Verifier:        verifier,
+				State:           store,
+				Rebooter:        reboot.System{},
 			}).Start(cmd.Context())
 		},
 	}
@@ -54,6 +134,33 @@ func NewAgent() *cobra.Command {
 	flgs := cmd.Flags()
 	flgs.StringVar(&opts.AgentID, "agent-id", "", "An ID that uniquely identifies the agent instance")
 	flgs.StringVar(&opts.TinkServerAddr, "tink-server-addr", "127.0.0.1:42113", "Tink server address")
+	flgs.StringVar(&opts.Runtime, "runtime", runtimeDocker,
+		fmt.Sprintf("The container runtime used to run actions (%v, %v, %v)", runtimeDocker, runtimeContainerd, runtimeProcess))
+	flgs.StringVar(&opts.ContainerdAddress, "containerd-address", runtime.DefaultContainerdAddress,
+		"The containerd socket address; used with the containerd runtime")
+	flgs.StringVar(&opts.ContainerdNamespace, "containerd-namespace", runtime.DefaultContainerdNamespace,
+		"The containerd namespace actions are run in; used with the containerd runtime")
+	flgs.StringVar(&opts.RootfsDir, "rootfs-dir", runtime.DefaultRootfsDir,
+		"The directory containing pre-extracted root filesystems; used with the process runtime")
+	flgs.StringVar(&opts.RegistryConfig, "registry-config", "",
+		"A Docker config.json file containing registry credentials used to pull action images")
+	flgs.StringSliceVar(&opts.RegistryMirrors, "registry-mirror", nil,
+		"A mirror images from a registry are pulled from of the form registry=mirror, for example docker.io=harbor.example.com/dockerhub; may be repeated")
+	flgs.IntVar(&opts.PullConcurrency, "pull-concurrency", agent.DefaultPullConcurrency,
+		"The maximum number of images pulled concurrently before running a workflow")
+	flgs.StringSliceVar(&opts.CosignPublicKeys, "cosign-public-keys", nil,
+		"PEM files containing public keys; when set, actions only run if their image has a cosign signature made by one of the keys")
+	flgs.StringVar(&opts.StateFile, "state-file", "",
+		"A file the agent persists its state to so workflows interrupted by the agent restarting are recovered and events are retained until the server acknowledges them; disabled when empty")
+	flgs.IntVar(&opts.EventOutboxSize, "event-outbox-size", transport.DefaultOutboxSize,
+		"The maximum number of events waiting to be published; recording an event blocks when the outbox is full")
+
+	flgs.StringVar(&opts.TLSCertFile, "tls-cert-file", "",
+		"A client certificate presented to the Tink server; its common name must be a MAC address of the Hardware the agent runs on. Connects via TLS when set")
+	flgs.StringVar(&opts.TLSKeyFile, "tls-key-file", "",
+		"The private key for the client certificate")
+	flgs.StringVar(&opts.TLSCAFile, "tls-ca-file", "",
+		"A PEM file of CA certificates used to verify the Tink server instead of the system's. Connects via TLS when set")
 
 	return &cmd
 }
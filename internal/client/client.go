@@ -2,26 +2,100 @@ package client
 
 import (
 	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"time"
 
 	"github.com/pkg/errors"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
 )
 
-func NewClientConn(authority string, tlsEnabled bool, tlsInsecure bool) (*grpc.ClientConn, error) {
+// keepaliveParams configures clients to probe idle connections so long-lived streams, such as watching
+// workflow contexts, detect a server that has gone away. The interval must not be less than the
+// server's minimum.
+var keepaliveParams = keepalive.ClientParameters{
+	Time:                30 * time.Second,
+	Timeout:             10 * time.Second,
+	PermitWithoutStream: true,
+}
+
+// Option configures optional behavior of client connections.
+type Option func(*options)
+
+type options struct {
+	certFile string
+	keyFile  string
+	caFile   string
+}
+
+// WithClientCertificate presents the certificate and key in certFile and keyFile to the server
+// when connecting via TLS. The server identifies the client by the certificate's common name.
+func WithClientCertificate(certFile, keyFile string) Option {
+	return func(o *options) {
+		o.certFile = certFile
+		o.keyFile = keyFile
+	}
+}
+
+// WithCAFile verifies the server's certificate against the PEM encoded CA certificates in caFile
+// instead of the system's roots when connecting via TLS.
+func WithCAFile(caFile string) Option {
+	return func(o *options) {
+		o.caFile = caFile
+	}
+}
+
+func NewClientConn(authority string, tlsEnabled bool, tlsInsecure bool, opts ...Option) (*grpc.ClientConn, error) {
 	var creds grpc.DialOption
-	if tlsEnabled { // #nosec G402
-		creds = grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{InsecureSkipVerify: tlsInsecure}))
+	if tlsEnabled {
+		cfg, err := tlsConfig(tlsInsecure, opts...)
+		if err != nil {
+			return nil, err
+		}
+		creds = grpc.WithTransportCredentials(credentials.NewTLS(cfg))
 	} else {
 		creds = grpc.WithTransportCredentials(insecure.NewCredentials())
 	}
 
-	conn, err := grpc.NewClient(authority, creds, grpc.WithStatsHandler(otelgrpc.NewClientHandler()))
+	conn, err := grpc.NewClient(authority, creds, grpc.WithStatsHandler(otelgrpc.NewClientHandler()), grpc.WithKeepaliveParams(keepaliveParams))
 	if err != nil {
 		return nil, errors.Wrap(err, "dial tinkerbell server")
 	}
 
 	return conn, nil
 }
+
+func tlsConfig(tlsInsecure bool, opts ...Option) (*tls.Config, error) {
+	var o options
+	for _, fn := range opts {
+		fn(&o)
+	}
+
+	cfg := &tls.Config{InsecureSkipVerify: tlsInsecure} // #nosec G402
+
+	if o.certFile != "" || o.keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(o.certFile, o.keyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "load client certificate")
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	if o.caFile != "" {
+		pem, err := os.ReadFile(o.caFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "read CA file")
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.Errorf("no certificates found in CA file: %v", o.caFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	return cfg, nil
+}
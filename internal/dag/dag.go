@@ -0,0 +1,77 @@
+// Package dag provides utilities for working with directed acyclic graphs of named nodes such as
+// workflow actions that depend on one another.
+package dag
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Sort orders nodes so every node appears after the nodes it depends on. deps maps a node to the
+// nodes it depends on. Nodes are otherwise kept in the order given so a graph whose nodes are
+// already ordered is returned unchanged.
+//
+// Sort returns an error if nodes contains duplicates, a node depends on itself or an unknown
+// node, or the graph contains a cycle.
+func Sort(nodes []string, deps map[string][]string) ([]string, error) {
+	index := make(map[string]int, len(nodes))
+	for i, n := range nodes {
+		if _, ok := index[n]; ok {
+			return nil, fmt.Errorf("duplicate node: %v", n)
+		}
+		index[n] = i
+	}
+
+	// remaining counts the unsatisfied dependencies of each node and dependents is the reverse
+	// of deps.
+	remaining := make([]int, len(nodes))
+	dependents := make([][]int, len(nodes))
+	for i, n := range nodes {
+		seen := map[string]bool{}
+		for _, d := range deps[n] {
+			if d == n {
+				return nil, fmt.Errorf("%v depends on itself", n)
+			}
+			j, ok := index[d]
+			if !ok {
+				return nil, fmt.Errorf("%v depends on unknown node: %v", n, d)
+			}
+			if seen[d] {
+				continue
+			}
+			seen[d] = true
+			remaining[i]++
+			dependents[j] = append(dependents[j], i)
+		}
+	}
+
+	sorted := make([]string, 0, len(nodes))
+	done := make([]bool, len(nodes))
+	for len(sorted) < len(nodes) {
+		// Pick the first node, in the given order, with all dependencies satisfied.
+		next := -1
+		for i := range nodes {
+			if !done[i] && remaining[i] == 0 {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			var cycle []string
+			for i, n := range nodes {
+				if !done[i] {
+					cycle = append(cycle, n)
+				}
+			}
+			return nil, fmt.Errorf("dependency cycle between: %v", strings.Join(cycle, ", "))
+		}
+
+		done[next] = true
+		sorted = append(sorted, nodes[next])
+		for _, d := range dependents[next] {
+			remaining[d]--
+		}
+	}
+
+	return sorted, nil
+}
@@ -0,0 +1,77 @@
+package dag_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tinkerbell/tink/internal/dag"
+)
+
+func TestSort(t *testing.T) {
+	cases := []struct {
+		name    string
+		nodes   []string
+		deps    map[string][]string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:  "no dependencies",
+			nodes: []string{"a", "b", "c"},
+			want:  []string{"a", "b", "c"},
+		},
+		{
+			name:  "already ordered",
+			nodes: []string{"a", "b", "c"},
+			deps:  map[string][]string{"b": {"a"}, "c": {"a", "b"}},
+			want:  []string{"a", "b", "c"},
+		},
+		{
+			name:  "reordered",
+			nodes: []string{"a", "b", "c"},
+			deps:  map[string][]string{"a": {"c"}},
+			want:  []string{"b", "c", "a"},
+		},
+		{
+			name:  "duplicate dependency",
+			nodes: []string{"a", "b"},
+			deps:  map[string][]string{"b": {"a", "a"}},
+			want:  []string{"a", "b"},
+		},
+		{
+			name:    "cycle",
+			nodes:   []string{"a", "b", "c"},
+			deps:    map[string][]string{"a": {"c"}, "c": {"a"}},
+			wantErr: true,
+		},
+		{
+			name:    "self dependency",
+			nodes:   []string{"a"},
+			deps:    map[string][]string{"a": {"a"}},
+			wantErr: true,
+		},
+		{
+			name:    "unknown dependency",
+			nodes:   []string{"a"},
+			deps:    map[string][]string{"a": {"b"}},
+			wantErr: true,
+		},
+		{
+			name:    "duplicate node",
+			nodes:   []string{"a", "a"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := dag.Sort(tc.nodes, tc.deps)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Sort() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
@@ -1,11 +1,15 @@
 package workflow
 
 import (
+	"encoding/json"
 	"fmt"
 	"sort"
 
 	"github.com/tinkerbell/tink/api/v1alpha1"
 	"github.com/tinkerbell/tink/internal/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 func ToWorkflowContext(wf *v1alpha1.Workflow) *proto.WorkflowContext {
@@ -29,8 +33,14 @@ func YAMLToStatus(wf *Workflow) *v1alpha1.WorkflowStatus {
 	}
 	tasks := []v1alpha1.Task{}
 	for _, task := range wf.Tasks {
+		// Templates are validated on render so sorting only fails for unvalidated workflows, in
+		// which case the declared order is kept.
+		ordered, err := sortActions(task.Actions)
+		if err != nil {
+			ordered = task.Actions
+		}
 		actions := []v1alpha1.Action{}
-		for _, action := range task.Actions {
+		for _, action := range ordered {
 			actions = append(actions, v1alpha1.Action{
 				Name:        action.Name,
 				Image:       action.Image,
@@ -40,6 +50,12 @@ func YAMLToStatus(wf *Workflow) *v1alpha1.WorkflowStatus {
 				Status:      v1alpha1.WorkflowState(proto.State_name[int32(proto.State_STATE_PENDING)]),
 				Environment: action.Environment,
 				Pid:         action.Pid,
+				Retries:     action.Retries,
+				Backoff:     action.Backoff,
+				RetryOn:     action.RetryOn,
+				DependsOn:   action.DependsOn,
+				When:        action.When,
+				Resources:   toResourceLimits(wf.Resources, action.Resources),
 			})
 		}
 		tasks = append(tasks, v1alpha1.Task{
@@ -56,6 +72,54 @@ func YAMLToStatus(wf *Workflow) *v1alpha1.WorkflowStatus {
 	}
 }
 
+// toResourceLimits combines the workflow level resources with those of an action. Limits of the
+// action take precedence. Invalid quantities are ignored as templates are validated on render.
+func toResourceLimits(workflow, action *Resources) *v1alpha1.ResourceLimits {
+	var merged Resources
+	for _, r := range []*Resources{workflow, action} {
+		if r == nil {
+			continue
+		}
+		if r.Memory != "" {
+			merged.Memory = r.Memory
+		}
+		if r.CPU != "" {
+			merged.CPU = r.CPU
+		}
+		if r.PIDs != 0 {
+			merged.PIDs = r.PIDs
+		}
+	}
+	if merged == (Resources{}) {
+		return nil
+	}
+
+	limits := &v1alpha1.ResourceLimits{PIDs: merged.PIDs}
+	if q, err := resource.ParseQuantity(merged.Memory); err == nil {
+		limits.Memory = &q
+	}
+	if q, err := resource.ParseQuantity(merged.CPU); err == nil {
+		limits.CPU = &q
+	}
+	return limits
+}
+
+// ConditionData returns the data action conditions of wf are evaluated against. It is the data
+// exposed to the Template when rendered for hardware using the field names of its JSON
+// representation.
+func ConditionData(wf *v1alpha1.Workflow, hardware v1alpha1.Hardware) (*structpb.Struct, error) {
+	raw, err := json.Marshal(templateData(wf, hardware))
+	if err != nil {
+		return nil, fmt.Errorf("marshal condition data: %w", err)
+	}
+
+	var data structpb.Struct
+	if err := protojson.Unmarshal(raw, &data); err != nil {
+		return nil, fmt.Errorf("unmarshal condition data: %w", err)
+	}
+	return &data, nil
+}
+
 func ActionListCRDToProto(wf *v1alpha1.Workflow) *proto.WorkflowActionList {
 	if wf == nil {
 		return nil
@@ -90,9 +154,28 @@ func ActionListCRDToProto(wf *v1alpha1.Workflow) *proto.WorkflowActionList {
 					sort.Strings(resp)
 					return resp
 				}(task.Environment),
-				Pid: action.Pid,
+				Pid:       action.Pid,
+				Retries:   action.Retries,
+				Backoff:   action.Backoff,
+				RetryOn:   action.RetryOn,
+				When:      action.When,
+				Resources: toResourceLimitsProto(action.Resources),
 			})
 		}
 	}
 	return resp
 }
+
+func toResourceLimitsProto(r *v1alpha1.ResourceLimits) *proto.ResourceLimits {
+	if r == nil {
+		return nil
+	}
+	limits := &proto.ResourceLimits{Pids: r.PIDs}
+	if r.Memory != nil {
+		limits.MemoryBytes = r.Memory.Value()
+	}
+	if r.CPU != nil {
+		limits.NanoCpus = r.CPU.ScaledValue(resource.Nano)
+	}
+	return limits
+}
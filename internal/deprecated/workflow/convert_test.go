@@ -9,6 +9,7 @@ import (
 	"github.com/tinkerbell/tink/internal/proto"
 	"github.com/tinkerbell/tink/internal/testtime"
 	"google.golang.org/protobuf/testing/protocmp"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -311,14 +312,160 @@ func TestYAMLToStatus(t *testing.T) {
 				},
 			},
 		},
+		{
+			"Actions ordered by dependencies",
+			&Workflow{
+				GlobalTimeout: 600,
+				Tasks: []Task{
+					{
+						Name:       "provision",
+						WorkerAddr: "00:00:53:00:53:F4",
+						Actions: []Action{
+							{Name: "install", Image: "install", DependsOn: []string{"partition"}},
+							{Name: "partition", Image: "partition", When: "true"},
+						},
+					},
+				},
+			},
+			&v1alpha1.WorkflowStatus{
+				GlobalTimeout: 600,
+				Tasks: []v1alpha1.Task{
+					{
+						Name:       "provision",
+						WorkerAddr: "00:00:53:00:53:F4",
+						Actions: []v1alpha1.Action{
+							{Name: "partition", Image: "partition", Status: "STATE_PENDING", When: "true"},
+							{Name: "install", Image: "install", Status: "STATE_PENDING", DependsOn: []string{"partition"}},
+						},
+					},
+				},
+			},
+		},
+		{
+			"Action resources override template resources",
+			&Workflow{
+				GlobalTimeout: 600,
+				Resources:     &Resources{Memory: "1Gi", PIDs: 1000},
+				Tasks: []Task{
+					{
+						Name:       "provision",
+						WorkerAddr: "00:00:53:00:53:F4",
+						Actions: []Action{
+							{Name: "partition", Image: "partition"},
+							{Name: "install", Image: "install", Resources: &Resources{Memory: "512Mi", CPU: "500m"}},
+						},
+					},
+				},
+			},
+			&v1alpha1.WorkflowStatus{
+				GlobalTimeout: 600,
+				Tasks: []v1alpha1.Task{
+					{
+						Name:       "provision",
+						WorkerAddr: "00:00:53:00:53:F4",
+						Actions: []v1alpha1.Action{
+							{
+								Name:      "partition",
+								Image:     "partition",
+								Status:    "STATE_PENDING",
+								Resources: &v1alpha1.ResourceLimits{Memory: quantity("1Gi"), PIDs: 1000},
+							},
+							{
+								Name:   "install",
+								Image:  "install",
+								Status: "STATE_PENDING",
+								Resources: &v1alpha1.ResourceLimits{
+									Memory: quantity("512Mi"),
+									CPU:    quantity("500m"),
+									PIDs:   1000,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
 			got := YAMLToStatus(tc.inputWf)
-			if diff := cmp.Diff(got, tc.want, protocmp.Transform()); diff != "" {
+			if diff := cmp.Diff(got, tc.want, protocmp.Transform(), cmp.Comparer(quantityEqual)); diff != "" {
 				t.Errorf("unexpected difference:\n%v", diff)
 			}
 		})
 	}
 }
+
+func TestActionListCRDToProtoResources(t *testing.T) {
+	wf := &v1alpha1.Workflow{
+		Status: v1alpha1.WorkflowStatus{
+			Tasks: []v1alpha1.Task{
+				{
+					Name: "provision",
+					Actions: []v1alpha1.Action{
+						{
+							Name: "install",
+							Resources: &v1alpha1.ResourceLimits{
+								Memory: quantity("512Mi"),
+								CPU:    quantity("1500m"),
+								PIDs:   100,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	want := &proto.ResourceLimits{
+		MemoryBytes: 512 << 20,
+		NanoCpus:    1_500_000_000,
+		Pids:        100,
+	}
+	got := ActionListCRDToProto(wf).GetActionList()[0].GetResources()
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("unexpected difference:\n%v", diff)
+	}
+}
+
+func quantity(s string) *resource.Quantity {
+	q := resource.MustParse(s)
+	return &q
+}
+
+func quantityEqual(a, b resource.Quantity) bool {
+	return a.Cmp(b) == 0
+}
+
+func TestConditionData(t *testing.T) {
+	wf := &v1alpha1.Workflow{
+		Spec: v1alpha1.WorkflowSpec{
+			HardwareMap: map[string]string{"device_1": "00:00:53:00:53:F4"},
+		},
+	}
+	hw := v1alpha1.Hardware{
+		Spec: v1alpha1.HardwareSpec{
+			Disks: []v1alpha1.Disk{{Device: "/dev/sda"}},
+		},
+	}
+
+	got, err := ConditionData(wf, hw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]any{
+		"device_1": "00:00:53:00:53:F4",
+		"Hardware": map[string]any{
+			"Disks":      []any{"/dev/sda"},
+			"Interfaces": nil,
+			"UserData":   "",
+			"Metadata":   map[string]any{},
+			"VendorData": "",
+		},
+	}
+	if diff := cmp.Diff(want, got.AsMap()); diff != "" {
+		t.Errorf("unexpected difference:\n%v", diff)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	rufio "github.com/tinkerbell/rufio/api/v1alpha1"
 	"github.com/tinkerbell/tink/api/v1alpha1"
 	"github.com/tinkerbell/tink/internal/deprecated/workflow/journal"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
@@ -39,12 +40,20 @@ func (s *state) postActions(ctx context.Context) (reconcile.Result, error) {
 
 			r, err := s.handleJob(ctx, actions, name)
 			if s.workflow.Status.BootOptions.Jobs[name.String()].Complete {
-				s.workflow.Status.State = v1alpha1.WorkflowStateSuccess
+				s.workflow.Status.State = s.completedState()
 			}
 			return r, err
 		}
 	}
 
-	s.workflow.Status.State = v1alpha1.WorkflowStateSuccess
+	s.workflow.Status.State = s.completedState()
 	return reconcile.Result{}, nil
 }
+
+// completedState returns the state a Workflow enters once post actions are complete.
+func (s *state) completedState() v1alpha1.WorkflowState {
+	if s.workflow.Status.HasCondition(v1alpha1.WorkflowCanceled, metav1.ConditionTrue) {
+		return v1alpha1.WorkflowStateCanceled
+	}
+	return v1alpha1.WorkflowStateSuccess
+}
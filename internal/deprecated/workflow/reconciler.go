@@ -83,6 +83,15 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 
 	wflow := stored.DeepCopy()
 
+	if wflow.Spec.Cancel && isCancelable(wflow.Status.State) {
+		journal.Log(ctx, "canceling workflow")
+		r.cancelWorkflow(wflow)
+
+		return reconcile.Result{}, mergePatchStatus(ctx, r.client, stored, wflow)
+	}
+
+	r.setPausedCondition(wflow)
+
 	switch wflow.Status.State {
 	case "":
 		journal.Log(ctx, "new workflow")
@@ -114,7 +123,10 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		rc, err := s.postActions(ctx)
 
 		return rc, serrors.Join(err, mergePatchStatus(ctx, r.client, stored, wflow))
-	case v1alpha1.WorkflowStatePending, v1alpha1.WorkflowStateTimeout, v1alpha1.WorkflowStateFailed, v1alpha1.WorkflowStateSuccess:
+	case v1alpha1.WorkflowStatePending:
+		journal.Log(ctx, "pending workflow")
+		return reconcile.Result{}, mergePatchStatus(ctx, r.client, stored, wflow)
+	case v1alpha1.WorkflowStateTimeout, v1alpha1.WorkflowStateFailed, v1alpha1.WorkflowStateSuccess, v1alpha1.WorkflowStateCanceled:
 		journal.Log(ctx, "controller will not trigger another reconcile", "state", wflow.Status.State)
 		return reconcile.Result{}, nil
 	}
@@ -200,12 +212,7 @@ func (r *Reconciler) processNewWorkflow(ctx context.Context, logger logr.Logger,
 		)
 	}
 
-	data := make(map[string]interface{})
-	for key, val := range stored.Spec.HardwareMap {
-		data[key] = val
-	}
-	contract := toTemplateHardwareData(hardware)
-	data["Hardware"] = contract
+	data := templateData(stored, hardware)
 
 	tinkWf, err := renderTemplateHardware(stored.Name, ptr.StringValue(tpl.Spec.Data), data)
 	if err != nil {
@@ -241,6 +248,16 @@ func (r *Reconciler) processNewWorkflow(ctx context.Context, logger logr.Logger,
 	return reconcile.Result{}, nil
 }
 
+// templateData returns the data exposed to the Template of wf when rendered for hardware.
+func templateData(wf *v1alpha1.Workflow, hardware v1alpha1.Hardware) map[string]interface{} {
+	data := make(map[string]interface{})
+	for key, val := range wf.Spec.HardwareMap {
+		data[key] = val
+	}
+	data["Hardware"] = toTemplateHardwareData(hardware)
+	return data
+}
+
 // templateHardwareData defines the data exposed for a Hardware instance to a Template.
 type templateHardwareData struct {
 	Disks      []string
@@ -298,3 +315,82 @@ func (r *Reconciler) processRunningWorkflow(stored *v1alpha1.Workflow) {
 		}
 	}
 }
+
+// setPausedCondition records whether stored is paused, as determined by stored.IsPaused(), in its
+// Paused condition. The condition is only added once the Workflow pauses.
+func (r *Reconciler) setPausedCondition(stored *v1alpha1.Workflow) {
+	paused := stored.IsPaused()
+	if !paused && !stored.Status.HasCondition(v1alpha1.WorkflowPaused, metav1.ConditionTrue) {
+		return
+	}
+
+	cond := v1alpha1.WorkflowCondition{
+		Type:    v1alpha1.WorkflowPaused,
+		Status:  metav1.ConditionFalse,
+		Reason:  "Resumed",
+		Message: "workflow resumed",
+	}
+	switch {
+	case paused && stored.Spec.Paused:
+		cond.Status = metav1.ConditionTrue
+		cond.Reason = "Paused"
+		cond.Message = fmt.Sprintf("workflow paused before action %v", stored.GetCurrentAction())
+	case paused:
+		cond.Status = metav1.ConditionTrue
+		cond.Reason = "Breakpoint"
+		cond.Message = fmt.Sprintf("workflow paused at breakpoint before action %v",
+			v1alpha1.BreakpointKey(stored.GetCurrentTask(), stored.GetCurrentAction()))
+	}
+
+	// Only update the condition when it changes so the transition time is preserved.
+	for _, c := range stored.Status.Conditions {
+		if c.Type == cond.Type && c.Status == cond.Status && c.Reason == cond.Reason && c.Message == cond.Message {
+			return
+		}
+	}
+	cond.Time = &metav1.Time{Time: r.nowFunc().UTC()}
+	stored.Status.SetCondition(cond)
+}
+
+// isCancelable determines if a Workflow in state can be canceled. Workflows that have finished
+// executing actions can no longer be canceled.
+func isCancelable(state v1alpha1.WorkflowState) bool {
+	switch state { //nolint:exhaustive // Only states prior to completion are cancelable.
+	case "", v1alpha1.WorkflowStatePreparing, v1alpha1.WorkflowStatePending, v1alpha1.WorkflowStateRunning:
+		return true
+	}
+	return false
+}
+
+// cancelWorkflow marks the current action of stored as canceled so workers stop executing it. If
+// the Workflow has been rendered it's transitioned to the post state so any boot options are
+// reverted before it enters the canceled state.
+func (r *Reconciler) cancelWorkflow(stored *v1alpha1.Workflow) {
+	stored.Status.SetCondition(v1alpha1.WorkflowCondition{
+		Type:    v1alpha1.WorkflowCanceled,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Canceled",
+		Message: "workflow canceled",
+		Time:    &metav1.Time{Time: r.nowFunc().UTC()},
+	})
+
+	if stored.Status.State == "" {
+		stored.Status.State = v1alpha1.WorkflowStateCanceled
+		return
+	}
+	stored.Status.State = v1alpha1.WorkflowStatePost
+
+	for ti, task := range stored.Status.Tasks {
+		for ai, action := range task.Actions {
+			if action.Status != v1alpha1.WorkflowStatePending && action.Status != v1alpha1.WorkflowStateRunning {
+				continue
+			}
+			stored.Status.Tasks[ti].Actions[ai].Status = v1alpha1.WorkflowStateCanceled
+			stored.Status.Tasks[ti].Actions[ai].Message = "Action canceled"
+			if action.StartedAt != nil {
+				stored.Status.Tasks[ti].Actions[ai].Seconds = int64(r.nowFunc().Sub(action.StartedAt.Time).Seconds())
+			}
+			return
+		}
+	}
+}
@@ -952,6 +952,358 @@ tasks:
 			},
 			wantErr: nil,
 		},
+		{
+			name: "CanceledRunningWorkflow",
+			seedWorkflow: &v1alpha1.Workflow{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "Workflow",
+					APIVersion: "tinkerbell.org/v1alpha1",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "debian",
+					Namespace: "default",
+				},
+				Spec: v1alpha1.WorkflowSpec{
+					TemplateRef: "debian",
+					Cancel:      true,
+				},
+				Status: v1alpha1.WorkflowStatus{
+					State:         v1alpha1.WorkflowStateRunning,
+					GlobalTimeout: 600,
+					Tasks: []v1alpha1.Task{
+						{
+							Name:       "os-installation",
+							WorkerAddr: "3c:ec:ef:4c:4f:54",
+							Actions: []v1alpha1.Action{
+								{
+									Name:   "stream-debian-image",
+									Status: v1alpha1.WorkflowStateSuccess,
+								},
+								{
+									Name:      "kexec",
+									Status:    v1alpha1.WorkflowStateRunning,
+									StartedAt: TestTime.MetaV1BeforeSec(30),
+								},
+								{
+									Name:   "reboot",
+									Status: v1alpha1.WorkflowStatePending,
+								},
+							},
+						},
+					},
+				},
+			},
+			req: reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      "debian",
+					Namespace: "default",
+				},
+			},
+			want: reconcile.Result{},
+			wantWflow: &v1alpha1.Workflow{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "Workflow",
+					APIVersion: "tinkerbell.org/v1alpha1",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					ResourceVersion: "1000",
+					Name:            "debian",
+					Namespace:       "default",
+				},
+				Spec: v1alpha1.WorkflowSpec{
+					TemplateRef: "debian",
+					Cancel:      true,
+				},
+				Status: v1alpha1.WorkflowStatus{
+					State:         v1alpha1.WorkflowStatePost,
+					GlobalTimeout: 600,
+					Conditions: []v1alpha1.WorkflowCondition{
+						{Type: v1alpha1.WorkflowCanceled, Status: metav1.ConditionTrue, Reason: "Canceled", Message: "workflow canceled"},
+					},
+					Tasks: []v1alpha1.Task{
+						{
+							Name:       "os-installation",
+							WorkerAddr: "3c:ec:ef:4c:4f:54",
+							Actions: []v1alpha1.Action{
+								{
+									Name:   "stream-debian-image",
+									Status: v1alpha1.WorkflowStateSuccess,
+								},
+								{
+									Name:      "kexec",
+									Status:    v1alpha1.WorkflowStateCanceled,
+									StartedAt: TestTime.MetaV1BeforeSec(30),
+									Seconds:   30,
+									Message:   "Action canceled",
+								},
+								{
+									Name:   "reboot",
+									Status: v1alpha1.WorkflowStatePending,
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "CanceledWorkflowPostActions",
+			seedWorkflow: &v1alpha1.Workflow{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "Workflow",
+					APIVersion: "tinkerbell.org/v1alpha1",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "debian",
+					Namespace: "default",
+				},
+				Spec: v1alpha1.WorkflowSpec{
+					TemplateRef: "debian",
+					Cancel:      true,
+				},
+				Status: v1alpha1.WorkflowStatus{
+					State: v1alpha1.WorkflowStatePost,
+					Conditions: []v1alpha1.WorkflowCondition{
+						{Type: v1alpha1.WorkflowCanceled, Status: metav1.ConditionTrue, Reason: "Canceled", Message: "workflow canceled"},
+					},
+				},
+			},
+			req: reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      "debian",
+					Namespace: "default",
+				},
+			},
+			want: reconcile.Result{},
+			wantWflow: &v1alpha1.Workflow{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "Workflow",
+					APIVersion: "tinkerbell.org/v1alpha1",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					ResourceVersion: "1000",
+					Name:            "debian",
+					Namespace:       "default",
+				},
+				Spec: v1alpha1.WorkflowSpec{
+					TemplateRef: "debian",
+					Cancel:      true,
+				},
+				Status: v1alpha1.WorkflowStatus{
+					State: v1alpha1.WorkflowStateCanceled,
+					Conditions: []v1alpha1.WorkflowCondition{
+						{Type: v1alpha1.WorkflowCanceled, Status: metav1.ConditionTrue, Reason: "Canceled", Message: "workflow canceled"},
+					},
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "CanceledNewWorkflow",
+			seedWorkflow: &v1alpha1.Workflow{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "Workflow",
+					APIVersion: "tinkerbell.org/v1alpha1",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "debian",
+					Namespace: "default",
+				},
+				Spec: v1alpha1.WorkflowSpec{
+					TemplateRef: "debian",
+					Cancel:      true,
+				},
+			},
+			req: reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      "debian",
+					Namespace: "default",
+				},
+			},
+			want: reconcile.Result{},
+			wantWflow: &v1alpha1.Workflow{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "Workflow",
+					APIVersion: "tinkerbell.org/v1alpha1",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					ResourceVersion: "1000",
+					Name:            "debian",
+					Namespace:       "default",
+				},
+				Spec: v1alpha1.WorkflowSpec{
+					TemplateRef: "debian",
+					Cancel:      true,
+				},
+				Status: v1alpha1.WorkflowStatus{
+					State: v1alpha1.WorkflowStateCanceled,
+					Conditions: []v1alpha1.WorkflowCondition{
+						{Type: v1alpha1.WorkflowCanceled, Status: metav1.ConditionTrue, Reason: "Canceled", Message: "workflow canceled"},
+					},
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "BreakpointRunningWorkflow",
+			seedWorkflow: &v1alpha1.Workflow{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "Workflow",
+					APIVersion: "tinkerbell.org/v1alpha1",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "debian",
+					Namespace: "default",
+				},
+				Spec: v1alpha1.WorkflowSpec{
+					TemplateRef: "debian",
+					Breakpoints: []string{"os-installation/kexec"},
+				},
+				Status: v1alpha1.WorkflowStatus{
+					State:         v1alpha1.WorkflowStateRunning,
+					GlobalTimeout: 600,
+					Tasks: []v1alpha1.Task{
+						{
+							Name:       "os-installation",
+							WorkerAddr: "3c:ec:ef:4c:4f:54",
+							Actions: []v1alpha1.Action{
+								{
+									Name:      "stream-debian-image",
+									Status:    v1alpha1.WorkflowStateSuccess,
+									StartedAt: TestTime.MetaV1BeforeSec(30),
+								},
+								{
+									Name:   "kexec",
+									Status: v1alpha1.WorkflowStatePending,
+								},
+							},
+						},
+					},
+				},
+			},
+			req: reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      "debian",
+					Namespace: "default",
+				},
+			},
+			want: reconcile.Result{},
+			wantWflow: &v1alpha1.Workflow{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "Workflow",
+					APIVersion: "tinkerbell.org/v1alpha1",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					ResourceVersion: "1000",
+					Name:            "debian",
+					Namespace:       "default",
+				},
+				Spec: v1alpha1.WorkflowSpec{
+					TemplateRef: "debian",
+					Breakpoints: []string{"os-installation/kexec"},
+				},
+				Status: v1alpha1.WorkflowStatus{
+					State:         v1alpha1.WorkflowStateRunning,
+					GlobalTimeout: 600,
+					Conditions: []v1alpha1.WorkflowCondition{
+						{Type: v1alpha1.WorkflowPaused, Status: metav1.ConditionTrue, Reason: "Breakpoint", Message: "workflow paused at breakpoint before action os-installation/kexec"},
+					},
+					Tasks: []v1alpha1.Task{
+						{
+							Name:       "os-installation",
+							WorkerAddr: "3c:ec:ef:4c:4f:54",
+							Actions: []v1alpha1.Action{
+								{
+									Name:      "stream-debian-image",
+									Status:    v1alpha1.WorkflowStateSuccess,
+									StartedAt: TestTime.MetaV1BeforeSec(30),
+								},
+								{
+									Name:   "kexec",
+									Status: v1alpha1.WorkflowStatePending,
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "ResumedPendingWorkflow",
+			seedWorkflow: &v1alpha1.Workflow{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "Workflow",
+					APIVersion: "tinkerbell.org/v1alpha1",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "debian",
+					Namespace: "default",
+				},
+				Spec: v1alpha1.WorkflowSpec{
+					TemplateRef: "debian",
+				},
+				Status: v1alpha1.WorkflowStatus{
+					State: v1alpha1.WorkflowStatePending,
+					Conditions: []v1alpha1.WorkflowCondition{
+						{Type: v1alpha1.WorkflowPaused, Status: metav1.ConditionTrue, Reason: "Paused", Message: "workflow paused before action stream-debian-image"},
+					},
+					Tasks: []v1alpha1.Task{
+						{
+							Name:       "os-installation",
+							WorkerAddr: "3c:ec:ef:4c:4f:54",
+							Actions: []v1alpha1.Action{
+								{
+									Name:   "stream-debian-image",
+									Status: v1alpha1.WorkflowStatePending,
+								},
+							},
+						},
+					},
+				},
+			},
+			req: reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      "debian",
+					Namespace: "default",
+				},
+			},
+			want: reconcile.Result{},
+			wantWflow: &v1alpha1.Workflow{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "Workflow",
+					APIVersion: "tinkerbell.org/v1alpha1",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					ResourceVersion: "1000",
+					Name:            "debian",
+					Namespace:       "default",
+				},
+				Spec: v1alpha1.WorkflowSpec{
+					TemplateRef: "debian",
+				},
+				Status: v1alpha1.WorkflowStatus{
+					State: v1alpha1.WorkflowStatePending,
+					Conditions: []v1alpha1.WorkflowCondition{
+						{Type: v1alpha1.WorkflowPaused, Status: metav1.ConditionFalse, Reason: "Resumed", Message: "workflow resumed"},
+					},
+					Tasks: []v1alpha1.Task{
+						{
+							Name:       "os-installation",
+							WorkerAddr: "3c:ec:ef:4c:4f:54",
+							Actions: []v1alpha1.Action{
+								{
+									Name:   "stream-debian-image",
+									Status: v1alpha1.WorkflowStatePending,
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: nil,
+		},
 	}
 
 	for _, tc := range cases {
@@ -8,7 +8,9 @@ import (
 	"github.com/Masterminds/sprig/v3"
 	"github.com/distribution/reference"
 	"github.com/pkg/errors"
+	"github.com/tinkerbell/tink/internal/dag"
 	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 const (
@@ -74,6 +76,10 @@ func validate(wf *Workflow) error {
 		return errors.New("template must have at least one task defined")
 	}
 
+	if err := validateResources(wf.Resources); err != nil {
+		return errors.Errorf("invalid template resources: %v", err)
+	}
+
 	taskNameMap := make(map[string]struct{})
 	for _, task := range wf.Tasks {
 		if !hasValidLength(task.Name) {
@@ -95,16 +101,51 @@ func validate(wf *Workflow) error {
 				return errors.Errorf("invalid action image (%s): %v", action.Image, err)
 			}
 
+			if action.Retries < 0 || action.Backoff < 0 {
+				return errors.Errorf("action retries and backoff cannot be negative: %s", action.Name)
+			}
+
+			if err := validateResources(action.Resources); err != nil {
+				return errors.Errorf("invalid action resources (%s): %v", action.Name, err)
+			}
+
 			_, ok := actionNameMap[action.Name]
 			if ok {
 				return errors.Errorf("two actions in a task cannot have same name: %s", action.Name)
 			}
 			actionNameMap[action.Name] = struct{}{}
 		}
+
+		if _, err := sortActions(task.Actions); err != nil {
+			return errors.Errorf("invalid action dependencies in task %s: %v", task.Name, err)
+		}
 	}
 	return nil
 }
 
+// sortActions orders actions so each action follows the actions it depends on.
+func sortActions(actions []Action) ([]Action, error) {
+	names := make([]string, 0, len(actions))
+	deps := make(map[string][]string, len(actions))
+	byName := make(map[string]Action, len(actions))
+	for _, action := range actions {
+		names = append(names, action.Name)
+		deps[action.Name] = action.DependsOn
+		byName[action.Name] = action
+	}
+
+	sorted, err := dag.Sort(names, deps)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Action, 0, len(sorted))
+	for _, name := range sorted {
+		result = append(result, byName[name])
+	}
+	return result, nil
+}
+
 func hasValidLength(name string) bool {
 	return len(name) > 0 && len(name) < 200
 }
@@ -113,3 +154,25 @@ func validateImageName(name string) error {
 	_, err := reference.ParseNormalizedNamed(name)
 	return err
 }
+
+func validateResources(r *Resources) error {
+	if r == nil {
+		return nil
+	}
+	for name, value := range map[string]string{"memory": r.Memory, "cpu": r.CPU} {
+		if value == "" {
+			continue
+		}
+		q, err := resource.ParseQuantity(value)
+		if err != nil {
+			return errors.Errorf("%s: %v", name, err)
+		}
+		if q.Sign() <= 0 {
+			return errors.Errorf("%s must be positive", name)
+		}
+	}
+	if r.PIDs < 0 {
+		return errors.New("pids cannot be negative")
+	}
+	return nil
+}
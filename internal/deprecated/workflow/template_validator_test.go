@@ -128,10 +128,38 @@ func TestValidateTemplate(t *testing.T) {
 			wf:            toWorkflow(withActionInvalidImage()),
 			expectedError: true,
 		},
+		{
+			name:          "action depends on unknown action",
+			wf:            toWorkflow(withActionUnknownDependency()),
+			expectedError: true,
+		},
+		{
+			name:          "action dependencies are cyclic",
+			wf:            toWorkflow(withActionCyclicDependency()),
+			expectedError: true,
+		},
+		{
+			name:          "template resources are invalid",
+			wf:            toWorkflow(withTemplateInvalidResources()),
+			expectedError: true,
+		},
+		{
+			name:          "action resources are invalid",
+			wf:            toWorkflow(withActionInvalidResources()),
+			expectedError: true,
+		},
 		{
 			name: "valid task name",
 			wf:   toWorkflow(),
 		},
+		{
+			name: "valid action dependencies",
+			wf:   toWorkflow(withActionDependencies()),
+		},
+		{
+			name: "valid resources",
+			wf:   toWorkflow(withResources()),
+		},
 	}
 	for _, test := range testCases {
 		t.Run(test.name, func(t *testing.T) {
@@ -238,8 +266,43 @@ func withActionInvalidImage() workflowModifier {
 	return func(wf *Workflow) { wf.Tasks[0].Actions[0].Image = "action-image-with-$#@-" }
 }
 
+func withActionInvalidResources() workflowModifier {
+	return func(wf *Workflow) { wf.Tasks[0].Actions[0].Resources = &Resources{CPU: "-1"} }
+}
+
+func withActionUnknownDependency() workflowModifier {
+	return func(wf *Workflow) { wf.Tasks[0].Actions[0].DependsOn = []string{"unknown"} }
+}
+
+func withActionCyclicDependency() workflowModifier {
+	return func(wf *Workflow) {
+		wf.Tasks[0].Actions[0].DependsOn = []string{"install-grub"}
+		wf.Tasks[0].Actions[3].DependsOn = []string{"disk-wipe"}
+	}
+}
+
+// valid action modifiers
+
+func withActionDependencies() workflowModifier {
+	return func(wf *Workflow) {
+		wf.Tasks[0].Actions[0].DependsOn = []string{"disk-partition"}
+		wf.Tasks[0].Actions[3].DependsOn = []string{"disk-wipe", "install-root-fs"}
+	}
+}
+
+func withResources() workflowModifier {
+	return func(wf *Workflow) {
+		wf.Resources = &Resources{Memory: "1Gi", CPU: "2", PIDs: 1000}
+		wf.Tasks[0].Actions[0].Resources = &Resources{Memory: "512Mi", CPU: "500m"}
+	}
+}
+
 // invalid template modifiers
 
+func withTemplateInvalidResources() workflowModifier {
+	return func(wf *Workflow) { wf.Resources = &Resources{Memory: "lots"} }
+}
+
 func withTemplateInvalidName() workflowModifier {
 	return func(wf *Workflow) { wf.Name = "" }
 }
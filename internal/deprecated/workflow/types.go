@@ -2,11 +2,12 @@ package workflow
 
 // Workflow represents a workflow to be executed.
 type Workflow struct {
-	Version       string `yaml:"version"`
-	Name          string `yaml:"name"`
-	ID            string `yaml:"id"`
-	GlobalTimeout int    `yaml:"global_timeout"`
-	Tasks         []Task `yaml:"tasks"`
+	Version       string     `yaml:"version"`
+	Name          string     `yaml:"name"`
+	ID            string     `yaml:"id"`
+	GlobalTimeout int        `yaml:"global_timeout"`
+	Tasks         []Task     `yaml:"tasks"`
+	Resources     *Resources `yaml:"resources,omitempty"`
 }
 
 // Task represents a task to be executed as part of a workflow.
@@ -29,4 +30,19 @@ type Action struct {
 	Volumes     []string          `yaml:"volumes,omitempty"`
 	Environment map[string]string `yaml:"environment,omitempty"`
 	Pid         string            `yaml:"pid,omitempty"`
+	Retries     int64             `yaml:"retries,omitempty"`
+	Backoff     int64             `yaml:"backoff,omitempty"`
+	RetryOn     []string          `yaml:"retry-on,omitempty"`
+	DependsOn   []string          `yaml:"depends-on,omitempty"`
+	When        string            `yaml:"when,omitempty"`
+	Resources   *Resources        `yaml:"resources,omitempty"`
+}
+
+// Resources limits the resources available to an action's container. Memory and CPU are
+// Kubernetes quantities, for example 512Mi and 500m. Resources defined on the workflow apply to
+// all actions; resources defined on an action take precedence.
+type Resources struct {
+	Memory string `yaml:"memory,omitempty"`
+	CPU    string `yaml:"cpu,omitempty"`
+	PIDs   int64  `yaml:"pids,omitempty"`
 }
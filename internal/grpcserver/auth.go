@@ -0,0 +1,106 @@
+package grpcserver
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	healthgrpc "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/peer"
+	reflectionv1 "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	reflectionv1alpha "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+)
+
+// unauthorizedServices are served to any client without authorization. They expose the server's
+// health and, when registered, the API schema but no Workflow data.
+var unauthorizedServices = []string{
+	healthgrpc.Health_ServiceDesc.ServiceName,
+	reflectionv1.ServerReflection_ServiceDesc.ServiceName,
+	reflectionv1alpha.ServerReflection_ServiceDesc.ServiceName,
+}
+
+// isUnauthorized reports whether fullMethod belongs to one of unauthorizedServices.
+func isUnauthorized(fullMethod string) bool {
+	service, _, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	return slices.Contains(unauthorizedServices, service)
+}
+
+// Authorizer authorizes requests made by authenticated clients.
+type Authorizer interface {
+	// Authorize returns an error if the client identified by identity may not make req. Streaming
+	// requests are authorized for each message received from the client. The returned error
+	// should be a gRPC status error.
+	Authorize(_ context.Context, identity string, req any) error
+}
+
+// Identity returns the identity of the client that made the request associated with ctx. Clients
+// are identified by the common name of the client certificate they presented, which must have
+// been verified against the server's client CAs.
+func Identity(ctx context.Context) (string, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return "", false
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return "", false
+	}
+	if len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return "", false
+	}
+	identity := info.State.VerifiedChains[0][0].Subject.CommonName
+	return identity, identity != ""
+}
+
+// unaryAuthorizer returns an interceptor that authorizes unary requests with a. Requests to
+// unauthorizedServices aren't authorized.
+func unaryAuthorizer(a Authorizer) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if isUnauthorized(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		if err := authorize(ctx, a, req); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// streamAuthorizer returns an interceptor that authorizes each message received on a stream with
+// a. Streams to unauthorizedServices aren't authorized.
+func streamAuthorizer(a Authorizer) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if isUnauthorized(info.FullMethod) {
+			return handler(srv, ss)
+		}
+		if _, ok := Identity(ss.Context()); !ok {
+			return status.Error(codes.Unauthenticated, "client certificate required")
+		}
+		return handler(srv, &authorizedStream{ServerStream: ss, authorizer: a})
+	}
+}
+
+// authorizedStream authorizes each message received from the client.
+type authorizedStream struct {
+	grpc.ServerStream
+	authorizer Authorizer
+}
+
+func (s *authorizedStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return authorize(s.Context(), s.authorizer, m)
+}
+
+func authorize(ctx context.Context, a Authorizer, req any) error {
+	identity, ok := Identity(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "client certificate required")
+	}
+	return a.Authorize(ctx, identity, req)
+}
@@ -0,0 +1,99 @@
+package grpcserver
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// authorizerFunc adapts a function to an Authorizer.
+type authorizerFunc func(ctx context.Context, identity string, req any) error
+
+func (f authorizerFunc) Authorize(ctx context.Context, identity string, req any) error {
+	return f(ctx, identity, req)
+}
+
+func withIdentity(identity string) context.Context {
+	return peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{
+			State: tls.ConnectionState{
+				VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: identity}}}},
+			},
+		},
+	})
+}
+
+func TestUnaryAuthorizer(t *testing.T) {
+	authorizer := authorizerFunc(func(_ context.Context, identity string, _ any) error {
+		if identity != "worker" {
+			return status.Error(codes.PermissionDenied, "permission denied")
+		}
+		return nil
+	})
+	interceptor := unaryAuthorizer(authorizer)
+	handler := func(context.Context, any) (any, error) { return "ok", nil }
+
+	cases := []struct {
+		name     string
+		ctx      context.Context
+		method   string
+		wantCode codes.Code
+	}{
+		{"Authorized", withIdentity("worker"), "/proto.WorkflowService/GetWorkflowActions", codes.OK},
+		{"Denied", withIdentity("other"), "/proto.WorkflowService/GetWorkflowActions", codes.PermissionDenied},
+		{"NoCertificate", context.Background(), "/proto.WorkflowService/GetWorkflowActions", codes.Unauthenticated},
+		{"Health", context.Background(), "/grpc.health.v1.Health/Check", codes.OK},
+		{"Reflection", context.Background(), "/grpc.reflection.v1.ServerReflection/ServerReflectionInfo", codes.OK},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := interceptor(tc.ctx, nil, &grpc.UnaryServerInfo{FullMethod: tc.method}, handler)
+			if got := status.Code(err); got != tc.wantCode {
+				t.Fatalf("Unexpected code: got %v, want %v (%v)", got, tc.wantCode, err)
+			}
+		})
+	}
+}
+
+// serverStream is a fake server stream for ctx.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context { return s.ctx }
+
+func TestStreamAuthorizer(t *testing.T) {
+	interceptor := streamAuthorizer(authorizerFunc(func(context.Context, string, any) error { return nil }))
+	handler := func(any, grpc.ServerStream) error { return nil }
+
+	cases := []struct {
+		name     string
+		ctx      context.Context
+		method   string
+		wantCode codes.Code
+	}{
+		{"Authenticated", withIdentity("worker"), "/proto.WorkflowService/GetWorkflowContexts", codes.OK},
+		{"NoCertificate", context.Background(), "/proto.WorkflowService/GetWorkflowContexts", codes.Unauthenticated},
+		{"Health", context.Background(), "/grpc.health.v1.Health/Watch", codes.OK},
+		{"Reflection", context.Background(), "/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo", codes.OK},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := interceptor(nil, &serverStream{ctx: tc.ctx}, &grpc.StreamServerInfo{FullMethod: tc.method}, handler)
+			if got := status.Code(err); got != tc.wantCode {
+				t.Fatalf("Unexpected code: got %v, want %v (%v)", got, tc.wantCode, err)
+			}
+		})
+	}
+}
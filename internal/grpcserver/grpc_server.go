@@ -2,31 +2,99 @@ package grpcserver
 
 import (
 	"context"
+	"crypto/tls"
 	"net"
+	"time"
 
 	grpcprometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
 	"github.com/pkg/errors"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthgrpc "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/reflection"
 )
 
+const (
+	// keepaliveTime is the duration after which an idle connection is probed.
+	keepaliveTime = 30 * time.Second
+
+	// keepaliveTimeout is the duration to wait for a probe to be acknowledged before closing
+	// the connection.
+	keepaliveTimeout = 10 * time.Second
+
+	// keepaliveMinTime is the minimum duration clients should wait between probes.
+	keepaliveMinTime = 15 * time.Second
+)
+
 // Registrar is an interface for registering APIs on a gRPC server.
 type Registrar interface {
 	Register(*grpc.Server)
 }
 
+// Option configures optional behavior of the gRPC server.
+type Option func(*options)
+
+type options struct {
+	tls        *tls.Config
+	authorizer Authorizer
+}
+
+// WithTLS serves the gRPC server over TLS configured by cfg. Clients are authenticated when cfg
+// requires and verifies client certificates.
+func WithTLS(cfg *tls.Config) Option {
+	return func(o *options) {
+		o.tls = cfg
+	}
+}
+
+// WithAuthorizer authorizes every request with a. Requests from clients that haven't presented
+// a verified client certificate are rejected so a requires WithTLS to verify client certificates.
+func WithAuthorizer(a Authorizer) Option {
+	return func(o *options) {
+		o.authorizer = a
+	}
+}
+
 // SetupGRPC opens a listener and serves a given Registrar's APIs on a gRPC server and returns the listener's address or an error.
-func SetupGRPC(ctx context.Context, r Registrar, listenAddr string, errCh chan<- error) (string, error) {
+func SetupGRPC(ctx context.Context, r Registrar, listenAddr string, errCh chan<- error, opts ...Option) (string, error) {
+	var o options
+	for _, fn := range opts {
+		fn(&o)
+	}
+
+	unary := []grpc.UnaryServerInterceptor{grpcprometheus.UnaryServerInterceptor}
+	stream := []grpc.StreamServerInterceptor{grpcprometheus.StreamServerInterceptor}
+	if o.authorizer != nil {
+		unary = append(unary, unaryAuthorizer(o.authorizer))
+		stream = append(stream, streamAuthorizer(o.authorizer))
+	}
+
 	params := []grpc.ServerOption{
+		// Workers hold GetWorkflowContexts streams open so connections are probed to detect
+		// workers that have gone away without closing them.
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			Time:    keepaliveTime,
+			Timeout: keepaliveTimeout,
+		}),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             keepaliveMinTime,
+			PermitWithoutStream: true,
+		}),
 		grpc.StatsHandler(otelgrpc.NewServerHandler()),
-		grpc.UnaryInterceptor(grpcprometheus.UnaryServerInterceptor),
-		grpc.StreamInterceptor(grpcprometheus.StreamServerInterceptor),
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+	}
+	if o.tls != nil {
+		params = append(params, grpc.Creds(credentials.NewTLS(o.tls)))
 	}
 
 	// register servers
 	s := grpc.NewServer(params...)
 	r.Register(s)
+	healthgrpc.RegisterHealthServer(s, health.NewServer())
 	reflection.Register(s)
 	grpcprometheus.Register(s)
 
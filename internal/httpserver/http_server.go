@@ -2,6 +2,7 @@ package httpserver
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"net/http"
 	"runtime"
@@ -18,18 +19,46 @@ var (
 	logger    logr.Logger
 )
 
+// Option configures optional behavior of the HTTP server.
+type Option func(*options)
+
+type options struct {
+	tls *tls.Config
+}
+
+// WithTLS serves the HTTP server over TLS configured by cfg. cfg must provide the server's
+// certificate.
+func WithTLS(cfg *tls.Config) Option {
+	return func(o *options) {
+		o.tls = cfg
+	}
+}
+
 // SetupHTTP setup and return an HTTP server.
-func SetupHTTP(ctx context.Context, logger logr.Logger, authority string, errCh chan<- error) {
+func SetupHTTP(ctx context.Context, logger logr.Logger, authority string, errCh chan<- error, opts ...Option) {
+	var o options
+	for _, fn := range opts {
+		fn(&o)
+	}
+
 	http.Handle("/metrics", promhttp.Handler())
 	http.HandleFunc("/version", getGitRevJSONHandler())
 	http.HandleFunc("/healthz", healthCheckHandler)
 
 	srv := &http.Server{ //nolint:gosec // TODO: fix Potential Slowloris Attack because ReadHeaderTimeout is not configured
-		Addr: authority,
+		Addr:      authority,
+		TLSConfig: o.tls,
 	}
 	go func() {
-		logger.Info("serving http")
-		err := srv.ListenAndServe()
+		var err error
+		if o.tls != nil {
+			logger.Info("serving https")
+			// The certificate is provided by the TLS config.
+			err = srv.ListenAndServeTLS("", "")
+		} else {
+			logger.Info("serving http")
+			err = srv.ListenAndServe()
+		}
 		if errors.Is(err, http.ErrServerClosed) {
 			err = nil
 		}
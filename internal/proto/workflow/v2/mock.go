@@ -23,6 +23,12 @@ var _ WorkflowServiceClient = &WorkflowServiceClientMock{}
 //			GetWorkflowsFunc: func(ctx context.Context, in *GetWorkflowsRequest, opts ...grpc.CallOption) (WorkflowService_GetWorkflowsClient, error) {
 //				panic("mock out the GetWorkflows method")
 //			},
+//			ListActionLogsFunc: func(ctx context.Context, in *ListActionLogsRequest, opts ...grpc.CallOption) (*ListActionLogsResponse, error) {
+//				panic("mock out the ListActionLogs method")
+//			},
+//			PublishActionLogsFunc: func(ctx context.Context, opts ...grpc.CallOption) (WorkflowService_PublishActionLogsClient, error) {
+//				panic("mock out the PublishActionLogs method")
+//			},
 //			PublishEventFunc: func(ctx context.Context, in *PublishEventRequest, opts ...grpc.CallOption) (*PublishEventResponse, error) {
 //				panic("mock out the PublishEvent method")
 //			},
@@ -36,6 +42,12 @@ type WorkflowServiceClientMock struct {
 	// GetWorkflowsFunc mocks the GetWorkflows method.
 	GetWorkflowsFunc func(ctx context.Context, in *GetWorkflowsRequest, opts ...grpc.CallOption) (WorkflowService_GetWorkflowsClient, error)
 
+	// ListActionLogsFunc mocks the ListActionLogs method.
+	ListActionLogsFunc func(ctx context.Context, in *ListActionLogsRequest, opts ...grpc.CallOption) (*ListActionLogsResponse, error)
+
+	// PublishActionLogsFunc mocks the PublishActionLogs method.
+	PublishActionLogsFunc func(ctx context.Context, opts ...grpc.CallOption) (WorkflowService_PublishActionLogsClient, error)
+
 	// PublishEventFunc mocks the PublishEvent method.
 	PublishEventFunc func(ctx context.Context, in *PublishEventRequest, opts ...grpc.CallOption) (*PublishEventResponse, error)
 
@@ -50,6 +62,22 @@ type WorkflowServiceClientMock struct {
 			// Opts is the opts argument value.
 			Opts []grpc.CallOption
 		}
+		// ListActionLogs holds details about calls to the ListActionLogs method.
+		ListActionLogs []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// In is the in argument value.
+			In *ListActionLogsRequest
+			// Opts is the opts argument value.
+			Opts []grpc.CallOption
+		}
+		// PublishActionLogs holds details about calls to the PublishActionLogs method.
+		PublishActionLogs []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Opts is the opts argument value.
+			Opts []grpc.CallOption
+		}
 		// PublishEvent holds details about calls to the PublishEvent method.
 		PublishEvent []struct {
 			// Ctx is the ctx argument value.
@@ -60,8 +88,10 @@ type WorkflowServiceClientMock struct {
 			Opts []grpc.CallOption
 		}
 	}
-	lockGetWorkflows sync.RWMutex
-	lockPublishEvent sync.RWMutex
+	lockGetWorkflows      sync.RWMutex
+	lockListActionLogs    sync.RWMutex
+	lockPublishActionLogs sync.RWMutex
+	lockPublishEvent      sync.RWMutex
 }
 
 // GetWorkflows calls GetWorkflowsFunc.
@@ -104,6 +134,82 @@ func (mock *WorkflowServiceClientMock) GetWorkflowsCalls() []struct {
 	return calls
 }
 
+// ListActionLogs calls ListActionLogsFunc.
+func (mock *WorkflowServiceClientMock) ListActionLogs(ctx context.Context, in *ListActionLogsRequest, opts ...grpc.CallOption) (*ListActionLogsResponse, error) {
+	if mock.ListActionLogsFunc == nil {
+		panic("WorkflowServiceClientMock.ListActionLogsFunc: method is nil but WorkflowServiceClient.ListActionLogs was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		In   *ListActionLogsRequest
+		Opts []grpc.CallOption
+	}{
+		Ctx:  ctx,
+		In:   in,
+		Opts: opts,
+	}
+	mock.lockListActionLogs.Lock()
+	mock.calls.ListActionLogs = append(mock.calls.ListActionLogs, callInfo)
+	mock.lockListActionLogs.Unlock()
+	return mock.ListActionLogsFunc(ctx, in, opts...)
+}
+
+// ListActionLogsCalls gets all the calls that were made to ListActionLogs.
+// Check the length with:
+//
+//	len(mockedWorkflowServiceClient.ListActionLogsCalls())
+func (mock *WorkflowServiceClientMock) ListActionLogsCalls() []struct {
+	Ctx  context.Context
+	In   *ListActionLogsRequest
+	Opts []grpc.CallOption
+} {
+	var calls []struct {
+		Ctx  context.Context
+		In   *ListActionLogsRequest
+		Opts []grpc.CallOption
+	}
+	mock.lockListActionLogs.RLock()
+	calls = mock.calls.ListActionLogs
+	mock.lockListActionLogs.RUnlock()
+	return calls
+}
+
+// PublishActionLogs calls PublishActionLogsFunc.
+func (mock *WorkflowServiceClientMock) PublishActionLogs(ctx context.Context, opts ...grpc.CallOption) (WorkflowService_PublishActionLogsClient, error) {
+	if mock.PublishActionLogsFunc == nil {
+		panic("WorkflowServiceClientMock.PublishActionLogsFunc: method is nil but WorkflowServiceClient.PublishActionLogs was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		Opts []grpc.CallOption
+	}{
+		Ctx:  ctx,
+		Opts: opts,
+	}
+	mock.lockPublishActionLogs.Lock()
+	mock.calls.PublishActionLogs = append(mock.calls.PublishActionLogs, callInfo)
+	mock.lockPublishActionLogs.Unlock()
+	return mock.PublishActionLogsFunc(ctx, opts...)
+}
+
+// PublishActionLogsCalls gets all the calls that were made to PublishActionLogs.
+// Check the length with:
+//
+//	len(mockedWorkflowServiceClient.PublishActionLogsCalls())
+func (mock *WorkflowServiceClientMock) PublishActionLogsCalls() []struct {
+	Ctx  context.Context
+	Opts []grpc.CallOption
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Opts []grpc.CallOption
+	}
+	mock.lockPublishActionLogs.RLock()
+	calls = mock.calls.PublishActionLogs
+	mock.lockPublishActionLogs.RUnlock()
+	return calls
+}
+
 // PublishEvent calls PublishEventFunc.
 func (mock *WorkflowServiceClientMock) PublishEvent(ctx context.Context, in *PublishEventRequest, opts ...grpc.CallOption) (*PublishEventResponse, error) {
 	if mock.PublishEventFunc == nil {
@@ -438,3 +544,342 @@ func (mock *WorkflowService_GetWorkflowsClientMock) TrailerCalls() []struct {
 	mock.lockTrailer.RUnlock()
 	return calls
 }
+
+// Ensure, that WorkflowService_PublishActionLogsClientMock does implement WorkflowService_PublishActionLogsClient.
+// If this is not the case, regenerate this file with moq.
+var _ WorkflowService_PublishActionLogsClient = &WorkflowService_PublishActionLogsClientMock{}
+
+// WorkflowService_PublishActionLogsClientMock is a mock implementation of WorkflowService_PublishActionLogsClient.
+//
+//	func TestSomethingThatUsesWorkflowService_PublishActionLogsClient(t *testing.T) {
+//
+//		// make and configure a mocked WorkflowService_PublishActionLogsClient
+//		mockedWorkflowService_PublishActionLogsClient := &WorkflowService_PublishActionLogsClientMock{
+//			CloseAndRecvFunc: func() (*PublishActionLogsResponse, error) {
+//				panic("mock out the CloseAndRecv method")
+//			},
+//			CloseSendFunc: func() error {
+//				panic("mock out the CloseSend method")
+//			},
+//			ContextFunc: func() context.Context {
+//				panic("mock out the Context method")
+//			},
+//			HeaderFunc: func() (metadata.MD, error) {
+//				panic("mock out the Header method")
+//			},
+//			RecvMsgFunc: func(m interface{}) error {
+//				panic("mock out the RecvMsg method")
+//			},
+//			SendFunc: func(publishActionLogsRequest *PublishActionLogsRequest) error {
+//				panic("mock out the Send method")
+//			},
+//			SendMsgFunc: func(m interface{}) error {
+//				panic("mock out the SendMsg method")
+//			},
+//			TrailerFunc: func() metadata.MD {
+//				panic("mock out the Trailer method")
+//			},
+//		}
+//
+//		// use mockedWorkflowService_PublishActionLogsClient in code that requires WorkflowService_PublishActionLogsClient
+//		// and then make assertions.
+//
+//	}
+type WorkflowService_PublishActionLogsClientMock struct {
+	// CloseAndRecvFunc mocks the CloseAndRecv method.
+	CloseAndRecvFunc func() (*PublishActionLogsResponse, error)
+
+	// CloseSendFunc mocks the CloseSend method.
+	CloseSendFunc func() error
+
+	// ContextFunc mocks the Context method.
+	ContextFunc func() context.Context
+
+	// HeaderFunc mocks the Header method.
+	HeaderFunc func() (metadata.MD, error)
+
+	// RecvMsgFunc mocks the RecvMsg method.
+	RecvMsgFunc func(m interface{}) error
+
+	// SendFunc mocks the Send method.
+	SendFunc func(publishActionLogsRequest *PublishActionLogsRequest) error
+
+	// SendMsgFunc mocks the SendMsg method.
+	SendMsgFunc func(m interface{}) error
+
+	// TrailerFunc mocks the Trailer method.
+	TrailerFunc func() metadata.MD
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// CloseAndRecv holds details about calls to the CloseAndRecv method.
+		CloseAndRecv []struct {
+		}
+		// CloseSend holds details about calls to the CloseSend method.
+		CloseSend []struct {
+		}
+		// Context holds details about calls to the Context method.
+		Context []struct {
+		}
+		// Header holds details about calls to the Header method.
+		Header []struct {
+		}
+		// RecvMsg holds details about calls to the RecvMsg method.
+		RecvMsg []struct {
+			// M is the m argument value.
+			M interface{}
+		}
+		// Send holds details about calls to the Send method.
+		Send []struct {
+			// PublishActionLogsRequest is the publishActionLogsRequest argument value.
+			PublishActionLogsRequest *PublishActionLogsRequest
+		}
+		// SendMsg holds details about calls to the SendMsg method.
+		SendMsg []struct {
+			// M is the m argument value.
+			M interface{}
+		}
+		// Trailer holds details about calls to the Trailer method.
+		Trailer []struct {
+		}
+	}
+	lockCloseAndRecv sync.RWMutex
+	lockCloseSend    sync.RWMutex
+	lockContext      sync.RWMutex
+	lockHeader       sync.RWMutex
+	lockRecvMsg      sync.RWMutex
+	lockSend         sync.RWMutex
+	lockSendMsg      sync.RWMutex
+	lockTrailer      sync.RWMutex
+}
+
+// CloseAndRecv calls CloseAndRecvFunc.
+func (mock *WorkflowService_PublishActionLogsClientMock) CloseAndRecv() (*PublishActionLogsResponse, error) {
+	if mock.CloseAndRecvFunc == nil {
+		panic("WorkflowService_PublishActionLogsClientMock.CloseAndRecvFunc: method is nil but WorkflowService_PublishActionLogsClient.CloseAndRecv was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockCloseAndRecv.Lock()
+	mock.calls.CloseAndRecv = append(mock.calls.CloseAndRecv, callInfo)
+	mock.lockCloseAndRecv.Unlock()
+	return mock.CloseAndRecvFunc()
+}
+
+// CloseAndRecvCalls gets all the calls that were made to CloseAndRecv.
+// Check the length with:
+//
+//	len(mockedWorkflowService_PublishActionLogsClient.CloseAndRecvCalls())
+func (mock *WorkflowService_PublishActionLogsClientMock) CloseAndRecvCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockCloseAndRecv.RLock()
+	calls = mock.calls.CloseAndRecv
+	mock.lockCloseAndRecv.RUnlock()
+	return calls
+}
+
+// CloseSend calls CloseSendFunc.
+func (mock *WorkflowService_PublishActionLogsClientMock) CloseSend() error {
+	if mock.CloseSendFunc == nil {
+		panic("WorkflowService_PublishActionLogsClientMock.CloseSendFunc: method is nil but WorkflowService_PublishActionLogsClient.CloseSend was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockCloseSend.Lock()
+	mock.calls.CloseSend = append(mock.calls.CloseSend, callInfo)
+	mock.lockCloseSend.Unlock()
+	return mock.CloseSendFunc()
+}
+
+// CloseSendCalls gets all the calls that were made to CloseSend.
+// Check the length with:
+//
+//	len(mockedWorkflowService_PublishActionLogsClient.CloseSendCalls())
+func (mock *WorkflowService_PublishActionLogsClientMock) CloseSendCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockCloseSend.RLock()
+	calls = mock.calls.CloseSend
+	mock.lockCloseSend.RUnlock()
+	return calls
+}
+
+// Context calls ContextFunc.
+func (mock *WorkflowService_PublishActionLogsClientMock) Context() context.Context {
+	if mock.ContextFunc == nil {
+		panic("WorkflowService_PublishActionLogsClientMock.ContextFunc: method is nil but WorkflowService_PublishActionLogsClient.Context was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockContext.Lock()
+	mock.calls.Context = append(mock.calls.Context, callInfo)
+	mock.lockContext.Unlock()
+	return mock.ContextFunc()
+}
+
+// ContextCalls gets all the calls that were made to Context.
+// Check the length with:
+//
+//	len(mockedWorkflowService_PublishActionLogsClient.ContextCalls())
+func (mock *WorkflowService_PublishActionLogsClientMock) ContextCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockContext.RLock()
+	calls = mock.calls.Context
+	mock.lockContext.RUnlock()
+	return calls
+}
+
+// Header calls HeaderFunc.
+func (mock *WorkflowService_PublishActionLogsClientMock) Header() (metadata.MD, error) {
+	if mock.HeaderFunc == nil {
+		panic("WorkflowService_PublishActionLogsClientMock.HeaderFunc: method is nil but WorkflowService_PublishActionLogsClient.Header was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockHeader.Lock()
+	mock.calls.Header = append(mock.calls.Header, callInfo)
+	mock.lockHeader.Unlock()
+	return mock.HeaderFunc()
+}
+
+// HeaderCalls gets all the calls that were made to Header.
+// Check the length with:
+//
+//	len(mockedWorkflowService_PublishActionLogsClient.HeaderCalls())
+func (mock *WorkflowService_PublishActionLogsClientMock) HeaderCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockHeader.RLock()
+	calls = mock.calls.Header
+	mock.lockHeader.RUnlock()
+	return calls
+}
+
+// RecvMsg calls RecvMsgFunc.
+func (mock *WorkflowService_PublishActionLogsClientMock) RecvMsg(m interface{}) error {
+	if mock.RecvMsgFunc == nil {
+		panic("WorkflowService_PublishActionLogsClientMock.RecvMsgFunc: method is nil but WorkflowService_PublishActionLogsClient.RecvMsg was just called")
+	}
+	callInfo := struct {
+		M interface{}
+	}{
+		M: m,
+	}
+	mock.lockRecvMsg.Lock()
+	mock.calls.RecvMsg = append(mock.calls.RecvMsg, callInfo)
+	mock.lockRecvMsg.Unlock()
+	return mock.RecvMsgFunc(m)
+}
+
+// RecvMsgCalls gets all the calls that were made to RecvMsg.
+// Check the length with:
+//
+//	len(mockedWorkflowService_PublishActionLogsClient.RecvMsgCalls())
+func (mock *WorkflowService_PublishActionLogsClientMock) RecvMsgCalls() []struct {
+	M interface{}
+} {
+	var calls []struct {
+		M interface{}
+	}
+	mock.lockRecvMsg.RLock()
+	calls = mock.calls.RecvMsg
+	mock.lockRecvMsg.RUnlock()
+	return calls
+}
+
+// Send calls SendFunc.
+func (mock *WorkflowService_PublishActionLogsClientMock) Send(publishActionLogsRequest *PublishActionLogsRequest) error {
+	if mock.SendFunc == nil {
+		panic("WorkflowService_PublishActionLogsClientMock.SendFunc: method is nil but WorkflowService_PublishActionLogsClient.Send was just called")
+	}
+	callInfo := struct {
+		PublishActionLogsRequest *PublishActionLogsRequest
+	}{
+		PublishActionLogsRequest: publishActionLogsRequest,
+	}
+	mock.lockSend.Lock()
+	mock.calls.Send = append(mock.calls.Send, callInfo)
+	mock.lockSend.Unlock()
+	return mock.SendFunc(publishActionLogsRequest)
+}
+
+// SendCalls gets all the calls that were made to Send.
+// Check the length with:
+//
+//	len(mockedWorkflowService_PublishActionLogsClient.SendCalls())
+func (mock *WorkflowService_PublishActionLogsClientMock) SendCalls() []struct {
+	PublishActionLogsRequest *PublishActionLogsRequest
+} {
+	var calls []struct {
+		PublishActionLogsRequest *PublishActionLogsRequest
+	}
+	mock.lockSend.RLock()
+	calls = mock.calls.Send
+	mock.lockSend.RUnlock()
+	return calls
+}
+
+// SendMsg calls SendMsgFunc.
+func (mock *WorkflowService_PublishActionLogsClientMock) SendMsg(m interface{}) error {
+	if mock.SendMsgFunc == nil {
+		panic("WorkflowService_PublishActionLogsClientMock.SendMsgFunc: method is nil but WorkflowService_PublishActionLogsClient.SendMsg was just called")
+	}
+	callInfo := struct {
+		M interface{}
+	}{
+		M: m,
+	}
+	mock.lockSendMsg.Lock()
+	mock.calls.SendMsg = append(mock.calls.SendMsg, callInfo)
+	mock.lockSendMsg.Unlock()
+	return mock.SendMsgFunc(m)
+}
+
+// SendMsgCalls gets all the calls that were made to SendMsg.
+// Check the length with:
+//
+//	len(mockedWorkflowService_PublishActionLogsClient.SendMsgCalls())
+func (mock *WorkflowService_PublishActionLogsClientMock) SendMsgCalls() []struct {
+	M interface{}
+} {
+	var calls []struct {
+		M interface{}
+	}
+	mock.lockSendMsg.RLock()
+	calls = mock.calls.SendMsg
+	mock.lockSendMsg.RUnlock()
+	return calls
+}
+
+// Trailer calls TrailerFunc.
+func (mock *WorkflowService_PublishActionLogsClientMock) Trailer() metadata.MD {
+	if mock.TrailerFunc == nil {
+		panic("WorkflowService_PublishActionLogsClientMock.TrailerFunc: method is nil but WorkflowService_PublishActionLogsClient.Trailer was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockTrailer.Lock()
+	mock.calls.Trailer = append(mock.calls.Trailer, callInfo)
+	mock.lockTrailer.Unlock()
+	return mock.TrailerFunc()
+}
+
+// TrailerCalls gets all the calls that were made to Trailer.
+// Check the length with:
+//
+//	len(mockedWorkflowService_PublishActionLogsClient.TrailerCalls())
+func (mock *WorkflowService_PublishActionLogsClientMock) TrailerCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockTrailer.RLock()
+	calls = mock.calls.Trailer
+	mock.lockTrailer.RUnlock()
+	return calls
+}
@@ -12,6 +12,8 @@ import (
 
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 )
 
 const (
@@ -21,6 +23,104 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type ActionLog_Stream int32
+
+const (
+	ActionLog_STREAM_STDOUT ActionLog_Stream = 0
+	ActionLog_STREAM_STDERR ActionLog_Stream = 1
+)
+
+// Enum value maps for ActionLog_Stream.
+var (
+	ActionLog_Stream_name = map[int32]string{
+		0: "STREAM_STDOUT",
+		1: "STREAM_STDERR",
+	}
+	ActionLog_Stream_value = map[string]int32{
+		"STREAM_STDOUT": 0,
+		"STREAM_STDERR": 1,
+	}
+)
+
+func (x ActionLog_Stream) Enum() *ActionLog_Stream {
+	p := new(ActionLog_Stream)
+	*p = x
+	return p
+}
+
+func (x ActionLog_Stream) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ActionLog_Stream) Descriptor() protoreflect.EnumDescriptor {
+	return file_internal_proto_workflow_v2_workflow_proto_enumTypes[0].Descriptor()
+}
+
+func (ActionLog_Stream) Type() protoreflect.EnumType {
+	return &file_internal_proto_workflow_v2_workflow_proto_enumTypes[0]
+}
+
+func (x ActionLog_Stream) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ActionLog_Stream.Descriptor instead.
+func (ActionLog_Stream) EnumDescriptor() ([]byte, []int) {
+	return file_internal_proto_workflow_v2_workflow_proto_rawDescGZIP(), []int{8, 0}
+}
+
+type Workflow_Action_Type int32
+
+const (
+	// Run the image as a container.
+	Workflow_Action_TYPE_CONTAINER Workflow_Action_Type = 0
+	// Reboot the machine.
+	Workflow_Action_TYPE_REBOOT Workflow_Action_Type = 1
+	// Boot a new kernel without returning to the firmware.
+	Workflow_Action_TYPE_KEXEC Workflow_Action_Type = 2
+)
+
+// Enum value maps for Workflow_Action_Type.
+var (
+	Workflow_Action_Type_name = map[int32]string{
+		0: "TYPE_CONTAINER",
+		1: "TYPE_REBOOT",
+		2: "TYPE_KEXEC",
+	}
+	Workflow_Action_Type_value = map[string]int32{
+		"TYPE_CONTAINER": 0,
+		"TYPE_REBOOT":    1,
+		"TYPE_KEXEC":     2,
+	}
+)
+
+func (x Workflow_Action_Type) Enum() *Workflow_Action_Type {
+	p := new(Workflow_Action_Type)
+	*p = x
+	return p
+}
+
+func (x Workflow_Action_Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Workflow_Action_Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_internal_proto_workflow_v2_workflow_proto_enumTypes[1].Descriptor()
+}
+
+func (Workflow_Action_Type) Type() protoreflect.EnumType {
+	return &file_internal_proto_workflow_v2_workflow_proto_enumTypes[1]
+}
+
+func (x Workflow_Action_Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Workflow_Action_Type.Descriptor instead.
+func (Workflow_Action_Type) EnumDescriptor() ([]byte, []int) {
+	return file_internal_proto_workflow_v2_workflow_proto_rawDescGZIP(), []int{9, 3, 0}
+}
+
 type GetWorkflowsRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -77,6 +177,7 @@ type GetWorkflowsResponse struct {
 	//
 	//	*GetWorkflowsResponse_StartWorkflow_
 	//	*GetWorkflowsResponse_StopWorkflow_
+	//	*GetWorkflowsResponse_PauseWorkflow_
 	Cmd isGetWorkflowsResponse_Cmd `protobuf_oneof:"cmd"`
 }
 
@@ -133,6 +234,13 @@ func (x *GetWorkflowsResponse) GetStopWorkflow() *GetWorkflowsResponse_StopWorkf
 	return nil
 }
 
+func (x *GetWorkflowsResponse) GetPauseWorkflow() *GetWorkflowsResponse_PauseWorkflow {
+	if x, ok := x.GetCmd().(*GetWorkflowsResponse_PauseWorkflow_); ok {
+		return x.PauseWorkflow
+	}
+	return nil
+}
+
 type isGetWorkflowsResponse_Cmd interface {
 	isGetWorkflowsResponse_Cmd()
 }
@@ -145,16 +253,28 @@ type GetWorkflowsResponse_StopWorkflow_ struct {
 	StopWorkflow *GetWorkflowsResponse_StopWorkflow `protobuf:"bytes,2,opt,name=stop_workflow,json=stopWorkflow,proto3,oneof"`
 }
 
+type GetWorkflowsResponse_PauseWorkflow_ struct {
+	PauseWorkflow *GetWorkflowsResponse_PauseWorkflow `protobuf:"bytes,3,opt,name=pause_workflow,json=pauseWorkflow,proto3,oneof"`
+}
+
 func (*GetWorkflowsResponse_StartWorkflow_) isGetWorkflowsResponse_Cmd() {}
 
 func (*GetWorkflowsResponse_StopWorkflow_) isGetWorkflowsResponse_Cmd() {}
 
+func (*GetWorkflowsResponse_PauseWorkflow_) isGetWorkflowsResponse_Cmd() {}
+
 type PublishEventRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
 	Event *Event `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+	// Identifies the agent's event outbox. Together with sequence it lets the server ignore events
+	// the agent publishes again because it didn't receive the response. When empty, events aren't
+	// de-duplicated.
+	SessionId string `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	// The position of the event in the session starting from 1. Events are published in order.
+	Sequence uint64 `protobuf:"varint,3,opt,name=sequence,proto3" json:"sequence,omitempty"`
 }
 
 func (x *PublishEventRequest) Reset() {
@@ -196,6 +316,20 @@ func (x *PublishEventRequest) GetEvent() *Event {
 	return nil
 }
 
+func (x *PublishEventRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *PublishEventRequest) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
 type PublishEventResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -234,19 +368,16 @@ func (*PublishEventResponse) Descriptor() ([]byte, []int) {
 	return file_internal_proto_workflow_v2_workflow_proto_rawDescGZIP(), []int{3}
 }
 
-type Workflow struct {
+type PublishActionLogsRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// A unique identifier for a workflow.
-	WorkflowId string `protobuf:"bytes,1,opt,name=workflow_id,json=workflowId,proto3" json:"workflow_id,omitempty"`
-	// The actions that make up the workflow.
-	Actions []*Workflow_Action `protobuf:"bytes,2,rep,name=actions,proto3" json:"actions,omitempty"`
+	Log *ActionLog `protobuf:"bytes,1,opt,name=log,proto3" json:"log,omitempty"`
 }
 
-func (x *Workflow) Reset() {
-	*x = Workflow{}
+func (x *PublishActionLogsRequest) Reset() {
+	*x = PublishActionLogsRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -254,13 +385,13 @@ func (x *Workflow) Reset() {
 	}
 }
 
-func (x *Workflow) String() string {
+func (x *PublishActionLogsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Workflow) ProtoMessage() {}
+func (*PublishActionLogsRequest) ProtoMessage() {}
 
-func (x *Workflow) ProtoReflect() protoreflect.Message {
+func (x *PublishActionLogsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -272,43 +403,26 @@ func (x *Workflow) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Workflow.ProtoReflect.Descriptor instead.
-func (*Workflow) Descriptor() ([]byte, []int) {
+// Deprecated: Use PublishActionLogsRequest.ProtoReflect.Descriptor instead.
+func (*PublishActionLogsRequest) Descriptor() ([]byte, []int) {
 	return file_internal_proto_workflow_v2_workflow_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *Workflow) GetWorkflowId() string {
-	if x != nil {
-		return x.WorkflowId
-	}
-	return ""
-}
-
-func (x *Workflow) GetActions() []*Workflow_Action {
+func (x *PublishActionLogsRequest) GetLog() *ActionLog {
 	if x != nil {
-		return x.Actions
+		return x.Log
 	}
 	return nil
 }
 
-type Event struct {
+type PublishActionLogsResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-
-	// A unique identifier for a workflow.
-	WorkflowId string `protobuf:"bytes,1,opt,name=workflow_id,json=workflowId,proto3" json:"workflow_id,omitempty"`
-	// Types that are assignable to Event:
-	//
-	//	*Event_ActionStarted_
-	//	*Event_ActionSucceeded_
-	//	*Event_ActionFailed_
-	//	*Event_WorkflowRejected_
-	Event isEvent_Event `protobuf_oneof:"event"`
 }
 
-func (x *Event) Reset() {
-	*x = Event{}
+func (x *PublishActionLogsResponse) Reset() {
+	*x = PublishActionLogsResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -316,13 +430,13 @@ func (x *Event) Reset() {
 	}
 }
 
-func (x *Event) String() string {
+func (x *PublishActionLogsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Event) ProtoMessage() {}
+func (*PublishActionLogsResponse) ProtoMessage() {}
 
-func (x *Event) ProtoReflect() protoreflect.Message {
+func (x *PublishActionLogsResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -334,91 +448,27 @@ func (x *Event) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Event.ProtoReflect.Descriptor instead.
-func (*Event) Descriptor() ([]byte, []int) {
+// Deprecated: Use PublishActionLogsResponse.ProtoReflect.Descriptor instead.
+func (*PublishActionLogsResponse) Descriptor() ([]byte, []int) {
 	return file_internal_proto_workflow_v2_workflow_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *Event) GetWorkflowId() string {
-	if x != nil {
-		return x.WorkflowId
-	}
-	return ""
-}
-
-func (m *Event) GetEvent() isEvent_Event {
-	if m != nil {
-		return m.Event
-	}
-	return nil
-}
-
-func (x *Event) GetActionStarted() *Event_ActionStarted {
-	if x, ok := x.GetEvent().(*Event_ActionStarted_); ok {
-		return x.ActionStarted
-	}
-	return nil
-}
-
-func (x *Event) GetActionSucceeded() *Event_ActionSucceeded {
-	if x, ok := x.GetEvent().(*Event_ActionSucceeded_); ok {
-		return x.ActionSucceeded
-	}
-	return nil
-}
-
-func (x *Event) GetActionFailed() *Event_ActionFailed {
-	if x, ok := x.GetEvent().(*Event_ActionFailed_); ok {
-		return x.ActionFailed
-	}
-	return nil
-}
-
-func (x *Event) GetWorkflowRejected() *Event_WorkflowRejected {
-	if x, ok := x.GetEvent().(*Event_WorkflowRejected_); ok {
-		return x.WorkflowRejected
-	}
-	return nil
-}
-
-type isEvent_Event interface {
-	isEvent_Event()
-}
-
-type Event_ActionStarted_ struct {
-	ActionStarted *Event_ActionStarted `protobuf:"bytes,2,opt,name=action_started,json=actionStarted,proto3,oneof"`
-}
-
-type Event_ActionSucceeded_ struct {
-	ActionSucceeded *Event_ActionSucceeded `protobuf:"bytes,3,opt,name=action_succeeded,json=actionSucceeded,proto3,oneof"`
-}
-
-type Event_ActionFailed_ struct {
-	ActionFailed *Event_ActionFailed `protobuf:"bytes,4,opt,name=action_failed,json=actionFailed,proto3,oneof"`
-}
-
-type Event_WorkflowRejected_ struct {
-	WorkflowRejected *Event_WorkflowRejected `protobuf:"bytes,5,opt,name=workflow_rejected,json=workflowRejected,proto3,oneof"`
-}
-
-func (*Event_ActionStarted_) isEvent_Event() {}
-
-func (*Event_ActionSucceeded_) isEvent_Event() {}
-
-func (*Event_ActionFailed_) isEvent_Event() {}
-
-func (*Event_WorkflowRejected_) isEvent_Event() {}
-
-type GetWorkflowsResponse_StartWorkflow struct {
+type ListActionLogsRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Workflow *Workflow `protobuf:"bytes,1,opt,name=workflow,proto3" json:"workflow,omitempty"`
+	// A unique identifier for a workflow.
+	WorkflowId string `protobuf:"bytes,1,opt,name=workflow_id,json=workflowId,proto3" json:"workflow_id,omitempty"`
+	// A unique identifier for an action in the context of a workflow.
+	ActionId string `protobuf:"bytes,2,opt,name=action_id,json=actionId,proto3" json:"action_id,omitempty"`
+	// The maximum number of bytes, counted from the most recent output, to return. When 0, all
+	// retained output is returned.
+	LimitBytes int64 `protobuf:"varint,3,opt,name=limit_bytes,json=limitBytes,proto3" json:"limit_bytes,omitempty"`
 }
 
-func (x *GetWorkflowsResponse_StartWorkflow) Reset() {
-	*x = GetWorkflowsResponse_StartWorkflow{}
+func (x *ListActionLogsRequest) Reset() {
+	*x = ListActionLogsRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -426,13 +476,13 @@ func (x *GetWorkflowsResponse_StartWorkflow) Reset() {
 	}
 }
 
-func (x *GetWorkflowsResponse_StartWorkflow) String() string {
+func (x *ListActionLogsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetWorkflowsResponse_StartWorkflow) ProtoMessage() {}
+func (*ListActionLogsRequest) ProtoMessage() {}
 
-func (x *GetWorkflowsResponse_StartWorkflow) ProtoReflect() protoreflect.Message {
+func (x *ListActionLogsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -444,28 +494,44 @@ func (x *GetWorkflowsResponse_StartWorkflow) ProtoReflect() protoreflect.Message
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetWorkflowsResponse_StartWorkflow.ProtoReflect.Descriptor instead.
-func (*GetWorkflowsResponse_StartWorkflow) Descriptor() ([]byte, []int) {
-	return file_internal_proto_workflow_v2_workflow_proto_rawDescGZIP(), []int{1, 0}
+// Deprecated: Use ListActionLogsRequest.ProtoReflect.Descriptor instead.
+func (*ListActionLogsRequest) Descriptor() ([]byte, []int) {
+	return file_internal_proto_workflow_v2_workflow_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *GetWorkflowsResponse_StartWorkflow) GetWorkflow() *Workflow {
+func (x *ListActionLogsRequest) GetWorkflowId() string {
 	if x != nil {
-		return x.Workflow
+		return x.WorkflowId
 	}
-	return nil
+	return ""
 }
 
-type GetWorkflowsResponse_StopWorkflow struct {
+func (x *ListActionLogsRequest) GetActionId() string {
+	if x != nil {
+		return x.ActionId
+	}
+	return ""
+}
+
+func (x *ListActionLogsRequest) GetLimitBytes() int64 {
+	if x != nil {
+		return x.LimitBytes
+	}
+	return 0
+}
+
+type ListActionLogsResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	WorkflowId string `protobuf:"bytes,1,opt,name=workflow_id,json=workflowId,proto3" json:"workflow_id,omitempty"`
+	Logs []*ActionLog `protobuf:"bytes,1,rep,name=logs,proto3" json:"logs,omitempty"`
+	// Indicates older output has been discarded by the server.
+	Truncated bool `protobuf:"varint,2,opt,name=truncated,proto3" json:"truncated,omitempty"`
 }
 
-func (x *GetWorkflowsResponse_StopWorkflow) Reset() {
-	*x = GetWorkflowsResponse_StopWorkflow{}
+func (x *ListActionLogsResponse) Reset() {
+	*x = ListActionLogsResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -473,13 +539,13 @@ func (x *GetWorkflowsResponse_StopWorkflow) Reset() {
 	}
 }
 
-func (x *GetWorkflowsResponse_StopWorkflow) String() string {
+func (x *ListActionLogsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetWorkflowsResponse_StopWorkflow) ProtoMessage() {}
+func (*ListActionLogsResponse) ProtoMessage() {}
 
-func (x *GetWorkflowsResponse_StopWorkflow) ProtoReflect() protoreflect.Message {
+func (x *ListActionLogsResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -491,44 +557,45 @@ func (x *GetWorkflowsResponse_StopWorkflow) ProtoReflect() protoreflect.Message
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetWorkflowsResponse_StopWorkflow.ProtoReflect.Descriptor instead.
-func (*GetWorkflowsResponse_StopWorkflow) Descriptor() ([]byte, []int) {
-	return file_internal_proto_workflow_v2_workflow_proto_rawDescGZIP(), []int{1, 1}
+// Deprecated: Use ListActionLogsResponse.ProtoReflect.Descriptor instead.
+func (*ListActionLogsResponse) Descriptor() ([]byte, []int) {
+	return file_internal_proto_workflow_v2_workflow_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *GetWorkflowsResponse_StopWorkflow) GetWorkflowId() string {
+func (x *ListActionLogsResponse) GetLogs() []*ActionLog {
 	if x != nil {
-		return x.WorkflowId
+		return x.Logs
 	}
-	return ""
+	return nil
 }
 
-type Workflow_Action struct {
+func (x *ListActionLogsResponse) GetTruncated() bool {
+	if x != nil {
+		return x.Truncated
+	}
+	return false
+}
+
+// ActionLog is a chunk of output produced by an action.
+type ActionLog struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
+	// A unique identifier for a workflow.
+	WorkflowId string `protobuf:"bytes,1,opt,name=workflow_id,json=workflowId,proto3" json:"workflow_id,omitempty"`
 	// A unique identifier for an action in the context of a workflow.
-	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	// The name of the action. This can be used to identify actions in logging.
-	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	// The image to run.
-	Image string `protobuf:"bytes,3,opt,name=image,proto3" json:"image,omitempty"`
-	// The command to execute when launching the image. When using Docker as the action runtime
-	// it is used as the entrypoint.
-	Cmd *string `protobuf:"bytes,4,opt,name=cmd,proto3,oneof" json:"cmd,omitempty"`
-	// Arguments to pass to the container.
-	Args []string `protobuf:"bytes,5,rep,name=args,proto3" json:"args,omitempty"`
-	// Environment variables to configure when launching the container.
-	Env map[string]string `protobuf:"bytes,6,rep,name=env,proto3" json:"env,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	// Volumes to mount when launching the container.
-	Volumes []string `protobuf:"bytes,7,rep,name=volumes,proto3" json:"volumes,omitempty"`
-	// The network namespace to launch the container in.
-	NetworkNamespace *string `protobuf:"bytes,8,opt,name=network_namespace,json=networkNamespace,proto3,oneof" json:"network_namespace,omitempty"`
+	ActionId string `protobuf:"bytes,2,opt,name=action_id,json=actionId,proto3" json:"action_id,omitempty"`
+	// The output stream the data was read from.
+	Stream ActionLog_Stream `protobuf:"varint,3,opt,name=stream,proto3,enum=internal.proto.workflow.v2.ActionLog_Stream" json:"stream,omitempty"`
+	// The output produced by the action.
+	Data []byte `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
+	// When the output was captured.
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 }
 
-func (x *Workflow_Action) Reset() {
-	*x = Workflow_Action{}
+func (x *ActionLog) Reset() {
+	*x = ActionLog{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -536,13 +603,13 @@ func (x *Workflow_Action) Reset() {
 	}
 }
 
-func (x *Workflow_Action) String() string {
+func (x *ActionLog) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Workflow_Action) ProtoMessage() {}
+func (*ActionLog) ProtoMessage() {}
 
-func (x *Workflow_Action) ProtoReflect() protoreflect.Message {
+func (x *ActionLog) ProtoReflect() protoreflect.Message {
 	mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -554,65 +621,1085 @@ func (x *Workflow_Action) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Workflow_Action.ProtoReflect.Descriptor instead.
-func (*Workflow_Action) Descriptor() ([]byte, []int) {
-	return file_internal_proto_workflow_v2_workflow_proto_rawDescGZIP(), []int{4, 0}
+// Deprecated: Use ActionLog.ProtoReflect.Descriptor instead.
+func (*ActionLog) Descriptor() ([]byte, []int) {
+	return file_internal_proto_workflow_v2_workflow_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *Workflow_Action) GetId() string {
+func (x *ActionLog) GetWorkflowId() string {
 	if x != nil {
-		return x.Id
+		return x.WorkflowId
 	}
 	return ""
 }
 
-func (x *Workflow_Action) GetName() string {
+func (x *ActionLog) GetActionId() string {
 	if x != nil {
-		return x.Name
+		return x.ActionId
 	}
 	return ""
 }
 
-func (x *Workflow_Action) GetImage() string {
+func (x *ActionLog) GetStream() ActionLog_Stream {
 	if x != nil {
-		return x.Image
+		return x.Stream
 	}
-	return ""
+	return ActionLog_STREAM_STDOUT
 }
 
-func (x *Workflow_Action) GetCmd() string {
-	if x != nil && x.Cmd != nil {
-		return *x.Cmd
+func (x *ActionLog) GetData() []byte {
+	if x != nil {
+		return x.Data
 	}
-	return ""
+	return nil
 }
 
-func (x *Workflow_Action) GetArgs() []string {
+func (x *ActionLog) GetCreatedAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.Args
+		return x.CreatedAt
 	}
 	return nil
 }
 
-func (x *Workflow_Action) GetEnv() map[string]string {
+type Workflow struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// A unique identifier for a workflow.
+	WorkflowId string `protobuf:"bytes,1,opt,name=workflow_id,json=workflowId,proto3" json:"workflow_id,omitempty"`
+	// The actions that make up the workflow. When no action has dependencies the actions are run
+	// sequentially in the order specified.
+	Actions []*Workflow_Action `protobuf:"bytes,2,rep,name=actions,proto3" json:"actions,omitempty"`
+	// The data action conditions are evaluated against.
+	ConditionData *structpb.Struct `protobuf:"bytes,3,opt,name=condition_data,json=conditionData,proto3" json:"condition_data,omitempty"`
+	// Actions run sequentially when an action in actions fails. They cannot have dependencies.
+	OnFailure []*Workflow_Action `protobuf:"bytes,4,rep,name=on_failure,json=onFailure,proto3" json:"on_failure,omitempty"`
+	// Actions run sequentially once actions, and on_failure if applicable, have finished
+	// regardless of whether they succeeded. They cannot have dependencies.
+	Finally []*Workflow_Action `protobuf:"bytes,5,rep,name=finally,proto3" json:"finally,omitempty"`
+	// Prevents actions from starting. Actions already running are unaffected.
+	Pause *Workflow_Pause `protobuf:"bytes,6,opt,name=pause,proto3" json:"pause,omitempty"`
+	// The outcome of actions that finished before the workflow was dispatched, for example before
+	// the machine rebooted, keyed by action ID. These actions aren't run again.
+	Results map[string]*Workflow_ActionResult `protobuf:"bytes,7,rep,name=results,proto3" json:"results,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *Workflow) Reset() {
+	*x = Workflow{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Workflow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Workflow) ProtoMessage() {}
+
+func (x *Workflow) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Workflow.ProtoReflect.Descriptor instead.
+func (*Workflow) Descriptor() ([]byte, []int) {
+	return file_internal_proto_workflow_v2_workflow_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Workflow) GetWorkflowId() string {
+	if x != nil {
+		return x.WorkflowId
+	}
+	return ""
+}
+
+func (x *Workflow) GetActions() []*Workflow_Action {
+	if x != nil {
+		return x.Actions
+	}
+	return nil
+}
+
+func (x *Workflow) GetConditionData() *structpb.Struct {
+	if x != nil {
+		return x.ConditionData
+	}
+	return nil
+}
+
+func (x *Workflow) GetOnFailure() []*Workflow_Action {
+	if x != nil {
+		return x.OnFailure
+	}
+	return nil
+}
+
+func (x *Workflow) GetFinally() []*Workflow_Action {
+	if x != nil {
+		return x.Finally
+	}
+	return nil
+}
+
+func (x *Workflow) GetPause() *Workflow_Pause {
+	if x != nil {
+		return x.Pause
+	}
+	return nil
+}
+
+func (x *Workflow) GetResults() map[string]*Workflow_ActionResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type Event struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// A unique identifier for a workflow.
+	WorkflowId string `protobuf:"bytes,1,opt,name=workflow_id,json=workflowId,proto3" json:"workflow_id,omitempty"`
+	// Types that are assignable to Event:
+	//
+	//	*Event_ActionStarted_
+	//	*Event_ActionSucceeded_
+	//	*Event_ActionFailed_
+	//	*Event_WorkflowRejected_
+	//	*Event_ActionSkipped_
+	//	*Event_ActionPaused_
+	//	*Event_ImagePulled_
+	//	*Event_ImagePullFailed_
+	Event isEvent_Event `protobuf_oneof:"event"`
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_internal_proto_workflow_v2_workflow_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Event) GetWorkflowId() string {
+	if x != nil {
+		return x.WorkflowId
+	}
+	return ""
+}
+
+func (m *Event) GetEvent() isEvent_Event {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+func (x *Event) GetActionStarted() *Event_ActionStarted {
+	if x, ok := x.GetEvent().(*Event_ActionStarted_); ok {
+		return x.ActionStarted
+	}
+	return nil
+}
+
+func (x *Event) GetActionSucceeded() *Event_ActionSucceeded {
+	if x, ok := x.GetEvent().(*Event_ActionSucceeded_); ok {
+		return x.ActionSucceeded
+	}
+	return nil
+}
+
+func (x *Event) GetActionFailed() *Event_ActionFailed {
+	if x, ok := x.GetEvent().(*Event_ActionFailed_); ok {
+		return x.ActionFailed
+	}
+	return nil
+}
+
+func (x *Event) GetWorkflowRejected() *Event_WorkflowRejected {
+	if x, ok := x.GetEvent().(*Event_WorkflowRejected_); ok {
+		return x.WorkflowRejected
+	}
+	return nil
+}
+
+func (x *Event) GetActionSkipped() *Event_ActionSkipped {
+	if x, ok := x.GetEvent().(*Event_ActionSkipped_); ok {
+		return x.ActionSkipped
+	}
+	return nil
+}
+
+func (x *Event) GetActionPaused() *Event_ActionPaused {
+	if x, ok := x.GetEvent().(*Event_ActionPaused_); ok {
+		return x.ActionPaused
+	}
+	return nil
+}
+
+func (x *Event) GetImagePulled() *Event_ImagePulled {
+	if x, ok := x.GetEvent().(*Event_ImagePulled_); ok {
+		return x.ImagePulled
+	}
+	return nil
+}
+
+func (x *Event) GetImagePullFailed() *Event_ImagePullFailed {
+	if x, ok := x.GetEvent().(*Event_ImagePullFailed_); ok {
+		return x.ImagePullFailed
+	}
+	return nil
+}
+
+type isEvent_Event interface {
+	isEvent_Event()
+}
+
+type Event_ActionStarted_ struct {
+	ActionStarted *Event_ActionStarted `protobuf:"bytes,2,opt,name=action_started,json=actionStarted,proto3,oneof"`
+}
+
+type Event_ActionSucceeded_ struct {
+	ActionSucceeded *Event_ActionSucceeded `protobuf:"bytes,3,opt,name=action_succeeded,json=actionSucceeded,proto3,oneof"`
+}
+
+type Event_ActionFailed_ struct {
+	ActionFailed *Event_ActionFailed `protobuf:"bytes,4,opt,name=action_failed,json=actionFailed,proto3,oneof"`
+}
+
+type Event_WorkflowRejected_ struct {
+	WorkflowRejected *Event_WorkflowRejected `protobuf:"bytes,5,opt,name=workflow_rejected,json=workflowRejected,proto3,oneof"`
+}
+
+type Event_ActionSkipped_ struct {
+	ActionSkipped *Event_ActionSkipped `protobuf:"bytes,6,opt,name=action_skipped,json=actionSkipped,proto3,oneof"`
+}
+
+type Event_ActionPaused_ struct {
+	ActionPaused *Event_ActionPaused `protobuf:"bytes,7,opt,name=action_paused,json=actionPaused,proto3,oneof"`
+}
+
+type Event_ImagePulled_ struct {
+	ImagePulled *Event_ImagePulled `protobuf:"bytes,8,opt,name=image_pulled,json=imagePulled,proto3,oneof"`
+}
+
+type Event_ImagePullFailed_ struct {
+	ImagePullFailed *Event_ImagePullFailed `protobuf:"bytes,9,opt,name=image_pull_failed,json=imagePullFailed,proto3,oneof"`
+}
+
+func (*Event_ActionStarted_) isEvent_Event() {}
+
+func (*Event_ActionSucceeded_) isEvent_Event() {}
+
+func (*Event_ActionFailed_) isEvent_Event() {}
+
+func (*Event_WorkflowRejected_) isEvent_Event() {}
+
+func (*Event_ActionSkipped_) isEvent_Event() {}
+
+func (*Event_ActionPaused_) isEvent_Event() {}
+
+func (*Event_ImagePulled_) isEvent_Event() {}
+
+func (*Event_ImagePullFailed_) isEvent_Event() {}
+
+type GetWorkflowsResponse_StartWorkflow struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Workflow *Workflow `protobuf:"bytes,1,opt,name=workflow,proto3" json:"workflow,omitempty"`
+}
+
+func (x *GetWorkflowsResponse_StartWorkflow) Reset() {
+	*x = GetWorkflowsResponse_StartWorkflow{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetWorkflowsResponse_StartWorkflow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetWorkflowsResponse_StartWorkflow) ProtoMessage() {}
+
+func (x *GetWorkflowsResponse_StartWorkflow) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetWorkflowsResponse_StartWorkflow.ProtoReflect.Descriptor instead.
+func (*GetWorkflowsResponse_StartWorkflow) Descriptor() ([]byte, []int) {
+	return file_internal_proto_workflow_v2_workflow_proto_rawDescGZIP(), []int{1, 0}
+}
+
+func (x *GetWorkflowsResponse_StartWorkflow) GetWorkflow() *Workflow {
+	if x != nil {
+		return x.Workflow
+	}
+	return nil
+}
+
+type GetWorkflowsResponse_StopWorkflow struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	WorkflowId string `protobuf:"bytes,1,opt,name=workflow_id,json=workflowId,proto3" json:"workflow_id,omitempty"`
+}
+
+func (x *GetWorkflowsResponse_StopWorkflow) Reset() {
+	*x = GetWorkflowsResponse_StopWorkflow{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetWorkflowsResponse_StopWorkflow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetWorkflowsResponse_StopWorkflow) ProtoMessage() {}
+
+func (x *GetWorkflowsResponse_StopWorkflow) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetWorkflowsResponse_StopWorkflow.ProtoReflect.Descriptor instead.
+func (*GetWorkflowsResponse_StopWorkflow) Descriptor() ([]byte, []int) {
+	return file_internal_proto_workflow_v2_workflow_proto_rawDescGZIP(), []int{1, 1}
+}
+
+func (x *GetWorkflowsResponse_StopWorkflow) GetWorkflowId() string {
+	if x != nil {
+		return x.WorkflowId
+	}
+	return ""
+}
+
+// PauseWorkflow replaces the pause configuration of a dispatched workflow.
+type GetWorkflowsResponse_PauseWorkflow struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	WorkflowId string          `protobuf:"bytes,1,opt,name=workflow_id,json=workflowId,proto3" json:"workflow_id,omitempty"`
+	Pause      *Workflow_Pause `protobuf:"bytes,2,opt,name=pause,proto3" json:"pause,omitempty"`
+}
+
+func (x *GetWorkflowsResponse_PauseWorkflow) Reset() {
+	*x = GetWorkflowsResponse_PauseWorkflow{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetWorkflowsResponse_PauseWorkflow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetWorkflowsResponse_PauseWorkflow) ProtoMessage() {}
+
+func (x *GetWorkflowsResponse_PauseWorkflow) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetWorkflowsResponse_PauseWorkflow.ProtoReflect.Descriptor instead.
+func (*GetWorkflowsResponse_PauseWorkflow) Descriptor() ([]byte, []int) {
+	return file_internal_proto_workflow_v2_workflow_proto_rawDescGZIP(), []int{1, 2}
+}
+
+func (x *GetWorkflowsResponse_PauseWorkflow) GetWorkflowId() string {
+	if x != nil {
+		return x.WorkflowId
+	}
+	return ""
+}
+
+func (x *GetWorkflowsResponse_PauseWorkflow) GetPause() *Workflow_Pause {
+	if x != nil {
+		return x.Pause
+	}
+	return nil
+}
+
+type Workflow_ActionResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Whether the action succeeded or was skipped.
+	Succeeded bool `protobuf:"varint,1,opt,name=succeeded,proto3" json:"succeeded,omitempty"`
+	// The outputs of the action.
+	Outputs map[string]string `protobuf:"bytes,2,rep,name=outputs,proto3" json:"outputs,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *Workflow_ActionResult) Reset() {
+	*x = Workflow_ActionResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Workflow_ActionResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Workflow_ActionResult) ProtoMessage() {}
+
+func (x *Workflow_ActionResult) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Workflow_ActionResult.ProtoReflect.Descriptor instead.
+func (*Workflow_ActionResult) Descriptor() ([]byte, []int) {
+	return file_internal_proto_workflow_v2_workflow_proto_rawDescGZIP(), []int{9, 1}
+}
+
+func (x *Workflow_ActionResult) GetSucceeded() bool {
+	if x != nil {
+		return x.Succeeded
+	}
+	return false
+}
+
+func (x *Workflow_ActionResult) GetOutputs() map[string]string {
+	if x != nil {
+		return x.Outputs
+	}
+	return nil
+}
+
+type Workflow_Pause struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Prevents any action from starting.
+	Paused bool `protobuf:"varint,1,opt,name=paused,proto3" json:"paused,omitempty"`
+	// The IDs of actions that must not start.
+	Breakpoints []string `protobuf:"bytes,2,rep,name=breakpoints,proto3" json:"breakpoints,omitempty"`
+}
+
+func (x *Workflow_Pause) Reset() {
+	*x = Workflow_Pause{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Workflow_Pause) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Workflow_Pause) ProtoMessage() {}
+
+func (x *Workflow_Pause) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Workflow_Pause.ProtoReflect.Descriptor instead.
+func (*Workflow_Pause) Descriptor() ([]byte, []int) {
+	return file_internal_proto_workflow_v2_workflow_proto_rawDescGZIP(), []int{9, 2}
+}
+
+func (x *Workflow_Pause) GetPaused() bool {
+	if x != nil {
+		return x.Paused
+	}
+	return false
+}
+
+func (x *Workflow_Pause) GetBreakpoints() []string {
+	if x != nil {
+		return x.Breakpoints
+	}
+	return nil
+}
+
+type Workflow_Action struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// A unique identifier for an action in the context of a workflow.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// The name of the action. This can be used to identify actions in logging.
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// The image to run.
+	Image string `protobuf:"bytes,3,opt,name=image,proto3" json:"image,omitempty"`
+	// The command to execute when launching the image. When using Docker as the action runtime
+	// it is used as the entrypoint.
+	Cmd *string `protobuf:"bytes,4,opt,name=cmd,proto3,oneof" json:"cmd,omitempty"`
+	// Arguments to pass to the container.
+	Args []string `protobuf:"bytes,5,rep,name=args,proto3" json:"args,omitempty"`
+	// Environment variables to configure when launching the container.
+	Env map[string]string `protobuf:"bytes,6,rep,name=env,proto3" json:"env,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Volumes to mount when launching the container.
+	Volumes []string `protobuf:"bytes,7,rep,name=volumes,proto3" json:"volumes,omitempty"`
+	// The network namespace to launch the container in.
+	NetworkNamespace *string `protobuf:"bytes,8,opt,name=network_namespace,json=networkNamespace,proto3,oneof" json:"network_namespace,omitempty"`
+	// The policy used to retry the action when it fails. When unset the action is not retried.
+	RetryPolicy *Workflow_RetryPolicy `protobuf:"bytes,9,opt,name=retry_policy,json=retryPolicy,proto3,oneof" json:"retry_policy,omitempty"`
+	// The IDs of actions that must succeed before this action is run. Actions whose dependencies
+	// have succeeded are run concurrently.
+	DependsOn []string `protobuf:"bytes,10,rep,name=depends_on,json=dependsOn,proto3" json:"depends_on,omitempty"`
+	// The maximum number of seconds the action may run for, including retries. When 0 the action
+	// has no timeout.
+	TimeoutSeconds int64 `protobuf:"varint,11,opt,name=timeout_seconds,json=timeoutSeconds,proto3" json:"timeout_seconds,omitempty"`
+	// A condition evaluated against the workflow's condition data immediately before running the
+	// action. When the condition is false the action is skipped.
+	When *string `protobuf:"bytes,12,opt,name=when,proto3,oneof" json:"when,omitempty"`
+	// The PID namespace to launch the container in.
+	PidNamespace *string `protobuf:"bytes,13,opt,name=pid_namespace,json=pidNamespace,proto3,oneof" json:"pid_namespace,omitempty"`
+	// Run the container with all capabilities and access to all host devices.
+	Privileged bool `protobuf:"varint,14,opt,name=privileged,proto3" json:"privileged,omitempty"`
+	// Modifications to the container's default Linux capabilities.
+	Capabilities *Workflow_Capabilities `protobuf:"bytes,15,opt,name=capabilities,proto3,oneof" json:"capabilities,omitempty"`
+	// Host devices to make available to the container of the form
+	// host-path[:container-path[:permissions]].
+	Devices []string `protobuf:"bytes,16,rep,name=devices,proto3" json:"devices,omitempty"`
+	// Resource limits of the container. When unset the container is unlimited.
+	Resources *Workflow_ResourceLimits `protobuf:"bytes,17,opt,name=resources,proto3,oneof" json:"resources,omitempty"`
+	// Whether the action can safely be re-run from the start when it's interrupted by an agent
+	// restart.
+	Idempotent bool `protobuf:"varint,18,opt,name=idempotent,proto3" json:"idempotent,omitempty"`
+	// How the action is performed.
+	Type Workflow_Action_Type `protobuf:"varint,19,opt,name=type,proto3,enum=internal.proto.workflow.v2.Workflow_Action_Type" json:"type,omitempty"`
+	// The kernel booted by TYPE_KEXEC actions.
+	Kexec *Workflow_Kexec `protobuf:"bytes,20,opt,name=kexec,proto3,oneof" json:"kexec,omitempty"`
+}
+
+func (x *Workflow_Action) Reset() {
+	*x = Workflow_Action{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Workflow_Action) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Workflow_Action) ProtoMessage() {}
+
+func (x *Workflow_Action) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Workflow_Action.ProtoReflect.Descriptor instead.
+func (*Workflow_Action) Descriptor() ([]byte, []int) {
+	return file_internal_proto_workflow_v2_workflow_proto_rawDescGZIP(), []int{9, 3}
+}
+
+func (x *Workflow_Action) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Workflow_Action) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Workflow_Action) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+func (x *Workflow_Action) GetCmd() string {
+	if x != nil && x.Cmd != nil {
+		return *x.Cmd
+	}
+	return ""
+}
+
+func (x *Workflow_Action) GetArgs() []string {
+	if x != nil {
+		return x.Args
+	}
+	return nil
+}
+
+func (x *Workflow_Action) GetEnv() map[string]string {
+	if x != nil {
+		return x.Env
+	}
+	return nil
+}
+
+func (x *Workflow_Action) GetVolumes() []string {
+	if x != nil {
+		return x.Volumes
+	}
+	return nil
+}
+
+func (x *Workflow_Action) GetNetworkNamespace() string {
+	if x != nil && x.NetworkNamespace != nil {
+		return *x.NetworkNamespace
+	}
+	return ""
+}
+
+func (x *Workflow_Action) GetRetryPolicy() *Workflow_RetryPolicy {
+	if x != nil {
+		return x.RetryPolicy
+	}
+	return nil
+}
+
+func (x *Workflow_Action) GetDependsOn() []string {
+	if x != nil {
+		return x.DependsOn
+	}
+	return nil
+}
+
+func (x *Workflow_Action) GetTimeoutSeconds() int64 {
+	if x != nil {
+		return x.TimeoutSeconds
+	}
+	return 0
+}
+
+func (x *Workflow_Action) GetWhen() string {
+	if x != nil && x.When != nil {
+		return *x.When
+	}
+	return ""
+}
+
+func (x *Workflow_Action) GetPidNamespace() string {
+	if x != nil && x.PidNamespace != nil {
+		return *x.PidNamespace
+	}
+	return ""
+}
+
+func (x *Workflow_Action) GetPrivileged() bool {
+	if x != nil {
+		return x.Privileged
+	}
+	return false
+}
+
+func (x *Workflow_Action) GetCapabilities() *Workflow_Capabilities {
+	if x != nil {
+		return x.Capabilities
+	}
+	return nil
+}
+
+func (x *Workflow_Action) GetDevices() []string {
+	if x != nil {
+		return x.Devices
+	}
+	return nil
+}
+
+func (x *Workflow_Action) GetResources() *Workflow_ResourceLimits {
+	if x != nil {
+		return x.Resources
+	}
+	return nil
+}
+
+func (x *Workflow_Action) GetIdempotent() bool {
+	if x != nil {
+		return x.Idempotent
+	}
+	return false
+}
+
+func (x *Workflow_Action) GetType() Workflow_Action_Type {
+	if x != nil {
+		return x.Type
+	}
+	return Workflow_Action_TYPE_CONTAINER
+}
+
+func (x *Workflow_Action) GetKexec() *Workflow_Kexec {
+	if x != nil {
+		return x.Kexec
+	}
+	return nil
+}
+
+type Workflow_Kexec struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The device containing the kernel. When empty, paths are on the agent's filesystem.
+	BlockDevice string `protobuf:"bytes,1,opt,name=block_device,json=blockDevice,proto3" json:"block_device,omitempty"`
+	// The filesystem type of block_device.
+	FsType string `protobuf:"bytes,2,opt,name=fs_type,json=fsType,proto3" json:"fs_type,omitempty"`
+	// The path to the kernel.
+	KernelPath string `protobuf:"bytes,3,opt,name=kernel_path,json=kernelPath,proto3" json:"kernel_path,omitempty"`
+	// The path to the initial ramdisk.
+	InitrdPath string `protobuf:"bytes,4,opt,name=initrd_path,json=initrdPath,proto3" json:"initrd_path,omitempty"`
+	// The kernel command line.
+	Cmdline string `protobuf:"bytes,5,opt,name=cmdline,proto3" json:"cmdline,omitempty"`
+}
+
+func (x *Workflow_Kexec) Reset() {
+	*x = Workflow_Kexec{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Workflow_Kexec) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Workflow_Kexec) ProtoMessage() {}
+
+func (x *Workflow_Kexec) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Workflow_Kexec.ProtoReflect.Descriptor instead.
+func (*Workflow_Kexec) Descriptor() ([]byte, []int) {
+	return file_internal_proto_workflow_v2_workflow_proto_rawDescGZIP(), []int{9, 4}
+}
+
+func (x *Workflow_Kexec) GetBlockDevice() string {
+	if x != nil {
+		return x.BlockDevice
+	}
+	return ""
+}
+
+func (x *Workflow_Kexec) GetFsType() string {
+	if x != nil {
+		return x.FsType
+	}
+	return ""
+}
+
+func (x *Workflow_Kexec) GetKernelPath() string {
+	if x != nil {
+		return x.KernelPath
+	}
+	return ""
+}
+
+func (x *Workflow_Kexec) GetInitrdPath() string {
+	if x != nil {
+		return x.InitrdPath
+	}
+	return ""
+}
+
+func (x *Workflow_Kexec) GetCmdline() string {
+	if x != nil {
+		return x.Cmdline
+	}
+	return ""
+}
+
+type Workflow_ResourceLimits struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The maximum memory in bytes. Zero is unlimited.
+	MemoryBytes int64 `protobuf:"varint,1,opt,name=memory_bytes,json=memoryBytes,proto3" json:"memory_bytes,omitempty"`
+	// The maximum CPU time in billionths of a CPU. Zero is unlimited.
+	NanoCpus int64 `protobuf:"varint,2,opt,name=nano_cpus,json=nanoCpus,proto3" json:"nano_cpus,omitempty"`
+	// The maximum number of processes. Zero is unlimited.
+	Pids int64 `protobuf:"varint,3,opt,name=pids,proto3" json:"pids,omitempty"`
+}
+
+func (x *Workflow_ResourceLimits) Reset() {
+	*x = Workflow_ResourceLimits{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Workflow_ResourceLimits) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Workflow_ResourceLimits) ProtoMessage() {}
+
+func (x *Workflow_ResourceLimits) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Workflow_ResourceLimits.ProtoReflect.Descriptor instead.
+func (*Workflow_ResourceLimits) Descriptor() ([]byte, []int) {
+	return file_internal_proto_workflow_v2_workflow_proto_rawDescGZIP(), []int{9, 5}
+}
+
+func (x *Workflow_ResourceLimits) GetMemoryBytes() int64 {
+	if x != nil {
+		return x.MemoryBytes
+	}
+	return 0
+}
+
+func (x *Workflow_ResourceLimits) GetNanoCpus() int64 {
+	if x != nil {
+		return x.NanoCpus
+	}
+	return 0
+}
+
+func (x *Workflow_ResourceLimits) GetPids() int64 {
+	if x != nil {
+		return x.Pids
+	}
+	return 0
+}
+
+type Workflow_Capabilities struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Capabilities added to the default set.
+	Add []string `protobuf:"bytes,1,rep,name=add,proto3" json:"add,omitempty"`
+	// Capabilities dropped from the default set.
+	Drop []string `protobuf:"bytes,2,rep,name=drop,proto3" json:"drop,omitempty"`
+}
+
+func (x *Workflow_Capabilities) Reset() {
+	*x = Workflow_Capabilities{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Workflow_Capabilities) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Workflow_Capabilities) ProtoMessage() {}
+
+func (x *Workflow_Capabilities) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Workflow_Capabilities.ProtoReflect.Descriptor instead.
+func (*Workflow_Capabilities) Descriptor() ([]byte, []int) {
+	return file_internal_proto_workflow_v2_workflow_proto_rawDescGZIP(), []int{9, 6}
+}
+
+func (x *Workflow_Capabilities) GetAdd() []string {
 	if x != nil {
-		return x.Env
+		return x.Add
 	}
 	return nil
 }
 
-func (x *Workflow_Action) GetVolumes() []string {
+func (x *Workflow_Capabilities) GetDrop() []string {
 	if x != nil {
-		return x.Volumes
+		return x.Drop
 	}
 	return nil
 }
 
-func (x *Workflow_Action) GetNetworkNamespace() string {
-	if x != nil && x.NetworkNamespace != nil {
-		return *x.NetworkNamespace
+type Workflow_RetryPolicy struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The maximum number of times the action is retried after the initial attempt.
+	Retries int64 `protobuf:"varint,1,opt,name=retries,proto3" json:"retries,omitempty"`
+	// The delay, in seconds, before the first retry. The delay doubles for each subsequent retry.
+	BackoffSeconds int64 `protobuf:"varint,2,opt,name=backoff_seconds,json=backoffSeconds,proto3" json:"backoff_seconds,omitempty"`
+	// Failure reasons that are retried. When empty, all failures are retried.
+	Reasons []string `protobuf:"bytes,3,rep,name=reasons,proto3" json:"reasons,omitempty"`
+}
+
+func (x *Workflow_RetryPolicy) Reset() {
+	*x = Workflow_RetryPolicy{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return ""
+}
+
+func (x *Workflow_RetryPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Workflow_RetryPolicy) ProtoMessage() {}
+
+func (x *Workflow_RetryPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Workflow_RetryPolicy.ProtoReflect.Descriptor instead.
+func (*Workflow_RetryPolicy) Descriptor() ([]byte, []int) {
+	return file_internal_proto_workflow_v2_workflow_proto_rawDescGZIP(), []int{9, 7}
+}
+
+func (x *Workflow_RetryPolicy) GetRetries() int64 {
+	if x != nil {
+		return x.Retries
+	}
+	return 0
+}
+
+func (x *Workflow_RetryPolicy) GetBackoffSeconds() int64 {
+	if x != nil {
+		return x.BackoffSeconds
+	}
+	return 0
+}
+
+func (x *Workflow_RetryPolicy) GetReasons() []string {
+	if x != nil {
+		return x.Reasons
+	}
+	return nil
 }
 
 type Event_ActionStarted struct {
@@ -620,27 +1707,284 @@ type Event_ActionStarted struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
+	// A unique identifier for an action in the context of a workflow.
+	ActionId string `protobuf:"bytes,1,opt,name=action_id,json=actionId,proto3" json:"action_id,omitempty"`
+	// The attempt number starting from 1. Zero is considered the first attempt.
+	Attempt int64 `protobuf:"varint,2,opt,name=attempt,proto3" json:"attempt,omitempty"`
+}
+
+func (x *Event_ActionStarted) Reset() {
+	*x = Event_ActionStarted{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Event_ActionStarted) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event_ActionStarted) ProtoMessage() {}
+
+func (x *Event_ActionStarted) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event_ActionStarted.ProtoReflect.Descriptor instead.
+func (*Event_ActionStarted) Descriptor() ([]byte, []int) {
+	return file_internal_proto_workflow_v2_workflow_proto_rawDescGZIP(), []int{10, 0}
+}
+
+func (x *Event_ActionStarted) GetActionId() string {
+	if x != nil {
+		return x.ActionId
+	}
+	return ""
+}
+
+func (x *Event_ActionStarted) GetAttempt() int64 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
+type Event_ActionSucceeded struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// A unique identifier for an action in the context of a workflow.
+	ActionId string `protobuf:"bytes,1,opt,name=action_id,json=actionId,proto3" json:"action_id,omitempty"`
+	// The attempt number starting from 1. Zero is considered the first attempt.
+	Attempt int64 `protobuf:"varint,2,opt,name=attempt,proto3" json:"attempt,omitempty"`
+	// Key/value pairs written by the action for consumption by other actions.
+	Outputs map[string]string `protobuf:"bytes,3,rep,name=outputs,proto3" json:"outputs,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *Event_ActionSucceeded) Reset() {
+	*x = Event_ActionSucceeded{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Event_ActionSucceeded) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event_ActionSucceeded) ProtoMessage() {}
+
+func (x *Event_ActionSucceeded) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event_ActionSucceeded.ProtoReflect.Descriptor instead.
+func (*Event_ActionSucceeded) Descriptor() ([]byte, []int) {
+	return file_internal_proto_workflow_v2_workflow_proto_rawDescGZIP(), []int{10, 1}
+}
+
+func (x *Event_ActionSucceeded) GetActionId() string {
+	if x != nil {
+		return x.ActionId
+	}
+	return ""
+}
+
+func (x *Event_ActionSucceeded) GetAttempt() int64 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
+func (x *Event_ActionSucceeded) GetOutputs() map[string]string {
+	if x != nil {
+		return x.Outputs
+	}
+	return nil
+}
+
+type Event_ActionFailed struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// A unique identifier for an action in the context of a workflow.
+	ActionId string `protobuf:"bytes,1,opt,name=action_id,json=actionId,proto3" json:"action_id,omitempty"`
+	// A UpperCamelCase word or phrase concisly describing why an action failed. It is typically
+	// provided by the action itself.
+	FailureReason *string `protobuf:"bytes,2,opt,name=failure_reason,json=failureReason,proto3,oneof" json:"failure_reason,omitempty"`
+	// A free-form human readable string elaborating on the reason for failure. It is typically
+	// provided by the action itself.
+	FailureMessage *string `protobuf:"bytes,3,opt,name=failure_message,json=failureMessage,proto3,oneof" json:"failure_message,omitempty"`
+	// The attempt number starting from 1. Zero is considered the first attempt.
+	Attempt int64 `protobuf:"varint,4,opt,name=attempt,proto3" json:"attempt,omitempty"`
+	// Indicates the agent will retry the action. The workflow should not be considered failed.
+	WillRetry bool `protobuf:"varint,5,opt,name=will_retry,json=willRetry,proto3" json:"will_retry,omitempty"`
+}
+
+func (x *Event_ActionFailed) Reset() {
+	*x = Event_ActionFailed{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Event_ActionFailed) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event_ActionFailed) ProtoMessage() {}
+
+func (x *Event_ActionFailed) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event_ActionFailed.ProtoReflect.Descriptor instead.
+func (*Event_ActionFailed) Descriptor() ([]byte, []int) {
+	return file_internal_proto_workflow_v2_workflow_proto_rawDescGZIP(), []int{10, 2}
+}
+
+func (x *Event_ActionFailed) GetActionId() string {
+	if x != nil {
+		return x.ActionId
+	}
+	return ""
+}
+
+func (x *Event_ActionFailed) GetFailureReason() string {
+	if x != nil && x.FailureReason != nil {
+		return *x.FailureReason
+	}
+	return ""
+}
+
+func (x *Event_ActionFailed) GetFailureMessage() string {
+	if x != nil && x.FailureMessage != nil {
+		return *x.FailureMessage
+	}
+	return ""
+}
+
+func (x *Event_ActionFailed) GetAttempt() int64 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
+func (x *Event_ActionFailed) GetWillRetry() bool {
+	if x != nil {
+		return x.WillRetry
+	}
+	return false
+}
+
+type Event_ActionSkipped struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// A unique identifier for an action in the context of a workflow.
+	ActionId string `protobuf:"bytes,1,opt,name=action_id,json=actionId,proto3" json:"action_id,omitempty"`
+}
+
+func (x *Event_ActionSkipped) Reset() {
+	*x = Event_ActionSkipped{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Event_ActionSkipped) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event_ActionSkipped) ProtoMessage() {}
+
+func (x *Event_ActionSkipped) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event_ActionSkipped.ProtoReflect.Descriptor instead.
+func (*Event_ActionSkipped) Descriptor() ([]byte, []int) {
+	return file_internal_proto_workflow_v2_workflow_proto_rawDescGZIP(), []int{10, 3}
+}
+
+func (x *Event_ActionSkipped) GetActionId() string {
+	if x != nil {
+		return x.ActionId
+	}
+	return ""
+}
+
+type Event_ActionPaused struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
 	// A unique identifier for an action in the context of a workflow.
 	ActionId string `protobuf:"bytes,1,opt,name=action_id,json=actionId,proto3" json:"action_id,omitempty"`
 }
 
-func (x *Event_ActionStarted) Reset() {
-	*x = Event_ActionStarted{}
+func (x *Event_ActionPaused) Reset() {
+	*x = Event_ActionPaused{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[10]
+		mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[28]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *Event_ActionStarted) String() string {
+func (x *Event_ActionPaused) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Event_ActionStarted) ProtoMessage() {}
+func (*Event_ActionPaused) ProtoMessage() {}
 
-func (x *Event_ActionStarted) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[10]
+func (x *Event_ActionPaused) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[28]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -651,44 +1995,48 @@ func (x *Event_ActionStarted) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Event_ActionStarted.ProtoReflect.Descriptor instead.
-func (*Event_ActionStarted) Descriptor() ([]byte, []int) {
-	return file_internal_proto_workflow_v2_workflow_proto_rawDescGZIP(), []int{5, 0}
+// Deprecated: Use Event_ActionPaused.ProtoReflect.Descriptor instead.
+func (*Event_ActionPaused) Descriptor() ([]byte, []int) {
+	return file_internal_proto_workflow_v2_workflow_proto_rawDescGZIP(), []int{10, 4}
 }
 
-func (x *Event_ActionStarted) GetActionId() string {
+func (x *Event_ActionPaused) GetActionId() string {
 	if x != nil {
 		return x.ActionId
 	}
 	return ""
 }
 
-type Event_ActionSucceeded struct {
+type Event_ImagePulled struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// A unique identifier for an action in the context of a workflow.
-	ActionId string `protobuf:"bytes,1,opt,name=action_id,json=actionId,proto3" json:"action_id,omitempty"`
+	// The image that was pulled.
+	Image string `protobuf:"bytes,1,opt,name=image,proto3" json:"image,omitempty"`
+	// The number of distinct images used by the workflow that have been pulled.
+	Pulled int64 `protobuf:"varint,2,opt,name=pulled,proto3" json:"pulled,omitempty"`
+	// The number of distinct images used by the workflow.
+	Total int64 `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`
 }
 
-func (x *Event_ActionSucceeded) Reset() {
-	*x = Event_ActionSucceeded{}
+func (x *Event_ImagePulled) Reset() {
+	*x = Event_ImagePulled{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[11]
+		mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[29]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *Event_ActionSucceeded) String() string {
+func (x *Event_ImagePulled) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Event_ActionSucceeded) ProtoMessage() {}
+func (*Event_ImagePulled) ProtoMessage() {}
 
-func (x *Event_ActionSucceeded) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[11]
+func (x *Event_ImagePulled) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[29]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -699,50 +2047,60 @@ func (x *Event_ActionSucceeded) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Event_ActionSucceeded.ProtoReflect.Descriptor instead.
-func (*Event_ActionSucceeded) Descriptor() ([]byte, []int) {
-	return file_internal_proto_workflow_v2_workflow_proto_rawDescGZIP(), []int{5, 1}
+// Deprecated: Use Event_ImagePulled.ProtoReflect.Descriptor instead.
+func (*Event_ImagePulled) Descriptor() ([]byte, []int) {
+	return file_internal_proto_workflow_v2_workflow_proto_rawDescGZIP(), []int{10, 5}
 }
 
-func (x *Event_ActionSucceeded) GetActionId() string {
+func (x *Event_ImagePulled) GetImage() string {
 	if x != nil {
-		return x.ActionId
+		return x.Image
 	}
 	return ""
 }
 
-type Event_ActionFailed struct {
+func (x *Event_ImagePulled) GetPulled() int64 {
+	if x != nil {
+		return x.Pulled
+	}
+	return 0
+}
+
+func (x *Event_ImagePulled) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type Event_ImagePullFailed struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// A unique identifier for an action in the context of a workflow.
-	ActionId string `protobuf:"bytes,1,opt,name=action_id,json=actionId,proto3" json:"action_id,omitempty"`
-	// A UpperCamelCase word or phrase concisly describing why an action failed. It is typically
-	// provided by the action itself.
-	FailureReason *string `protobuf:"bytes,2,opt,name=failure_reason,json=failureReason,proto3,oneof" json:"failure_reason,omitempty"`
-	// A free-form human readable string elaborating on the reason for failure. It is typically
-	// provided by the action itself.
-	FailureMessage *string `protobuf:"bytes,3,opt,name=failure_message,json=failureMessage,proto3,oneof" json:"failure_message,omitempty"`
+	// The image that couldn't be pulled.
+	Image string `protobuf:"bytes,1,opt,name=image,proto3" json:"image,omitempty"`
+	// A message describing why the image couldn't be pulled.
+	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
 }
 
-func (x *Event_ActionFailed) Reset() {
-	*x = Event_ActionFailed{}
+func (x *Event_ImagePullFailed) Reset() {
+	*x = Event_ImagePullFailed{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[12]
+		mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[30]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *Event_ActionFailed) String() string {
+func (x *Event_ImagePullFailed) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Event_ActionFailed) ProtoMessage() {}
+func (*Event_ImagePullFailed) ProtoMessage() {}
 
-func (x *Event_ActionFailed) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[12]
+func (x *Event_ImagePullFailed) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[30]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -753,28 +2111,21 @@ func (x *Event_ActionFailed) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Event_ActionFailed.ProtoReflect.Descriptor instead.
-func (*Event_ActionFailed) Descriptor() ([]byte, []int) {
-	return file_internal_proto_workflow_v2_workflow_proto_rawDescGZIP(), []int{5, 2}
+// Deprecated: Use Event_ImagePullFailed.ProtoReflect.Descriptor instead.
+func (*Event_ImagePullFailed) Descriptor() ([]byte, []int) {
+	return file_internal_proto_workflow_v2_workflow_proto_rawDescGZIP(), []int{10, 6}
 }
 
-func (x *Event_ActionFailed) GetActionId() string {
+func (x *Event_ImagePullFailed) GetImage() string {
 	if x != nil {
-		return x.ActionId
-	}
-	return ""
-}
-
-func (x *Event_ActionFailed) GetFailureReason() string {
-	if x != nil && x.FailureReason != nil {
-		return *x.FailureReason
+		return x.Image
 	}
 	return ""
 }
 
-func (x *Event_ActionFailed) GetFailureMessage() string {
-	if x != nil && x.FailureMessage != nil {
-		return *x.FailureMessage
+func (x *Event_ImagePullFailed) GetMessage() string {
+	if x != nil {
+		return x.Message
 	}
 	return ""
 }
@@ -791,7 +2142,7 @@ type Event_WorkflowRejected struct {
 func (x *Event_WorkflowRejected) Reset() {
 	*x = Event_WorkflowRejected{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[13]
+		mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[31]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -804,7 +2155,7 @@ func (x *Event_WorkflowRejected) String() string {
 func (*Event_WorkflowRejected) ProtoMessage() {}
 
 func (x *Event_WorkflowRejected) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[13]
+	mi := &file_internal_proto_workflow_v2_workflow_proto_msgTypes[31]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -817,7 +2168,7 @@ func (x *Event_WorkflowRejected) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Event_WorkflowRejected.ProtoReflect.Descriptor instead.
 func (*Event_WorkflowRejected) Descriptor() ([]byte, []int) {
-	return file_internal_proto_workflow_v2_workflow_proto_rawDescGZIP(), []int{5, 3}
+	return file_internal_proto_workflow_v2_workflow_proto_rawDescGZIP(), []int{10, 7}
 }
 
 func (x *Event_WorkflowRejected) GetMessage() string {
@@ -834,135 +2185,387 @@ var file_internal_proto_workflow_v2_workflow_proto_rawDesc = []byte{
 	0x2f, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2f, 0x76, 0x32, 0x2f, 0x77, 0x6f, 0x72,
 	0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x1a, 0x69, 0x6e, 0x74,
 	0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x77, 0x6f, 0x72, 0x6b,
-	0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x76, 0x32, 0x22, 0x30, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x57, 0x6f,
-	0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19,
-	0x0a, 0x08, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x07, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x22, 0xf0, 0x02, 0x0a, 0x14, 0x47, 0x65,
-	0x74, 0x57, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x12, 0x67, 0x0a, 0x0e, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x77, 0x6f, 0x72, 0x6b,
-	0x66, 0x6c, 0x6f, 0x77, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x3e, 0x2e, 0x69, 0x6e, 0x74,
-	0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x77, 0x6f, 0x72, 0x6b,
-	0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x76, 0x32, 0x2e, 0x47, 0x65, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x66,
-	0x6c, 0x6f, 0x77, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x53, 0x74, 0x61,
-	0x72, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x48, 0x00, 0x52, 0x0d, 0x73, 0x74,
-	0x61, 0x72, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x12, 0x64, 0x0a, 0x0d, 0x73,
-	0x74, 0x6f, 0x70, 0x5f, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x3d, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x76, 0x32, 0x2e,
-	0x47, 0x65, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x73, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x53, 0x74, 0x6f, 0x70, 0x57, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f,
-	0x77, 0x48, 0x00, 0x52, 0x0c, 0x73, 0x74, 0x6f, 0x70, 0x57, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f,
-	0x77, 0x1a, 0x51, 0x0a, 0x0d, 0x53, 0x74, 0x61, 0x72, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x66, 0x6c,
-	0x6f, 0x77, 0x12, 0x40, 0x0a, 0x08, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x76,
-	0x32, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x52, 0x08, 0x77, 0x6f, 0x72, 0x6b,
-	0x66, 0x6c, 0x6f, 0x77, 0x1a, 0x2f, 0x0a, 0x0c, 0x53, 0x74, 0x6f, 0x70, 0x57, 0x6f, 0x72, 0x6b,
-	0x66, 0x6c, 0x6f, 0x77, 0x12, 0x1f, 0x0a, 0x0b, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77,
-	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x77, 0x6f, 0x72, 0x6b, 0x66,
-	0x6c, 0x6f, 0x77, 0x49, 0x64, 0x42, 0x05, 0x0a, 0x03, 0x63, 0x6d, 0x64, 0x22, 0x4e, 0x0a, 0x13,
-	0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x12, 0x37, 0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x21, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x76, 0x32, 0x2e,
-	0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x22, 0x16, 0x0a, 0x14,
-	0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x22, 0xcc, 0x03, 0x0a, 0x08, 0x57, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f,
-	0x77, 0x12, 0x1f, 0x0a, 0x0b, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x5f, 0x69, 0x64,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77,
-	0x49, 0x64, 0x12, 0x45, 0x0a, 0x07, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20,
+	0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x76, 0x32, 0x1a, 0x1c, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x30, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x57, 0x6f, 0x72,
+	0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a,
+	0x08, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x22, 0xcd, 0x04, 0x0a, 0x14, 0x47, 0x65, 0x74,
+	0x57, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x67, 0x0a, 0x0e, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x77, 0x6f, 0x72, 0x6b, 0x66,
+	0x6c, 0x6f, 0x77, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x3e, 0x2e, 0x69, 0x6e, 0x74, 0x65,
+	0x72, 0x6e, 0x61, 0x6c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x66,
+	0x6c, 0x6f, 0x77, 0x2e, 0x76, 0x32, 0x2e, 0x47, 0x65, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x66, 0x6c,
+	0x6f, 0x77, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x53, 0x74, 0x61, 0x72,
+	0x74, 0x57, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x48, 0x00, 0x52, 0x0d, 0x73, 0x74, 0x61,
+	0x72, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x12, 0x64, 0x0a, 0x0d, 0x73, 0x74,
+	0x6f, 0x70, 0x5f, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x3d, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x76, 0x32, 0x2e, 0x47,
+	0x65, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x2e, 0x53, 0x74, 0x6f, 0x70, 0x57, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77,
+	0x48, 0x00, 0x52, 0x0c, 0x73, 0x74, 0x6f, 0x70, 0x57, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77,
+	0x12, 0x67, 0x0a, 0x0e, 0x70, 0x61, 0x75, 0x73, 0x65, 0x5f, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c,
+	0x6f, 0x77, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x3e, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72,
+	0x6e, 0x61, 0x6c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c,
+	0x6f, 0x77, 0x2e, 0x76, 0x32, 0x2e, 0x47, 0x65, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f,
+	0x77, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x50, 0x61, 0x75, 0x73, 0x65,
+	0x57, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x48, 0x00, 0x52, 0x0d, 0x70, 0x61, 0x75, 0x73,
+	0x65, 0x57, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x1a, 0x51, 0x0a, 0x0d, 0x53, 0x74, 0x61,
+	0x72, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x12, 0x40, 0x0a, 0x08, 0x77, 0x6f,
+	0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x69,
+	0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x77, 0x6f,
+	0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x76, 0x32, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x66, 0x6c,
+	0x6f, 0x77, 0x52, 0x08, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x1a, 0x2f, 0x0a, 0x0c,
+	0x53, 0x74, 0x6f, 0x70, 0x57, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x12, 0x1f, 0x0a, 0x0b,
+	0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0a, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x49, 0x64, 0x1a, 0x72, 0x0a,
+	0x0d, 0x50, 0x61, 0x75, 0x73, 0x65, 0x57, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x12, 0x1f,
+	0x0a, 0x0b, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0a, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x49, 0x64, 0x12,
+	0x40, 0x0a, 0x05, 0x70, 0x61, 0x75, 0x73, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2a,
+	0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x76, 0x32, 0x2e, 0x57, 0x6f, 0x72, 0x6b,
+	0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x50, 0x61, 0x75, 0x73, 0x65, 0x52, 0x05, 0x70, 0x61, 0x75, 0x73,
+	0x65, 0x42, 0x05, 0x0a, 0x03, 0x63, 0x6d, 0x64, 0x22, 0x89, 0x01, 0x0a, 0x13, 0x50, 0x75, 0x62,
+	0x6c, 0x69, 0x73, 0x68, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x37, 0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x21, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x76, 0x32, 0x2e, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x52, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x73,
+	0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73,
+	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65, 0x71, 0x75,
+	0x65, 0x6e, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x73, 0x65, 0x71, 0x75,
+	0x65, 0x6e, 0x63, 0x65, 0x22, 0x16, 0x0a, 0x14, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x53, 0x0a, 0x18,
+	0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4c, 0x6f, 0x67,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x37, 0x0a, 0x03, 0x6c, 0x6f, 0x67, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e,
+	0x76, 0x32, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4c, 0x6f, 0x67, 0x52, 0x03, 0x6c, 0x6f,
+	0x67, 0x22, 0x1b, 0x0a, 0x19, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x41, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x4c, 0x6f, 0x67, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x76,
+	0x0a, 0x15, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4c, 0x6f, 0x67, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x77, 0x6f, 0x72, 0x6b, 0x66,
+	0x6c, 0x6f, 0x77, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x77, 0x6f,
+	0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x61, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x5f, 0x62,
+	0x79, 0x74, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x6c, 0x69, 0x6d, 0x69,
+	0x74, 0x42, 0x79, 0x74, 0x65, 0x73, 0x22, 0x71, 0x0a, 0x16, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x4c, 0x6f, 0x67, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x39, 0x0a, 0x04, 0x6c, 0x6f, 0x67, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x25,
+	0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x76, 0x32, 0x2e, 0x41, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x4c, 0x6f, 0x67, 0x52, 0x04, 0x6c, 0x6f, 0x67, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x74,
+	0x72, 0x75, 0x6e, 0x63, 0x61, 0x74, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09,
+	0x74, 0x72, 0x75, 0x6e, 0x63, 0x61, 0x74, 0x65, 0x64, 0x22, 0x8e, 0x02, 0x0a, 0x09, 0x41, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x4c, 0x6f, 0x67, 0x12, 0x1f, 0x0a, 0x0b, 0x77, 0x6f, 0x72, 0x6b, 0x66,
+	0x6c, 0x6f, 0x77, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x77, 0x6f,
+	0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x61, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x44, 0x0a, 0x06, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x2c, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e,
+	0x76, 0x32, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4c, 0x6f, 0x67, 0x2e, 0x53, 0x74, 0x72,
+	0x65, 0x61, 0x6d, 0x52, 0x06, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x12, 0x0a, 0x04, 0x64,
+	0x61, 0x74, 0x61, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12,
+	0x39, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52,
+	0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x22, 0x2e, 0x0a, 0x06, 0x53, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x12, 0x11, 0x0a, 0x0d, 0x53, 0x54, 0x52, 0x45, 0x41, 0x4d, 0x5f, 0x53,
+	0x54, 0x44, 0x4f, 0x55, 0x54, 0x10, 0x00, 0x12, 0x11, 0x0a, 0x0d, 0x53, 0x54, 0x52, 0x45, 0x41,
+	0x4d, 0x5f, 0x53, 0x54, 0x44, 0x45, 0x52, 0x52, 0x10, 0x01, 0x22, 0xe1, 0x12, 0x0a, 0x08, 0x57,
+	0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x12, 0x1f, 0x0a, 0x0b, 0x77, 0x6f, 0x72, 0x6b, 0x66,
+	0x6c, 0x6f, 0x77, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x77, 0x6f,
+	0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x49, 0x64, 0x12, 0x45, 0x0a, 0x07, 0x61, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2b, 0x2e, 0x69, 0x6e, 0x74, 0x65,
+	0x72, 0x6e, 0x61, 0x6c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x66,
+	0x6c, 0x6f, 0x77, 0x2e, 0x76, 0x32, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e,
+	0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x07, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12,
+	0x3e, 0x0a, 0x0e, 0x63, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x64, 0x61, 0x74,
+	0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74,
+	0x52, 0x0d, 0x63, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x44, 0x61, 0x74, 0x61, 0x12,
+	0x4a, 0x0a, 0x0a, 0x6f, 0x6e, 0x5f, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x18, 0x04, 0x20,
 	0x03, 0x28, 0x0b, 0x32, 0x2b, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2e, 0x70,
 	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x76, 0x32,
 	0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e,
-	0x52, 0x07, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x1a, 0xd7, 0x02, 0x0a, 0x06, 0x41, 0x63,
-	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6d, 0x61, 0x67,
-	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x15,
-	0x0a, 0x03, 0x63, 0x6d, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x03, 0x63,
-	0x6d, 0x64, 0x88, 0x01, 0x01, 0x12, 0x12, 0x0a, 0x04, 0x61, 0x72, 0x67, 0x73, 0x18, 0x05, 0x20,
-	0x03, 0x28, 0x09, 0x52, 0x04, 0x61, 0x72, 0x67, 0x73, 0x12, 0x46, 0x0a, 0x03, 0x65, 0x6e, 0x76,
-	0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x34, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61,
-	0x6c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77,
-	0x2e, 0x76, 0x32, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x41, 0x63, 0x74,
-	0x69, 0x6f, 0x6e, 0x2e, 0x45, 0x6e, 0x76, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x03, 0x65, 0x6e,
-	0x76, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x73, 0x18, 0x07, 0x20, 0x03,
-	0x28, 0x09, 0x52, 0x07, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x73, 0x12, 0x30, 0x0a, 0x11, 0x6e,
-	0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65,
-	0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x10, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72,
-	0x6b, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x88, 0x01, 0x01, 0x1a, 0x36, 0x0a,
-	0x08, 0x45, 0x6e, 0x76, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
+	0x52, 0x09, 0x6f, 0x6e, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x12, 0x45, 0x0a, 0x07, 0x66,
+	0x69, 0x6e, 0x61, 0x6c, 0x6c, 0x79, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2b, 0x2e, 0x69,
+	0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x77, 0x6f,
+	0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x76, 0x32, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x66, 0x6c,
+	0x6f, 0x77, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x07, 0x66, 0x69, 0x6e, 0x61, 0x6c,
+	0x6c, 0x79, 0x12, 0x40, 0x0a, 0x05, 0x70, 0x61, 0x75, 0x73, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x2a, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x76, 0x32, 0x2e, 0x57,
+	0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x50, 0x61, 0x75, 0x73, 0x65, 0x52, 0x05, 0x70,
+	0x61, 0x75, 0x73, 0x65, 0x12, 0x4b, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18,
+	0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x31, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e,
+	0x76, 0x32, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x73, 0x1a, 0x6d, 0x0a, 0x0c, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x6b, 0x65, 0x79, 0x12, 0x47, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x31, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x76, 0x32, 0x2e,
+	0x57, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01,
+	0x1a, 0xc2, 0x01, 0x0a, 0x0c, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x75, 0x63, 0x63, 0x65, 0x65, 0x64, 0x65, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x73, 0x75, 0x63, 0x63, 0x65, 0x65, 0x64, 0x65, 0x64, 0x12,
+	0x58, 0x0a, 0x07, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x3e, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x76, 0x32, 0x2e, 0x57, 0x6f,
+	0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x2e, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x52, 0x07, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x73, 0x1a, 0x3a, 0x0a, 0x0c, 0x4f, 0x75, 0x74,
+	0x70, 0x75, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
 	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76,
 	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
-	0x65, 0x3a, 0x02, 0x38, 0x01, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x63, 0x6d, 0x64, 0x42, 0x14, 0x0a,
-	0x12, 0x5f, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70,
-	0x61, 0x63, 0x65, 0x22, 0xe0, 0x05, 0x0a, 0x05, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x1f, 0x0a,
-	0x0b, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x0a, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x49, 0x64, 0x12, 0x58,
-	0x0a, 0x0e, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x73, 0x74, 0x61, 0x72, 0x74, 0x65, 0x64,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2f, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61,
-	0x6c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77,
-	0x2e, 0x76, 0x32, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e,
-	0x53, 0x74, 0x61, 0x72, 0x74, 0x65, 0x64, 0x48, 0x00, 0x52, 0x0d, 0x61, 0x63, 0x74, 0x69, 0x6f,
-	0x6e, 0x53, 0x74, 0x61, 0x72, 0x74, 0x65, 0x64, 0x12, 0x5e, 0x0a, 0x10, 0x61, 0x63, 0x74, 0x69,
-	0x6f, 0x6e, 0x5f, 0x73, 0x75, 0x63, 0x63, 0x65, 0x65, 0x64, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x31, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2e, 0x70, 0x72,
+	0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x41, 0x0a, 0x05, 0x50, 0x61, 0x75, 0x73, 0x65, 0x12, 0x16,
+	0x0a, 0x06, 0x70, 0x61, 0x75, 0x73, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06,
+	0x70, 0x61, 0x75, 0x73, 0x65, 0x64, 0x12, 0x20, 0x0a, 0x0b, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x70,
+	0x6f, 0x69, 0x6e, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x62, 0x72, 0x65,
+	0x61, 0x6b, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x1a, 0xe9, 0x08, 0x0a, 0x06, 0x41, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x15, 0x0a,
+	0x03, 0x63, 0x6d, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x03, 0x63, 0x6d,
+	0x64, 0x88, 0x01, 0x01, 0x12, 0x12, 0x0a, 0x04, 0x61, 0x72, 0x67, 0x73, 0x18, 0x05, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x04, 0x61, 0x72, 0x67, 0x73, 0x12, 0x46, 0x0a, 0x03, 0x65, 0x6e, 0x76, 0x18,
+	0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x34, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e,
+	0x76, 0x32, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x41, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x2e, 0x45, 0x6e, 0x76, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x03, 0x65, 0x6e, 0x76,
+	0x12, 0x18, 0x0a, 0x07, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x07, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x73, 0x12, 0x30, 0x0a, 0x11, 0x6e, 0x65,
+	0x74, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x10, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b,
+	0x4e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x88, 0x01, 0x01, 0x12, 0x58, 0x0a, 0x0c,
+	0x72, 0x65, 0x74, 0x72, 0x79, 0x5f, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x18, 0x09, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x30, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2e, 0x70, 0x72,
 	0x6f, 0x74, 0x6f, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x76, 0x32, 0x2e,
-	0x45, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x75, 0x63, 0x63,
-	0x65, 0x65, 0x64, 0x65, 0x64, 0x48, 0x00, 0x52, 0x0f, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53,
-	0x75, 0x63, 0x63, 0x65, 0x65, 0x64, 0x65, 0x64, 0x12, 0x55, 0x0a, 0x0d, 0x61, 0x63, 0x74, 0x69,
-	0x6f, 0x6e, 0x5f, 0x66, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x2e, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x76, 0x32, 0x2e, 0x45, 0x76, 0x65,
-	0x6e, 0x74, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x46, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x48,
-	0x00, 0x52, 0x0c, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x46, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x12,
-	0x61, 0x0a, 0x11, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x5f, 0x72, 0x65, 0x6a, 0x65,
-	0x63, 0x74, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x32, 0x2e, 0x69, 0x6e, 0x74,
+	0x57, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x52, 0x65, 0x74, 0x72, 0x79, 0x50, 0x6f,
+	0x6c, 0x69, 0x63, 0x79, 0x48, 0x02, 0x52, 0x0b, 0x72, 0x65, 0x74, 0x72, 0x79, 0x50, 0x6f, 0x6c,
+	0x69, 0x63, 0x79, 0x88, 0x01, 0x01, 0x12, 0x1d, 0x0a, 0x0a, 0x64, 0x65, 0x70, 0x65, 0x6e, 0x64,
+	0x73, 0x5f, 0x6f, 0x6e, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x64, 0x65, 0x70, 0x65,
+	0x6e, 0x64, 0x73, 0x4f, 0x6e, 0x12, 0x27, 0x0a, 0x0f, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74,
+	0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e,
+	0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x12, 0x17,
+	0x0a, 0x04, 0x77, 0x68, 0x65, 0x6e, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x48, 0x03, 0x52, 0x04,
+	0x77, 0x68, 0x65, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x28, 0x0a, 0x0d, 0x70, 0x69, 0x64, 0x5f, 0x6e,
+	0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x48, 0x04,
+	0x52, 0x0c, 0x70, 0x69, 0x64, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x88, 0x01,
+	0x01, 0x12, 0x1e, 0x0a, 0x0a, 0x70, 0x72, 0x69, 0x76, 0x69, 0x6c, 0x65, 0x67, 0x65, 0x64, 0x18,
+	0x0e, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x70, 0x72, 0x69, 0x76, 0x69, 0x6c, 0x65, 0x67, 0x65,
+	0x64, 0x12, 0x5a, 0x0a, 0x0c, 0x63, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65,
+	0x73, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x31, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e,
+	0x61, 0x6c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f,
+	0x77, 0x2e, 0x76, 0x32, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x43, 0x61,
+	0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x48, 0x05, 0x52, 0x0c, 0x63, 0x61,
+	0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x88, 0x01, 0x01, 0x12, 0x18, 0x0a,
+	0x07, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x73, 0x18, 0x10, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07,
+	0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x73, 0x12, 0x56, 0x0a, 0x09, 0x72, 0x65, 0x73, 0x6f, 0x75,
+	0x72, 0x63, 0x65, 0x73, 0x18, 0x11, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x33, 0x2e, 0x69, 0x6e, 0x74,
 	0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x77, 0x6f, 0x72, 0x6b,
-	0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x76, 0x32, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x57, 0x6f,
-	0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x52, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x65, 0x64, 0x48, 0x00,
-	0x52, 0x10, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x52, 0x65, 0x6a, 0x65, 0x63, 0x74,
-	0x65, 0x64, 0x1a, 0x2c, 0x0a, 0x0d, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x72,
-	0x74, 0x65, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64,
-	0x1a, 0x2e, 0x0a, 0x0f, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x75, 0x63, 0x63, 0x65, 0x65,
-	0x64, 0x65, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64,
-	0x1a, 0xac, 0x01, 0x0a, 0x0c, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x46, 0x61, 0x69, 0x6c, 0x65,
-	0x64, 0x12, 0x1b, 0x0a, 0x09, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x2a,
-	0x0a, 0x0e, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x5f, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0d, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72,
-	0x65, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x2c, 0x0a, 0x0f, 0x66, 0x61,
-	0x69, 0x6c, 0x75, 0x72, 0x65, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20,
-	0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x0e, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x4d, 0x65,
-	0x73, 0x73, 0x61, 0x67, 0x65, 0x88, 0x01, 0x01, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x66, 0x61, 0x69,
-	0x6c, 0x75, 0x72, 0x65, 0x5f, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x42, 0x12, 0x0a, 0x10, 0x5f,
-	0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x1a,
-	0x2c, 0x0a, 0x10, 0x57, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x52, 0x65, 0x6a, 0x65, 0x63,
-	0x74, 0x65, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x42, 0x07, 0x0a,
-	0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x32, 0xfd, 0x01, 0x0a, 0x0f, 0x57, 0x6f, 0x72, 0x6b, 0x66,
-	0x6c, 0x6f, 0x77, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x75, 0x0a, 0x0c, 0x47, 0x65,
-	0x74, 0x57, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x73, 0x12, 0x2f, 0x2e, 0x69, 0x6e, 0x74,
+	0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x76, 0x32, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77,
+	0x2e, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x48,
+	0x06, 0x52, 0x09, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x88, 0x01, 0x01, 0x12,
+	0x1e, 0x0a, 0x0a, 0x69, 0x64, 0x65, 0x6d, 0x70, 0x6f, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x12, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x0a, 0x69, 0x64, 0x65, 0x6d, 0x70, 0x6f, 0x74, 0x65, 0x6e, 0x74, 0x12,
+	0x44, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x13, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x30, 0x2e,
+	0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x77,
+	0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x76, 0x32, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x66,
+	0x6c, 0x6f, 0x77, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x52,
+	0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x45, 0x0a, 0x05, 0x6b, 0x65, 0x78, 0x65, 0x63, 0x18, 0x14,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x76,
+	0x32, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x4b, 0x65, 0x78, 0x65, 0x63,
+	0x48, 0x07, 0x52, 0x05, 0x6b, 0x65, 0x78, 0x65, 0x63, 0x88, 0x01, 0x01, 0x1a, 0x36, 0x0a, 0x08,
+	0x45, 0x6e, 0x76, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x3a, 0x02, 0x38, 0x01, 0x22, 0x3b, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x0e,
+	0x54, 0x59, 0x50, 0x45, 0x5f, 0x43, 0x4f, 0x4e, 0x54, 0x41, 0x49, 0x4e, 0x45, 0x52, 0x10, 0x00,
+	0x12, 0x0f, 0x0a, 0x0b, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x52, 0x45, 0x42, 0x4f, 0x4f, 0x54, 0x10,
+	0x01, 0x12, 0x0e, 0x0a, 0x0a, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x4b, 0x45, 0x58, 0x45, 0x43, 0x10,
+	0x02, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x63, 0x6d, 0x64, 0x42, 0x14, 0x0a, 0x12, 0x5f, 0x6e, 0x65,
+	0x74, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x42,
+	0x0f, 0x0a, 0x0d, 0x5f, 0x72, 0x65, 0x74, 0x72, 0x79, 0x5f, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79,
+	0x42, 0x07, 0x0a, 0x05, 0x5f, 0x77, 0x68, 0x65, 0x6e, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x70, 0x69,
+	0x64, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x42, 0x0f, 0x0a, 0x0d, 0x5f,
+	0x63, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x42, 0x0c, 0x0a, 0x0a,
+	0x5f, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x6b,
+	0x65, 0x78, 0x65, 0x63, 0x1a, 0x9f, 0x01, 0x0a, 0x05, 0x4b, 0x65, 0x78, 0x65, 0x63, 0x12, 0x21,
+	0x0a, 0x0c, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x44, 0x65, 0x76, 0x69, 0x63,
+	0x65, 0x12, 0x17, 0x0a, 0x07, 0x66, 0x73, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x66, 0x73, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x6b, 0x65,
+	0x72, 0x6e, 0x65, 0x6c, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0a, 0x6b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x50, 0x61, 0x74, 0x68, 0x12, 0x1f, 0x0a, 0x0b, 0x69,
+	0x6e, 0x69, 0x74, 0x72, 0x64, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x69, 0x6e, 0x69, 0x74, 0x72, 0x64, 0x50, 0x61, 0x74, 0x68, 0x12, 0x18, 0x0a, 0x07,
+	0x63, 0x6d, 0x64, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63,
+	0x6d, 0x64, 0x6c, 0x69, 0x6e, 0x65, 0x1a, 0x64, 0x0a, 0x0e, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72,
+	0x63, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x6d, 0x65, 0x6d, 0x6f,
+	0x72, 0x79, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b,
+	0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x6e,
+	0x61, 0x6e, 0x6f, 0x5f, 0x63, 0x70, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08,
+	0x6e, 0x61, 0x6e, 0x6f, 0x43, 0x70, 0x75, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x69, 0x64, 0x73,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x70, 0x69, 0x64, 0x73, 0x1a, 0x34, 0x0a, 0x0c,
+	0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12, 0x10, 0x0a, 0x03,
+	0x61, 0x64, 0x64, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x03, 0x61, 0x64, 0x64, 0x12, 0x12,
+	0x0a, 0x04, 0x64, 0x72, 0x6f, 0x70, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x64, 0x72,
+	0x6f, 0x70, 0x1a, 0x6a, 0x0a, 0x0b, 0x52, 0x65, 0x74, 0x72, 0x79, 0x50, 0x6f, 0x6c, 0x69, 0x63,
+	0x79, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x65, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x07, 0x72, 0x65, 0x74, 0x72, 0x69, 0x65, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x62,
+	0x61, 0x63, 0x6b, 0x6f, 0x66, 0x66, 0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x62, 0x61, 0x63, 0x6b, 0x6f, 0x66, 0x66, 0x53, 0x65, 0x63,
+	0x6f, 0x6e, 0x64, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x73, 0x18,
+	0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x73, 0x22, 0xbb,
+	0x0c, 0x0a, 0x05, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x77, 0x6f, 0x72, 0x6b,
+	0x66, 0x6c, 0x6f, 0x77, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x77,
+	0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x49, 0x64, 0x12, 0x58, 0x0a, 0x0e, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x5f, 0x73, 0x74, 0x61, 0x72, 0x74, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x2f, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x76, 0x32, 0x2e, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x72, 0x74,
+	0x65, 0x64, 0x48, 0x00, 0x52, 0x0d, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x72,
+	0x74, 0x65, 0x64, 0x12, 0x5e, 0x0a, 0x10, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x73, 0x75,
+	0x63, 0x63, 0x65, 0x65, 0x64, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x31, 0x2e,
+	0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x77,
+	0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x76, 0x32, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x75, 0x63, 0x63, 0x65, 0x65, 0x64, 0x65, 0x64,
+	0x48, 0x00, 0x52, 0x0f, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x75, 0x63, 0x63, 0x65, 0x65,
+	0x64, 0x65, 0x64, 0x12, 0x55, 0x0a, 0x0d, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x66, 0x61,
+	0x69, 0x6c, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2e, 0x2e, 0x69, 0x6e, 0x74,
 	0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x77, 0x6f, 0x72, 0x6b,
-	0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x76, 0x32, 0x2e, 0x47, 0x65, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x66,
-	0x6c, 0x6f, 0x77, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x30, 0x2e, 0x69, 0x6e,
-	0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x77, 0x6f, 0x72,
-	0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x76, 0x32, 0x2e, 0x47, 0x65, 0x74, 0x57, 0x6f, 0x72, 0x6b,
-	0x66, 0x6c, 0x6f, 0x77, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x30,
-	0x01, 0x12, 0x73, 0x0a, 0x0c, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x45, 0x76, 0x65, 0x6e,
-	0x74, 0x12, 0x2f, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x76, 0x32, 0x2e, 0x50,
-	0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x30, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2e, 0x70, 0x72,
+	0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x76, 0x32, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x41, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x46, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x48, 0x00, 0x52, 0x0c, 0x61, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x46, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x12, 0x61, 0x0a, 0x11, 0x77, 0x6f,
+	0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x5f, 0x72, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x65, 0x64, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x32, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e,
+	0x76, 0x32, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f,
+	0x77, 0x52, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x65, 0x64, 0x48, 0x00, 0x52, 0x10, 0x77, 0x6f, 0x72,
+	0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x52, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x65, 0x64, 0x12, 0x58, 0x0a,
+	0x0e, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x73, 0x6b, 0x69, 0x70, 0x70, 0x65, 0x64, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2f, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e,
+	0x76, 0x32, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53,
+	0x6b, 0x69, 0x70, 0x70, 0x65, 0x64, 0x48, 0x00, 0x52, 0x0d, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x53, 0x6b, 0x69, 0x70, 0x70, 0x65, 0x64, 0x12, 0x55, 0x0a, 0x0d, 0x61, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x5f, 0x70, 0x61, 0x75, 0x73, 0x65, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2e,
+	0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x76, 0x32, 0x2e, 0x45, 0x76, 0x65, 0x6e,
+	0x74, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x61, 0x75, 0x73, 0x65, 0x64, 0x48, 0x00,
+	0x52, 0x0c, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x61, 0x75, 0x73, 0x65, 0x64, 0x12, 0x52,
+	0x0a, 0x0c, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x5f, 0x70, 0x75, 0x6c, 0x6c, 0x65, 0x64, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x2d, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x76,
+	0x32, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x50, 0x75, 0x6c,
+	0x6c, 0x65, 0x64, 0x48, 0x00, 0x52, 0x0b, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x50, 0x75, 0x6c, 0x6c,
+	0x65, 0x64, 0x12, 0x5f, 0x0a, 0x11, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x5f, 0x70, 0x75, 0x6c, 0x6c,
+	0x5f, 0x66, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x31, 0x2e,
+	0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x77,
+	0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x76, 0x32, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x2e, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x50, 0x75, 0x6c, 0x6c, 0x46, 0x61, 0x69, 0x6c, 0x65, 0x64,
+	0x48, 0x00, 0x52, 0x0f, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x50, 0x75, 0x6c, 0x6c, 0x46, 0x61, 0x69,
+	0x6c, 0x65, 0x64, 0x1a, 0x46, 0x0a, 0x0d, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61,
+	0x72, 0x74, 0x65, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49,
+	0x64, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x07, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x1a, 0xde, 0x01, 0x0a, 0x0f,
+	0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x75, 0x63, 0x63, 0x65, 0x65, 0x64, 0x65, 0x64, 0x12,
+	0x1b, 0x0a, 0x09, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07,
+	0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x61,
+	0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x12, 0x58, 0x0a, 0x07, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74,
+	0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x3e, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e,
+	0x61, 0x6c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f,
+	0x77, 0x2e, 0x76, 0x32, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x53, 0x75, 0x63, 0x63, 0x65, 0x65, 0x64, 0x65, 0x64, 0x2e, 0x4f, 0x75, 0x74, 0x70, 0x75,
+	0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x73,
+	0x1a, 0x3a, 0x0a, 0x0c, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b,
+	0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0xe5, 0x01, 0x0a,
+	0x0c, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x46, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x12, 0x1b, 0x0a,
+	0x09, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x2a, 0x0a, 0x0e, 0x66, 0x61,
+	0x69, 0x6c, 0x75, 0x72, 0x65, 0x5f, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x48, 0x00, 0x52, 0x0d, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x52, 0x65, 0x61,
+	0x73, 0x6f, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x2c, 0x0a, 0x0f, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72,
+	0x65, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48,
+	0x01, 0x52, 0x0e, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x88, 0x01, 0x01, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x12, 0x1d,
+	0x0a, 0x0a, 0x77, 0x69, 0x6c, 0x6c, 0x5f, 0x72, 0x65, 0x74, 0x72, 0x79, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x09, 0x77, 0x69, 0x6c, 0x6c, 0x52, 0x65, 0x74, 0x72, 0x79, 0x42, 0x11, 0x0a,
+	0x0f, 0x5f, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x5f, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e,
+	0x42, 0x12, 0x0a, 0x10, 0x5f, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x5f, 0x6d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x1a, 0x2c, 0x0a, 0x0d, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x6b,
+	0x69, 0x70, 0x70, 0x65, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x49, 0x64, 0x1a, 0x2b, 0x0a, 0x0c, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x61, 0x75, 0x73,
+	0x65, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x1a,
+	0x51, 0x0a, 0x0b, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x50, 0x75, 0x6c, 0x6c, 0x65, 0x64, 0x12, 0x14,
+	0x0a, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x69,
+	0x6d, 0x61, 0x67, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x75, 0x6c, 0x6c, 0x65, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x70, 0x75, 0x6c, 0x6c, 0x65, 0x64, 0x12, 0x14, 0x0a, 0x05,
+	0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x74, 0x6f, 0x74,
+	0x61, 0x6c, 0x1a, 0x41, 0x0a, 0x0f, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x50, 0x75, 0x6c, 0x6c, 0x46,
+	0x61, 0x69, 0x6c, 0x65, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x1a, 0x2c, 0x0a, 0x10, 0x57, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f,
+	0x77, 0x52, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x65, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x42, 0x07, 0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x32, 0xff, 0x03, 0x0a,
+	0x0f, 0x57, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x12, 0x75, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x73,
+	0x12, 0x2f, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x76, 0x32, 0x2e, 0x47, 0x65,
+	0x74, 0x57, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x30, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x76, 0x32, 0x2e, 0x47,
+	0x65, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x00, 0x30, 0x01, 0x12, 0x73, 0x0a, 0x0c, 0x50, 0x75, 0x62, 0x6c, 0x69,
+	0x73, 0x68, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x2f, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e,
+	0x61, 0x6c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f,
+	0x77, 0x2e, 0x76, 0x32, 0x2e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x45, 0x76, 0x65, 0x6e,
+	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x30, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72,
+	0x6e, 0x61, 0x6c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c,
+	0x6f, 0x77, 0x2e, 0x76, 0x32, 0x2e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x84, 0x01, 0x0a,
+	0x11, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4c, 0x6f,
+	0x67, 0x73, 0x12, 0x34, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2e, 0x70, 0x72,
 	0x6f, 0x74, 0x6f, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x76, 0x32, 0x2e,
-	0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x40, 0x5a, 0x3e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
-	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x74, 0x69, 0x6e, 0x6b, 0x65, 0x72, 0x62, 0x65, 0x6c, 0x6c, 0x2f,
-	0x74, 0x69, 0x6e, 0x6b, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x2f, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2f, 0x76, 0x32, 0x3b,
-	0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4c, 0x6f, 0x67,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x35, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72,
+	0x6e, 0x61, 0x6c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c,
+	0x6f, 0x77, 0x2e, 0x76, 0x32, 0x2e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x41, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x4c, 0x6f, 0x67, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x00, 0x28, 0x01, 0x12, 0x79, 0x0a, 0x0e, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x4c, 0x6f, 0x67, 0x73, 0x12, 0x31, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e,
+	0x76, 0x32, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4c, 0x6f, 0x67,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x32, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72,
+	0x6e, 0x61, 0x6c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c,
+	0x6f, 0x77, 0x2e, 0x76, 0x32, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x4c, 0x6f, 0x67, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x40,
+	0x5a, 0x3e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x74, 0x69, 0x6e,
+	0x6b, 0x65, 0x72, 0x62, 0x65, 0x6c, 0x6c, 0x2f, 0x74, 0x69, 0x6e, 0x6b, 0x2f, 0x69, 0x6e, 0x74,
+	0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x77, 0x6f, 0x72, 0x6b,
+	0x66, 0x6c, 0x6f, 0x77, 0x2f, 0x76, 0x32, 0x3b, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77,
+	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -978,44 +2581,95 @@ func file_internal_proto_workflow_v2_workflow_proto_rawDescGZIP() []byte {
 }
 
 var (
-	file_internal_proto_workflow_v2_workflow_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
-	file_internal_proto_workflow_v2_workflow_proto_goTypes  = []interface{}{
-		(*GetWorkflowsRequest)(nil),                // 0: internal.proto.workflow.v2.GetWorkflowsRequest
-		(*GetWorkflowsResponse)(nil),               // 1: internal.proto.workflow.v2.GetWorkflowsResponse
-		(*PublishEventRequest)(nil),                // 2: internal.proto.workflow.v2.PublishEventRequest
-		(*PublishEventResponse)(nil),               // 3: internal.proto.workflow.v2.PublishEventResponse
-		(*Workflow)(nil),                           // 4: internal.proto.workflow.v2.Workflow
-		(*Event)(nil),                              // 5: internal.proto.workflow.v2.Event
-		(*GetWorkflowsResponse_StartWorkflow)(nil), // 6: internal.proto.workflow.v2.GetWorkflowsResponse.StartWorkflow
-		(*GetWorkflowsResponse_StopWorkflow)(nil),  // 7: internal.proto.workflow.v2.GetWorkflowsResponse.StopWorkflow
-		(*Workflow_Action)(nil),                    // 8: internal.proto.workflow.v2.Workflow.Action
-		nil,                                        // 9: internal.proto.workflow.v2.Workflow.Action.EnvEntry
-		(*Event_ActionStarted)(nil),                // 10: internal.proto.workflow.v2.Event.ActionStarted
-		(*Event_ActionSucceeded)(nil),              // 11: internal.proto.workflow.v2.Event.ActionSucceeded
-		(*Event_ActionFailed)(nil),                 // 12: internal.proto.workflow.v2.Event.ActionFailed
-		(*Event_WorkflowRejected)(nil),             // 13: internal.proto.workflow.v2.Event.WorkflowRejected
+	file_internal_proto_workflow_v2_workflow_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+	file_internal_proto_workflow_v2_workflow_proto_msgTypes  = make([]protoimpl.MessageInfo, 33)
+	file_internal_proto_workflow_v2_workflow_proto_goTypes   = []interface{}{
+		(ActionLog_Stream)(0),                      // 0: internal.proto.workflow.v2.ActionLog.Stream
+		(Workflow_Action_Type)(0),                  // 1: internal.proto.workflow.v2.Workflow.Action.Type
+		(*GetWorkflowsRequest)(nil),                // 2: internal.proto.workflow.v2.GetWorkflowsRequest
+		(*GetWorkflowsResponse)(nil),               // 3: internal.proto.workflow.v2.GetWorkflowsResponse
+		(*PublishEventRequest)(nil),                // 4: internal.proto.workflow.v2.PublishEventRequest
+		(*PublishEventResponse)(nil),               // 5: internal.proto.workflow.v2.PublishEventResponse
+		(*PublishActionLogsRequest)(nil),           // 6: internal.proto.workflow.v2.PublishActionLogsRequest
+		(*PublishActionLogsResponse)(nil),          // 7: internal.proto.workflow.v2.PublishActionLogsResponse
+		(*ListActionLogsRequest)(nil),              // 8: internal.proto.workflow.v2.ListActionLogsRequest
+		(*ListActionLogsResponse)(nil),             // 9: internal.proto.workflow.v2.ListActionLogsResponse
+		(*ActionLog)(nil),                          // 10: internal.proto.workflow.v2.ActionLog
+		(*Workflow)(nil),                           // 11: internal.proto.workflow.v2.Workflow
+		(*Event)(nil),                              // 12: internal.proto.workflow.v2.Event
+		(*GetWorkflowsResponse_StartWorkflow)(nil), // 13: internal.proto.workflow.v2.GetWorkflowsResponse.StartWorkflow
+		(*GetWorkflowsResponse_StopWorkflow)(nil),  // 14: internal.proto.workflow.v2.GetWorkflowsResponse.StopWorkflow
+		(*GetWorkflowsResponse_PauseWorkflow)(nil), // 15: internal.proto.workflow.v2.GetWorkflowsResponse.PauseWorkflow
+		nil,                             // 16: internal.proto.workflow.v2.Workflow.ResultsEntry
+		(*Workflow_ActionResult)(nil),   // 17: internal.proto.workflow.v2.Workflow.ActionResult
+		(*Workflow_Pause)(nil),          // 18: internal.proto.workflow.v2.Workflow.Pause
+		(*Workflow_Action)(nil),         // 19: internal.proto.workflow.v2.Workflow.Action
+		(*Workflow_Kexec)(nil),          // 20: internal.proto.workflow.v2.Workflow.Kexec
+		(*Workflow_ResourceLimits)(nil), // 21: internal.proto.workflow.v2.Workflow.ResourceLimits
+		(*Workflow_Capabilities)(nil),   // 22: internal.proto.workflow.v2.Workflow.Capabilities
+		(*Workflow_RetryPolicy)(nil),    // 23: internal.proto.workflow.v2.Workflow.RetryPolicy
+		nil,                             // 24: internal.proto.workflow.v2.Workflow.ActionResult.OutputsEntry
+		nil,                             // 25: internal.proto.workflow.v2.Workflow.Action.EnvEntry
+		(*Event_ActionStarted)(nil),     // 26: internal.proto.workflow.v2.Event.ActionStarted
+		(*Event_ActionSucceeded)(nil),   // 27: internal.proto.workflow.v2.Event.ActionSucceeded
+		(*Event_ActionFailed)(nil),      // 28: internal.proto.workflow.v2.Event.ActionFailed
+		(*Event_ActionSkipped)(nil),     // 29: internal.proto.workflow.v2.Event.ActionSkipped
+		(*Event_ActionPaused)(nil),      // 30: internal.proto.workflow.v2.Event.ActionPaused
+		(*Event_ImagePulled)(nil),       // 31: internal.proto.workflow.v2.Event.ImagePulled
+		(*Event_ImagePullFailed)(nil),   // 32: internal.proto.workflow.v2.Event.ImagePullFailed
+		(*Event_WorkflowRejected)(nil),  // 33: internal.proto.workflow.v2.Event.WorkflowRejected
+		nil,                             // 34: internal.proto.workflow.v2.Event.ActionSucceeded.OutputsEntry
+		(*timestamppb.Timestamp)(nil),   // 35: google.protobuf.Timestamp
+		(*structpb.Struct)(nil),         // 36: google.protobuf.Struct
 	}
 )
 var file_internal_proto_workflow_v2_workflow_proto_depIdxs = []int32{
-	6,  // 0: internal.proto.workflow.v2.GetWorkflowsResponse.start_workflow:type_name -> internal.proto.workflow.v2.GetWorkflowsResponse.StartWorkflow
-	7,  // 1: internal.proto.workflow.v2.GetWorkflowsResponse.stop_workflow:type_name -> internal.proto.workflow.v2.GetWorkflowsResponse.StopWorkflow
-	5,  // 2: internal.proto.workflow.v2.PublishEventRequest.event:type_name -> internal.proto.workflow.v2.Event
-	8,  // 3: internal.proto.workflow.v2.Workflow.actions:type_name -> internal.proto.workflow.v2.Workflow.Action
-	10, // 4: internal.proto.workflow.v2.Event.action_started:type_name -> internal.proto.workflow.v2.Event.ActionStarted
-	11, // 5: internal.proto.workflow.v2.Event.action_succeeded:type_name -> internal.proto.workflow.v2.Event.ActionSucceeded
-	12, // 6: internal.proto.workflow.v2.Event.action_failed:type_name -> internal.proto.workflow.v2.Event.ActionFailed
-	13, // 7: internal.proto.workflow.v2.Event.workflow_rejected:type_name -> internal.proto.workflow.v2.Event.WorkflowRejected
-	4,  // 8: internal.proto.workflow.v2.GetWorkflowsResponse.StartWorkflow.workflow:type_name -> internal.proto.workflow.v2.Workflow
-	9,  // 9: internal.proto.workflow.v2.Workflow.Action.env:type_name -> internal.proto.workflow.v2.Workflow.Action.EnvEntry
-	0,  // 10: internal.proto.workflow.v2.WorkflowService.GetWorkflows:input_type -> internal.proto.workflow.v2.GetWorkflowsRequest
-	2,  // 11: internal.proto.workflow.v2.WorkflowService.PublishEvent:input_type -> internal.proto.workflow.v2.PublishEventRequest
-	1,  // 12: internal.proto.workflow.v2.WorkflowService.GetWorkflows:output_type -> internal.proto.workflow.v2.GetWorkflowsResponse
-	3,  // 13: internal.proto.workflow.v2.WorkflowService.PublishEvent:output_type -> internal.proto.workflow.v2.PublishEventResponse
-	12, // [12:14] is the sub-list for method output_type
-	10, // [10:12] is the sub-list for method input_type
-	10, // [10:10] is the sub-list for extension type_name
-	10, // [10:10] is the sub-list for extension extendee
-	0,  // [0:10] is the sub-list for field type_name
+	13, // 0: internal.proto.workflow.v2.GetWorkflowsResponse.start_workflow:type_name -> internal.proto.workflow.v2.GetWorkflowsResponse.StartWorkflow
+	14, // 1: internal.proto.workflow.v2.GetWorkflowsResponse.stop_workflow:type_name -> internal.proto.workflow.v2.GetWorkflowsResponse.StopWorkflow
+	15, // 2: internal.proto.workflow.v2.GetWorkflowsResponse.pause_workflow:type_name -> internal.proto.workflow.v2.GetWorkflowsResponse.PauseWorkflow
+	12, // 3: internal.proto.workflow.v2.PublishEventRequest.event:type_name -> internal.proto.workflow.v2.Event
+	10, // 4: internal.proto.workflow.v2.PublishActionLogsRequest.log:type_name -> internal.proto.workflow.v2.ActionLog
+	10, // 5: internal.proto.workflow.v2.ListActionLogsResponse.logs:type_name -> internal.proto.workflow.v2.ActionLog
+	0,  // 6: internal.proto.workflow.v2.ActionLog.stream:type_name -> internal.proto.workflow.v2.ActionLog.Stream
+	35, // 7: internal.proto.workflow.v2.ActionLog.created_at:type_name -> google.protobuf.Timestamp
+	19, // 8: internal.proto.workflow.v2.Workflow.actions:type_name -> internal.proto.workflow.v2.Workflow.Action
+	36, // 9: internal.proto.workflow.v2.Workflow.condition_data:type_name -> google.protobuf.Struct
+	19, // 10: internal.proto.workflow.v2.Workflow.on_failure:type_name -> internal.proto.workflow.v2.Workflow.Action
+	19, // 11: internal.proto.workflow.v2.Workflow.finally:type_name -> internal.proto.workflow.v2.Workflow.Action
+	18, // 12: internal.proto.workflow.v2.Workflow.pause:type_name -> internal.proto.workflow.v2.Workflow.Pause
+	16, // 13: internal.proto.workflow.v2.Workflow.results:type_name -> internal.proto.workflow.v2.Workflow.ResultsEntry
+	26, // 14: internal.proto.workflow.v2.Event.action_started:type_name -> internal.proto.workflow.v2.Event.ActionStarted
+	27, // 15: internal.proto.workflow.v2.Event.action_succeeded:type_name -> internal.proto.workflow.v2.Event.ActionSucceeded
+	28, // 16: internal.proto.workflow.v2.Event.action_failed:type_name -> internal.proto.workflow.v2.Event.ActionFailed
+	33, // 17: internal.proto.workflow.v2.Event.workflow_rejected:type_name -> internal.proto.workflow.v2.Event.WorkflowRejected
+	29, // 18: internal.proto.workflow.v2.Event.action_skipped:type_name -> internal.proto.workflow.v2.Event.ActionSkipped
+	30, // 19: internal.proto.workflow.v2.Event.action_paused:type_name -> internal.proto.workflow.v2.Event.ActionPaused
+	31, // 20: internal.proto.workflow.v2.Event.image_pulled:type_name -> internal.proto.workflow.v2.Event.ImagePulled
+	32, // 21: internal.proto.workflow.v2.Event.image_pull_failed:type_name -> internal.proto.workflow.v2.Event.ImagePullFailed
+	11, // 22: internal.proto.workflow.v2.GetWorkflowsResponse.StartWorkflow.workflow:type_name -> internal.proto.workflow.v2.Workflow
+	18, // 23: internal.proto.workflow.v2.GetWorkflowsResponse.PauseWorkflow.pause:type_name -> internal.proto.workflow.v2.Workflow.Pause
+	17, // 24: internal.proto.workflow.v2.Workflow.ResultsEntry.value:type_name -> internal.proto.workflow.v2.Workflow.ActionResult
+	24, // 25: internal.proto.workflow.v2.Workflow.ActionResult.outputs:type_name -> internal.proto.workflow.v2.Workflow.ActionResult.OutputsEntry
+	25, // 26: internal.proto.workflow.v2.Workflow.Action.env:type_name -> internal.proto.workflow.v2.Workflow.Action.EnvEntry
+	23, // 27: internal.proto.workflow.v2.Workflow.Action.retry_policy:type_name -> internal.proto.workflow.v2.Workflow.RetryPolicy
+	22, // 28: internal.proto.workflow.v2.Workflow.Action.capabilities:type_name -> internal.proto.workflow.v2.Workflow.Capabilities
+	21, // 29: internal.proto.workflow.v2.Workflow.Action.resources:type_name -> internal.proto.workflow.v2.Workflow.ResourceLimits
+	1,  // 30: internal.proto.workflow.v2.Workflow.Action.type:type_name -> internal.proto.workflow.v2.Workflow.Action.Type
+	20, // 31: internal.proto.workflow.v2.Workflow.Action.kexec:type_name -> internal.proto.workflow.v2.Workflow.Kexec
+	34, // 32: internal.proto.workflow.v2.Event.ActionSucceeded.outputs:type_name -> internal.proto.workflow.v2.Event.ActionSucceeded.OutputsEntry
+	2,  // 33: internal.proto.workflow.v2.WorkflowService.GetWorkflows:input_type -> internal.proto.workflow.v2.GetWorkflowsRequest
+	4,  // 34: internal.proto.workflow.v2.WorkflowService.PublishEvent:input_type -> internal.proto.workflow.v2.PublishEventRequest
+	6,  // 35: internal.proto.workflow.v2.WorkflowService.PublishActionLogs:input_type -> internal.proto.workflow.v2.PublishActionLogsRequest
+	8,  // 36: internal.proto.workflow.v2.WorkflowService.ListActionLogs:input_type -> internal.proto.workflow.v2.ListActionLogsRequest
+	3,  // 37: internal.proto.workflow.v2.WorkflowService.GetWorkflows:output_type -> internal.proto.workflow.v2.GetWorkflowsResponse
+	5,  // 38: internal.proto.workflow.v2.WorkflowService.PublishEvent:output_type -> internal.proto.workflow.v2.PublishEventResponse
+	7,  // 39: internal.proto.workflow.v2.WorkflowService.PublishActionLogs:output_type -> internal.proto.workflow.v2.PublishActionLogsResponse
+	9,  // 40: internal.proto.workflow.v2.WorkflowService.ListActionLogs:output_type -> internal.proto.workflow.v2.ListActionLogsResponse
+	37, // [37:41] is the sub-list for method output_type
+	33, // [33:37] is the sub-list for method input_type
+	33, // [33:33] is the sub-list for extension type_name
+	33, // [33:33] is the sub-list for extension extendee
+	0,  // [0:33] is the sub-list for field type_name
 }
 
 func init() { file_internal_proto_workflow_v2_workflow_proto_init() }
@@ -1073,7 +2727,7 @@ func file_internal_proto_workflow_v2_workflow_proto_init() {
 			}
 		}
 		file_internal_proto_workflow_v2_workflow_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Workflow); i {
+			switch v := v.(*PublishActionLogsRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1085,7 +2739,7 @@ func file_internal_proto_workflow_v2_workflow_proto_init() {
 			}
 		}
 		file_internal_proto_workflow_v2_workflow_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Event); i {
+			switch v := v.(*PublishActionLogsResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1097,7 +2751,7 @@ func file_internal_proto_workflow_v2_workflow_proto_init() {
 			}
 		}
 		file_internal_proto_workflow_v2_workflow_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetWorkflowsResponse_StartWorkflow); i {
+			switch v := v.(*ListActionLogsRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1109,7 +2763,7 @@ func file_internal_proto_workflow_v2_workflow_proto_init() {
 			}
 		}
 		file_internal_proto_workflow_v2_workflow_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetWorkflowsResponse_StopWorkflow); i {
+			switch v := v.(*ListActionLogsResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1121,7 +2775,19 @@ func file_internal_proto_workflow_v2_workflow_proto_init() {
 			}
 		}
 		file_internal_proto_workflow_v2_workflow_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Workflow_Action); i {
+			switch v := v.(*ActionLog); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_proto_workflow_v2_workflow_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Workflow); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1133,7 +2799,7 @@ func file_internal_proto_workflow_v2_workflow_proto_init() {
 			}
 		}
 		file_internal_proto_workflow_v2_workflow_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Event_ActionStarted); i {
+			switch v := v.(*Event); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1145,7 +2811,7 @@ func file_internal_proto_workflow_v2_workflow_proto_init() {
 			}
 		}
 		file_internal_proto_workflow_v2_workflow_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Event_ActionSucceeded); i {
+			switch v := v.(*GetWorkflowsResponse_StartWorkflow); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1157,7 +2823,7 @@ func file_internal_proto_workflow_v2_workflow_proto_init() {
 			}
 		}
 		file_internal_proto_workflow_v2_workflow_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Event_ActionFailed); i {
+			switch v := v.(*GetWorkflowsResponse_StopWorkflow); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1169,6 +2835,186 @@ func file_internal_proto_workflow_v2_workflow_proto_init() {
 			}
 		}
 		file_internal_proto_workflow_v2_workflow_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetWorkflowsResponse_PauseWorkflow); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_proto_workflow_v2_workflow_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Workflow_ActionResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_proto_workflow_v2_workflow_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Workflow_Pause); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_proto_workflow_v2_workflow_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Workflow_Action); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_proto_workflow_v2_workflow_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Workflow_Kexec); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_proto_workflow_v2_workflow_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Workflow_ResourceLimits); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_proto_workflow_v2_workflow_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Workflow_Capabilities); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_proto_workflow_v2_workflow_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Workflow_RetryPolicy); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_proto_workflow_v2_workflow_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Event_ActionStarted); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_proto_workflow_v2_workflow_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Event_ActionSucceeded); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_proto_workflow_v2_workflow_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Event_ActionFailed); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_proto_workflow_v2_workflow_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Event_ActionSkipped); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_proto_workflow_v2_workflow_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Event_ActionPaused); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_proto_workflow_v2_workflow_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Event_ImagePulled); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_proto_workflow_v2_workflow_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Event_ImagePullFailed); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_proto_workflow_v2_workflow_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Event_WorkflowRejected); i {
 			case 0:
 				return &v.state
@@ -1184,27 +3030,33 @@ func file_internal_proto_workflow_v2_workflow_proto_init() {
 	file_internal_proto_workflow_v2_workflow_proto_msgTypes[1].OneofWrappers = []interface{}{
 		(*GetWorkflowsResponse_StartWorkflow_)(nil),
 		(*GetWorkflowsResponse_StopWorkflow_)(nil),
+		(*GetWorkflowsResponse_PauseWorkflow_)(nil),
 	}
-	file_internal_proto_workflow_v2_workflow_proto_msgTypes[5].OneofWrappers = []interface{}{
+	file_internal_proto_workflow_v2_workflow_proto_msgTypes[10].OneofWrappers = []interface{}{
 		(*Event_ActionStarted_)(nil),
 		(*Event_ActionSucceeded_)(nil),
 		(*Event_ActionFailed_)(nil),
 		(*Event_WorkflowRejected_)(nil),
+		(*Event_ActionSkipped_)(nil),
+		(*Event_ActionPaused_)(nil),
+		(*Event_ImagePulled_)(nil),
+		(*Event_ImagePullFailed_)(nil),
 	}
-	file_internal_proto_workflow_v2_workflow_proto_msgTypes[8].OneofWrappers = []interface{}{}
-	file_internal_proto_workflow_v2_workflow_proto_msgTypes[12].OneofWrappers = []interface{}{}
+	file_internal_proto_workflow_v2_workflow_proto_msgTypes[17].OneofWrappers = []interface{}{}
+	file_internal_proto_workflow_v2_workflow_proto_msgTypes[26].OneofWrappers = []interface{}{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_internal_proto_workflow_v2_workflow_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   14,
+			NumEnums:      2,
+			NumMessages:   33,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_internal_proto_workflow_v2_workflow_proto_goTypes,
 		DependencyIndexes: file_internal_proto_workflow_v2_workflow_proto_depIdxs,
+		EnumInfos:         file_internal_proto_workflow_v2_workflow_proto_enumTypes,
 		MessageInfos:      file_internal_proto_workflow_v2_workflow_proto_msgTypes,
 	}.Build()
 	File_internal_proto_workflow_v2_workflow_proto = out.File
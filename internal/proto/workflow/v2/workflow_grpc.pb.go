@@ -28,6 +28,14 @@ type WorkflowServiceClient interface {
 	GetWorkflows(ctx context.Context, in *GetWorkflowsRequest, opts ...grpc.CallOption) (WorkflowService_GetWorkflowsClient, error)
 	// PublishEvent publishes a workflow event.
 	PublishEvent(ctx context.Context, in *PublishEventRequest, opts ...grpc.CallOption) (*PublishEventResponse, error)
+	// PublishActionLogs ships the output of an action to the server as it is produced.
+	PublishActionLogs(ctx context.Context, opts ...grpc.CallOption) (WorkflowService_PublishActionLogsClient, error)
+	// ListActionLogs retrieves the output of an action retained by the server. Output is retained in
+	// memory by the server that received it, bounded per action and by the number of workflows, so
+	// it's lost when the server restarts and isn't shared between server replicas. Requests for
+	// output that isn't retained fail with NOT_FOUND. When clients are authenticated, only operators
+	// may retrieve output.
+	ListActionLogs(ctx context.Context, in *ListActionLogsRequest, opts ...grpc.CallOption) (*ListActionLogsResponse, error)
 }
 
 type workflowServiceClient struct {
@@ -79,6 +87,49 @@ func (c *workflowServiceClient) PublishEvent(ctx context.Context, in *PublishEve
 	return out, nil
 }
 
+func (c *workflowServiceClient) PublishActionLogs(ctx context.Context, opts ...grpc.CallOption) (WorkflowService_PublishActionLogsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &WorkflowService_ServiceDesc.Streams[1], "/internal.proto.workflow.v2.WorkflowService/PublishActionLogs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &workflowServicePublishActionLogsClient{stream}
+	return x, nil
+}
+
+type WorkflowService_PublishActionLogsClient interface {
+	Send(*PublishActionLogsRequest) error
+	CloseAndRecv() (*PublishActionLogsResponse, error)
+	grpc.ClientStream
+}
+
+type workflowServicePublishActionLogsClient struct {
+	grpc.ClientStream
+}
+
+func (x *workflowServicePublishActionLogsClient) Send(m *PublishActionLogsRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *workflowServicePublishActionLogsClient) CloseAndRecv() (*PublishActionLogsResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(PublishActionLogsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *workflowServiceClient) ListActionLogs(ctx context.Context, in *ListActionLogsRequest, opts ...grpc.CallOption) (*ListActionLogsResponse, error) {
+	out := new(ListActionLogsResponse)
+	err := c.cc.Invoke(ctx, "/internal.proto.workflow.v2.WorkflowService/ListActionLogs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // WorkflowServiceServer is the server API for WorkflowService service.
 // All implementations should embed UnimplementedWorkflowServiceServer
 // for forward compatibility
@@ -88,6 +139,14 @@ type WorkflowServiceServer interface {
 	GetWorkflows(*GetWorkflowsRequest, WorkflowService_GetWorkflowsServer) error
 	// PublishEvent publishes a workflow event.
 	PublishEvent(context.Context, *PublishEventRequest) (*PublishEventResponse, error)
+	// PublishActionLogs ships the output of an action to the server as it is produced.
+	PublishActionLogs(WorkflowService_PublishActionLogsServer) error
+	// ListActionLogs retrieves the output of an action retained by the server. Output is retained in
+	// memory by the server that received it, bounded per action and by the number of workflows, so
+	// it's lost when the server restarts and isn't shared between server replicas. Requests for
+	// output that isn't retained fail with NOT_FOUND. When clients are authenticated, only operators
+	// may retrieve output.
+	ListActionLogs(context.Context, *ListActionLogsRequest) (*ListActionLogsResponse, error)
 }
 
 // UnimplementedWorkflowServiceServer should be embedded to have forward compatible implementations.
@@ -101,6 +160,14 @@ func (UnimplementedWorkflowServiceServer) PublishEvent(context.Context, *Publish
 	return nil, status.Errorf(codes.Unimplemented, "method PublishEvent not implemented")
 }
 
+func (UnimplementedWorkflowServiceServer) PublishActionLogs(WorkflowService_PublishActionLogsServer) error {
+	return status.Errorf(codes.Unimplemented, "method PublishActionLogs not implemented")
+}
+
+func (UnimplementedWorkflowServiceServer) ListActionLogs(context.Context, *ListActionLogsRequest) (*ListActionLogsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListActionLogs not implemented")
+}
+
 // UnsafeWorkflowServiceServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to WorkflowServiceServer will
 // result in compilation errors.
@@ -151,6 +218,50 @@ func _WorkflowService_PublishEvent_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WorkflowService_PublishActionLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(WorkflowServiceServer).PublishActionLogs(&workflowServicePublishActionLogsServer{stream})
+}
+
+type WorkflowService_PublishActionLogsServer interface {
+	SendAndClose(*PublishActionLogsResponse) error
+	Recv() (*PublishActionLogsRequest, error)
+	grpc.ServerStream
+}
+
+type workflowServicePublishActionLogsServer struct {
+	grpc.ServerStream
+}
+
+func (x *workflowServicePublishActionLogsServer) SendAndClose(m *PublishActionLogsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *workflowServicePublishActionLogsServer) Recv() (*PublishActionLogsRequest, error) {
+	m := new(PublishActionLogsRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _WorkflowService_ListActionLogs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListActionLogsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServiceServer).ListActionLogs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/internal.proto.workflow.v2.WorkflowService/ListActionLogs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServiceServer).ListActionLogs(ctx, req.(*ListActionLogsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // WorkflowService_ServiceDesc is the grpc.ServiceDesc for WorkflowService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -162,6 +273,10 @@ var WorkflowService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "PublishEvent",
 			Handler:    _WorkflowService_PublishEvent_Handler,
 		},
+		{
+			MethodName: "ListActionLogs",
+			Handler:    _WorkflowService_ListActionLogs_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -169,6 +284,11 @@ var WorkflowService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _WorkflowService_GetWorkflows_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "PublishActionLogs",
+			Handler:       _WorkflowService_PublishActionLogs_Handler,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "internal/proto/workflow/v2/workflow.proto",
 }
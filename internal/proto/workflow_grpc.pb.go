@@ -26,6 +26,15 @@ type WorkflowServiceClient interface {
 	GetWorkflowContexts(ctx context.Context, in *WorkflowContextRequest, opts ...grpc.CallOption) (WorkflowService_GetWorkflowContextsClient, error)
 	GetWorkflowActions(ctx context.Context, in *WorkflowActionsRequest, opts ...grpc.CallOption) (*WorkflowActionList, error)
 	ReportActionStatus(ctx context.Context, in *WorkflowActionStatus, opts ...grpc.CallOption) (*Empty, error)
+	StreamActionLogs(ctx context.Context, opts ...grpc.CallOption) (WorkflowService_StreamActionLogsClient, error)
+	//
+	// GetActionLogs retrieves the output of an action retained by the server.
+	// Output is retained in memory by the server that received it, bounded per
+	// action and by the number of workflows, so it's lost when the server
+	// restarts and isn't shared between server replicas. Requests for output
+	// that isn't retained fail with NOT_FOUND. When clients are authenticated,
+	// only operators may retrieve output.
+	GetActionLogs(ctx context.Context, in *ActionLogsRequest, opts ...grpc.CallOption) (*ActionLogs, error)
 }
 
 type workflowServiceClient struct {
@@ -86,6 +95,49 @@ func (c *workflowServiceClient) ReportActionStatus(ctx context.Context, in *Work
 	return out, nil
 }
 
+func (c *workflowServiceClient) StreamActionLogs(ctx context.Context, opts ...grpc.CallOption) (WorkflowService_StreamActionLogsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &WorkflowService_ServiceDesc.Streams[1], "/proto.WorkflowService/StreamActionLogs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &workflowServiceStreamActionLogsClient{stream}
+	return x, nil
+}
+
+type WorkflowService_StreamActionLogsClient interface {
+	Send(*ActionLog) error
+	CloseAndRecv() (*Empty, error)
+	grpc.ClientStream
+}
+
+type workflowServiceStreamActionLogsClient struct {
+	grpc.ClientStream
+}
+
+func (x *workflowServiceStreamActionLogsClient) Send(m *ActionLog) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *workflowServiceStreamActionLogsClient) CloseAndRecv() (*Empty, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(Empty)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *workflowServiceClient) GetActionLogs(ctx context.Context, in *ActionLogsRequest, opts ...grpc.CallOption) (*ActionLogs, error) {
+	out := new(ActionLogs)
+	err := c.cc.Invoke(ctx, "/proto.WorkflowService/GetActionLogs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // WorkflowServiceServer is the server API for WorkflowService service.
 // All implementations should embed UnimplementedWorkflowServiceServer
 // for forward compatibility
@@ -93,6 +145,15 @@ type WorkflowServiceServer interface {
 	GetWorkflowContexts(*WorkflowContextRequest, WorkflowService_GetWorkflowContextsServer) error
 	GetWorkflowActions(context.Context, *WorkflowActionsRequest) (*WorkflowActionList, error)
 	ReportActionStatus(context.Context, *WorkflowActionStatus) (*Empty, error)
+	StreamActionLogs(WorkflowService_StreamActionLogsServer) error
+	//
+	// GetActionLogs retrieves the output of an action retained by the server.
+	// Output is retained in memory by the server that received it, bounded per
+	// action and by the number of workflows, so it's lost when the server
+	// restarts and isn't shared between server replicas. Requests for output
+	// that isn't retained fail with NOT_FOUND. When clients are authenticated,
+	// only operators may retrieve output.
+	GetActionLogs(context.Context, *ActionLogsRequest) (*ActionLogs, error)
 }
 
 // UnimplementedWorkflowServiceServer should be embedded to have forward compatible implementations.
@@ -110,6 +171,14 @@ func (UnimplementedWorkflowServiceServer) ReportActionStatus(context.Context, *W
 	return nil, status.Errorf(codes.Unimplemented, "method ReportActionStatus not implemented")
 }
 
+func (UnimplementedWorkflowServiceServer) StreamActionLogs(WorkflowService_StreamActionLogsServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamActionLogs not implemented")
+}
+
+func (UnimplementedWorkflowServiceServer) GetActionLogs(context.Context, *ActionLogsRequest) (*ActionLogs, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetActionLogs not implemented")
+}
+
 // UnsafeWorkflowServiceServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to WorkflowServiceServer will
 // result in compilation errors.
@@ -178,6 +247,50 @@ func _WorkflowService_ReportActionStatus_Handler(srv interface{}, ctx context.Co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WorkflowService_StreamActionLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(WorkflowServiceServer).StreamActionLogs(&workflowServiceStreamActionLogsServer{stream})
+}
+
+type WorkflowService_StreamActionLogsServer interface {
+	SendAndClose(*Empty) error
+	Recv() (*ActionLog, error)
+	grpc.ServerStream
+}
+
+type workflowServiceStreamActionLogsServer struct {
+	grpc.ServerStream
+}
+
+func (x *workflowServiceStreamActionLogsServer) SendAndClose(m *Empty) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *workflowServiceStreamActionLogsServer) Recv() (*ActionLog, error) {
+	m := new(ActionLog)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _WorkflowService_GetActionLogs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ActionLogsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServiceServer).GetActionLogs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.WorkflowService/GetActionLogs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServiceServer).GetActionLogs(ctx, req.(*ActionLogsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // WorkflowService_ServiceDesc is the grpc.ServiceDesc for WorkflowService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -193,6 +306,10 @@ var WorkflowService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ReportActionStatus",
 			Handler:    _WorkflowService_ReportActionStatus_Handler,
 		},
+		{
+			MethodName: "GetActionLogs",
+			Handler:    _WorkflowService_GetActionLogs_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -200,6 +317,11 @@ var WorkflowService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _WorkflowService_GetWorkflowContexts_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "StreamActionLogs",
+			Handler:       _WorkflowService_StreamActionLogs_Handler,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "internal/proto/workflow.proto",
 }
@@ -0,0 +1,282 @@
+// Package registry resolves the registries action images are pulled from and the credentials used
+// to pull them.
+package registry
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/containerd/containerd/remotes"
+	"github.com/containerd/containerd/remotes/docker"
+	"github.com/distribution/reference"
+)
+
+// dockerHubHost is the canonical host of Docker Hub. Docker Hub is known by several hosts that
+// are all normalized to dockerHubHost.
+const dockerHubHost = "docker.io"
+
+// Auth is the credentials used to authenticate with a registry.
+type Auth struct {
+	Username string
+	Password string
+
+	// IdentityToken is a token used in place of a password.
+	IdentityToken string
+}
+
+// Registry resolves images against registry mirrors and provides the credentials for
+// registries. The zero value has no mirrors or credentials.
+type Registry struct {
+	// auths are keyed by normalized registry host.
+	auths map[string]Auth
+
+	// mirrors maps normalized registry hosts to the host, and optional path prefix, of a mirror.
+	mirrors map[string]string
+}
+
+// Resolve rewrites image to be pulled from the mirror of its registry. When the registry has no
+// mirror image is returned unchanged.
+func (r *Registry) Resolve(image string) (string, error) {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return "", fmt.Errorf("parse image reference: %w", err)
+	}
+
+	mirror, ok := r.mirrors[reference.Domain(named)]
+	if !ok {
+		return image, nil
+	}
+
+	resolved := mirror + "/" + reference.Path(named)
+	if tagged, ok := named.(reference.Tagged); ok {
+		resolved += ":" + tagged.Tag()
+	}
+	if digested, ok := named.(reference.Digested); ok {
+		resolved += "@" + digested.Digest().String()
+	}
+
+	if _, err := reference.ParseNormalizedNamed(resolved); err != nil {
+		return "", fmt.Errorf("mirror image reference: %w", err)
+	}
+	return resolved, nil
+}
+
+// Auth retrieves the credentials for the registry at host.
+func (r *Registry) Auth(host string) (Auth, bool) {
+	auth, ok := r.auths[normalizeHost(host)]
+	return auth, ok
+}
+
+// Credentials retrieves the username and secret for the registry at host. The secret is the
+// identity token when one is configured. It returns empty credentials when the registry has
+// none.
+func (r *Registry) Credentials(host string) (string, string, error) {
+	auth, ok := r.Auth(host)
+	if !ok {
+		return "", "", nil
+	}
+	if auth.IdentityToken != "" {
+		return "", auth.IdentityToken, nil
+	}
+	return auth.Username, auth.Password, nil
+}
+
+// Resolver returns a resolver for retrieving images from registries using the configured
+// credentials. Consistent with Docker, registries on localhost are accessed over plain HTTP.
+func (r *Registry) Resolver() remotes.Resolver {
+	return docker.NewResolver(docker.ResolverOptions{
+		Hosts: docker.ConfigureDefaultRegistries(
+			docker.WithAuthorizer(docker.NewDockerAuthorizer(docker.WithAuthCreds(r.Credentials))),
+			docker.WithPlainHTTP(docker.MatchLocalhost),
+		),
+	})
+}
+
+// Pin resolves the tag of image to the digest it references, retrieving the digest from the
+// mirror of the image's registry if any. It returns image with the digest added. Images that
+// already specify a digest are returned unchanged.
+func (r *Registry) Pin(ctx context.Context, image string) (string, error) {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return "", fmt.Errorf("parse image reference: %w", err)
+	}
+	if _, ok := named.(reference.Digested); ok {
+		return image, nil
+	}
+
+	ref, err := r.Resolve(image)
+	if err != nil {
+		return "", err
+	}
+	resolved, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return "", fmt.Errorf("parse image reference: %w", err)
+	}
+
+	_, desc, err := r.Resolver().Resolve(ctx, reference.TagNameOnly(resolved).String())
+	if err != nil {
+		return "", fmt.Errorf("resolve image %v: %w", image, err)
+	}
+
+	pinned, err := reference.WithDigest(reference.TagNameOnly(named), desc.Digest)
+	if err != nil {
+		return "", fmt.Errorf("pin image %v: %w", image, err)
+	}
+	return reference.FamiliarString(pinned), nil
+}
+
+// Pinned reports whether image specifies a digest. The content of a pinned image can't change so
+// it never needs pulling again.
+func Pinned(image string) bool {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return false
+	}
+	_, ok := named.(reference.Digested)
+	return ok
+}
+
+// Host returns the normalized registry host of image.
+func Host(image string) (string, error) {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return "", fmt.Errorf("parse image reference: %w", err)
+	}
+	return reference.Domain(named), nil
+}
+
+// normalizeHost strips the scheme and path from a registry address and normalizes the hosts
+// of Docker Hub.
+func normalizeHost(address string) string {
+	host := strings.TrimPrefix(strings.TrimPrefix(address, "https://"), "http://")
+	host, _, _ = strings.Cut(host, "/")
+	switch host {
+	case "index.docker.io", "registry-1.docker.io":
+		return dockerHubHost
+	}
+	return host
+}
+
+// New creates a new Registry instance.
+func New(opts ...Option) *Registry {
+	o := &Registry{
+		auths:   map[string]Auth{},
+		mirrors: map[string]string{},
+	}
+
+	for _, fn := range opts {
+		fn(o)
+	}
+
+	return o
+}
+
+// Option defines optional configuration for a Registry instance.
+type Option func(*Registry)
+
+// WithAuths returns an option to configure the credentials of registries keyed by registry host.
+func WithAuths(auths map[string]Auth) Option {
+	return func(o *Registry) {
+		for host, auth := range auths {
+			o.auths[normalizeHost(host)] = auth
+		}
+	}
+}
+
+// WithMirror returns an option that configures images from the registry at host to be pulled
+// from mirror. The mirror is a registry host optionally followed by a path prefix, for example
+// 'harbor.example.com/dockerhub'.
+func WithMirror(host, mirror string) Option {
+	return func(o *Registry) {
+		o.mirrors[normalizeHost(host)] = strings.TrimSuffix(mirror, "/")
+	}
+}
+
+// ParseMirror parses a mirror of the form 'registry=mirror', for example
+// 'docker.io=harbor.example.com/dockerhub'.
+func ParseMirror(s string) (host, mirror string, err error) {
+	host, mirror, ok := strings.Cut(s, "=")
+	if !ok || host == "" || mirror == "" {
+		return "", "", fmt.Errorf("invalid mirror: %v: expected registry=mirror", s)
+	}
+	return host, mirror, nil
+}
+
+// Load creates a Registry from a Docker CLI configuration file containing credentials and mirrors
+// of the form 'registry=mirror'. The configuration file is optional.
+func Load(configPath string, mirrors []string) (*Registry, error) {
+	var opts []Option
+	if configPath != "" {
+		auths, err := LoadDockerConfig(configPath)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithAuths(auths))
+	}
+	for _, m := range mirrors {
+		host, mirror, err := ParseMirror(m)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithMirror(host, mirror))
+	}
+	return New(opts...), nil
+}
+
+// dockerConfig is the subset of the Docker CLI configuration file containing registry
+// credentials.
+type dockerConfig struct {
+	Auths map[string]dockerAuth `json:"auths"`
+}
+
+type dockerAuth struct {
+	Auth          string `json:"auth"`
+	Username      string `json:"username"`
+	Password      string `json:"password"`
+	IdentityToken string `json:"identitytoken"`
+}
+
+// LoadDockerConfig loads registry credentials from a Docker CLI configuration file, typically
+// ~/.docker/config.json. Credentials must be stored in the file; credential stores and helpers
+// aren't supported.
+func LoadDockerConfig(path string) (map[string]Auth, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read docker config: %w", err)
+	}
+
+	var cfg dockerConfig
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return nil, fmt.Errorf("decode docker config: %w", err)
+	}
+
+	auths := make(map[string]Auth, len(cfg.Auths))
+	for host, a := range cfg.Auths {
+		auth := Auth{
+			Username:      a.Username,
+			Password:      a.Password,
+			IdentityToken: a.IdentityToken,
+		}
+
+		// The auth property is the base64 encoded 'username:password' and takes precedence.
+		if a.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(a.Auth)
+			if err != nil {
+				return nil, fmt.Errorf("decode docker config: auth for %v: %w", host, err)
+			}
+			username, password, ok := strings.Cut(string(decoded), ":")
+			if !ok {
+				return nil, fmt.Errorf("decode docker config: auth for %v: expected username:password", host)
+			}
+			auth.Username, auth.Password = username, password
+		}
+
+		auths[host] = auth
+	}
+
+	return auths, nil
+}
@@ -0,0 +1,211 @@
+package registry_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/tinkerbell/tink/internal/registry"
+	"github.com/tinkerbell/tink/internal/registry/registrytest"
+)
+
+func TestResolve(t *testing.T) {
+	reg := registry.New(
+		registry.WithMirror("docker.io", "harbor.example.com/dockerhub"),
+		registry.WithMirror("https://quay.io/", "harbor.example.com"),
+	)
+
+	cases := map[string]string{
+		"alpine":                    "harbor.example.com/dockerhub/library/alpine",
+		"index.docker.io/alpine:3":  "harbor.example.com/dockerhub/library/alpine:3",
+		"quay.io/tinkerbell/action": "harbor.example.com/tinkerbell/action",
+		"ghcr.io/tinkerbell/action": "ghcr.io/tinkerbell/action",
+		"quay.io/tinkerbell/action@sha256:0123456789012345678901234567890123456789012345678901234567890123": "harbor.example.com/tinkerbell/action@sha256:0123456789012345678901234567890123456789012345678901234567890123",
+	}
+
+	for image, expect := range cases {
+		resolved, err := reg.Resolve(image)
+		if err != nil {
+			t.Fatalf("%v: %v", image, err)
+		}
+		if resolved != expect {
+			t.Fatalf("%v: expected %v; received %v", image, expect, resolved)
+		}
+	}
+
+	if _, err := reg.Resolve("Invalid"); err == nil {
+		t.Fatal("Expected error for invalid image")
+	}
+}
+
+func TestAuth(t *testing.T) {
+	reg := registry.New(registry.WithAuths(map[string]registry.Auth{
+		"https://index.docker.io/v1/": {Username: "user", Password: "pass"},
+		"harbor.example.com":          {IdentityToken: "token"},
+	}))
+
+	cases := []struct {
+		Host     string
+		Username string
+		Secret   string
+	}{
+		{Host: "docker.io", Username: "user", Secret: "pass"},
+		{Host: "registry-1.docker.io", Username: "user", Secret: "pass"},
+		{Host: "harbor.example.com", Secret: "token"},
+		{Host: "quay.io"},
+	}
+
+	for _, tc := range cases {
+		username, secret, err := reg.Credentials(tc.Host)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if username != tc.Username || secret != tc.Secret {
+			t.Fatalf("%v: expected %v/%v; received %v/%v", tc.Host, tc.Username, tc.Secret, username, secret)
+		}
+	}
+}
+
+func TestLoadDockerConfig(t *testing.T) {
+	cases := []struct {
+		Name   string
+		Config string
+		Expect map[string]registry.Auth
+		Error  bool
+	}{
+		{
+			Name: "Auths",
+			Config: `{
+				"auths": {
+					"https://index.docker.io/v1/": {"auth": "dXNlcjpwYXNzOndvcmQ="},
+					"harbor.example.com": {"username": "robot", "password": "secret"},
+					"quay.io": {"identitytoken": "token"}
+				},
+				"credsStore": "desktop"
+			}`,
+			Expect: map[string]registry.Auth{
+				"https://index.docker.io/v1/": {Username: "user", Password: "pass:word"},
+				"harbor.example.com":          {Username: "robot", Password: "secret"},
+				"quay.io":                     {IdentityToken: "token"},
+			},
+		},
+		{
+			Name:   "Empty",
+			Config: `{}`,
+			Expect: map[string]registry.Auth{},
+		},
+		{
+			Name:   "InvalidJSON",
+			Config: `{`,
+			Error:  true,
+		},
+		{
+			Name:   "InvalidAuth",
+			Config: `{"auths": {"quay.io": {"auth": "!"}}}`,
+			Error:  true,
+		},
+		{
+			Name:   "AuthMissingPassword",
+			Config: `{"auths": {"quay.io": {"auth": "dXNlcg=="}}}`,
+			Error:  true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.json")
+			if err := os.WriteFile(path, []byte(tc.Config), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			auths, err := registry.LoadDockerConfig(path)
+			if tc.Error != (err != nil) {
+				t.Fatalf("Expected error: %v; Received: %v", tc.Error, err)
+			}
+			if diff := cmp.Diff(tc.Expect, auths); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestParseMirror(t *testing.T) {
+	host, mirror, err := registry.ParseMirror("docker.io=harbor.example.com/dockerhub")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if host != "docker.io" || mirror != "harbor.example.com/dockerhub" {
+		t.Fatalf("Unexpected mirror: %v=%v", host, mirror)
+	}
+
+	for _, invalid := range []string{"docker.io", "=harbor.example.com", "docker.io="} {
+		if _, _, err := registry.ParseMirror(invalid); err == nil {
+			t.Fatalf("Expected error for %q", invalid)
+		}
+	}
+}
+
+func TestPin(t *testing.T) {
+	fake := registrytest.New(t)
+	dgst := fake.PutManifest("tinkerbell/action", "v1", ocispec.MediaTypeImageManifest, []byte(`{"schemaVersion":2}`))
+
+	reg := registry.New(registry.WithMirror("quay.io", fake.Host()))
+
+	cases := []struct {
+		Name   string
+		Image  string
+		Expect string
+		Error  bool
+	}{
+		{
+			Name:   "Tag",
+			Image:  fake.Host() + "/tinkerbell/action:v1",
+			Expect: fake.Host() + "/tinkerbell/action:v1@" + dgst.String(),
+		},
+		{
+			Name:   "Mirror",
+			Image:  "quay.io/tinkerbell/action:v1",
+			Expect: "quay.io/tinkerbell/action:v1@" + dgst.String(),
+		},
+		{
+			Name:   "Digest",
+			Image:  "quay.io/tinkerbell/action@" + dgst.String(),
+			Expect: "quay.io/tinkerbell/action@" + dgst.String(),
+		},
+		{
+			Name:  "MissingTag",
+			Image: fake.Host() + "/tinkerbell/action:v2",
+			Error: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			pinned, err := reg.Pin(context.Background(), tc.Image)
+			if tc.Error != (err != nil) {
+				t.Fatalf("Expected error: %v; Received: %v", tc.Error, err)
+			}
+			if pinned != tc.Expect {
+				t.Fatalf("Expected: %v; Received: %v", tc.Expect, pinned)
+			}
+		})
+	}
+}
+
+func TestPinned(t *testing.T) {
+	cases := map[string]bool{
+		"alpine":        false,
+		"alpine:latest": false,
+		"quay.io/tinkerbell/action@sha256:1e42bbe2508154c9126d48c2b8a75420c3544343bf86fd041fb7527e017a4b4a":    true,
+		"quay.io/tinkerbell/action:v1@sha256:1e42bbe2508154c9126d48c2b8a75420c3544343bf86fd041fb7527e017a4b4a": true,
+		"Invalid": false,
+	}
+	for image, expect := range cases {
+		if pinned := registry.Pinned(image); pinned != expect {
+			t.Fatalf("%v: Expected: %v; Received: %v", image, expect, pinned)
+		}
+	}
+}
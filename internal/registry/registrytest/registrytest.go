@@ -0,0 +1,112 @@
+// Package registrytest provides an in-memory OCI registry for testing.
+package registrytest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+)
+
+// pathRegex matches manifest and blob paths capturing the repository, kind and reference.
+var pathRegex = regexp.MustCompile(`^/v2/(.+)/(manifests|blobs)/([^/]+)$`)
+
+// Registry is an in-memory registry serving manifests and blobs over plain HTTP on localhost.
+type Registry struct {
+	server *httptest.Server
+
+	mtx       sync.Mutex
+	manifests map[string]content
+	blobs     map[string]content
+}
+
+type content struct {
+	mediaType string
+	data      []byte
+}
+
+// New starts a Registry that is closed when t completes.
+func New(t *testing.T) *Registry {
+	t.Helper()
+	r := &Registry{
+		manifests: map[string]content{},
+		blobs:     map[string]content{},
+	}
+	r.server = httptest.NewServer(http.HandlerFunc(r.serveHTTP))
+	t.Cleanup(r.server.Close)
+	return r
+}
+
+// Host returns the host and port of the registry for use in image references.
+func (r *Registry) Host() string {
+	return strings.TrimPrefix(r.server.URL, "http://")
+}
+
+// PutManifest stores a manifest of mediaType in repo, tagged with tag when not empty. It returns
+// the manifest digest.
+func (r *Registry) PutManifest(repo, tag, mediaType string, data []byte) digest.Digest {
+	dgst := digest.FromBytes(data)
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.manifests[repo+"@"+dgst.String()] = content{mediaType, data}
+	if tag != "" {
+		r.manifests[repo+":"+tag] = content{mediaType, data}
+	}
+	return dgst
+}
+
+// PutBlob stores a blob in repo and returns its digest.
+func (r *Registry) PutBlob(repo string, data []byte) digest.Digest {
+	dgst := digest.FromBytes(data)
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.blobs[repo+"@"+dgst.String()] = content{"application/octet-stream", data}
+	return dgst
+}
+
+func (r *Registry) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if req.URL.Path == "/v2/" {
+		return
+	}
+
+	m := pathRegex.FindStringSubmatch(req.URL.Path)
+	if m == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	repo, kind, ref := m[1], m[2], m[3]
+
+	key := repo + ":" + ref
+	if strings.Contains(ref, ":") {
+		key = repo + "@" + ref
+	}
+
+	r.mtx.Lock()
+	store := r.manifests
+	if kind == "blobs" {
+		store = r.blobs
+	}
+	c, ok := store[key]
+	r.mtx.Unlock()
+
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", c.mediaType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(c.data)))
+	w.Header().Set("Docker-Content-Digest", digest.FromBytes(c.data).String())
+	if req.Method == http.MethodGet {
+		_, _ = w.Write(c.data)
+	}
+}
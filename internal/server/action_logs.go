@@ -0,0 +1,171 @@
+package server
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultActionLogBytes is the default number of bytes of output retained per action.
+	DefaultActionLogBytes = 64 << 10
+
+	// DefaultActionLogWorkflows is the default number of workflows output is retained for.
+	DefaultActionLogWorkflows = 256
+
+	// failedActionLogBytes is the number of bytes of output, counted from the most recent, copied
+	// to the status of a failed action.
+	failedActionLogBytes = 4 << 10
+
+	errActionLogsNotRetained = "no output retained for action: output is retained in memory by the server " +
+		"that received it until the server restarts or the output is evicted"
+)
+
+// logEntry is a chunk of output produced by an action.
+type logEntry struct {
+	Stderr    bool
+	Data      []byte
+	CreatedAt time.Time
+}
+
+// actionLogStore retains the most recent output of actions in memory. Output is bounded per
+// action, discarding the oldest output first, and by the number of workflows, discarding the
+// workflow least recently written to first. Output isn't persisted so it's lost when the server
+// restarts and each server replica retains only the output shipped to it.
+//
+// A nil *actionLogStore retains nothing.
+type actionLogStore struct {
+	maxBytes     int
+	maxWorkflows int
+
+	mtx       sync.Mutex
+	workflows map[string]*workflowLogs
+	// recent orders workflow IDs from most to least recently written.
+	recent *list.List
+}
+
+type workflowLogs struct {
+	elem    *list.Element
+	actions map[string]*actionLogs
+}
+
+type actionLogs struct {
+	entries   []logEntry
+	size      int
+	truncated bool
+}
+
+func newActionLogStore(maxBytes, maxWorkflows int) *actionLogStore {
+	if maxBytes <= 0 {
+		maxBytes = DefaultActionLogBytes
+	}
+	if maxWorkflows <= 0 {
+		maxWorkflows = DefaultActionLogWorkflows
+	}
+	return &actionLogStore{
+		maxBytes:     maxBytes,
+		maxWorkflows: maxWorkflows,
+		workflows:    make(map[string]*workflowLogs),
+		recent:       list.New(),
+	}
+}
+
+// Append retains entry as output of the action identified by workflowID and actionKey.
+func (s *actionLogStore) Append(workflowID, actionKey string, entry logEntry) {
+	if s == nil || len(entry.Data) == 0 {
+		return
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	wf, ok := s.workflows[workflowID]
+	if !ok {
+		if len(s.workflows) >= s.maxWorkflows {
+			oldest := s.recent.Back()
+			if id, ok := s.recent.Remove(oldest).(string); ok {
+				delete(s.workflows, id)
+			}
+		}
+		wf = &workflowLogs{
+			elem:    s.recent.PushFront(workflowID),
+			actions: make(map[string]*actionLogs),
+		}
+		s.workflows[workflowID] = wf
+	}
+	s.recent.MoveToFront(wf.elem)
+
+	action, ok := wf.actions[actionKey]
+	if !ok {
+		action = &actionLogs{}
+		wf.actions[actionKey] = action
+	}
+
+	// Copy the data so callers may reuse their buffers.
+	data := entry.Data
+	if len(data) > s.maxBytes {
+		data = data[len(data)-s.maxBytes:]
+		action.truncated = true
+	}
+	entry.Data = append([]byte(nil), data...)
+
+	for len(action.entries) > 0 && action.size+len(entry.Data) > s.maxBytes {
+		action.size -= len(action.entries[0].Data)
+		action.entries = action.entries[1:]
+		action.truncated = true
+	}
+	action.entries = append(action.entries, entry)
+	action.size += len(entry.Data)
+}
+
+// Get retrieves output retained for the action identified by workflowID and actionKey. When
+// limit is greater than 0, at most limit bytes counted from the most recent output are returned.
+// Get reports whether older output has been discarded and whether any output is retained for the
+// action.
+func (s *actionLogStore) Get(workflowID, actionKey string, limit int) (entries []logEntry, truncated, ok bool) {
+	if s == nil {
+		return nil, false, false
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	wf, ok := s.workflows[workflowID]
+	if !ok {
+		return nil, false, false
+	}
+	action, ok := wf.actions[actionKey]
+	if !ok {
+		return nil, false, false
+	}
+
+	if limit <= 0 || limit >= action.size {
+		return append([]logEntry(nil), action.entries...), action.truncated, true
+	}
+
+	var size int
+	for i := len(action.entries) - 1; i >= 0 && size < limit; i-- {
+		entry := action.entries[i]
+		if remaining := limit - size; len(entry.Data) > remaining {
+			entry.Data = entry.Data[len(entry.Data)-remaining:]
+		}
+		entries = append([]logEntry{entry}, entries...)
+		size += len(entry.Data)
+	}
+	return entries, true, true
+}
+
+// Tail returns at most limit bytes of the most recent output of the action identified by
+// workflowID and actionKey as a string suitable for inclusion in a resource status.
+func (s *actionLogStore) Tail(workflowID, actionKey string, limit int) string {
+	entries, _, _ := s.Get(workflowID, actionKey, limit)
+
+	var b strings.Builder
+	for _, e := range entries {
+		b.Write(e.Data)
+	}
+
+	// Truncation may split multi-byte characters and actions may emit arbitrary bytes.
+	return strings.ToValidUTF8(b.String(), "")
+}
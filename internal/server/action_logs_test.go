@@ -0,0 +1,71 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestActionLogStoreRetention(t *testing.T) {
+	store := newActionLogStore(8, 2)
+
+	store.Append("wf", "action", logEntry{Data: []byte("12345")})
+	store.Append("wf", "action", logEntry{Data: []byte("678"), Stderr: true})
+
+	entries, truncated, _ := store.Get("wf", "action", 0)
+	if truncated {
+		t.Fatal("expected output to be complete")
+	}
+	if diff := cmp.Diff([]logEntry{{Data: []byte("12345")}, {Data: []byte("678"), Stderr: true}}, entries); diff != "" {
+		t.Fatal(diff)
+	}
+
+	// Exceeding the per action bound discards the oldest output.
+	store.Append("wf", "action", logEntry{Data: []byte("9")})
+	entries, truncated, _ = store.Get("wf", "action", 0)
+	if !truncated {
+		t.Fatal("expected output to be truncated")
+	}
+	if diff := cmp.Diff([]logEntry{{Data: []byte("678"), Stderr: true}, {Data: []byte("9")}}, entries); diff != "" {
+		t.Fatal(diff)
+	}
+
+	// Limits are counted from the most recent output.
+	if got := store.Tail("wf", "action", 3); got != "789" {
+		t.Fatalf("expected tail 789, got %q", got)
+	}
+
+	// Output larger than the bound is truncated to the most recent bytes.
+	store.Append("wf", "large", logEntry{Data: []byte("0123456789")})
+	if got := store.Tail("wf", "large", 0); got != "23456789" {
+		t.Fatalf("expected tail 23456789, got %q", got)
+	}
+}
+
+func TestActionLogStoreEviction(t *testing.T) {
+	store := newActionLogStore(8, 2)
+
+	store.Append("wf1", "action", logEntry{Data: []byte("1")})
+	store.Append("wf2", "action", logEntry{Data: []byte("2")})
+	// Writing to wf1 makes wf2 the least recently written.
+	store.Append("wf1", "action", logEntry{Data: []byte("1")})
+	store.Append("wf3", "action", logEntry{Data: []byte("3")})
+
+	if _, _, ok := store.Get("wf2", "action", 0); ok {
+		t.Fatal("expected wf2 to be evicted")
+	}
+	if got := store.Tail("wf1", "action", 0); got != "11" {
+		t.Fatalf("expected wf1 output 11, got %q", got)
+	}
+	if got := store.Tail("wf3", "action", 0); got != "3" {
+		t.Fatalf("expected wf3 output 3, got %q", got)
+	}
+}
+
+func TestActionLogStoreNil(t *testing.T) {
+	var store *actionLogStore
+	store.Append("wf", "action", logEntry{Data: []byte("1")})
+	if got := store.Tail("wf", "action", 0); got != "" {
+		t.Fatalf("expected no output, got %q", got)
+	}
+}
@@ -0,0 +1,109 @@
+package server
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/tinkerbell/tink/api/v1alpha2"
+	"github.com/tinkerbell/tink/internal/proto"
+	workflowproto "github.com/tinkerbell/tink/internal/proto/workflow/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const errPermissionDenied = "permission denied"
+
+// Authorize authorizes req made by the worker, agent or operator identified by identity. Workers
+// may only read and report on Workflows whose current task is assigned to them. Agents may only
+// read and report on Workflows for the Hardware they run on. Action logs may only be read by
+// operators.
+func (s *KubernetesBackedServer) Authorize(ctx context.Context, identity string, req any) error {
+	switch r := req.(type) {
+	case *proto.WorkflowContextRequest:
+		return authorizeWorker(identity, r.GetWorkerId())
+	case *proto.WorkflowActionsRequest:
+		return s.authorizeWorkflow(ctx, identity, r.GetWorkflowId())
+	case *proto.WorkflowActionStatus:
+		if err := authorizeWorker(identity, r.GetWorkerId()); err != nil {
+			return err
+		}
+		return s.authorizeWorkflow(ctx, identity, r.GetWorkflowId())
+	case *proto.ActionLog:
+		return s.authorizeWorkflow(ctx, identity, r.GetWorkflowId())
+	case *proto.ActionLogsRequest:
+		return s.authorizeOperator(identity)
+
+	case *workflowproto.GetWorkflowsRequest:
+		return authorizeWorker(identity, r.GetAgentId())
+	case *workflowproto.PublishEventRequest:
+		return s.authorizeWorkflowV2(ctx, identity, r.GetEvent().GetWorkflowId())
+	case *workflowproto.PublishActionLogsRequest:
+		return s.authorizeWorkflowV2(ctx, identity, r.GetLog().GetWorkflowId())
+	case *workflowproto.ListActionLogsRequest:
+		return s.authorizeOperator(identity)
+	}
+
+	return status.Errorf(codes.PermissionDenied, errPermissionDenied)
+}
+
+// authorizeWorker authorizes identity to act as workerID.
+func authorizeWorker(identity, workerID string) error {
+	if !strings.EqualFold(identity, workerID) {
+		return status.Errorf(codes.PermissionDenied, errPermissionDenied)
+	}
+	return nil
+}
+
+// authorizeOperator authorizes identity to make requests reserved for operators.
+func (s *KubernetesBackedServer) authorizeOperator(identity string) error {
+	if !slices.ContainsFunc(s.operators, func(o string) bool { return strings.EqualFold(identity, o) }) {
+		return status.Errorf(codes.PermissionDenied, errPermissionDenied)
+	}
+	return nil
+}
+
+// authorizeWorkflow authorizes identity to read and report on the v1alpha1 Workflow identified by
+// workflowID. Workflows that don't exist are indistinguishable from those identity may not access.
+// When Workflows are served from more than one namespace, the Workflow must also be in the
+// namespace identity belongs to.
+func (s *KubernetesBackedServer) authorizeWorkflow(ctx context.Context, identity, workflowID string) error {
+	wf, err := s.getWorkflow(ctx, s.ClientFunc(), workflowID)
+	if err != nil {
+		return status.Errorf(codes.PermissionDenied, errPermissionDenied)
+	}
+	if err := authorizeWorker(identity, wf.GetCurrentWorker()); err != nil {
+		return err
+	}
+
+	if s.resolveWorkerNamespace {
+		namespace, err := s.workerNamespace(ctx, identity)
+		if err != nil {
+			return err
+		}
+		if namespace != "" && namespace != wf.Namespace {
+			return status.Errorf(codes.PermissionDenied, errPermissionDenied)
+		}
+	}
+	return nil
+}
+
+// authorizeWorkflowV2 authorizes identity to read and report on the v1alpha2 Workflow identified
+// by workflowID. Agents are identified by a MAC address of the Hardware they run on.
+func (s *KubernetesBackedServer) authorizeWorkflowV2(ctx context.Context, identity, workflowID string) error {
+	hw, err := s.getHardwareForAgent(ctx, identity)
+	if err != nil {
+		return status.Errorf(codes.PermissionDenied, errPermissionDenied)
+	}
+
+	namespace, name, _ := strings.Cut(workflowID, "/")
+	var wf v1alpha2.Workflow
+	if err := s.ClientFunc().Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &wf); err != nil {
+		return status.Errorf(codes.PermissionDenied, errPermissionDenied)
+	}
+	if wf.Namespace != hw.Namespace || wf.Spec.HardwareRef.Name != hw.Name {
+		return status.Errorf(codes.PermissionDenied, errPermissionDenied)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/zapr"
+	"github.com/tinkerbell/tink/api/v1alpha1"
+	"github.com/tinkerbell/tink/api/v1alpha2"
+	"github.com/tinkerbell/tink/internal/proto"
+	workflowproto "github.com/tinkerbell/tink/internal/proto/workflow/v2"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestAuthorize(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+
+	wf := &v1alpha1.Workflow{
+		ObjectMeta: metav1.ObjectMeta{Name: "workflow", Namespace: "default"},
+		Status: v1alpha1.WorkflowStatus{
+			State: v1alpha1.WorkflowStateRunning,
+			Tasks: []v1alpha1.Task{
+				{
+					Name:       "provision",
+					WorkerAddr: "00:00:00:00:00:01",
+					Actions:    []v1alpha1.Action{{Name: "stream", Status: v1alpha1.WorkflowStateSuccess}},
+				},
+				{
+					Name:       "configure",
+					WorkerAddr: "00:00:00:00:00:02",
+					Actions:    []v1alpha1.Action{{Name: "write", Status: v1alpha1.WorkflowStatePending}},
+				},
+			},
+		},
+	}
+	clnt := fake.NewClientBuilder().WithScheme(scheme).WithObjects(wf).Build()
+	v1 := &KubernetesBackedServer{
+		logger:     zapr.NewLogger(zap.Must(zap.NewDevelopment())),
+		ClientFunc: func() client.Client { return clnt },
+		nowFunc:    TestTime.Now,
+		operators:  []string{"operator"},
+	}
+
+	v2 := newWorkflowV2Server(
+		&v1alpha2.Hardware{
+			ObjectMeta: metav1.ObjectMeta{Name: "hardware", Namespace: "default"},
+			Spec: v1alpha2.HardwareSpec{
+				NetworkInterfaces: v1alpha2.NetworkInterfaces{"00:00:00:00:00:01": {}},
+			},
+		},
+		newWorkflowV2(v1alpha2.WorkflowStateRunning, v1alpha2.ActionStateRunning),
+	)
+	v2.operators = []string{"operator"}
+
+	event := func(workflowID string) *workflowproto.PublishEventRequest {
+		return &workflowproto.PublishEventRequest{Event: &workflowproto.Event{WorkflowId: workflowID}}
+	}
+
+	cases := []struct {
+		name     string
+		server   *KubernetesBackedServer
+		identity string
+		req      any
+		wantCode codes.Code
+	}{
+		{"OwnContexts", v1, "00:00:00:00:00:02", &proto.WorkflowContextRequest{WorkerId: "00:00:00:00:00:02"}, codes.OK},
+		{"OtherContexts", v1, "00:00:00:00:00:02", &proto.WorkflowContextRequest{WorkerId: "00:00:00:00:00:01"}, codes.PermissionDenied},
+		{"CurrentTaskActions", v1, "00:00:00:00:00:02", &proto.WorkflowActionsRequest{WorkflowId: "default/workflow"}, codes.OK},
+		{"FinishedTaskActions", v1, "00:00:00:00:00:01", &proto.WorkflowActionsRequest{WorkflowId: "default/workflow"}, codes.PermissionDenied},
+		{"UnknownWorkflow", v1, "00:00:00:00:00:02", &proto.WorkflowActionsRequest{WorkflowId: "default/unknown"}, codes.PermissionDenied},
+		{
+			"ReportCurrentTask", v1, "00:00:00:00:00:02",
+			&proto.WorkflowActionStatus{WorkflowId: "default/workflow", WorkerId: "00:00:00:00:00:02"},
+			codes.OK,
+		},
+		{
+			"ReportAsOtherWorker", v1, "00:00:00:00:00:01",
+			&proto.WorkflowActionStatus{WorkflowId: "default/workflow", WorkerId: "00:00:00:00:00:02"},
+			codes.PermissionDenied,
+		},
+		{
+			"ReportFinishedTask", v1, "00:00:00:00:00:01",
+			&proto.WorkflowActionStatus{WorkflowId: "default/workflow", WorkerId: "00:00:00:00:00:01"},
+			codes.PermissionDenied,
+		},
+		{"ShipLogs", v1, "00:00:00:00:00:02", &proto.ActionLog{WorkflowId: "default/workflow"}, codes.OK},
+		{"ShipOtherLogs", v1, "00:00:00:00:00:01", &proto.ActionLog{WorkflowId: "default/workflow"}, codes.PermissionDenied},
+		{"UnknownRequest", v1, "00:00:00:00:00:02", &proto.Empty{}, codes.PermissionDenied},
+		{"OperatorReadsLogs", v1, "operator", &proto.ActionLogsRequest{WorkflowId: "default/workflow"}, codes.OK},
+		{"OperatorReadsUnknownWorkflowLogs", v1, "operator", &proto.ActionLogsRequest{WorkflowId: "default/unknown"}, codes.OK},
+		{"WorkerReadsLogs", v1, "00:00:00:00:00:02", &proto.ActionLogsRequest{WorkflowId: "default/workflow"}, codes.PermissionDenied},
+		{"OperatorActions", v1, "operator", &proto.WorkflowActionsRequest{WorkflowId: "default/workflow"}, codes.PermissionDenied},
+
+		{"OwnWorkflows", v2, "00:00:00:00:00:01", &workflowproto.GetWorkflowsRequest{AgentId: "00:00:00:00:00:01"}, codes.OK},
+		{"OtherWorkflows", v2, "00:00:00:00:00:01", &workflowproto.GetWorkflowsRequest{AgentId: "00:00:00:00:00:02"}, codes.PermissionDenied},
+		{"OwnHardwareEvent", v2, "00:00:00:00:00:01", event("default/workflow"), codes.OK},
+		{"OtherHardwareEvent", v2, "00:00:00:00:00:02", event("default/workflow"), codes.PermissionDenied},
+		{"UnknownWorkflowEvent", v2, "00:00:00:00:00:01", event("default/unknown"), codes.PermissionDenied},
+		{"OperatorListsLogs", v2, "operator", &workflowproto.ListActionLogsRequest{WorkflowId: "default/workflow"}, codes.OK},
+		{"AgentListsLogs", v2, "00:00:00:00:00:01", &workflowproto.ListActionLogsRequest{WorkflowId: "default/workflow"}, codes.PermissionDenied},
+		{"OperatorEvent", v2, "operator", event("default/workflow"), codes.PermissionDenied},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.server.Authorize(context.Background(), tc.identity, tc.req)
+			if got := status.Code(err); got != tc.wantCode {
+				t.Fatalf("Unexpected code: got %v, want %v (%v)", got, tc.wantCode, err)
+			}
+		})
+	}
+}
@@ -1,7 +1,11 @@
 package server
 
 import (
+	"strings"
+
 	"github.com/tinkerbell/tink/api/v1alpha1"
+	"github.com/tinkerbell/tink/api/v1alpha2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -9,7 +13,9 @@ import (
 const workflowByNonTerminalState = ".status.state.nonTerminalWorker"
 
 // workflowByNonTerminalStateFunc inspects obj - which must be a Workflow - for a Pending or
-// Running state. If in either Pending or Running it returns a list of worker addresses.
+// Running state. If in either Pending or Running it returns a list of worker addresses. Canceled
+// Workflows are also indexed until they reach a terminal state so workers executing them observe
+// the cancellation.
 func workflowByNonTerminalStateFunc(obj client.Object) []string {
 	wf, ok := obj.(*v1alpha1.Workflow)
 	if !ok {
@@ -17,7 +23,9 @@ func workflowByNonTerminalStateFunc(obj client.Object) []string {
 	}
 
 	resp := []string{}
-	if !(wf.Status.State == v1alpha1.WorkflowStateRunning || wf.Status.State == v1alpha1.WorkflowStatePending) {
+	canceling := wf.Status.HasCondition(v1alpha1.WorkflowCanceled, metav1.ConditionTrue) &&
+		!isTerminalWorkflowStateV1(wf.Status.State)
+	if !(wf.Status.State == v1alpha1.WorkflowStateRunning || wf.Status.State == v1alpha1.WorkflowStatePending || canceling) {
 		return resp
 	}
 	for _, task := range wf.Status.Tasks {
@@ -28,3 +36,63 @@ func workflowByNonTerminalStateFunc(obj client.Object) []string {
 
 	return resp
 }
+
+// isTerminalWorkflowStateV1 determines if a v1alpha1 Workflow in state has finished.
+func isTerminalWorkflowStateV1(state v1alpha1.WorkflowState) bool {
+	switch state { //nolint:exhaustive // Only terminal states are of interest.
+	case v1alpha1.WorkflowStateSuccess, v1alpha1.WorkflowStateFailed, v1alpha1.WorkflowStateTimeout,
+		v1alpha1.WorkflowStateCanceled:
+		return true
+	}
+	return false
+}
+
+// hardwareByMACAddr is the index name for retrieving v1alpha2 Hardware by MAC address.
+const hardwareByMACAddr = ".spec.networkInterfaces.mac"
+
+// hardwareByMACAddrFunc inspects obj - which must be a v1alpha2 Hardware - and returns the MAC
+// addresses of its network interfaces.
+func hardwareByMACAddrFunc(obj client.Object) []string {
+	hw, ok := obj.(*v1alpha2.Hardware)
+	if !ok {
+		return nil
+	}
+	return hw.GetMACs()
+}
+
+// workflowByHardwareRef is the index name for retrieving v1alpha2 Workflows by the name of the
+// Hardware they execute on.
+const workflowByHardwareRef = ".spec.hardwareRef.name"
+
+// workflowByHardwareRefFunc inspects obj - which must be a v1alpha2 Workflow - and returns the
+// name of the Hardware it references.
+func workflowByHardwareRefFunc(obj client.Object) []string {
+	wf, ok := obj.(*v1alpha2.Workflow)
+	if !ok {
+		return nil
+	}
+	if wf.Spec.HardwareRef.Name == "" {
+		return nil
+	}
+	return []string{wf.Spec.HardwareRef.Name}
+}
+
+// hardwareV1ByMACAddr is the index name for retrieving v1alpha1 Hardware by MAC address.
+const hardwareV1ByMACAddr = ".spec.interfaces.dhcp.mac"
+
+// hardwareV1ByMACAddrFunc inspects obj - which must be a v1alpha1 Hardware - and returns the
+// lowercase MAC addresses of its interfaces.
+func hardwareV1ByMACAddrFunc(obj client.Object) []string {
+	hw, ok := obj.(*v1alpha1.Hardware)
+	if !ok {
+		return nil
+	}
+
+	var macs []string
+	for _, iface := range hw.Spec.Interfaces {
+		if iface.DHCP != nil && iface.DHCP.MAC != "" {
+			macs = append(macs, strings.ToLower(iface.DHCP.MAC))
+		}
+	}
+	return macs
+}
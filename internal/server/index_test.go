@@ -5,7 +5,7 @@ import (
 	"testing"
 
 	"github.com/tinkerbell/tink/api/v1alpha1"
-
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -75,6 +75,46 @@ func TestWorkflowIndexFuncs(t *testing.T) {
 			},
 			[]string{},
 		},
+		{
+			"canceled workflow",
+			&v1alpha1.Workflow{
+				Status: v1alpha1.WorkflowStatus{
+					State: v1alpha1.WorkflowStatePost,
+					Tasks: []v1alpha1.Task{
+						{
+							WorkerAddr: "worker1",
+						},
+					},
+					Conditions: []v1alpha1.WorkflowCondition{
+						{
+							Type:   v1alpha1.WorkflowCanceled,
+							Status: metav1.ConditionTrue,
+						},
+					},
+				},
+			},
+			[]string{"worker1"},
+		},
+		{
+			"canceled workflow in terminal state",
+			&v1alpha1.Workflow{
+				Status: v1alpha1.WorkflowStatus{
+					State: v1alpha1.WorkflowStateCanceled,
+					Tasks: []v1alpha1.Task{
+						{
+							WorkerAddr: "worker1",
+						},
+					},
+					Conditions: []v1alpha1.WorkflowCondition{
+						{
+							Type:   v1alpha1.WorkflowCanceled,
+							Status: metav1.ConditionTrue,
+						},
+					},
+				},
+			},
+			[]string{},
+		},
 	}
 
 	for _, tc := range cases {
@@ -8,8 +8,10 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/go-logr/zapr"
 	"github.com/tinkerbell/tink/api/v1alpha1"
+	"github.com/tinkerbell/tink/api/v1alpha2"
 	"github.com/tinkerbell/tink/internal/deprecated/controller"
 	"github.com/tinkerbell/tink/internal/proto"
+	workflowproto "github.com/tinkerbell/tink/internal/proto/workflow/v2"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"k8s.io/client-go/rest"
@@ -24,8 +26,55 @@ import (
 // +kubebuilder:rbac:groups=tinkerbell.org,resources=templates;templates/status,verbs=get;list;watch
 // +kubebuilder:rbac:groups=tinkerbell.org,resources=workflows;workflows/status,verbs=get;list;watch;update;patch
 
+// Option configures optional behavior of a KubernetesBackedServer.
+type Option func(*options)
+
+type options struct {
+	namespaces         []string
+	workflowV2         bool
+	actionLogBytes     int
+	actionLogWorkflows int
+	operators          []string
+}
+
+// WithNamespaces serves Workflows from namespaces in addition to the namespace the server is
+// created with. When Workflows are served from more than one namespace, including all namespaces,
+// workers are only served Workflows from the namespace of the Hardware matching their ID.
+func WithNamespaces(namespaces ...string) Option {
+	return func(o *options) {
+		o.namespaces = append(o.namespaces, namespaces...)
+	}
+}
+
+// WithWorkflowV2 enables the v2 WorkflowService used by tink-agent. The service is backed by
+// v1alpha2 Workflow resources so the v1alpha2 API version must be served by the cluster.
+func WithWorkflowV2(enabled bool) Option {
+	return func(o *options) {
+		o.workflowV2 = enabled
+	}
+}
+
+// WithActionLogRetention bounds the action output retained by the server. Up to bytes of the
+// most recent output is retained per action for up to workflows Workflows. Values less than or
+// equal to 0 use DefaultActionLogBytes and DefaultActionLogWorkflows respectively.
+func WithActionLogRetention(bytes, workflows int) Option {
+	return func(o *options) {
+		o.actionLogBytes = bytes
+		o.actionLogWorkflows = workflows
+	}
+}
+
+// WithOperators authorizes the clients identified by identities to read the action logs of any
+// Workflow when requests are authorized. Action logs are read by operators rather than workers or
+// agents.
+func WithOperators(identities ...string) Option {
+	return func(o *options) {
+		o.operators = append(o.operators, identities...)
+	}
+}
+
 // NewKubeBackedServer returns a server that implements the Workflow server interface for a given kubeconfig.
-func NewKubeBackedServer(logger logr.Logger, kubeconfig, apiserver, namespace string) (*KubernetesBackedServer, error) {
+func NewKubeBackedServer(logger logr.Logger, kubeconfig, apiserver, namespace string, opts ...Option) (*KubernetesBackedServer, error) {
 	ccfg := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
 		&clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfig},
 		&clientcmd.ConfigOverrides{
@@ -43,19 +92,37 @@ func NewKubeBackedServer(logger logr.Logger, kubeconfig, apiserver, namespace st
 		return nil, err
 	}
 
-	return NewKubeBackedServerFromREST(logger, cfg, namespace)
+	return NewKubeBackedServerFromREST(logger, cfg, namespace, opts...)
 }
 
 // NewKubeBackedServerFromREST returns a server that implements the Workflow
-// server interface with the given Kubernetes rest client and namespace.
-func NewKubeBackedServerFromREST(logger logr.Logger, config *rest.Config, namespace string) (*KubernetesBackedServer, error) {
+// server interface with the given Kubernetes rest client and namespace. Workflows are served from
+// all namespaces when namespace is empty and no namespaces are configured with WithNamespaces.
+func NewKubeBackedServerFromREST(logger logr.Logger, config *rest.Config, namespace string, opts ...Option) (*KubernetesBackedServer, error) {
+	var o options
+	for _, fn := range opts {
+		fn(&o)
+	}
+
+	scheme := controller.DefaultScheme()
+	if o.workflowV2 {
+		if err := v1alpha2.AddToScheme(scheme); err != nil {
+			return nil, fmt.Errorf("init scheme: %w", err)
+		}
+	}
+
+	namespaces := map[string]cache.Config{}
+	for _, ns := range append([]string{namespace}, o.namespaces...) {
+		if ns != "" {
+			namespaces[ns] = cache.Config{}
+		}
+	}
+
 	clstr, err := cluster.New(config, func(opts *cluster.Options) {
-		opts.Scheme = controller.DefaultScheme()
+		opts.Scheme = scheme
 		opts.Logger = zapr.NewLogger(zap.NewNop())
-		if namespace != "" {
-			opts.Cache.DefaultNamespaces = map[string]cache.Config{
-				namespace: {},
-			}
+		if len(namespaces) > 0 {
+			opts.Cache.DefaultNamespaces = namespaces
 		}
 	})
 	if err != nil {
@@ -72,6 +139,32 @@ func NewKubeBackedServerFromREST(logger logr.Logger, config *rest.Config, namesp
 		return nil, fmt.Errorf("setup %s index: %w", workflowByNonTerminalState, err)
 	}
 
+	srv := &KubernetesBackedServer{
+		logger:     logger,
+		ClientFunc: clstr.GetClient,
+		apiReader:  clstr.GetAPIReader(),
+		nowFunc:    time.Now,
+		actionLogs: newActionLogStore(o.actionLogBytes, o.actionLogWorkflows),
+		operators:  o.operators,
+	}
+
+	if err := srv.setupWorkflowContexts(clstr); err != nil {
+		return nil, err
+	}
+
+	// Workers are identified by MAC address which isn't unique across namespaces.
+	if len(namespaces) != 1 {
+		if err := srv.setupWorkerNamespaces(clstr); err != nil {
+			return nil, err
+		}
+	}
+
+	if o.workflowV2 {
+		if err := srv.setupWorkflowV2(clstr); err != nil {
+			return nil, err
+		}
+	}
+
 	go func() {
 		err := clstr.Start(context.Background())
 		if err != nil {
@@ -79,11 +172,7 @@ func NewKubeBackedServerFromREST(logger logr.Logger, config *rest.Config, namesp
 		}
 	}()
 
-	return &KubernetesBackedServer{
-		logger:     logger,
-		ClientFunc: clstr.GetClient,
-		nowFunc:    time.Now,
-	}, nil
+	return srv, nil
 }
 
 // KubernetesBackedServer is a server that implements a workflow API.
@@ -91,10 +180,35 @@ type KubernetesBackedServer struct {
 	logger     logr.Logger
 	ClientFunc func() client.Client
 
+	// apiReader reads directly from the API server, bypassing the cache ClientFunc reads from.
+	// When nil, reads that must observe the latest state use ClientFunc.
+	apiReader client.Reader
+
 	nowFunc func() time.Time
+
+	// resolveWorkerNamespace is set when Workflows are served from more than one namespace. Workers
+	// are then only served Workflows from the namespace they belong to.
+	resolveWorkerNamespace bool
+
+	// workflowContexts notifies GetWorkflowContexts streams watching for changes to the Workflows
+	// assigned to their worker.
+	workflowContexts *notifier
+
+	// workflowV2 is non-nil when the v2 WorkflowService is enabled. It notifies GetWorkflows
+	// streams of changes to Workflows for the Hardware they serve.
+	workflowV2 *notifier
+
+	// actionLogs retains output shipped by workers and agents for the actions they execute.
+	actionLogs *actionLogStore
+
+	// operators identifies the clients authorized to read action logs.
+	operators []string
 }
 
 // Register registers the service on the gRPC server.
 func (s *KubernetesBackedServer) Register(server *grpc.Server) {
 	proto.RegisterWorkflowServiceServer(server, s)
+	if s.workflowV2 != nil {
+		workflowproto.RegisterWorkflowServiceServer(server, s)
+	}
 }
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -11,6 +12,15 @@ import (
 	"github.com/tinkerbell/tink/internal/proto"
 	"github.com/tinkerbell/tink/internal/testtime"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 )
 
 var TestTime = testtime.NewFrozenTimeUnix(1637361793)
@@ -414,6 +424,63 @@ func TestModifyWorkflowState(t *testing.T) {
 			},
 			wantErr: nil,
 		},
+		{
+			name: "skipped last action",
+			inputWf: &v1alpha1.Workflow{
+				Status: v1alpha1.WorkflowStatus{
+					State:         "STATE_RUNNING",
+					GlobalTimeout: 600,
+					Tasks: []v1alpha1.Task{
+						{
+							Name:       "provision",
+							WorkerAddr: "machine-mac-1",
+							Actions: []v1alpha1.Action{
+								{
+									Name:   "stream",
+									Status: "STATE_SUCCESS",
+								},
+								{
+									Name:   "kexec",
+									Status: "STATE_PENDING",
+									When:   `eq .Hardware.UserData "kexec"`,
+								},
+							},
+						},
+					},
+				},
+			},
+			inputWfContext: &proto.WorkflowContext{
+				CurrentWorker:        "machine-mac-1",
+				CurrentTask:          "provision",
+				CurrentAction:        "kexec",
+				CurrentActionIndex:   1,
+				CurrentActionState:   proto.State_STATE_SKIPPED,
+				TotalNumberOfActions: 2,
+			},
+			want: &v1alpha1.Workflow{
+				Status: v1alpha1.WorkflowStatus{
+					State:         "STATE_POST",
+					GlobalTimeout: 600,
+					Tasks: []v1alpha1.Task{
+						{
+							Name:       "provision",
+							WorkerAddr: "machine-mac-1",
+							Actions: []v1alpha1.Action{
+								{
+									Name:   "stream",
+									Status: "STATE_SUCCESS",
+								},
+								{
+									Name:   "kexec",
+									Status: "STATE_SKIPPED",
+									When:   `eq .Hardware.UserData "kexec"`,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, tc := range cases {
@@ -451,3 +518,593 @@ func compareErrors(t *testing.T, got, want error) {
 		t.Fatalf("Missing expected error: %v", want)
 	}
 }
+
+func TestReportActionStatusCanceledWorkflow(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+
+	wf := &v1alpha1.Workflow{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "workflow",
+			Namespace: "default",
+		},
+		Spec: v1alpha1.WorkflowSpec{Cancel: true},
+		Status: v1alpha1.WorkflowStatus{
+			State: v1alpha1.WorkflowStateCanceled,
+			Tasks: []v1alpha1.Task{
+				{
+					Name:       "provision",
+					WorkerAddr: "machine-mac-1",
+					Actions: []v1alpha1.Action{
+						{
+							Name:   "stream",
+							Status: v1alpha1.WorkflowStateCanceled,
+						},
+					},
+				},
+			},
+			Conditions: []v1alpha1.WorkflowCondition{
+				{Type: v1alpha1.WorkflowCanceled, Status: metav1.ConditionTrue},
+			},
+		},
+	}
+	clnt := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(wf).
+		WithStatusSubresource(&v1alpha1.Workflow{}).
+		Build()
+
+	srv := &KubernetesBackedServer{
+		logger:     zapr.NewLogger(zap.Must(zap.NewDevelopment())),
+		ClientFunc: func() client.Client { return clnt },
+		nowFunc:    TestTime.Now,
+	}
+
+	_, err := srv.ReportActionStatus(context.Background(), &proto.WorkflowActionStatus{
+		WorkflowId:   "default/workflow",
+		TaskName:     "provision",
+		ActionName:   "stream",
+		ActionStatus: proto.State_STATE_FAILED,
+		WorkerId:     "machine-mac-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got v1alpha1.Workflow
+	if err := clnt.Get(context.Background(), client.ObjectKeyFromObject(wf), &got); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(wf.Status, got.Status); diff != "" {
+		t.Fatalf("status changed: %v", diff)
+	}
+}
+
+func TestReportActionStatusRetriedAction(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+
+	startedAt := metav1.NewTime(TestTime.Before(time.Minute))
+	wf := &v1alpha1.Workflow{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "workflow",
+			Namespace: "default",
+		},
+		Status: v1alpha1.WorkflowStatus{
+			State: v1alpha1.WorkflowStateRunning,
+			Tasks: []v1alpha1.Task{
+				{
+					Name:       "provision",
+					WorkerAddr: "machine-mac-1",
+					Actions: []v1alpha1.Action{
+						{
+							Name:      "stream",
+							Status:    v1alpha1.WorkflowStateRunning,
+							StartedAt: &startedAt,
+							Retries:   1,
+						},
+					},
+				},
+			},
+		},
+	}
+	clnt := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(wf).
+		WithStatusSubresource(&v1alpha1.Workflow{}).
+		Build()
+
+	srv := &KubernetesBackedServer{
+		logger:     zapr.NewLogger(zap.Must(zap.NewDevelopment())),
+		ClientFunc: func() client.Client { return clnt },
+		nowFunc:    TestTime.Now,
+	}
+
+	reports := []*proto.WorkflowActionStatus{
+		{ActionStatus: proto.State_STATE_FAILED, Attempt: 1, WillRetry: true, Message: "exit status 1"},
+		{ActionStatus: proto.State_STATE_RUNNING, Attempt: 2},
+		{ActionStatus: proto.State_STATE_SUCCESS, Attempt: 2},
+	}
+	for _, r := range reports {
+		r.WorkflowId = "default/workflow"
+		r.TaskName = "provision"
+		r.ActionName = "stream"
+		r.WorkerId = "machine-mac-1"
+		if _, err := srv.ReportActionStatus(context.Background(), r); err != nil {
+			t.Fatalf("report %v: %v", r.ActionStatus, err)
+		}
+	}
+
+	var got v1alpha1.Workflow
+	if err := clnt.Get(context.Background(), client.ObjectKeyFromObject(wf), &got); err != nil {
+		t.Fatal(err)
+	}
+
+	now := metav1.NewTime(TestTime.Now())
+	want := v1alpha1.WorkflowStatus{
+		State: v1alpha1.WorkflowStatePost,
+		Tasks: []v1alpha1.Task{
+			{
+				Name:       "provision",
+				WorkerAddr: "machine-mac-1",
+				Actions: []v1alpha1.Action{
+					{
+						Name:      "stream",
+						Status:    v1alpha1.WorkflowStateSuccess,
+						StartedAt: &now,
+						Retries:   1,
+						Attempts: []v1alpha1.ActionAttempt{
+							{
+								Status:    v1alpha1.WorkflowStateFailed,
+								StartedAt: &startedAt,
+								Seconds:   60,
+								Message:   "exit status 1",
+							},
+							{
+								Status:    v1alpha1.WorkflowStateSuccess,
+								StartedAt: &now,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got.Status); diff != "" {
+		t.Fatalf("unexpected status: %v", diff)
+	}
+}
+
+func TestReportActionStatusDuplicateReports(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+
+	wf := &v1alpha1.Workflow{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "workflow",
+			Namespace: "default",
+		},
+		Status: v1alpha1.WorkflowStatus{
+			State: v1alpha1.WorkflowStatePending,
+			Tasks: []v1alpha1.Task{
+				{
+					Name:       "provision",
+					WorkerAddr: "machine-mac-1",
+					Actions: []v1alpha1.Action{
+						{Name: "stream", Status: v1alpha1.WorkflowStatePending, Retries: 1},
+						{Name: "kexec", Status: v1alpha1.WorkflowStatePending},
+					},
+				},
+			},
+		},
+	}
+	clnt := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(wf).
+		WithStatusSubresource(&v1alpha1.Workflow{}).
+		Build()
+
+	srv := &KubernetesBackedServer{
+		logger:     zapr.NewLogger(zap.Must(zap.NewDevelopment())),
+		ClientFunc: func() client.Client { return clnt },
+		nowFunc:    TestTime.Now,
+	}
+
+	// Workers retry reports until they're acknowledged so each report may be received again
+	// after it was applied.
+	reports := []*proto.WorkflowActionStatus{
+		{ActionName: "stream", ActionStatus: proto.State_STATE_RUNNING, Attempt: 1},
+		{ActionName: "stream", ActionStatus: proto.State_STATE_FAILED, Attempt: 1, WillRetry: true, Message: "exit status 1"},
+		{ActionName: "stream", ActionStatus: proto.State_STATE_RUNNING, Attempt: 2},
+		{ActionName: "stream", ActionStatus: proto.State_STATE_SUCCESS, Attempt: 2},
+		{ActionName: "kexec", ActionStatus: proto.State_STATE_RUNNING, Attempt: 1},
+	}
+	for _, r := range reports {
+		r.WorkflowId = "default/workflow"
+		r.TaskName = "provision"
+		r.WorkerId = "machine-mac-1"
+		for i := range 2 {
+			if _, err := srv.ReportActionStatus(context.Background(), r); err != nil {
+				t.Fatalf("report %v %v (%v): %v", r.ActionName, r.ActionStatus, i, err)
+			}
+		}
+	}
+
+	var got v1alpha1.Workflow
+	if err := clnt.Get(context.Background(), client.ObjectKeyFromObject(wf), &got); err != nil {
+		t.Fatal(err)
+	}
+
+	now := metav1.NewTime(TestTime.Now())
+	want := v1alpha1.WorkflowStatus{
+		State: v1alpha1.WorkflowStateRunning,
+		Tasks: []v1alpha1.Task{
+			{
+				Name:       "provision",
+				WorkerAddr: "machine-mac-1",
+				Actions: []v1alpha1.Action{
+					{
+						Name:      "stream",
+						Status:    v1alpha1.WorkflowStateSuccess,
+						StartedAt: &now,
+						Retries:   1,
+						Attempts: []v1alpha1.ActionAttempt{
+							{
+								Status:    v1alpha1.WorkflowStateFailed,
+								StartedAt: &now,
+								Message:   "exit status 1",
+							},
+							{
+								Status:    v1alpha1.WorkflowStateSuccess,
+								StartedAt: &now,
+							},
+						},
+					},
+					{
+						Name:      "kexec",
+						Status:    v1alpha1.WorkflowStateRunning,
+						StartedAt: &now,
+					},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got.Status); diff != "" {
+		t.Fatalf("unexpected status: %v", diff)
+	}
+}
+
+func TestReportActionStatusConflict(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+
+	newWorkflow := func() *v1alpha1.Workflow {
+		return &v1alpha1.Workflow{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "workflow",
+				Namespace: "default",
+			},
+			Status: v1alpha1.WorkflowStatus{
+				State: v1alpha1.WorkflowStatePending,
+				Tasks: []v1alpha1.Task{
+					{
+						Name:       "provision",
+						WorkerAddr: "machine-mac-1",
+						Actions: []v1alpha1.Action{
+							{Name: "stream", Status: v1alpha1.WorkflowStatePending},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	cases := []struct {
+		name      string
+		conflicts int
+		workflow  string
+		wantCode  codes.Code
+		wantState v1alpha1.WorkflowState
+	}{
+		{
+			name:      "RetriedUntilUpdated",
+			conflicts: 2,
+			workflow:  "default/workflow",
+			wantCode:  codes.OK,
+			wantState: v1alpha1.WorkflowStateRunning,
+		},
+		{
+			name:      "PersistentConflict",
+			conflicts: 100,
+			workflow:  "default/workflow",
+			wantCode:  codes.Aborted,
+			wantState: v1alpha1.WorkflowStatePending,
+		},
+		{
+			name:      "NotFound",
+			workflow:  "default/unknown",
+			wantCode:  codes.NotFound,
+			wantState: v1alpha1.WorkflowStatePending,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			wf := newWorkflow()
+			conflicts := tc.conflicts
+			clnt := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(wf).
+				WithStatusSubresource(&v1alpha1.Workflow{}).
+				WithInterceptorFuncs(interceptor.Funcs{
+					SubResourceUpdate: func(ctx context.Context, c client.Client, sub string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+						if conflicts > 0 {
+							conflicts--
+							return apierrors.NewConflict(v1alpha1.GroupVersion.WithResource("workflows").GroupResource(), obj.GetName(), errors.New("modified"))
+						}
+						return c.SubResource(sub).Update(ctx, obj, opts...)
+					},
+				}).
+				Build()
+
+			srv := &KubernetesBackedServer{
+				logger:     zapr.NewLogger(zap.Must(zap.NewDevelopment())),
+				ClientFunc: func() client.Client { return clnt },
+				apiReader:  clnt,
+				nowFunc:    TestTime.Now,
+			}
+
+			_, err := srv.ReportActionStatus(context.Background(), &proto.WorkflowActionStatus{
+				WorkflowId:   tc.workflow,
+				TaskName:     "provision",
+				ActionName:   "stream",
+				ActionStatus: proto.State_STATE_RUNNING,
+				WorkerId:     "machine-mac-1",
+				Attempt:      1,
+			})
+			if got := status.Code(err); got != tc.wantCode {
+				t.Fatalf("Unexpected code: got %v, want %v (%v)", got, tc.wantCode, err)
+			}
+
+			var got v1alpha1.Workflow
+			if err := clnt.Get(context.Background(), client.ObjectKeyFromObject(wf), &got); err != nil {
+				t.Fatal(err)
+			}
+			if got.Status.State != tc.wantState {
+				t.Fatalf("Unexpected state: got %v, want %v", got.Status.State, tc.wantState)
+			}
+		})
+	}
+}
+
+func TestReportActionStatusFailedActionLogs(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+
+	wf := &v1alpha1.Workflow{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "workflow",
+			Namespace: "default",
+		},
+		Status: v1alpha1.WorkflowStatus{
+			State: v1alpha1.WorkflowStateRunning,
+			Tasks: []v1alpha1.Task{
+				{
+					Name:       "provision",
+					WorkerAddr: "machine-mac-1",
+					Actions: []v1alpha1.Action{
+						{
+							Name:   "stream",
+							Status: v1alpha1.WorkflowStateRunning,
+						},
+					},
+				},
+			},
+		},
+	}
+	clnt := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(wf).
+		WithStatusSubresource(&v1alpha1.Workflow{}).
+		Build()
+
+	srv := &KubernetesBackedServer{
+		logger:     zapr.NewLogger(zap.Must(zap.NewDevelopment())),
+		ClientFunc: func() client.Client { return clnt },
+		nowFunc:    TestTime.Now,
+		actionLogs: newActionLogStore(0, 0),
+	}
+	srv.actionLogs.Append("default/workflow", actionLogKey("provision", "stream"), logEntry{
+		Data: []byte("writing image\n"),
+	})
+	srv.actionLogs.Append("default/workflow", actionLogKey("provision", "stream"), logEntry{
+		Stderr: true,
+		Data:   []byte("no space left on device\n"),
+	})
+
+	_, err := srv.ReportActionStatus(context.Background(), &proto.WorkflowActionStatus{
+		WorkflowId:   "default/workflow",
+		TaskName:     "provision",
+		ActionName:   "stream",
+		ActionStatus: proto.State_STATE_FAILED,
+		WorkerId:     "machine-mac-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got v1alpha1.Workflow
+	if err := clnt.Get(context.Background(), client.ObjectKeyFromObject(wf), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := "writing image\nno space left on device\n"
+	if logs := got.Status.Tasks[0].Actions[0].Logs; logs != want {
+		t.Fatalf("unexpected logs: got %q, want %q", logs, want)
+	}
+}
+
+// getWorkflowContextsStream is a fake GetWorkflowContexts server stream that records sent messages.
+type getWorkflowContextsStream struct {
+	grpc.ServerStream
+	sent []*proto.WorkflowContext
+}
+
+func (s *getWorkflowContextsStream) Send(c *proto.WorkflowContext) error {
+	s.sent = append(s.sent, c)
+	return nil
+}
+
+func (s *getWorkflowContextsStream) Context() context.Context {
+	return context.Background()
+}
+
+func TestGetWorkflowContextsPausedWorkflows(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+
+	newWorkflow := func(name string, spec v1alpha1.WorkflowSpec) *v1alpha1.Workflow {
+		return &v1alpha1.Workflow{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       spec,
+			Status: v1alpha1.WorkflowStatus{
+				State: v1alpha1.WorkflowStateRunning,
+				Tasks: []v1alpha1.Task{
+					{
+						Name:       "provision",
+						WorkerAddr: "machine-mac-1",
+						Actions: []v1alpha1.Action{
+							{Name: "stream", Status: v1alpha1.WorkflowStateSuccess},
+							{Name: "kexec", Status: v1alpha1.WorkflowStatePending},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	clnt := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			newWorkflow("running", v1alpha1.WorkflowSpec{}),
+			newWorkflow("paused", v1alpha1.WorkflowSpec{Paused: true}),
+			newWorkflow("breakpoint", v1alpha1.WorkflowSpec{Breakpoints: []string{"provision/kexec"}}),
+		).
+		WithIndex(&v1alpha1.Workflow{}, workflowByNonTerminalState, workflowByNonTerminalStateFunc).
+		Build()
+
+	srv := &KubernetesBackedServer{
+		logger:     zapr.NewLogger(zap.Must(zap.NewDevelopment())),
+		ClientFunc: func() client.Client { return clnt },
+		nowFunc:    TestTime.Now,
+	}
+
+	stream := &getWorkflowContextsStream{}
+	if err := srv.GetWorkflowContexts(&proto.WorkflowContextRequest{WorkerId: "machine-mac-1"}, stream); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, c := range stream.sent {
+		got = append(got, c.GetWorkflowId())
+	}
+	if diff := cmp.Diff([]string{"default/running"}, got); diff != "" {
+		t.Fatalf("unexpected workflows served:\n%v", diff)
+	}
+}
+
+// watchWorkflowContextsStream is a fake GetWorkflowContexts server stream that publishes sent
+// messages to a channel.
+type watchWorkflowContextsStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent chan *proto.WorkflowContext
+}
+
+func (s *watchWorkflowContextsStream) Send(c *proto.WorkflowContext) error {
+	s.sent <- c
+	return nil
+}
+
+func (s *watchWorkflowContextsStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestGetWorkflowContextsWatch(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+
+	wf := &v1alpha1.Workflow{
+		ObjectMeta: metav1.ObjectMeta{Name: "workflow", Namespace: "default"},
+		Status: v1alpha1.WorkflowStatus{
+			State: v1alpha1.WorkflowStatePending,
+			Tasks: []v1alpha1.Task{
+				{
+					Name:       "provision",
+					WorkerAddr: "machine-mac-1",
+					Actions: []v1alpha1.Action{
+						{Name: "stream", Status: v1alpha1.WorkflowStatePending},
+					},
+				},
+			},
+		},
+	}
+	clnt := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(wf).
+		WithStatusSubresource(&v1alpha1.Workflow{}).
+		WithIndex(&v1alpha1.Workflow{}, workflowByNonTerminalState, workflowByNonTerminalStateFunc).
+		Build()
+
+	srv := &KubernetesBackedServer{
+		logger:           zapr.NewLogger(zap.Must(zap.NewDevelopment())),
+		ClientFunc:       func() client.Client { return clnt },
+		nowFunc:          TestTime.Now,
+		workflowContexts: newNotifier(),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := &watchWorkflowContextsStream{ctx: ctx, sent: make(chan *proto.WorkflowContext, 10)}
+	done := make(chan error, 1)
+	go func() {
+		done <- srv.GetWorkflowContexts(&proto.WorkflowContextRequest{WorkerId: "machine-mac-1", Watch: true}, stream)
+	}()
+
+	receive := func() *proto.WorkflowContext {
+		t.Helper()
+		select {
+		case c := <-stream.sent:
+			return c
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for workflow context")
+			return nil
+		}
+	}
+
+	if got := receive(); got.GetCurrentActionState() != proto.State_STATE_PENDING {
+		t.Fatalf("Unexpected initial context: %v", got)
+	}
+
+	// Notifications for unchanged Workflows don't send contexts so the next context sent is
+	// the change.
+	srv.notifyWorkflowContexts(wf)
+
+	var update v1alpha1.Workflow
+	if err := clnt.Get(ctx, client.ObjectKeyFromObject(wf), &update); err != nil {
+		t.Fatal(err)
+	}
+	update.Status.State = v1alpha1.WorkflowStateRunning
+	update.Status.Tasks[0].Actions[0].Status = v1alpha1.WorkflowStateRunning
+	if err := clnt.Status().Update(ctx, &update); err != nil {
+		t.Fatal(err)
+	}
+	srv.notifyWorkflowContexts(&update)
+
+	if got := receive(); got.GetCurrentActionState() != proto.State_STATE_RUNNING {
+		t.Fatalf("Unexpected context after update: %v", got)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
@@ -2,19 +2,33 @@ package server
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"strings"
+	"time"
 
+	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	"github.com/tinkerbell/tink/api/v1alpha1"
 	"github.com/tinkerbell/tink/internal/deprecated/workflow"
 	"github.com/tinkerbell/tink/internal/proto"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	googleproto "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	toolscache "k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
 )
 
+// workflowContextResyncPeriod is the maximum time a watching GetWorkflowContexts stream waits
+// before sending the contexts for its worker in the absence of change notifications.
+const workflowContextResyncPeriod = 30 * time.Second
+
 const (
 	errInvalidWorkflowID     = "invalid workflow id"
 	errInvalidTaskName       = "invalid task name"
@@ -50,13 +64,11 @@ func (s *KubernetesBackedServer) getCurrentAssignedNonTerminalWorkflowsForWorker
 			wfs = append(wfs, wf)
 		}
 	}
-	return wfs, nil
+	return s.ownedWorkflows(ctx, workerID, wfs)
 }
 
 func (s *KubernetesBackedServer) getWorkflowByName(ctx context.Context, workflowID string) (*v1alpha1.Workflow, error) {
-	workflowNamespace, workflowName, _ := strings.Cut(workflowID, "/")
-	wflw := &v1alpha1.Workflow{}
-	err := s.ClientFunc().Get(ctx, types.NamespacedName{Name: workflowName, Namespace: workflowNamespace}, wflw)
+	wflw, err := s.getWorkflow(ctx, s.ClientFunc(), workflowID)
 	if err != nil {
 		s.logger.Error(err, "get client", "workflow", workflowID)
 		return nil, err
@@ -64,29 +76,140 @@ func (s *KubernetesBackedServer) getWorkflowByName(ctx context.Context, workflow
 	return wflw, nil
 }
 
+// getWorkflow retrieves the Workflow identified by workflowID using reader.
+func (s *KubernetesBackedServer) getWorkflow(ctx context.Context, reader client.Reader, workflowID string) (*v1alpha1.Workflow, error) {
+	workflowNamespace, workflowName, _ := strings.Cut(workflowID, "/")
+	wflw := &v1alpha1.Workflow{}
+	if err := reader.Get(ctx, types.NamespacedName{Name: workflowName, Namespace: workflowNamespace}, wflw); err != nil {
+		return nil, err
+	}
+	return wflw, nil
+}
+
+// setupWorkflowContexts configures the informer event handlers that notify GetWorkflowContexts
+// streams watching for changes.
+func (s *KubernetesBackedServer) setupWorkflowContexts(clstr cluster.Cluster) error {
+	informer, err := clstr.GetCache().GetInformer(context.Background(), &v1alpha1.Workflow{})
+	if err != nil {
+		return fmt.Errorf("get workflow informer: %w", err)
+	}
+
+	s.workflowContexts = newNotifier()
+	_, err = informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		AddFunc: s.notifyWorkflowContexts,
+		// Workers are notified of both the old and new Workflow so workers the Workflow is no
+		// longer assigned to observe the change.
+		UpdateFunc: func(old, obj any) {
+			s.notifyWorkflowContexts(old)
+			s.notifyWorkflowContexts(obj)
+		},
+		DeleteFunc: s.notifyWorkflowContexts,
+	})
+	if err != nil {
+		return fmt.Errorf("add workflow event handler: %w", err)
+	}
+
+	return nil
+}
+
+// notifyWorkflowContexts notifies GetWorkflowContexts streams for the workers obj is indexed for
+// by workflowByNonTerminalState.
+func (s *KubernetesBackedServer) notifyWorkflowContexts(obj any) {
+	if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	wf, ok := obj.(*v1alpha1.Workflow)
+	if !ok {
+		return
+	}
+	for _, workerID := range workflowByNonTerminalStateFunc(wf) {
+		s.workflowContexts.Notify(workerID)
+	}
+}
+
 // The following APIs are used by the worker.
 
 func (s *KubernetesBackedServer) GetWorkflowContexts(req *proto.WorkflowContextRequest, stream proto.WorkflowService_GetWorkflowContextsServer) error {
-	// if spec.Netboot is true, and allowPXE: false in the hardware then don't serve a workflow context
-	// if spec.ToggleHardwareNetworkBooting is true, and any associated bmc jobs dont exists or have not completed successfully then don't serve a workflow context
 	if req.GetWorkerId() == "" {
 		return status.Errorf(codes.InvalidArgument, errInvalidWorkflowID)
 	}
-	wflows, err := s.getCurrentAssignedNonTerminalWorkflowsForWorker(stream.Context(), req.WorkerId)
+	ctx := stream.Context()
+
+	if !req.GetWatch() {
+		wfContexts, err := s.getWorkflowContextsForWorker(ctx, req.GetWorkerId())
+		if err != nil {
+			return err
+		}
+		for _, wfContext := range wfContexts {
+			if err := stream.Send(wfContext); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// Subscribe before listing so changes made while listing aren't missed.
+	notify, unsubscribe := s.workflowContexts.Subscribe(req.GetWorkerId())
+	defer unsubscribe()
+
+	// sent tracks the context last sent for each Workflow so only changes are sent in response
+	// to notifications. All contexts are sent on resync so workers recover from contexts they
+	// chose to ignore and idle streams see regular traffic.
+	sent := map[string]*proto.WorkflowContext{}
+	resync := true
+	for {
+		wfContexts, err := s.getWorkflowContextsForWorker(ctx, req.GetWorkerId())
+		if err != nil {
+			return err
+		}
+
+		served := make(map[string]*proto.WorkflowContext, len(wfContexts))
+		for _, wfContext := range wfContexts {
+			served[wfContext.GetWorkflowId()] = wfContext
+			if last, ok := sent[wfContext.GetWorkflowId()]; ok && !resync && googleproto.Equal(last, wfContext) {
+				continue
+			}
+			if err := stream.Send(wfContext); err != nil {
+				return err
+			}
+		}
+		sent = served
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-notify:
+			resync = false
+		case <-time.After(workflowContextResyncPeriod):
+			resync = true
+		}
+	}
+}
+
+// getWorkflowContextsForWorker returns the contexts of the Workflows served to the worker
+// identified by workerID.
+func (s *KubernetesBackedServer) getWorkflowContextsForWorker(ctx context.Context, workerID string) ([]*proto.WorkflowContext, error) {
+	// if spec.Netboot is true, and allowPXE: false in the hardware then don't serve a workflow context
+	// if spec.ToggleHardwareNetworkBooting is true, and any associated bmc jobs dont exists or have not completed successfully then don't serve a workflow context
+	wflows, err := s.getCurrentAssignedNonTerminalWorkflowsForWorker(ctx, workerID)
 	if err != nil {
-		return err
+		return nil, err
 	}
+
+	var wfContexts []*proto.WorkflowContext
 	for _, wf := range wflows {
 		// Don't serve Actions when in a v1alpha1.WorkflowStatePreparing state.
 		// This is to prevent the worker from starting Actions before Workflow boot options are performed.
 		if wf.Spec.BootOptions.BootMode != "" && wf.Status.State == v1alpha1.WorkflowStatePreparing {
 			continue
 		}
-		if err := stream.Send(getWorkflowContext(wf)); err != nil {
-			return err
+		// Don't serve paused Workflows so workers don't start the next Action until they're resumed.
+		if wf.IsPaused() {
+			continue
 		}
+		wfContexts = append(wfContexts, getWorkflowContext(wf))
 	}
-	return nil
+	return wfContexts, nil
 }
 
 func (s *KubernetesBackedServer) GetWorkflowActions(ctx context.Context, req *proto.WorkflowActionsRequest) (*proto.WorkflowActionList, error) {
@@ -98,7 +221,25 @@ func (s *KubernetesBackedServer) GetWorkflowActions(ctx context.Context, req *pr
 	if err != nil {
 		return nil, err
 	}
-	return workflow.ActionListCRDToProto(wf), nil
+
+	// Conditions are evaluated against the Hardware as it is when the actions are requested.
+	// Workflows may not reference Hardware in which case conditions only see the hardware map.
+	var hw v1alpha1.Hardware
+	if wf.Spec.HardwareRef != "" {
+		key := types.NamespacedName{Name: wf.Spec.HardwareRef, Namespace: wf.Namespace}
+		if err := s.ClientFunc().Get(ctx, key, &hw); client.IgnoreNotFound(err) != nil {
+			return nil, status.Errorf(codes.Internal, "get hardware: %v", err)
+		}
+	}
+
+	data, err := workflow.ConditionData(wf, hw)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+
+	actions := workflow.ActionListCRDToProto(wf)
+	actions.ConditionData = data
+	return actions, nil
 }
 
 // Modifies a workflow for a given workflowContext.
@@ -109,32 +250,9 @@ func (s *KubernetesBackedServer) modifyWorkflowState(wf *v1alpha1.Workflow, wfCo
 	if wfContext == nil {
 		return errors.New("no workflow context provided")
 	}
-	var (
-		taskIndex   = -1
-		actionIndex = -1
-	)
-
-	seenActions := 0
-	for ti, task := range wf.Status.Tasks {
-		if wfContext.CurrentTask == task.Name {
-			taskIndex = ti
-			for ai, action := range task.Actions {
-				if action.Name == wfContext.CurrentAction && (wfContext.CurrentActionIndex == int64(ai) || wfContext.CurrentActionIndex == int64(seenActions)) {
-					actionIndex = ai
-					goto cont
-				}
-				seenActions++
-			}
-		}
-		seenActions += len(task.Actions)
-	}
-cont:
-
-	if taskIndex < 0 {
-		return errors.New("task not found")
-	}
-	if actionIndex < 0 {
-		return errors.New("action not found")
+	taskIndex, actionIndex, err := findAction(wf, wfContext)
+	if err != nil {
+		return err
 	}
 	wf.Status.Tasks[taskIndex].Actions[actionIndex].Status = v1alpha1.WorkflowState(proto.State_name[int32(wfContext.CurrentActionState)])
 
@@ -152,8 +270,14 @@ cont:
 		if wf.Status.Tasks[taskIndex].Actions[actionIndex].StartedAt != nil {
 			wf.Status.Tasks[taskIndex].Actions[actionIndex].Seconds = int64(s.nowFunc().Sub(wf.Status.Tasks[taskIndex].Actions[actionIndex].StartedAt.Time).Seconds())
 		}
-	case proto.State_STATE_SUCCESS:
-		// Handle a success by marking the task as complete
+		// Surface the most recent output so users needn't retrieve the logs to diagnose failures.
+		wf.Status.Tasks[taskIndex].Actions[actionIndex].Logs = s.actionLogs.Tail(
+			wfContext.WorkflowId,
+			actionLogKey(wfContext.CurrentTask, wfContext.CurrentAction),
+			failedActionLogBytes,
+		)
+	case proto.State_STATE_SUCCESS, proto.State_STATE_SKIPPED:
+		// Handle a success or skip by marking the task as complete
 		if wf.Status.Tasks[taskIndex].Actions[actionIndex].StartedAt != nil {
 			wf.Status.Tasks[taskIndex].Actions[actionIndex].Seconds = int64(s.nowFunc().Sub(wf.Status.Tasks[taskIndex].Actions[actionIndex].StartedAt.Time).Seconds())
 		}
@@ -169,6 +293,69 @@ cont:
 	return nil
 }
 
+// findAction returns the task and action indices in wf identified by wfContext.
+func findAction(wf *v1alpha1.Workflow, wfContext *proto.WorkflowContext) (int, int, error) {
+	var (
+		taskIndex   = -1
+		actionIndex = -1
+	)
+
+	seenActions := 0
+	for ti, task := range wf.Status.Tasks {
+		if wfContext.CurrentTask == task.Name {
+			taskIndex = ti
+			for ai, action := range task.Actions {
+				if action.Name == wfContext.CurrentAction && (wfContext.CurrentActionIndex == int64(ai) || wfContext.CurrentActionIndex == int64(seenActions)) {
+					actionIndex = ai
+					goto cont
+				}
+				seenActions++
+			}
+		}
+		seenActions += len(task.Actions)
+	}
+cont:
+
+	if taskIndex < 0 {
+		return -1, -1, errors.New("task not found")
+	}
+	if actionIndex < 0 {
+		return -1, -1, errors.New("action not found")
+	}
+	return taskIndex, actionIndex, nil
+}
+
+// recordActionAttempt records the outcome of a single attempt at executing the action reported by
+// req. When the worker will retry the action, the action and workflow remain running.
+func (s *KubernetesBackedServer) recordActionAttempt(wf *v1alpha1.Workflow, wfContext *proto.WorkflowContext, req *proto.WorkflowActionStatus) error {
+	taskIndex, actionIndex, err := findAction(wf, wfContext)
+	if err != nil {
+		return err
+	}
+	action := &wf.Status.Tasks[taskIndex].Actions[actionIndex]
+
+	attempt := int(req.GetAttempt())
+	if attempt < 1 {
+		attempt = 1
+	}
+	for len(action.Attempts) < attempt {
+		action.Attempts = append(action.Attempts, v1alpha1.ActionAttempt{})
+	}
+	record := &action.Attempts[attempt-1]
+	record.Status = v1alpha1.WorkflowState(proto.State_name[int32(req.GetActionStatus())])
+	record.StartedAt = action.StartedAt
+	record.Message = req.GetMessage()
+	if action.StartedAt != nil {
+		record.Seconds = int64(s.nowFunc().Sub(action.StartedAt.Time).Seconds())
+	}
+
+	if req.GetWillRetry() {
+		action.Status = v1alpha1.WorkflowState(proto.State_name[int32(proto.State_STATE_RUNNING)])
+		wf.Status.State = v1alpha1.WorkflowState(proto.State_name[int32(proto.State_STATE_RUNNING)])
+	}
+	return nil
+}
+
 func validateActionStatusRequest(req *proto.WorkflowActionStatus) error {
 	if req.GetWorkflowId() == "" {
 		return status.Errorf(codes.InvalidArgument, errInvalidWorkflowID)
@@ -196,32 +383,223 @@ func (s *KubernetesBackedServer) ReportActionStatus(ctx context.Context, req *pr
 	if err != nil {
 		return nil, err
 	}
-	wfID := req.GetWorkflowId()
 	l := s.logger.WithValues("actionName", req.GetActionName(), "status", req.GetActionStatus(), "workflowID", req.GetWorkflowId(), "taskName", req.GetTaskName(), "worker", req.WorkerId)
 
-	wf, err := s.getWorkflowByName(ctx, wfID)
-	if err != nil {
-		l.Error(err, "get workflow")
-		return nil, status.Errorf(codes.InvalidArgument, errInvalidWorkflowID)
+	// The Workflow is read from the cache on the first attempt. The cache may not have observed
+	// the update that caused a conflict so subsequent attempts read from the API server.
+	var reader client.Reader = s.ClientFunc()
+	err = retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		wf, err := s.getWorkflow(ctx, reader, req.GetWorkflowId())
+		if err != nil {
+			return err
+		}
+		if s.apiReader != nil {
+			reader = s.apiReader
+		}
+
+		changed, err := s.applyActionStatus(l, wf, req)
+		if err != nil || !changed {
+			return err
+		}
+
+		l.Info("updating workflow in Kubernetes")
+		return s.ClientFunc().Status().Update(ctx, wf)
+	})
+
+	switch {
+	case err == nil:
+		return &proto.Empty{}, nil
+	case apierrors.IsNotFound(err):
+		return nil, status.Errorf(codes.NotFound, errInvalidWorkflowID)
+	case apierrors.IsConflict(err):
+		l.Error(err, "applying update to workflow")
+		return nil, status.Errorf(codes.Aborted, "update workflow: %v", err)
+	}
+
+	if _, ok := status.FromError(err); ok {
+		return nil, err
 	}
+	l.Error(err, "applying update to workflow")
+	return nil, status.Errorf(codes.Unavailable, "update workflow: %v", err)
+}
+
+// applyActionStatus applies the action status reported by req to wf and reports whether wf was
+// changed.
+func (s *KubernetesBackedServer) applyActionStatus(l logr.Logger, wf *v1alpha1.Workflow, req *proto.WorkflowActionStatus) (bool, error) {
+	if wf.Status.HasCondition(v1alpha1.WorkflowCanceled, metav1.ConditionTrue) {
+		// Workers stop executing canceled Workflows so reports are expected only from workers
+		// that have not yet observed the cancellation. Acknowledge them so they move on.
+		l.Info("ignoring action status for canceled workflow")
+		return false, nil
+	}
+
+	// Workers retry reports until they're acknowledged so a report may be received after it was
+	// applied, for example when the response was lost. Acknowledge it without applying it again.
+	if action := findReportedAction(wf, req); action != nil && isActionStatusApplied(*action, req) {
+		l.Info("ignoring action status already applied to workflow")
+		return false, nil
+	}
+
 	if req.GetTaskName() != wf.GetCurrentTask() {
-		return nil, status.Errorf(codes.InvalidArgument, errInvalidTaskReported)
+		return false, status.Errorf(codes.InvalidArgument, errInvalidTaskReported)
 	}
 	if req.GetActionName() != wf.GetCurrentAction() {
-		return nil, status.Errorf(codes.InvalidArgument, errInvalidActionReported)
+		return false, status.Errorf(codes.InvalidArgument, errInvalidActionReported)
 	}
 
+	var err error
 	wfContext := getWorkflowContextForRequest(req, wf)
-	err = s.modifyWorkflowState(wf, wfContext)
+	switch {
+	case req.GetWillRetry() && isFailedStatus(req.GetActionStatus()):
+		err = s.recordActionAttempt(wf, wfContext, req)
+	case req.GetAttempt() > 0 && isTerminalStatus(req.GetActionStatus()):
+		err = s.modifyWorkflowState(wf, wfContext)
+		if err == nil {
+			err = s.recordActionAttempt(wf, wfContext, req)
+		}
+	default:
+		err = s.modifyWorkflowState(wf, wfContext)
+	}
 	if err != nil {
 		l.Error(err, "modify workflow state")
-		return nil, status.Errorf(codes.InvalidArgument, errInvalidWorkflowID)
+		return false, status.Errorf(codes.InvalidArgument, errInvalidWorkflowID)
 	}
-	l.Info("updating workflow in Kubernetes")
-	err = s.ClientFunc().Status().Update(ctx, wf)
-	if err != nil {
-		l.Error(err, "applying update to workflow")
-		return nil, status.Errorf(codes.InvalidArgument, errInvalidWorkflowID)
+	return true, nil
+}
+
+// findReportedAction returns the action in wf identified by the task and action name of req.
+func findReportedAction(wf *v1alpha1.Workflow, req *proto.WorkflowActionStatus) *v1alpha1.Action {
+	for ti, task := range wf.Status.Tasks {
+		if task.Name != req.GetTaskName() {
+			continue
+		}
+		for ai, action := range task.Actions {
+			if action.Name == req.GetActionName() {
+				return &wf.Status.Tasks[ti].Actions[ai]
+			}
+		}
+	}
+	return nil
+}
+
+// isActionStatusApplied determines if the status reported by req has already been applied to
+// action.
+func isActionStatusApplied(action v1alpha1.Action, req *proto.WorkflowActionStatus) bool {
+	attempt := max(int(req.GetAttempt()), 1)
+
+	switch {
+	case req.GetWillRetry() && isFailedStatus(req.GetActionStatus()):
+		// Failed attempts are recorded when applied.
+		return len(action.Attempts) >= attempt
+
+	case req.GetActionStatus() == proto.State_STATE_RUNNING:
+		switch action.Status { //nolint:exhaustive // Remaining states are terminal.
+		case v1alpha1.WorkflowStatePending:
+			return false
+		case v1alpha1.WorkflowStateRunning:
+		default:
+			// The action finished so the attempt started.
+			return true
+		}
+		if attempt == 1 || len(action.Attempts) >= attempt {
+			return true
+		}
+		if len(action.Attempts) < attempt-1 {
+			return false
+		}
+		// Starting a retry updates the action start time so it no longer matches the start time
+		// of the previous attempt.
+		previous := action.Attempts[attempt-2]
+		return !previous.StartedAt.Equal(action.StartedAt)
+	}
+
+	reported := req.GetActionStatus()
+	return (isTerminalStatus(reported) || reported == proto.State_STATE_SKIPPED) &&
+		string(action.Status) == proto.State_name[int32(reported)]
+}
+
+// actionLogKey identifies an action of a v1alpha1 Workflow in the action log store.
+func actionLogKey(taskName, actionName string) string {
+	return taskName + "/" + actionName
+}
+
+func validateActionLogIdentity(workflowID, taskName, actionName string) error {
+	if workflowID == "" {
+		return status.Errorf(codes.InvalidArgument, errInvalidWorkflowID)
+	}
+	if taskName == "" {
+		return status.Errorf(codes.InvalidArgument, errInvalidTaskName)
+	}
+	if actionName == "" {
+		return status.Errorf(codes.InvalidArgument, errInvalidActionName)
 	}
-	return &proto.Empty{}, nil
+	return nil
+}
+
+// StreamActionLogs retains output shipped by workers for the actions they execute.
+func (s *KubernetesBackedServer) StreamActionLogs(stream proto.WorkflowService_StreamActionLogsServer) error {
+	for {
+		l, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return stream.SendAndClose(&proto.Empty{})
+		}
+		if err != nil {
+			return err
+		}
+		if err := validateActionLogIdentity(l.GetWorkflowId(), l.GetTaskName(), l.GetActionName()); err != nil {
+			return err
+		}
+
+		s.actionLogs.Append(l.GetWorkflowId(), actionLogKey(l.GetTaskName(), l.GetActionName()), logEntry{
+			Stderr:    l.GetStream() == proto.LogStream_LOG_STREAM_STDERR,
+			Data:      l.GetData(),
+			CreatedAt: s.timestampOrNow(l.GetCreatedAt()),
+		})
+	}
+}
+
+// GetActionLogs retrieves output retained for an action. Output is retained in memory so it's
+// NotFound once discarded, for example because the server restarted.
+func (s *KubernetesBackedServer) GetActionLogs(_ context.Context, req *proto.ActionLogsRequest) (*proto.ActionLogs, error) {
+	if err := validateActionLogIdentity(req.GetWorkflowId(), req.GetTaskName(), req.GetActionName()); err != nil {
+		return nil, err
+	}
+
+	entries, truncated, ok := s.actionLogs.Get(req.GetWorkflowId(), actionLogKey(req.GetTaskName(), req.GetActionName()), int(req.GetLimitBytes()))
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, errActionLogsNotRetained)
+	}
+
+	resp := &proto.ActionLogs{Truncated: truncated}
+	for _, e := range entries {
+		stream := proto.LogStream_LOG_STREAM_STDOUT
+		if e.Stderr {
+			stream = proto.LogStream_LOG_STREAM_STDERR
+		}
+		resp.Logs = append(resp.Logs, &proto.ActionLog{
+			WorkflowId: req.GetWorkflowId(),
+			TaskName:   req.GetTaskName(),
+			ActionName: req.GetActionName(),
+			Stream:     stream,
+			Data:       e.Data,
+			CreatedAt:  timestamppb.New(e.CreatedAt),
+		})
+	}
+	return resp, nil
+}
+
+// timestampOrNow converts ts to a time.Time defaulting to the current time when ts is unset.
+func (s *KubernetesBackedServer) timestampOrNow(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return s.nowFunc()
+	}
+	return ts.AsTime()
+}
+
+func isFailedStatus(state proto.State) bool {
+	return state == proto.State_STATE_FAILED || state == proto.State_STATE_TIMEOUT
+}
+
+func isTerminalStatus(state proto.State) bool {
+	return isFailedStatus(state) || state == proto.State_STATE_SUCCESS
 }
@@ -0,0 +1,953 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/tinkerbell/tink/api/v1alpha2"
+	workflowproto "github.com/tinkerbell/tink/internal/proto/workflow/v2"
+	"github.com/tinkerbell/tink/internal/ptr"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	toolscache "k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+)
+
+const (
+	errInvalidAgentID  = "invalid agent id"
+	errInvalidActionID = "invalid action id"
+	errInvalidEvent    = "invalid event"
+	errNoHardware      = "no hardware found for agent"
+	errManyHardware    = "multiple hardware found for agent"
+)
+
+// workflowV2ResyncPeriod is the maximum time a GetWorkflows stream waits before re-evaluating
+// the Workflows for its agent in the absence of change notifications.
+const workflowV2ResyncPeriod = 30 * time.Second
+
+// setupWorkflowV2 configures the indexes and informer event handlers required to serve the v2
+// WorkflowService.
+func (s *KubernetesBackedServer) setupWorkflowV2(clstr cluster.Cluster) error {
+	ctx := context.Background()
+
+	err := clstr.GetFieldIndexer().IndexField(ctx, &v1alpha2.Hardware{}, hardwareByMACAddr, hardwareByMACAddrFunc)
+	if err != nil {
+		return fmt.Errorf("setup %s index: %w", hardwareByMACAddr, err)
+	}
+
+	err = clstr.GetFieldIndexer().IndexField(ctx, &v1alpha2.Workflow{}, workflowByHardwareRef, workflowByHardwareRefFunc)
+	if err != nil {
+		return fmt.Errorf("setup %s index: %w", workflowByHardwareRef, err)
+	}
+
+	informer, err := clstr.GetCache().GetInformer(ctx, &v1alpha2.Workflow{})
+	if err != nil {
+		return fmt.Errorf("get workflow informer: %w", err)
+	}
+
+	s.workflowV2 = newNotifier()
+	_, err = informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		AddFunc:    s.notifyWorkflowV2,
+		UpdateFunc: func(_, obj any) { s.notifyWorkflowV2(obj) },
+		DeleteFunc: s.notifyWorkflowV2,
+	})
+	if err != nil {
+		return fmt.Errorf("add workflow event handler: %w", err)
+	}
+
+	return nil
+}
+
+// notifyWorkflowV2 notifies GetWorkflows streams serving the Hardware referenced by obj.
+func (s *KubernetesBackedServer) notifyWorkflowV2(obj any) {
+	if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	wf, ok := obj.(*v1alpha2.Workflow)
+	if !ok {
+		return
+	}
+	s.workflowV2.Notify(wf.Namespace + "/" + wf.Spec.HardwareRef.Name)
+}
+
+// getHardwareForAgent retrieves the Hardware with a network interface matching agentID.
+func (s *KubernetesBackedServer) getHardwareForAgent(ctx context.Context, agentID string) (*v1alpha2.Hardware, error) {
+	var hw v1alpha2.HardwareList
+	err := s.ClientFunc().List(ctx, &hw, client.MatchingFields{
+		hardwareByMACAddr: strings.ToLower(agentID),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "list hardware: %v", err)
+	}
+
+	switch len(hw.Items) {
+	case 0:
+		return nil, status.Errorf(codes.NotFound, errNoHardware)
+	case 1:
+		return &hw.Items[0], nil
+	default:
+		return nil, status.Errorf(codes.FailedPrecondition, errManyHardware)
+	}
+}
+
+// The following APIs are used by the agent.
+
+// GetWorkflows streams Workflows to the agent identified by the request. Workflows for the agent's
+// Hardware are dispatched as they become Pending and are stopped if they are cancelled or
+// deleted while dispatched. The stream remains open until the client disconnects.
+func (s *KubernetesBackedServer) GetWorkflows(req *workflowproto.GetWorkflowsRequest, stream workflowproto.WorkflowService_GetWorkflowsServer) error {
+	if req.GetAgentId() == "" {
+		return status.Errorf(codes.InvalidArgument, errInvalidAgentID)
+	}
+
+	ctx := stream.Context()
+	log := s.logger.WithValues("agentID", req.GetAgentId())
+
+	hw, err := s.getHardwareForAgent(ctx, req.GetAgentId())
+	if err != nil {
+		return err
+	}
+	log = log.WithValues("hardware", hw.Namespace+"/"+hw.Name)
+
+	notify, unsubscribe := s.workflowV2.Subscribe(hw.Namespace + "/" + hw.Name)
+	defer unsubscribe()
+
+	// dispatched tracks the Workflows sent to the agent on this stream.
+	dispatched := map[string]*dispatchedWorkflow{}
+
+	for {
+		if err := s.dispatchWorkflows(ctx, log, hw, stream, dispatched); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-notify:
+		case <-time.After(workflowV2ResyncPeriod):
+		}
+	}
+}
+
+// dispatchedWorkflow describes a Workflow sent to the agent.
+type dispatchedWorkflow struct {
+	// Stopped indicates the agent has been instructed to stop the Workflow.
+	Stopped bool
+
+	// Pause is the pause configuration last sent to the agent.
+	Pause *workflowproto.Workflow_Pause
+}
+
+// dispatchWorkflows sends start, pause and stop commands to the agent for the Workflows
+// associated with hw. dispatched is updated with the commands sent.
+func (s *KubernetesBackedServer) dispatchWorkflows(
+	ctx context.Context,
+	log logr.Logger,
+	hw *v1alpha2.Hardware,
+	stream workflowproto.WorkflowService_GetWorkflowsServer,
+	dispatched map[string]*dispatchedWorkflow,
+) error {
+	var wflws v1alpha2.WorkflowList
+	err := s.ClientFunc().List(ctx, &wflws, client.InNamespace(hw.Namespace), client.MatchingFields{
+		workflowByHardwareRef: hw.Name,
+	})
+	if err != nil {
+		return status.Errorf(codes.Internal, "list workflows: %v", err)
+	}
+
+	seen := map[string]struct{}{}
+	for _, wf := range wflws.Items {
+		wfID := wf.Namespace + "/" + wf.Name
+		log := log.WithValues("workflowID", wfID)
+		seen[wfID] = struct{}{}
+
+		switch wf.Status.State {
+		case v1alpha2.WorkflowStatePending, v1alpha2.WorkflowStateScheduled, v1alpha2.WorkflowStateRunning:
+			if d, ok := dispatched[wfID]; ok {
+				if err := sendPauseWorkflow(stream, wf, d); err != nil {
+					return err
+				}
+				continue
+			}
+
+			// Running Workflows that weren't dispatched on this stream are already executing on
+			// the agent, possibly resumed after the agent restarted. They're tracked so pause and
+			// stop commands still reach the agent. Workflows running a reboot or kexec action were
+			// interrupted by the machine restarting so the action is complete and the Workflow is
+			// dispatched again to continue with the next action.
+			if wf.Status.State == v1alpha2.WorkflowStateRunning {
+				if !completeRebootActions(&wf, metav1.NewTime(s.nowFunc())) {
+					d := &dispatchedWorkflow{}
+					dispatched[wfID] = d
+					if err := sendPauseWorkflow(stream, wf, d); err != nil {
+						return err
+					}
+					continue
+				}
+
+				log.Info("Agent reconnected after restarting machine; continuing workflow")
+				if err := s.ClientFunc().Status().Update(ctx, &wf); err != nil {
+					// The Workflow will be re-evaluated when the update is observed or on resync.
+					log.Info("Could not complete reboot action", "error", err)
+					continue
+				}
+				if isTerminalWorkflowState(wf.Status.State) {
+					continue
+				}
+			}
+
+			if len(wf.Status.Actions) == 0 {
+				continue
+			}
+
+			// Workflows already Scheduled were dispatched on a previous stream that the agent
+			// never acknowledged so we re-send them as is.
+			if wf.Status.State == v1alpha2.WorkflowStatePending {
+				wf.Status.State = v1alpha2.WorkflowStateScheduled
+				wf.Status.LastTransition = metav1.NewTime(s.nowFunc())
+				if err := s.ClientFunc().Status().Update(ctx, &wf); err != nil {
+					// The Workflow will be re-evaluated when the update is observed or on resync.
+					log.Info("Could not schedule workflow", "error", err)
+					continue
+				}
+			}
+
+			wfProto, err := toWorkflowProto(wf, hw)
+			if err != nil {
+				log.Error(err, "Could not convert workflow")
+				continue
+			}
+
+			log.Info("Dispatching workflow to agent")
+			err = stream.Send(&workflowproto.GetWorkflowsResponse{
+				Cmd: &workflowproto.GetWorkflowsResponse_StartWorkflow_{
+					StartWorkflow: &workflowproto.GetWorkflowsResponse_StartWorkflow{
+						Workflow: wfProto,
+					},
+				},
+			})
+			if err != nil {
+				return err
+			}
+			dispatched[wfID] = &dispatchedWorkflow{Pause: wfProto.GetPause()}
+
+		case v1alpha2.WorkflowStateCancelling:
+			if d, ok := dispatched[wfID]; !ok || d.Stopped {
+				continue
+			}
+			log.Info("Stopping workflow on agent")
+			if err := sendStopWorkflow(stream, wfID); err != nil {
+				return err
+			}
+			dispatched[wfID].Stopped = true
+		}
+	}
+
+	// Workflows deleted after being dispatched must be stopped on the agent.
+	for wfID, d := range dispatched {
+		if _, ok := seen[wfID]; ok {
+			continue
+		}
+		if !d.Stopped {
+			log.Info("Stopping deleted workflow on agent", "workflowID", wfID)
+			if err := sendStopWorkflow(stream, wfID); err != nil {
+				return err
+			}
+		}
+		delete(dispatched, wfID)
+	}
+
+	return nil
+}
+
+func sendStopWorkflow(stream workflowproto.WorkflowService_GetWorkflowsServer, wfID string) error {
+	return stream.Send(&workflowproto.GetWorkflowsResponse{
+		Cmd: &workflowproto.GetWorkflowsResponse_StopWorkflow_{
+			StopWorkflow: &workflowproto.GetWorkflowsResponse_StopWorkflow{
+				WorkflowId: wfID,
+			},
+		},
+	})
+}
+
+// sendPauseWorkflow sends the pause configuration of wf to the agent if it has changed since it
+// was last sent. d is updated with the configuration sent.
+func sendPauseWorkflow(stream workflowproto.WorkflowService_GetWorkflowsServer, wf v1alpha2.Workflow, d *dispatchedWorkflow) error {
+	if d.Stopped {
+		return nil
+	}
+
+	pause := toPauseProto(wf)
+	if proto.Equal(pause, d.Pause) {
+		return nil
+	}
+
+	err := stream.Send(&workflowproto.GetWorkflowsResponse{
+		Cmd: &workflowproto.GetWorkflowsResponse_PauseWorkflow_{
+			PauseWorkflow: &workflowproto.GetWorkflowsResponse_PauseWorkflow{
+				WorkflowId: wf.Namespace + "/" + wf.Name,
+				Pause:      pause,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	d.Pause = pause
+	return nil
+}
+
+// toPauseProto converts the pause configuration of wf to a v2 Pause message. Breakpoints that
+// don't name an action are ignored. It returns nil if wf isn't configured to pause.
+func toPauseProto(wf v1alpha2.Workflow) *workflowproto.Workflow_Pause {
+	var breakpoints []string
+	for _, a := range slices.Concat(wf.Status.Actions, wf.Status.OnFailure, wf.Status.Finally) {
+		if slices.Contains(wf.Spec.Breakpoints, a.Rendered.Name) {
+			breakpoints = append(breakpoints, a.ID)
+		}
+	}
+
+	if !wf.Spec.Paused && len(breakpoints) == 0 {
+		return nil
+	}
+	return &workflowproto.Workflow_Pause{
+		Paused:      wf.Spec.Paused,
+		Breakpoints: breakpoints,
+	}
+}
+
+// toWorkflowProto converts the rendered actions of wf to a v2 Workflow message. Action conditions
+// are evaluated against hw and the template parameters of wf.
+func toWorkflowProto(wf v1alpha2.Workflow, hw *v1alpha2.Hardware) (*workflowproto.Workflow, error) {
+	// Templates reference dependencies by name whereas the agent identifies actions by ID.
+	ids := make(map[string]string, len(wf.Status.Actions))
+	for _, a := range wf.Status.Actions {
+		ids[a.Rendered.Name] = a.ID
+	}
+
+	data, err := conditionData(wf, hw)
+	if err != nil {
+		return nil, err
+	}
+
+	return &workflowproto.Workflow{
+		WorkflowId:    wf.Namespace + "/" + wf.Name,
+		Actions:       toActionProtos(wf.Status.Actions, ids),
+		ConditionData: data,
+		OnFailure:     toActionProtos(wf.Status.OnFailure, ids),
+		Finally:       toActionProtos(wf.Status.Finally, ids),
+		Pause:         toPauseProto(wf),
+		Results:       toResultProtos(wf),
+	}, nil
+}
+
+// toResultProtos converts the actions of wf that finished to results so they aren't run again
+// when wf is dispatched after the agent restarted the machine.
+func toResultProtos(wf v1alpha2.Workflow) map[string]*workflowproto.Workflow_ActionResult {
+	var results map[string]*workflowproto.Workflow_ActionResult
+	for _, a := range slices.Concat(wf.Status.Actions, wf.Status.OnFailure, wf.Status.Finally) {
+		if !isTerminalActionState(a.State) {
+			continue
+		}
+		if results == nil {
+			results = map[string]*workflowproto.Workflow_ActionResult{}
+		}
+		results[a.ID] = &workflowproto.Workflow_ActionResult{
+			Succeeded: a.State != v1alpha2.ActionStateFailed,
+			Outputs:   a.Outputs,
+		}
+	}
+	return results
+}
+
+// toActionProtos converts the rendered actions of status to v2 Action messages. ids maps action
+// names to IDs.
+func toActionProtos(status []v1alpha2.ActionStatus, ids map[string]string) []*workflowproto.Workflow_Action {
+	actions := make([]*workflowproto.Workflow_Action, 0, len(status))
+	for _, a := range status {
+		action := &workflowproto.Workflow_Action{
+			Id:             a.ID,
+			Name:           a.Rendered.Name,
+			Image:          a.Rendered.Image,
+			Cmd:            a.Rendered.Cmd,
+			Args:           a.Rendered.Args,
+			Env:            a.Rendered.Env,
+			TimeoutSeconds: a.Rendered.TimeoutSeconds,
+		}
+		for _, name := range a.Rendered.DependsOn {
+			action.DependsOn = append(action.DependsOn, ids[name])
+		}
+		if a.Rendered.When != "" {
+			action.When = ptr.String(a.Rendered.When)
+		}
+		for _, v := range a.Rendered.Volumes {
+			action.Volumes = append(action.Volumes, string(v))
+		}
+		if ns := a.Rendered.Namespace; ns != nil {
+			action.NetworkNamespace = ns.Network
+			// Other PID namespaces are rejected when the template is rendered.
+			if ns.PID != nil && *ns.PID == 1 {
+				action.PidNamespace = ptr.String("host")
+			}
+		}
+		action.Privileged = a.Rendered.Privileged
+		action.Idempotent = a.Rendered.Idempotent
+		switch a.Rendered.Type {
+		case v1alpha2.ActionTypeReboot:
+			action.Type = workflowproto.Workflow_Action_TYPE_REBOOT
+		case v1alpha2.ActionTypeKexec:
+			action.Type = workflowproto.Workflow_Action_TYPE_KEXEC
+		}
+		if k := a.Rendered.Kexec; k != nil {
+			action.Kexec = &workflowproto.Workflow_Kexec{
+				BlockDevice: k.BlockDevice,
+				FsType:      k.FSType,
+				KernelPath:  k.KernelPath,
+				InitrdPath:  k.InitrdPath,
+				Cmdline:     k.Cmdline,
+			}
+		}
+		if c := a.Rendered.Capabilities; c != nil {
+			action.Capabilities = &workflowproto.Workflow_Capabilities{
+				Add:  c.Add,
+				Drop: c.Drop,
+			}
+		}
+		for _, d := range a.Rendered.Devices {
+			action.Devices = append(action.Devices, string(d))
+		}
+		if r := a.Rendered.Resources; r != nil {
+			action.Resources = toResourceLimitsProto(r)
+		}
+		if r := a.Rendered.Retry; r != nil {
+			action.RetryPolicy = &workflowproto.Workflow_RetryPolicy{
+				Retries:        r.Retries,
+				BackoffSeconds: r.BackoffSeconds,
+				Reasons:        r.Reasons,
+			}
+		}
+		actions = append(actions, action)
+	}
+	return actions
+}
+
+// toResourceLimitsProto converts r to the limits used by the agent.
+func toResourceLimitsProto(r *v1alpha2.ResourceLimits) *workflowproto.Workflow_ResourceLimits {
+	limits := &workflowproto.Workflow_ResourceLimits{}
+	if r.Memory != nil {
+		limits.MemoryBytes = r.Memory.Value()
+	}
+	if r.CPU != nil {
+		limits.NanoCpus = r.CPU.ScaledValue(resource.Nano)
+	}
+	if r.PIDs != nil {
+		limits.Pids = *r.PIDs
+	}
+	return limits
+}
+
+// conditionData builds the data action conditions are evaluated against. Data is exposed using
+// the field names of its JSON representation.
+func conditionData(wf v1alpha2.Workflow, hw *v1alpha2.Hardware) (*structpb.Struct, error) {
+	raw, err := json.Marshal(map[string]any{
+		"Hardware": hw.Spec,
+		"Param":    wf.Spec.TemplateParams,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshal condition data: %w", err)
+	}
+
+	var data structpb.Struct
+	if err := protojson.Unmarshal(raw, &data); err != nil {
+		return nil, fmt.Errorf("unmarshal condition data: %w", err)
+	}
+	return &data, nil
+}
+
+// PublishEvent records an event published by the agent against the Workflow it relates to.
+// Events that don't change the Workflow, such as those re-published by the agent, are ignored.
+func (s *KubernetesBackedServer) PublishEvent(ctx context.Context, req *workflowproto.PublishEventRequest) (*workflowproto.PublishEventResponse, error) {
+	evnt := req.GetEvent()
+	if evnt.GetWorkflowId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, errInvalidWorkflowID)
+	}
+	if evnt.GetEvent() == nil {
+		return nil, status.Errorf(codes.InvalidArgument, errInvalidEvent)
+	}
+
+	namespace, name, _ := strings.Cut(evnt.GetWorkflowId(), "/")
+	log := s.logger.WithValues("workflowID", evnt.GetWorkflowId(), "event", fmt.Sprintf("%T", evnt.GetEvent()))
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var wf v1alpha2.Workflow
+		if err := s.ClientFunc().Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &wf); err != nil {
+			return err
+		}
+
+		// Agents publish events until they're acknowledged so an event may be received more than
+		// once. Acknowledge events that have already been applied without applying them again.
+		if eventApplied(wf, req) {
+			log.V(1).Info("Ignoring replayed event", "session", req.GetSessionId(), "sequence", req.GetSequence())
+			return nil
+		}
+
+		changed, err := s.applyWorkflowEvent(&wf, evnt)
+		if err != nil || !changed {
+			return err
+		}
+
+		// The event is recorded in the same update that applies it so replays are recognized
+		// across server restarts.
+		if req.GetSessionId() != "" {
+			wf.Status.LastEvent = &v1alpha2.EventSequence{
+				Session:  req.GetSessionId(),
+				Sequence: req.GetSequence(),
+			}
+		}
+
+		return s.ClientFunc().Status().Update(ctx, &wf)
+	})
+
+	switch {
+	case err == nil:
+		return &workflowproto.PublishEventResponse{}, nil
+	case apierrors.IsNotFound(err):
+		return nil, status.Errorf(codes.NotFound, errInvalidWorkflowID)
+	case apierrors.IsConflict(err):
+		log.Error(err, "update workflow")
+		return nil, status.Errorf(codes.Aborted, "update workflow: %v", err)
+	}
+
+	if _, ok := status.FromError(err); ok {
+		return nil, err
+	}
+	log.Error(err, "update workflow")
+	return nil, status.Errorf(codes.Internal, "update workflow: %v", err)
+}
+
+// eventApplied reports whether the event published in req was already applied to wf. Agents
+// publish the events of a session in sequence order so any event up to the last applied one was
+// applied, or had no effect when it was received.
+func eventApplied(wf v1alpha2.Workflow, req *workflowproto.PublishEventRequest) bool {
+	last := wf.Status.LastEvent
+	return last != nil && req.GetSessionId() != "" &&
+		last.Session == req.GetSessionId() && req.GetSequence() <= last.Sequence
+}
+
+// applyWorkflowEvent applies evnt to wf and reports whether wf was changed.
+func (s *KubernetesBackedServer) applyWorkflowEvent(wf *v1alpha2.Workflow, evnt *workflowproto.Event) (bool, error) {
+	// Terminal Workflows are immutable so late events, for example from an agent that is still
+	// winding down a cancelled Workflow, are dropped.
+	if isTerminalWorkflowState(wf.Status.State) {
+		return false, nil
+	}
+
+	now := metav1.NewTime(s.nowFunc())
+
+	switch e := evnt.GetEvent().(type) {
+	case *workflowproto.Event_ActionStarted_:
+		action := findActionStatus(wf, e.ActionStarted.GetActionId())
+		if action == nil {
+			return false, status.Errorf(codes.NotFound, errInvalidActionID)
+		}
+		n := attemptNumber(e.ActionStarted.GetAttempt())
+		if isTerminalActionState(action.State) || startedAttempts(action) >= n {
+			return false, nil
+		}
+		attempt := getActionAttempt(action, n)
+		attempt.State = v1alpha2.ActionStateRunning
+		attempt.StartedAt = &now
+		attempt.LastTransition = &now
+
+		action.State = v1alpha2.ActionStateRunning
+		if action.StartedAt == nil {
+			action.StartedAt = &now
+		}
+		action.LastTransition = &now
+
+		startWorkflow(wf, now)
+
+	case *workflowproto.Event_ActionSucceeded_:
+		action := findActionStatus(wf, e.ActionSucceeded.GetActionId())
+		if action == nil {
+			return false, status.Errorf(codes.NotFound, errInvalidActionID)
+		}
+		if isTerminalActionState(action.State) {
+			return false, nil
+		}
+		attempt := getActionAttempt(action, attemptNumber(e.ActionSucceeded.GetAttempt()))
+		attempt.State = v1alpha2.ActionStateSucceeded
+		attempt.LastTransition = &now
+
+		action.State = v1alpha2.ActionStateSucceeded
+		action.LastTransition = &now
+		action.Outputs = e.ActionSucceeded.GetOutputs()
+
+		settleWorkflowState(wf, now)
+
+	case *workflowproto.Event_ActionFailed_:
+		action := findActionStatus(wf, e.ActionFailed.GetActionId())
+		if action == nil {
+			return false, status.Errorf(codes.NotFound, errInvalidActionID)
+		}
+		attempt := getActionAttempt(action, attemptNumber(e.ActionFailed.GetAttempt()))
+		if isTerminalActionState(action.State) || attempt.State == v1alpha2.ActionStateFailed {
+			return false, nil
+		}
+		attempt.State = v1alpha2.ActionStateFailed
+		attempt.LastTransition = &now
+		attempt.FailureReason = e.ActionFailed.GetFailureReason()
+		attempt.FailureMessage = e.ActionFailed.GetFailureMessage()
+
+		// The agent retries the action so it remains running.
+		if e.ActionFailed.GetWillRetry() {
+			break
+		}
+
+		action.State = v1alpha2.ActionStateFailed
+		action.LastTransition = &now
+		action.FailureReason = e.ActionFailed.GetFailureReason()
+		action.FailureMessage = e.ActionFailed.GetFailureMessage()
+		action.Logs = s.actionLogs.Tail(evnt.GetWorkflowId(), action.ID, failedActionLogBytes)
+
+		settleWorkflowState(wf, now)
+
+	case *workflowproto.Event_ActionSkipped_:
+		action := findActionStatus(wf, e.ActionSkipped.GetActionId())
+		if action == nil {
+			return false, status.Errorf(codes.NotFound, errInvalidActionID)
+		}
+		if action.State != v1alpha2.ActionStatePending && action.State != v1alpha2.ActionStatePaused {
+			return false, nil
+		}
+		action.State = v1alpha2.ActionStateSkipped
+		action.LastTransition = &now
+
+		startWorkflow(wf, now)
+		settleWorkflowState(wf, now)
+
+	case *workflowproto.Event_ActionPaused_:
+		action := findActionStatus(wf, e.ActionPaused.GetActionId())
+		if action == nil {
+			return false, status.Errorf(codes.NotFound, errInvalidActionID)
+		}
+		if action.State != v1alpha2.ActionStatePending {
+			return false, nil
+		}
+		action.State = v1alpha2.ActionStatePaused
+		action.LastTransition = &now
+
+	case *workflowproto.Event_ImagePulled_:
+		pulled, total := e.ImagePulled.GetPulled(), e.ImagePulled.GetTotal()
+		cond := v1alpha2.Condition{
+			Type:           v1alpha2.WorkflowConditionImagesPulled,
+			Status:         v1alpha2.ConditionStatusFalse,
+			LastTransition: now,
+			Reason:         ptr.String("Pulling"),
+			Message:        ptr.String(fmt.Sprintf("pulled %d of %d images", pulled, total)),
+		}
+		if pulled >= total {
+			cond.Status = v1alpha2.ConditionStatusTrue
+			cond.Reason = ptr.String("Pulled")
+		}
+		if current, ok := wf.Status.Conditions.Get(cond.Type); ok && current.Status == cond.Status && current.Message != nil &&
+			*current.Message == *cond.Message {
+			return false, nil
+		}
+		wf.Status.Conditions.Set(cond)
+
+	case *workflowproto.Event_ImagePullFailed_:
+		// The agent doesn't run any action when an image can't be pulled.
+		wf.Status.Conditions.Set(v1alpha2.Condition{
+			Type:           v1alpha2.WorkflowConditionImagesPulled,
+			Status:         v1alpha2.ConditionStatusFalse,
+			LastTransition: now,
+			Reason:         ptr.String("PullFailed"),
+			Message: ptr.String(fmt.Sprintf("pull image %v: %v",
+				e.ImagePullFailed.GetImage(), e.ImagePullFailed.GetMessage())),
+		})
+		setWorkflowState(wf, v1alpha2.WorkflowStateFailed, now)
+
+	case *workflowproto.Event_WorkflowRejected_:
+		s.logger.Info("Agent rejected workflow",
+			"workflowID", evnt.GetWorkflowId(),
+			"message", e.WorkflowRejected.GetMessage(),
+		)
+		setWorkflowState(wf, v1alpha2.WorkflowStateFailed, now)
+
+	default:
+		return false, status.Errorf(codes.InvalidArgument, errInvalidEvent)
+	}
+
+	setPausedCondition(wf, now)
+
+	return true, nil
+}
+
+// setPausedCondition records whether the agent is holding an action of wf in the Paused
+// condition. The condition is only added once the Workflow pauses.
+func setPausedCondition(wf *v1alpha2.Workflow, now metav1.Time) {
+	var paused *v1alpha2.ActionStatus
+	for _, actions := range [][]v1alpha2.ActionStatus{wf.Status.Actions, wf.Status.OnFailure, wf.Status.Finally} {
+		for i := range actions {
+			if actions[i].State == v1alpha2.ActionStatePaused {
+				paused = &actions[i]
+			}
+		}
+	}
+
+	switch {
+	case paused != nil && slices.Contains(wf.Spec.Breakpoints, paused.Rendered.Name):
+		wf.Status.Conditions.Set(v1alpha2.Condition{
+			Type:           v1alpha2.WorkflowConditionPaused,
+			Status:         v1alpha2.ConditionStatusTrue,
+			LastTransition: now,
+			Reason:         ptr.String("Breakpoint"),
+			Message:        ptr.String(fmt.Sprintf("workflow paused at breakpoint before action %v", paused.Rendered.Name)),
+		})
+	case paused != nil:
+		wf.Status.Conditions.Set(v1alpha2.Condition{
+			Type:           v1alpha2.WorkflowConditionPaused,
+			Status:         v1alpha2.ConditionStatusTrue,
+			LastTransition: now,
+			Reason:         ptr.String("Paused"),
+			Message:        ptr.String(fmt.Sprintf("workflow paused before action %v", paused.Rendered.Name)),
+		})
+	case wf.Status.Conditions.IsTrue(v1alpha2.WorkflowConditionPaused):
+		wf.Status.Conditions.Set(v1alpha2.Condition{
+			Type:           v1alpha2.WorkflowConditionPaused,
+			Status:         v1alpha2.ConditionStatusFalse,
+			LastTransition: now,
+			Reason:         ptr.String("Resumed"),
+			Message:        ptr.String("workflow resumed"),
+		})
+	}
+}
+
+// PublishActionLogs retains output shipped by the agent for the actions it executes.
+func (s *KubernetesBackedServer) PublishActionLogs(stream workflowproto.WorkflowService_PublishActionLogsServer) error {
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return stream.SendAndClose(&workflowproto.PublishActionLogsResponse{})
+		}
+		if err != nil {
+			return err
+		}
+
+		l := req.GetLog()
+		if l.GetWorkflowId() == "" {
+			return status.Errorf(codes.InvalidArgument, errInvalidWorkflowID)
+		}
+		if l.GetActionId() == "" {
+			return status.Errorf(codes.InvalidArgument, errInvalidActionID)
+		}
+
+		s.actionLogs.Append(l.GetWorkflowId(), l.GetActionId(), logEntry{
+			Stderr:    l.GetStream() == workflowproto.ActionLog_STREAM_STDERR,
+			Data:      l.GetData(),
+			CreatedAt: s.timestampOrNow(l.GetCreatedAt()),
+		})
+	}
+}
+
+// ListActionLogs retrieves output retained for an action. Output is retained in memory so it's
+// NotFound once discarded, for example because the server restarted.
+func (s *KubernetesBackedServer) ListActionLogs(_ context.Context, req *workflowproto.ListActionLogsRequest) (*workflowproto.ListActionLogsResponse, error) {
+	if req.GetWorkflowId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, errInvalidWorkflowID)
+	}
+	if req.GetActionId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, errInvalidActionID)
+	}
+
+	entries, truncated, ok := s.actionLogs.Get(req.GetWorkflowId(), req.GetActionId(), int(req.GetLimitBytes()))
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, errActionLogsNotRetained)
+	}
+
+	resp := &workflowproto.ListActionLogsResponse{Truncated: truncated}
+	for _, e := range entries {
+		stream := workflowproto.ActionLog_STREAM_STDOUT
+		if e.Stderr {
+			stream = workflowproto.ActionLog_STREAM_STDERR
+		}
+		resp.Logs = append(resp.Logs, &workflowproto.ActionLog{
+			WorkflowId: req.GetWorkflowId(),
+			ActionId:   req.GetActionId(),
+			Stream:     stream,
+			Data:       e.Data,
+			CreatedAt:  timestamppb.New(e.CreatedAt),
+		})
+	}
+	return resp, nil
+}
+
+// findActionStatus retrieves the status of the action identified by actionID from the Actions,
+// OnFailure or Finally of wf.
+func findActionStatus(wf *v1alpha2.Workflow, actionID string) *v1alpha2.ActionStatus {
+	for _, actions := range [][]v1alpha2.ActionStatus{wf.Status.Actions, wf.Status.OnFailure, wf.Status.Finally} {
+		for i := range actions {
+			if actions[i].ID == actionID {
+				return &actions[i]
+			}
+		}
+	}
+	return nil
+}
+
+// completeRebootActions marks the running reboot and kexec actions of wf as succeeded. The agent
+// can't report these actions succeeded because they restart the machine so they're completed
+// when the agent reconnects. It returns true if an action was completed.
+func completeRebootActions(wf *v1alpha2.Workflow, now metav1.Time) bool {
+	var completed bool
+	for _, actions := range [][]v1alpha2.ActionStatus{wf.Status.Actions, wf.Status.OnFailure, wf.Status.Finally} {
+		for i := range actions {
+			action := &actions[i]
+			if action.State != v1alpha2.ActionStateRunning || !action.Rendered.Type.IsReboot() {
+				continue
+			}
+			attempt := getActionAttempt(action, max(startedAttempts(action), 1))
+			attempt.State = v1alpha2.ActionStateSucceeded
+			attempt.LastTransition = &now
+
+			action.State = v1alpha2.ActionStateSucceeded
+			action.LastTransition = &now
+			completed = true
+		}
+	}
+
+	if completed {
+		settleWorkflowState(wf, now)
+	}
+	return completed
+}
+
+// attemptNumber normalizes an attempt number received from the agent. Agents that don't support
+// retries don't set the attempt which is considered the first attempt.
+func attemptNumber(n int64) int {
+	if n < 1 {
+		return 1
+	}
+	return int(n)
+}
+
+// getActionAttempt retrieves attempt n, starting from 1, of action. Attempts are added to action
+// as needed.
+func getActionAttempt(action *v1alpha2.ActionStatus, n int) *v1alpha2.ActionAttempt {
+	for len(action.Attempts) < n {
+		action.Attempts = append(action.Attempts, v1alpha2.ActionAttempt{})
+	}
+	return &action.Attempts[n-1]
+}
+
+// startedAttempts returns the number of attempts at running action that have started.
+func startedAttempts(action *v1alpha2.ActionStatus) int {
+	if len(action.Attempts) == 0 && action.State != v1alpha2.ActionStatePending && action.State != v1alpha2.ActionStatePaused {
+		return 1
+	}
+	return len(action.Attempts)
+}
+
+func isTerminalActionState(state v1alpha2.ActionState) bool {
+	switch state {
+	case v1alpha2.ActionStateSucceeded, v1alpha2.ActionStateFailed, v1alpha2.ActionStateSkipped:
+		return true
+	}
+	return false
+}
+
+// actionsFinished determines if no further actions will run because every action finished or an
+// action failed and no actions are running. The agent doesn't start actions once an action has
+// failed.
+func actionsFinished(actions []v1alpha2.ActionStatus) (finished, failed bool) {
+	finished = true
+	for _, a := range actions {
+		switch a.State {
+		case v1alpha2.ActionStateFailed:
+			failed = true
+		case v1alpha2.ActionStateRunning:
+			return false, false
+		case v1alpha2.ActionStatePending, v1alpha2.ActionStatePaused:
+			finished = false
+		}
+	}
+	return finished || failed, failed
+}
+
+// settleWorkflowState transitions wf to a terminal state once its actions and the handlers that
+// apply have finished. OnFailure actions only apply when an action failed and Finally actions
+// always apply. The Workflow succeeds when no action in Actions or Finally failed. Cancelling
+// Workflows are left for the reconciler to complete once the cancellation grace period elapses.
+func settleWorkflowState(wf *v1alpha2.Workflow, now metav1.Time) {
+	if wf.Status.State == v1alpha2.WorkflowStateCancelling {
+		return
+	}
+
+	finished, failed := actionsFinished(wf.Status.Actions)
+	if !finished {
+		return
+	}
+
+	if failed {
+		if finished, _ := actionsFinished(wf.Status.OnFailure); !finished {
+			return
+		}
+	}
+
+	finished, finallyFailed := actionsFinished(wf.Status.Finally)
+	if !finished {
+		return
+	}
+
+	if failed || finallyFailed {
+		setWorkflowState(wf, v1alpha2.WorkflowStateFailed, now)
+		return
+	}
+	setWorkflowState(wf, v1alpha2.WorkflowStateSucceeded, now)
+}
+
+// startWorkflow records wf as started and transitions it to Running if it hasn't started running.
+// A Workflow that is being cancelled remains Cancelling while the agent winds down.
+func startWorkflow(wf *v1alpha2.Workflow, now metav1.Time) {
+	if wf.Status.StartedAt == nil {
+		wf.Status.StartedAt = &now
+	}
+	switch wf.Status.State {
+	case v1alpha2.WorkflowStatePending, v1alpha2.WorkflowStateScheduled:
+		setWorkflowState(wf, v1alpha2.WorkflowStateRunning, now)
+	}
+}
+
+func setWorkflowState(wf *v1alpha2.Workflow, state v1alpha2.WorkflowState, now metav1.Time) {
+	if wf.Status.State == state {
+		return
+	}
+	wf.Status.State = state
+	wf.Status.LastTransition = now
+}
+
+func isTerminalWorkflowState(state v1alpha2.WorkflowState) bool {
+	switch state {
+	case v1alpha2.WorkflowStateSucceeded, v1alpha2.WorkflowStateFailed, v1alpha2.WorkflowStateCanceled:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,1378 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/go-logr/zapr"
+	"github.com/google/go-cmp/cmp"
+	"github.com/tinkerbell/tink/api/v1alpha2"
+	workflowproto "github.com/tinkerbell/tink/internal/proto/workflow/v2"
+	"github.com/tinkerbell/tink/internal/ptr"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestPublishEvent(t *testing.T) {
+	cases := []struct {
+		name       string
+		workflow   *v1alpha2.Workflow
+		event      *workflowproto.Event
+		actionLogs string
+		wantStatus v1alpha2.WorkflowStatus
+		wantCode   codes.Code
+	}{
+		{
+			name:     "action started",
+			workflow: newWorkflowV2(v1alpha2.WorkflowStateScheduled, v1alpha2.ActionStatePending, v1alpha2.ActionStatePending),
+			event: &workflowproto.Event{
+				WorkflowId: "default/workflow",
+				Event: &workflowproto.Event_ActionStarted_{
+					ActionStarted: &workflowproto.Event_ActionStarted{ActionId: "action-0"},
+				},
+			},
+			wantStatus: v1alpha2.WorkflowStatus{
+				State:          v1alpha2.WorkflowStateRunning,
+				StartedAt:      TestTime.MetaV1Now(),
+				LastTransition: *TestTime.MetaV1Now(),
+				Actions: []v1alpha2.ActionStatus{
+					{
+						ID:             "action-0",
+						State:          v1alpha2.ActionStateRunning,
+						StartedAt:      TestTime.MetaV1Now(),
+						LastTransition: TestTime.MetaV1Now(),
+						Attempts: []v1alpha2.ActionAttempt{
+							{
+								State:          v1alpha2.ActionStateRunning,
+								StartedAt:      TestTime.MetaV1Now(),
+								LastTransition: TestTime.MetaV1Now(),
+							},
+						},
+					},
+					{ID: "action-1", State: v1alpha2.ActionStatePending},
+				},
+			},
+		},
+		{
+			name:     "action succeeded",
+			workflow: newWorkflowV2(v1alpha2.WorkflowStateRunning, v1alpha2.ActionStateRunning, v1alpha2.ActionStatePending),
+			event: &workflowproto.Event{
+				WorkflowId: "default/workflow",
+				Event: &workflowproto.Event_ActionSucceeded_{
+					ActionSucceeded: &workflowproto.Event_ActionSucceeded{
+						ActionId: "action-0",
+						Outputs:  map[string]string{"disk": "/dev/sda"},
+					},
+				},
+			},
+			wantStatus: v1alpha2.WorkflowStatus{
+				State: v1alpha2.WorkflowStateRunning,
+				Actions: []v1alpha2.ActionStatus{
+					{
+						ID:             "action-0",
+						State:          v1alpha2.ActionStateSucceeded,
+						LastTransition: TestTime.MetaV1Now(),
+						Outputs:        map[string]string{"disk": "/dev/sda"},
+						Attempts: []v1alpha2.ActionAttempt{
+							{State: v1alpha2.ActionStateSucceeded, LastTransition: TestTime.MetaV1Now()},
+						},
+					},
+					{ID: "action-1", State: v1alpha2.ActionStatePending},
+				},
+			},
+		},
+		{
+			name:     "last action succeeded",
+			workflow: newWorkflowV2(v1alpha2.WorkflowStateRunning, v1alpha2.ActionStateSucceeded, v1alpha2.ActionStateRunning),
+			event: &workflowproto.Event{
+				WorkflowId: "default/workflow",
+				Event: &workflowproto.Event_ActionSucceeded_{
+					ActionSucceeded: &workflowproto.Event_ActionSucceeded{ActionId: "action-1"},
+				},
+			},
+			wantStatus: v1alpha2.WorkflowStatus{
+				State:          v1alpha2.WorkflowStateSucceeded,
+				LastTransition: *TestTime.MetaV1Now(),
+				Actions: []v1alpha2.ActionStatus{
+					{ID: "action-0", State: v1alpha2.ActionStateSucceeded},
+					{
+						ID:             "action-1",
+						State:          v1alpha2.ActionStateSucceeded,
+						LastTransition: TestTime.MetaV1Now(),
+						Attempts: []v1alpha2.ActionAttempt{
+							{State: v1alpha2.ActionStateSucceeded, LastTransition: TestTime.MetaV1Now()},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "action skipped",
+			workflow: newWorkflowV2(v1alpha2.WorkflowStateRunning, v1alpha2.ActionStateSucceeded, v1alpha2.ActionStatePending, v1alpha2.ActionStatePending),
+			event: &workflowproto.Event{
+				WorkflowId: "default/workflow",
+				Event: &workflowproto.Event_ActionSkipped_{
+					ActionSkipped: &workflowproto.Event_ActionSkipped{ActionId: "action-1"},
+				},
+			},
+			wantStatus: v1alpha2.WorkflowStatus{
+				State:     v1alpha2.WorkflowStateRunning,
+				StartedAt: TestTime.MetaV1Now(),
+				Actions: []v1alpha2.ActionStatus{
+					{ID: "action-0", State: v1alpha2.ActionStateSucceeded},
+					{ID: "action-1", State: v1alpha2.ActionStateSkipped, LastTransition: TestTime.MetaV1Now()},
+					{ID: "action-2", State: v1alpha2.ActionStatePending},
+				},
+			},
+		},
+		{
+			name:     "last action skipped",
+			workflow: newWorkflowV2(v1alpha2.WorkflowStateRunning, v1alpha2.ActionStateSucceeded, v1alpha2.ActionStatePending),
+			event: &workflowproto.Event{
+				WorkflowId: "default/workflow",
+				Event: &workflowproto.Event_ActionSkipped_{
+					ActionSkipped: &workflowproto.Event_ActionSkipped{ActionId: "action-1"},
+				},
+			},
+			wantStatus: v1alpha2.WorkflowStatus{
+				State:          v1alpha2.WorkflowStateSucceeded,
+				StartedAt:      TestTime.MetaV1Now(),
+				LastTransition: *TestTime.MetaV1Now(),
+				Actions: []v1alpha2.ActionStatus{
+					{ID: "action-0", State: v1alpha2.ActionStateSucceeded},
+					{ID: "action-1", State: v1alpha2.ActionStateSkipped, LastTransition: TestTime.MetaV1Now()},
+				},
+			},
+		},
+		{
+			name:     "action started while cancelling",
+			workflow: newWorkflowV2(v1alpha2.WorkflowStateCancelling, v1alpha2.ActionStatePending),
+			event: &workflowproto.Event{
+				WorkflowId: "default/workflow",
+				Event: &workflowproto.Event_ActionStarted_{
+					ActionStarted: &workflowproto.Event_ActionStarted{ActionId: "action-0"},
+				},
+			},
+			wantStatus: v1alpha2.WorkflowStatus{
+				State:     v1alpha2.WorkflowStateCancelling,
+				StartedAt: TestTime.MetaV1Now(),
+				Actions: []v1alpha2.ActionStatus{
+					{
+						ID:             "action-0",
+						State:          v1alpha2.ActionStateRunning,
+						StartedAt:      TestTime.MetaV1Now(),
+						LastTransition: TestTime.MetaV1Now(),
+						Attempts: []v1alpha2.ActionAttempt{
+							{
+								State:          v1alpha2.ActionStateRunning,
+								StartedAt:      TestTime.MetaV1Now(),
+								LastTransition: TestTime.MetaV1Now(),
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "action skipped while cancelling",
+			workflow: newWorkflowV2(v1alpha2.WorkflowStateCancelling, v1alpha2.ActionStatePending, v1alpha2.ActionStatePending),
+			event: &workflowproto.Event{
+				WorkflowId: "default/workflow",
+				Event: &workflowproto.Event_ActionSkipped_{
+					ActionSkipped: &workflowproto.Event_ActionSkipped{ActionId: "action-0"},
+				},
+			},
+			wantStatus: v1alpha2.WorkflowStatus{
+				State:     v1alpha2.WorkflowStateCancelling,
+				StartedAt: TestTime.MetaV1Now(),
+				Actions: []v1alpha2.ActionStatus{
+					{ID: "action-0", State: v1alpha2.ActionStateSkipped, LastTransition: TestTime.MetaV1Now()},
+					{ID: "action-1", State: v1alpha2.ActionStatePending},
+				},
+			},
+		},
+		{
+			name:     "last action succeeded while cancelling",
+			workflow: newWorkflowV2(v1alpha2.WorkflowStateCancelling, v1alpha2.ActionStateRunning),
+			event: &workflowproto.Event{
+				WorkflowId: "default/workflow",
+				Event: &workflowproto.Event_ActionSucceeded_{
+					ActionSucceeded: &workflowproto.Event_ActionSucceeded{ActionId: "action-0"},
+				},
+			},
+			wantStatus: v1alpha2.WorkflowStatus{
+				State: v1alpha2.WorkflowStateCancelling,
+				Actions: []v1alpha2.ActionStatus{
+					{
+						ID:             "action-0",
+						State:          v1alpha2.ActionStateSucceeded,
+						LastTransition: TestTime.MetaV1Now(),
+						Attempts: []v1alpha2.ActionAttempt{
+							{State: v1alpha2.ActionStateSucceeded, LastTransition: TestTime.MetaV1Now()},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "action failed",
+			workflow: newWorkflowV2(v1alpha2.WorkflowStateRunning, v1alpha2.ActionStateRunning, v1alpha2.ActionStatePending),
+			event: &workflowproto.Event{
+				WorkflowId: "default/workflow",
+				Event: &workflowproto.Event_ActionFailed_{
+					ActionFailed: &workflowproto.Event_ActionFailed{
+						ActionId:       "action-0",
+						FailureReason:  ptr.String("DiskNotFound"),
+						FailureMessage: ptr.String("no disk"),
+					},
+				},
+			},
+			actionLogs: "searching for /dev/sda\n",
+			wantStatus: v1alpha2.WorkflowStatus{
+				State:          v1alpha2.WorkflowStateFailed,
+				LastTransition: *TestTime.MetaV1Now(),
+				Actions: []v1alpha2.ActionStatus{
+					{
+						ID:             "action-0",
+						State:          v1alpha2.ActionStateFailed,
+						LastTransition: TestTime.MetaV1Now(),
+						FailureReason:  "DiskNotFound",
+						FailureMessage: "no disk",
+						Logs:           "searching for /dev/sda\n",
+						Attempts: []v1alpha2.ActionAttempt{
+							{
+								State:          v1alpha2.ActionStateFailed,
+								LastTransition: TestTime.MetaV1Now(),
+								FailureReason:  "DiskNotFound",
+								FailureMessage: "no disk",
+							},
+						},
+					},
+					{ID: "action-1", State: v1alpha2.ActionStatePending},
+				},
+			},
+		},
+		{
+			name:     "action failed will retry",
+			workflow: newWorkflowV2(v1alpha2.WorkflowStateRunning, v1alpha2.ActionStateRunning),
+			event: &workflowproto.Event{
+				WorkflowId: "default/workflow",
+				Event: &workflowproto.Event_ActionFailed_{
+					ActionFailed: &workflowproto.Event_ActionFailed{
+						ActionId:       "action-0",
+						FailureReason:  ptr.String("NetworkError"),
+						FailureMessage: ptr.String("connection reset"),
+						Attempt:        1,
+						WillRetry:      true,
+					},
+				},
+			},
+			wantStatus: v1alpha2.WorkflowStatus{
+				State: v1alpha2.WorkflowStateRunning,
+				Actions: []v1alpha2.ActionStatus{
+					{
+						ID:    "action-0",
+						State: v1alpha2.ActionStateRunning,
+						Attempts: []v1alpha2.ActionAttempt{
+							{
+								State:          v1alpha2.ActionStateFailed,
+								LastTransition: TestTime.MetaV1Now(),
+								FailureReason:  "NetworkError",
+								FailureMessage: "connection reset",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "retry attempt started",
+			workflow: func() *v1alpha2.Workflow {
+				wf := newWorkflowV2(v1alpha2.WorkflowStateRunning, v1alpha2.ActionStateRunning)
+				wf.Status.Actions[0].Attempts = []v1alpha2.ActionAttempt{{State: v1alpha2.ActionStateFailed}}
+				return wf
+			}(),
+			event: &workflowproto.Event{
+				WorkflowId: "default/workflow",
+				Event: &workflowproto.Event_ActionStarted_{
+					ActionStarted: &workflowproto.Event_ActionStarted{ActionId: "action-0", Attempt: 2},
+				},
+			},
+			wantStatus: v1alpha2.WorkflowStatus{
+				State:     v1alpha2.WorkflowStateRunning,
+				StartedAt: TestTime.MetaV1Now(),
+				Actions: []v1alpha2.ActionStatus{
+					{
+						ID:             "action-0",
+						State:          v1alpha2.ActionStateRunning,
+						StartedAt:      TestTime.MetaV1Now(),
+						LastTransition: TestTime.MetaV1Now(),
+						Attempts: []v1alpha2.ActionAttempt{
+							{State: v1alpha2.ActionStateFailed},
+							{
+								State:          v1alpha2.ActionStateRunning,
+								StartedAt:      TestTime.MetaV1Now(),
+								LastTransition: TestTime.MetaV1Now(),
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "workflow rejected",
+			workflow: newWorkflowV2(v1alpha2.WorkflowStateScheduled, v1alpha2.ActionStatePending),
+			event: &workflowproto.Event{
+				WorkflowId: "default/workflow",
+				Event: &workflowproto.Event_WorkflowRejected_{
+					WorkflowRejected: &workflowproto.Event_WorkflowRejected{Message: "busy"},
+				},
+			},
+			wantStatus: v1alpha2.WorkflowStatus{
+				State:          v1alpha2.WorkflowStateFailed,
+				LastTransition: *TestTime.MetaV1Now(),
+				Actions: []v1alpha2.ActionStatus{
+					{ID: "action-0", State: v1alpha2.ActionStatePending},
+				},
+			},
+		},
+		{
+			name:     "duplicate action started",
+			workflow: newWorkflowV2(v1alpha2.WorkflowStateRunning, v1alpha2.ActionStateRunning),
+			event: &workflowproto.Event{
+				WorkflowId: "default/workflow",
+				Event: &workflowproto.Event_ActionStarted_{
+					ActionStarted: &workflowproto.Event_ActionStarted{ActionId: "action-0"},
+				},
+			},
+			wantStatus: v1alpha2.WorkflowStatus{
+				State: v1alpha2.WorkflowStateRunning,
+				Actions: []v1alpha2.ActionStatus{
+					{ID: "action-0", State: v1alpha2.ActionStateRunning},
+				},
+			},
+		},
+		{
+			name:     "terminal workflow",
+			workflow: newWorkflowV2(v1alpha2.WorkflowStateCanceled, v1alpha2.ActionStateRunning),
+			event: &workflowproto.Event{
+				WorkflowId: "default/workflow",
+				Event: &workflowproto.Event_ActionFailed_{
+					ActionFailed: &workflowproto.Event_ActionFailed{ActionId: "action-0"},
+				},
+			},
+			wantStatus: v1alpha2.WorkflowStatus{
+				State: v1alpha2.WorkflowStateCanceled,
+				Actions: []v1alpha2.ActionStatus{
+					{ID: "action-0", State: v1alpha2.ActionStateRunning},
+				},
+			},
+		},
+		{
+			name:     "unknown action",
+			workflow: newWorkflowV2(v1alpha2.WorkflowStateRunning, v1alpha2.ActionStateRunning),
+			event: &workflowproto.Event{
+				WorkflowId: "default/workflow",
+				Event: &workflowproto.Event_ActionStarted_{
+					ActionStarted: &workflowproto.Event_ActionStarted{ActionId: "unknown"},
+				},
+			},
+			wantCode: codes.NotFound,
+		},
+		{
+			name:     "unknown workflow",
+			workflow: newWorkflowV2(v1alpha2.WorkflowStateRunning, v1alpha2.ActionStateRunning),
+			event: &workflowproto.Event{
+				WorkflowId: "default/unknown",
+				Event: &workflowproto.Event_ActionStarted_{
+					ActionStarted: &workflowproto.Event_ActionStarted{ActionId: "action-0"},
+				},
+			},
+			wantCode: codes.NotFound,
+		},
+		{
+			name: "action failed with handlers",
+			workflow: withHandlers(
+				newWorkflowV2(v1alpha2.WorkflowStateRunning, v1alpha2.ActionStateRunning),
+				[]v1alpha2.ActionState{v1alpha2.ActionStatePending},
+				[]v1alpha2.ActionState{v1alpha2.ActionStatePending},
+			),
+			event: &workflowproto.Event{
+				WorkflowId: "default/workflow",
+				Event: &workflowproto.Event_ActionFailed_{
+					ActionFailed: &workflowproto.Event_ActionFailed{ActionId: "action-0"},
+				},
+			},
+			wantStatus: v1alpha2.WorkflowStatus{
+				State: v1alpha2.WorkflowStateRunning,
+				Actions: []v1alpha2.ActionStatus{
+					{
+						ID:             "action-0",
+						State:          v1alpha2.ActionStateFailed,
+						LastTransition: TestTime.MetaV1Now(),
+						Attempts: []v1alpha2.ActionAttempt{
+							{State: v1alpha2.ActionStateFailed, LastTransition: TestTime.MetaV1Now()},
+						},
+					},
+				},
+				OnFailure: []v1alpha2.ActionStatus{{ID: "on-failure-0", State: v1alpha2.ActionStatePending}},
+				Finally:   []v1alpha2.ActionStatus{{ID: "finally-0", State: v1alpha2.ActionStatePending}},
+			},
+		},
+		{
+			name: "finally action succeeded after failure",
+			workflow: withHandlers(
+				newWorkflowV2(v1alpha2.WorkflowStateRunning, v1alpha2.ActionStateFailed),
+				[]v1alpha2.ActionState{v1alpha2.ActionStateSucceeded},
+				[]v1alpha2.ActionState{v1alpha2.ActionStateRunning},
+			),
+			event: &workflowproto.Event{
+				WorkflowId: "default/workflow",
+				Event: &workflowproto.Event_ActionSucceeded_{
+					ActionSucceeded: &workflowproto.Event_ActionSucceeded{ActionId: "finally-0"},
+				},
+			},
+			wantStatus: v1alpha2.WorkflowStatus{
+				State:          v1alpha2.WorkflowStateFailed,
+				LastTransition: *TestTime.MetaV1Now(),
+				Actions:        []v1alpha2.ActionStatus{{ID: "action-0", State: v1alpha2.ActionStateFailed}},
+				OnFailure:      []v1alpha2.ActionStatus{{ID: "on-failure-0", State: v1alpha2.ActionStateSucceeded}},
+				Finally: []v1alpha2.ActionStatus{
+					{
+						ID:             "finally-0",
+						State:          v1alpha2.ActionStateSucceeded,
+						LastTransition: TestTime.MetaV1Now(),
+						Attempts: []v1alpha2.ActionAttempt{
+							{State: v1alpha2.ActionStateSucceeded, LastTransition: TestTime.MetaV1Now()},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "finally action succeeded",
+			workflow: withHandlers(
+				newWorkflowV2(v1alpha2.WorkflowStateRunning, v1alpha2.ActionStateSucceeded),
+				[]v1alpha2.ActionState{v1alpha2.ActionStatePending},
+				[]v1alpha2.ActionState{v1alpha2.ActionStateRunning},
+			),
+			event: &workflowproto.Event{
+				WorkflowId: "default/workflow",
+				Event: &workflowproto.Event_ActionSucceeded_{
+					ActionSucceeded: &workflowproto.Event_ActionSucceeded{ActionId: "finally-0"},
+				},
+			},
+			wantStatus: v1alpha2.WorkflowStatus{
+				State:          v1alpha2.WorkflowStateSucceeded,
+				LastTransition: *TestTime.MetaV1Now(),
+				Actions:        []v1alpha2.ActionStatus{{ID: "action-0", State: v1alpha2.ActionStateSucceeded}},
+				OnFailure:      []v1alpha2.ActionStatus{{ID: "on-failure-0", State: v1alpha2.ActionStatePending}},
+				Finally: []v1alpha2.ActionStatus{
+					{
+						ID:             "finally-0",
+						State:          v1alpha2.ActionStateSucceeded,
+						LastTransition: TestTime.MetaV1Now(),
+						Attempts: []v1alpha2.ActionAttempt{
+							{State: v1alpha2.ActionStateSucceeded, LastTransition: TestTime.MetaV1Now()},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "finally action failed",
+			workflow: withHandlers(
+				newWorkflowV2(v1alpha2.WorkflowStateRunning, v1alpha2.ActionStateSucceeded),
+				nil,
+				[]v1alpha2.ActionState{v1alpha2.ActionStateRunning},
+			),
+			event: &workflowproto.Event{
+				WorkflowId: "default/workflow",
+				Event: &workflowproto.Event_ActionFailed_{
+					ActionFailed: &workflowproto.Event_ActionFailed{ActionId: "finally-0"},
+				},
+			},
+			wantStatus: v1alpha2.WorkflowStatus{
+				State:          v1alpha2.WorkflowStateFailed,
+				LastTransition: *TestTime.MetaV1Now(),
+				Actions:        []v1alpha2.ActionStatus{{ID: "action-0", State: v1alpha2.ActionStateSucceeded}},
+				Finally: []v1alpha2.ActionStatus{
+					{
+						ID:             "finally-0",
+						State:          v1alpha2.ActionStateFailed,
+						LastTransition: TestTime.MetaV1Now(),
+						Attempts: []v1alpha2.ActionAttempt{
+							{State: v1alpha2.ActionStateFailed, LastTransition: TestTime.MetaV1Now()},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "action paused at breakpoint",
+			workflow: withBreakpoints(newWorkflowV2(v1alpha2.WorkflowStateRunning, v1alpha2.ActionStateSucceeded, v1alpha2.ActionStatePending), "action-1"),
+			event: &workflowproto.Event{
+				WorkflowId: "default/workflow",
+				Event: &workflowproto.Event_ActionPaused_{
+					ActionPaused: &workflowproto.Event_ActionPaused{ActionId: "action-1"},
+				},
+			},
+			wantStatus: v1alpha2.WorkflowStatus{
+				State: v1alpha2.WorkflowStateRunning,
+				Actions: []v1alpha2.ActionStatus{
+					{ID: "action-0", Rendered: v1alpha2.Action{Name: "action-0"}, State: v1alpha2.ActionStateSucceeded},
+					{
+						ID:             "action-1",
+						Rendered:       v1alpha2.Action{Name: "action-1"},
+						State:          v1alpha2.ActionStatePaused,
+						LastTransition: TestTime.MetaV1Now(),
+					},
+				},
+				Conditions: v1alpha2.Conditions{
+					{
+						Type:           v1alpha2.WorkflowConditionPaused,
+						Status:         v1alpha2.ConditionStatusTrue,
+						LastTransition: *TestTime.MetaV1Now(),
+						Reason:         ptr.String("Breakpoint"),
+						Message:        ptr.String("workflow paused at breakpoint before action action-1"),
+					},
+				},
+			},
+		},
+		{
+			name: "paused action started",
+			workflow: func() *v1alpha2.Workflow {
+				wf := withBreakpoints(newWorkflowV2(v1alpha2.WorkflowStateRunning, v1alpha2.ActionStatePaused))
+				wf.Status.Conditions = v1alpha2.Conditions{
+					{
+						Type:    v1alpha2.WorkflowConditionPaused,
+						Status:  v1alpha2.ConditionStatusTrue,
+						Reason:  ptr.String("Paused"),
+						Message: ptr.String("workflow paused before action action-0"),
+					},
+				}
+				return wf
+			}(),
+			event: &workflowproto.Event{
+				WorkflowId: "default/workflow",
+				Event: &workflowproto.Event_ActionStarted_{
+					ActionStarted: &workflowproto.Event_ActionStarted{ActionId: "action-0"},
+				},
+			},
+			wantStatus: v1alpha2.WorkflowStatus{
+				State:     v1alpha2.WorkflowStateRunning,
+				StartedAt: TestTime.MetaV1Now(),
+				Actions: []v1alpha2.ActionStatus{
+					{
+						ID:             "action-0",
+						Rendered:       v1alpha2.Action{Name: "action-0"},
+						State:          v1alpha2.ActionStateRunning,
+						StartedAt:      TestTime.MetaV1Now(),
+						LastTransition: TestTime.MetaV1Now(),
+						Attempts: []v1alpha2.ActionAttempt{
+							{
+								State:          v1alpha2.ActionStateRunning,
+								StartedAt:      TestTime.MetaV1Now(),
+								LastTransition: TestTime.MetaV1Now(),
+							},
+						},
+					},
+				},
+				Conditions: v1alpha2.Conditions{
+					{
+						Type:           v1alpha2.WorkflowConditionPaused,
+						Status:         v1alpha2.ConditionStatusFalse,
+						LastTransition: *TestTime.MetaV1Now(),
+						Reason:         ptr.String("Resumed"),
+						Message:        ptr.String("workflow resumed"),
+					},
+				},
+			},
+		},
+		{
+			name:     "image pulled",
+			workflow: newWorkflowV2(v1alpha2.WorkflowStateScheduled, v1alpha2.ActionStatePending),
+			event: &workflowproto.Event{
+				WorkflowId: "default/workflow",
+				Event: &workflowproto.Event_ImagePulled_{
+					ImagePulled: &workflowproto.Event_ImagePulled{Image: "image", Pulled: 1, Total: 2},
+				},
+			},
+			wantStatus: v1alpha2.WorkflowStatus{
+				State: v1alpha2.WorkflowStateScheduled,
+				Actions: []v1alpha2.ActionStatus{
+					{ID: "action-0", State: v1alpha2.ActionStatePending},
+				},
+				Conditions: v1alpha2.Conditions{
+					{
+						Type:           v1alpha2.WorkflowConditionImagesPulled,
+						Status:         v1alpha2.ConditionStatusFalse,
+						LastTransition: *TestTime.MetaV1Now(),
+						Reason:         ptr.String("Pulling"),
+						Message:        ptr.String("pulled 1 of 2 images"),
+					},
+				},
+			},
+		},
+		{
+			name: "all images pulled",
+			workflow: func() *v1alpha2.Workflow {
+				wf := newWorkflowV2(v1alpha2.WorkflowStateScheduled, v1alpha2.ActionStatePending)
+				wf.Status.Conditions = v1alpha2.Conditions{
+					{
+						Type:    v1alpha2.WorkflowConditionImagesPulled,
+						Status:  v1alpha2.ConditionStatusFalse,
+						Reason:  ptr.String("Pulling"),
+						Message: ptr.String("pulled 1 of 2 images"),
+					},
+				}
+				return wf
+			}(),
+			event: &workflowproto.Event{
+				WorkflowId: "default/workflow",
+				Event: &workflowproto.Event_ImagePulled_{
+					ImagePulled: &workflowproto.Event_ImagePulled{Image: "image", Pulled: 2, Total: 2},
+				},
+			},
+			wantStatus: v1alpha2.WorkflowStatus{
+				State: v1alpha2.WorkflowStateScheduled,
+				Actions: []v1alpha2.ActionStatus{
+					{ID: "action-0", State: v1alpha2.ActionStatePending},
+				},
+				Conditions: v1alpha2.Conditions{
+					{
+						Type:           v1alpha2.WorkflowConditionImagesPulled,
+						Status:         v1alpha2.ConditionStatusTrue,
+						LastTransition: *TestTime.MetaV1Now(),
+						Reason:         ptr.String("Pulled"),
+						Message:        ptr.String("pulled 2 of 2 images"),
+					},
+				},
+			},
+		},
+		{
+			name:     "image pull failed",
+			workflow: newWorkflowV2(v1alpha2.WorkflowStateScheduled, v1alpha2.ActionStatePending),
+			event: &workflowproto.Event{
+				WorkflowId: "default/workflow",
+				Event: &workflowproto.Event_ImagePullFailed_{
+					ImagePullFailed: &workflowproto.Event_ImagePullFailed{Image: "image", Message: "manifest unknown"},
+				},
+			},
+			wantStatus: v1alpha2.WorkflowStatus{
+				State:          v1alpha2.WorkflowStateFailed,
+				LastTransition: *TestTime.MetaV1Now(),
+				Actions: []v1alpha2.ActionStatus{
+					{ID: "action-0", State: v1alpha2.ActionStatePending},
+				},
+				Conditions: v1alpha2.Conditions{
+					{
+						Type:           v1alpha2.WorkflowConditionImagesPulled,
+						Status:         v1alpha2.ConditionStatusFalse,
+						LastTransition: *TestTime.MetaV1Now(),
+						Reason:         ptr.String("PullFailed"),
+						Message:        ptr.String("pull image image: manifest unknown"),
+					},
+				},
+			},
+		},
+		{
+			name:     "missing workflow id",
+			workflow: newWorkflowV2(v1alpha2.WorkflowStateRunning, v1alpha2.ActionStateRunning),
+			event: &workflowproto.Event{
+				Event: &workflowproto.Event_ActionStarted_{
+					ActionStarted: &workflowproto.Event_ActionStarted{ActionId: "action-0"},
+				},
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "missing event",
+			workflow: newWorkflowV2(v1alpha2.WorkflowStateRunning, v1alpha2.ActionStateRunning),
+			event: &workflowproto.Event{
+				WorkflowId: "default/workflow",
+			},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := newWorkflowV2Server(tc.workflow)
+			server.actionLogs.Append("default/workflow", "action-0", logEntry{Data: []byte(tc.actionLogs)})
+
+			_, err := server.PublishEvent(context.Background(), &workflowproto.PublishEventRequest{Event: tc.event})
+			if got := status.Code(err); got != tc.wantCode {
+				t.Fatalf("Unexpected code: got %v, want %v (%v)", got, tc.wantCode, err)
+			}
+			if tc.wantCode != codes.OK {
+				return
+			}
+
+			var got v1alpha2.Workflow
+			if err := server.ClientFunc().Get(context.Background(), client.ObjectKeyFromObject(tc.workflow), &got); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.wantStatus, got.Status); diff != "" {
+				t.Errorf("unexpected difference:\n%v", diff)
+			}
+		})
+	}
+}
+
+func TestPublishEventReplayed(t *testing.T) {
+	wf := newWorkflowV2(v1alpha2.WorkflowStateScheduled, v1alpha2.ActionStatePending, v1alpha2.ActionStatePending)
+	server := newWorkflowV2Server(wf)
+
+	started := func(actionID string) *workflowproto.Event {
+		return &workflowproto.Event{
+			WorkflowId: "default/workflow",
+			Event: &workflowproto.Event_ActionStarted_{
+				ActionStarted: &workflowproto.Event_ActionStarted{ActionId: actionID},
+			},
+		}
+	}
+
+	publish := func(server *KubernetesBackedServer, session string, sequence uint64, event *workflowproto.Event) v1alpha2.WorkflowStatus {
+		t.Helper()
+		_, err := server.PublishEvent(context.Background(), &workflowproto.PublishEventRequest{
+			Event:     event,
+			SessionId: session,
+			Sequence:  sequence,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got v1alpha2.Workflow
+		if err := server.ClientFunc().Get(context.Background(), client.ObjectKeyFromObject(wf), &got); err != nil {
+			t.Fatal(err)
+		}
+		return got.Status
+	}
+
+	got := publish(server, "session", 1, started("action-0"))
+	if diff := cmp.Diff(&v1alpha2.EventSequence{Session: "session", Sequence: 1}, got.LastEvent); diff != "" {
+		t.Fatalf("Unexpected last event:\n%v", diff)
+	}
+
+	// Replays of an applied sequence number are acknowledged without being applied, including
+	// after the server restarts.
+	restarted := &KubernetesBackedServer{
+		logger:     server.logger,
+		ClientFunc: server.ClientFunc,
+		nowFunc:    server.nowFunc,
+	}
+	got = publish(restarted, "session", 1, started("action-1"))
+	if got.Actions[1].State != v1alpha2.ActionStatePending {
+		t.Fatalf("Unexpected application of replayed event: %+v", got.Actions[1])
+	}
+
+	// Sequence numbers are tracked per session.
+	got = publish(restarted, "other", 1, started("action-1"))
+	for _, action := range got.Actions {
+		if action.State != v1alpha2.ActionStateRunning || len(action.Attempts) != 1 {
+			t.Fatalf("Unexpected action status: %+v", action)
+		}
+	}
+}
+
+func TestDispatchWorkflows(t *testing.T) {
+	hw := &v1alpha2.Hardware{
+		ObjectMeta: metav1.ObjectMeta{Name: "hardware", Namespace: "default"},
+		Spec: v1alpha2.HardwareSpec{
+			NetworkInterfaces: v1alpha2.NetworkInterfaces{"00:00:00:00:00:01": {}},
+		},
+	}
+	wf := newWorkflowV2(v1alpha2.WorkflowStatePending, v1alpha2.ActionStatePending, v1alpha2.ActionStatePending)
+	wf.Status.Actions[0].Rendered = v1alpha2.Action{
+		Name:       "action",
+		Image:      "image",
+		Cmd:        ptr.String("/bin/sh"),
+		Args:       []string{"-c", "true"},
+		Env:        map[string]string{"FOO": "BAR"},
+		Volumes:    []v1alpha2.Volume{"/tmp:/tmp:ro"},
+		Namespace:  &v1alpha2.Namespace{Network: ptr.String("host"), PID: ptr.Int(1)},
+		Privileged: true,
+		Capabilities: &v1alpha2.Capabilities{
+			Add:  []string{"SYS_ADMIN"},
+			Drop: []string{"NET_RAW"},
+		},
+		Devices: []v1alpha2.Device{"/dev/sda"},
+		Resources: &v1alpha2.ResourceLimits{
+			Memory: quantity("512Mi"),
+			CPU:    quantity("500m"),
+			PIDs:   ptr.Int64(100),
+		},
+	}
+	wf.Status.Actions[1].Rendered = v1alpha2.Action{
+		Name:           "dependent",
+		Image:          "image",
+		DependsOn:      []string{"action"},
+		TimeoutSeconds: 60,
+		When:           `eq .Param.disk "/dev/sda"`,
+	}
+	wf.Spec.TemplateParams = map[string]string{"disk": "/dev/sda"}
+	withHandlers(wf, nil, []v1alpha2.ActionState{v1alpha2.ActionStatePending})
+	wf.Status.Finally[0].Rendered = v1alpha2.Action{Name: "reset", Image: "image"}
+
+	server := newWorkflowV2Server(wf, hw)
+	ctx := context.Background()
+	logger := server.logger
+	stream := &getWorkflowsStream{ctx: ctx}
+	dispatched := map[string]*dispatchedWorkflow{}
+
+	got, err := server.getHardwareForAgent(ctx, "00:00:00:00:00:01")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A Pending workflow should be scheduled and started.
+	if err := server.dispatchWorkflows(ctx, logger, got, stream, dispatched); err != nil {
+		t.Fatal(err)
+	}
+	want := []*workflowproto.GetWorkflowsResponse{
+		{
+			Cmd: &workflowproto.GetWorkflowsResponse_StartWorkflow_{
+				StartWorkflow: &workflowproto.GetWorkflowsResponse_StartWorkflow{
+					Workflow: &workflowproto.Workflow{
+						WorkflowId: "default/workflow",
+						Actions: []*workflowproto.Workflow_Action{
+							{
+								Id:               "action-0",
+								Name:             "action",
+								Image:            "image",
+								Cmd:              ptr.String("/bin/sh"),
+								Args:             []string{"-c", "true"},
+								Env:              map[string]string{"FOO": "BAR"},
+								Volumes:          []string{"/tmp:/tmp:ro"},
+								NetworkNamespace: ptr.String("host"),
+								PidNamespace:     ptr.String("host"),
+								Privileged:       true,
+								Capabilities: &workflowproto.Workflow_Capabilities{
+									Add:  []string{"SYS_ADMIN"},
+									Drop: []string{"NET_RAW"},
+								},
+								Devices: []string{"/dev/sda"},
+								Resources: &workflowproto.Workflow_ResourceLimits{
+									MemoryBytes: 512 << 20,
+									NanoCpus:    500_000_000,
+									Pids:        100,
+								},
+							},
+							{
+								Id:             "action-1",
+								Name:           "dependent",
+								Image:          "image",
+								DependsOn:      []string{"action-0"},
+								TimeoutSeconds: 60,
+								When:           ptr.String(`eq .Param.disk "/dev/sda"`),
+							},
+						},
+						ConditionData: mustStruct(t, map[string]any{
+							"Hardware": map[string]any{
+								"networkInterfaces": map[string]any{"00:00:00:00:00:01": map[string]any{}},
+								"osie":              map[string]any{},
+							},
+							"Param": map[string]any{"disk": "/dev/sda"},
+						}),
+						Finally: []*workflowproto.Workflow_Action{
+							{Id: "finally-0", Name: "reset", Image: "image"},
+						},
+					},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, stream.sent, protocmp.Transform()); diff != "" {
+		t.Fatalf("unexpected difference:\n%v", diff)
+	}
+
+	var stored v1alpha2.Workflow
+	if err := server.ClientFunc().Get(ctx, client.ObjectKeyFromObject(wf), &stored); err != nil {
+		t.Fatal(err)
+	}
+	if stored.Status.State != v1alpha2.WorkflowStateScheduled {
+		t.Fatalf("Expected state %v; received %v", v1alpha2.WorkflowStateScheduled, stored.Status.State)
+	}
+
+	// Re-evaluating shouldn't re-dispatch the workflow.
+	if err := server.dispatchWorkflows(ctx, logger, got, stream, dispatched); err != nil {
+		t.Fatal(err)
+	}
+	if len(stream.sent) != 1 {
+		t.Fatalf("Expected 1 message; received %v", len(stream.sent))
+	}
+
+	// Setting a breakpoint should pause the workflow on the agent.
+	stored.Spec.Breakpoints = []string{"dependent", "reset"}
+	if err := server.ClientFunc().Update(ctx, &stored); err != nil {
+		t.Fatal(err)
+	}
+	if err := server.dispatchWorkflows(ctx, logger, got, stream, dispatched); err != nil {
+		t.Fatal(err)
+	}
+	wantPause := &workflowproto.GetWorkflowsResponse{
+		Cmd: &workflowproto.GetWorkflowsResponse_PauseWorkflow_{
+			PauseWorkflow: &workflowproto.GetWorkflowsResponse_PauseWorkflow{
+				WorkflowId: "default/workflow",
+				Pause:      &workflowproto.Workflow_Pause{Breakpoints: []string{"action-1", "finally-0"}},
+			},
+		},
+	}
+	if len(stream.sent) != 2 {
+		t.Fatalf("Expected 2 messages; received %v", len(stream.sent))
+	}
+	if diff := cmp.Diff(wantPause, stream.sent[1], protocmp.Transform()); diff != "" {
+		t.Fatalf("unexpected difference:\n%v", diff)
+	}
+
+	// Re-evaluating shouldn't re-send an unchanged pause.
+	if err := server.dispatchWorkflows(ctx, logger, got, stream, dispatched); err != nil {
+		t.Fatal(err)
+	}
+	if len(stream.sent) != 2 {
+		t.Fatalf("Expected 2 messages; received %v", len(stream.sent))
+	}
+
+	// Cancelling the workflow should stop it on the agent.
+	stored.Status.State = v1alpha2.WorkflowStateCancelling
+	if err := server.ClientFunc().Status().Update(ctx, &stored); err != nil {
+		t.Fatal(err)
+	}
+	if err := server.dispatchWorkflows(ctx, logger, got, stream, dispatched); err != nil {
+		t.Fatal(err)
+	}
+	wantStop := &workflowproto.GetWorkflowsResponse{
+		Cmd: &workflowproto.GetWorkflowsResponse_StopWorkflow_{
+			StopWorkflow: &workflowproto.GetWorkflowsResponse_StopWorkflow{WorkflowId: "default/workflow"},
+		},
+	}
+	if len(stream.sent) != 3 {
+		t.Fatalf("Expected 3 messages; received %v", len(stream.sent))
+	}
+	if diff := cmp.Diff(wantStop, stream.sent[2], protocmp.Transform()); diff != "" {
+		t.Fatalf("unexpected difference:\n%v", diff)
+	}
+}
+
+func TestGetWorkflowsUnknownAgent(t *testing.T) {
+	server := newWorkflowV2Server()
+	stream := &getWorkflowsStream{ctx: context.Background()}
+
+	err := server.GetWorkflows(&workflowproto.GetWorkflowsRequest{AgentId: "00:00:00:00:00:01"}, stream)
+	if got := status.Code(err); got != codes.NotFound {
+		t.Fatalf("Unexpected code: got %v, want %v", got, codes.NotFound)
+	}
+
+	err = server.GetWorkflows(&workflowproto.GetWorkflowsRequest{}, stream)
+	if got := status.Code(err); got != codes.InvalidArgument {
+		t.Fatalf("Unexpected code: got %v, want %v", got, codes.InvalidArgument)
+	}
+}
+
+func TestNotifier(t *testing.T) {
+	n := newNotifier()
+	ch, unsubscribe := n.Subscribe("key")
+
+	// Notifications should be coalesced and never block.
+	n.Notify("key")
+	n.Notify("key")
+	n.Notify("other")
+
+	select {
+	case <-ch:
+	case <-time.After(time.Second):
+		t.Fatal("Expected notification")
+	}
+	select {
+	case <-ch:
+		t.Fatal("Unexpected notification")
+	default:
+	}
+
+	unsubscribe()
+	n.Notify("key")
+	select {
+	case <-ch:
+		t.Fatal("Unexpected notification after unsubscribe")
+	default:
+	}
+}
+
+// getWorkflowsStream is a fake GetWorkflows server stream that records sent messages.
+type getWorkflowsStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent []*workflowproto.GetWorkflowsResponse
+}
+
+func (s *getWorkflowsStream) Send(r *workflowproto.GetWorkflowsResponse) error {
+	s.sent = append(s.sent, r)
+	return nil
+}
+
+func (s *getWorkflowsStream) Context() context.Context {
+	return s.ctx
+}
+
+func mustStruct(t *testing.T, v map[string]any) *structpb.Struct {
+	t.Helper()
+	s, err := structpb.NewStruct(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func newWorkflowV2Server(objs ...client.Object) *KubernetesBackedServer {
+	scheme := runtime.NewScheme()
+	_ = v1alpha2.AddToScheme(scheme)
+
+	clnt := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(&v1alpha2.Workflow{}).
+		WithIndex(&v1alpha2.Hardware{}, hardwareByMACAddr, hardwareByMACAddrFunc).
+		WithIndex(&v1alpha2.Workflow{}, workflowByHardwareRef, workflowByHardwareRefFunc).
+		Build()
+
+	return &KubernetesBackedServer{
+		logger:     zapr.NewLogger(zap.Must(zap.NewDevelopment())),
+		ClientFunc: func() client.Client { return clnt },
+		nowFunc:    TestTime.Now,
+		workflowV2: newNotifier(),
+		actionLogs: newActionLogStore(0, 0),
+	}
+}
+
+// newWorkflowV2 creates a Workflow in state with an action for each actionStates.
+func newWorkflowV2(state v1alpha2.WorkflowState, actionStates ...v1alpha2.ActionState) *v1alpha2.Workflow {
+	wf := &v1alpha2.Workflow{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "workflow",
+			Namespace: "default",
+			UID:       types.UID("workflow"),
+		},
+		Spec: v1alpha2.WorkflowSpec{
+			HardwareRef: corev1.LocalObjectReference{Name: "hardware"},
+		},
+		Status: v1alpha2.WorkflowStatus{
+			State: state,
+		},
+	}
+	for i, s := range actionStates {
+		wf.Status.Actions = append(wf.Status.Actions, v1alpha2.ActionStatus{
+			ID:    fmt.Sprintf("action-%d", i),
+			State: s,
+		})
+	}
+	return wf
+}
+
+// withHandlers adds an OnFailure action to wf for each onFailure state and a Finally action for
+// each finally state.
+func withHandlers(wf *v1alpha2.Workflow, onFailure, finally []v1alpha2.ActionState) *v1alpha2.Workflow {
+	for i, s := range onFailure {
+		wf.Status.OnFailure = append(wf.Status.OnFailure, v1alpha2.ActionStatus{
+			ID:    fmt.Sprintf("on-failure-%d", i),
+			State: s,
+		})
+	}
+	for i, s := range finally {
+		wf.Status.Finally = append(wf.Status.Finally, v1alpha2.ActionStatus{
+			ID:    fmt.Sprintf("finally-%d", i),
+			State: s,
+		})
+	}
+	return wf
+}
+
+// withBreakpoints names the actions of wf after their IDs and sets breakpoints on wf.
+func withBreakpoints(wf *v1alpha2.Workflow, breakpoints ...string) *v1alpha2.Workflow {
+	for i := range wf.Status.Actions {
+		wf.Status.Actions[i].Rendered.Name = wf.Status.Actions[i].ID
+	}
+	wf.Spec.Breakpoints = breakpoints
+	return wf
+}
+
+type publishActionLogsStream struct {
+	grpc.ServerStream
+	reqs []*workflowproto.PublishActionLogsRequest
+	resp *workflowproto.PublishActionLogsResponse
+}
+
+func (s *publishActionLogsStream) Recv() (*workflowproto.PublishActionLogsRequest, error) {
+	if len(s.reqs) == 0 {
+		return nil, io.EOF
+	}
+	req := s.reqs[0]
+	s.reqs = s.reqs[1:]
+	return req, nil
+}
+
+func (s *publishActionLogsStream) SendAndClose(resp *workflowproto.PublishActionLogsResponse) error {
+	s.resp = resp
+	return nil
+}
+
+func TestPublishAndListActionLogs(t *testing.T) {
+	server := newWorkflowV2Server()
+
+	stream := &publishActionLogsStream{
+		reqs: []*workflowproto.PublishActionLogsRequest{
+			{Log: &workflowproto.ActionLog{WorkflowId: "default/workflow", ActionId: "action-0", Data: []byte("out")}},
+			{Log: &workflowproto.ActionLog{
+				WorkflowId: "default/workflow",
+				ActionId:   "action-0",
+				Stream:     workflowproto.ActionLog_STREAM_STDERR,
+				Data:       []byte("err"),
+			}},
+		},
+	}
+	if err := server.PublishActionLogs(stream); err != nil {
+		t.Fatal(err)
+	}
+	if stream.resp == nil {
+		t.Fatal("expected the stream to be closed")
+	}
+
+	resp, err := server.ListActionLogs(context.Background(), &workflowproto.ListActionLogsRequest{
+		WorkflowId: "default/workflow",
+		ActionId:   "action-0",
+		LimitBytes: 4,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &workflowproto.ListActionLogsResponse{
+		Truncated: true,
+		Logs: []*workflowproto.ActionLog{
+			{
+				WorkflowId: "default/workflow",
+				ActionId:   "action-0",
+				Data:       []byte("t"),
+				CreatedAt:  timestamppb.New(TestTime.Now()),
+			},
+			{
+				WorkflowId: "default/workflow",
+				ActionId:   "action-0",
+				Stream:     workflowproto.ActionLog_STREAM_STDERR,
+				Data:       []byte("err"),
+				CreatedAt:  timestamppb.New(TestTime.Now()),
+			},
+		},
+	}
+	if diff := cmp.Diff(want, resp, protocmp.Transform()); diff != "" {
+		t.Fatalf("unexpected logs:\n%v", diff)
+	}
+}
+
+func TestPublishActionLogsMissingActionID(t *testing.T) {
+	server := newWorkflowV2Server()
+
+	stream := &publishActionLogsStream{
+		reqs: []*workflowproto.PublishActionLogsRequest{
+			{Log: &workflowproto.ActionLog{WorkflowId: "default/workflow", Data: []byte("out")}},
+		},
+	}
+	if got := status.Code(server.PublishActionLogs(stream)); got != codes.InvalidArgument {
+		t.Fatalf("Unexpected code: got %v, want %v", got, codes.InvalidArgument)
+	}
+}
+
+func TestListActionLogsNotRetained(t *testing.T) {
+	server := newWorkflowV2Server()
+
+	_, err := server.ListActionLogs(context.Background(), &workflowproto.ListActionLogsRequest{
+		WorkflowId: "default/workflow",
+		ActionId:   "action-0",
+	})
+	if got := status.Code(err); got != codes.NotFound {
+		t.Fatalf("Unexpected code: got %v, want %v", got, codes.NotFound)
+	}
+}
+
+func quantity(s string) *resource.Quantity {
+	q := resource.MustParse(s)
+	return &q
+}
+
+func TestDispatchWorkflowsRunning(t *testing.T) {
+	hw := &v1alpha2.Hardware{
+		ObjectMeta: metav1.ObjectMeta{Name: "hardware", Namespace: "default"},
+		Spec: v1alpha2.HardwareSpec{
+			NetworkInterfaces: v1alpha2.NetworkInterfaces{"00:00:00:00:00:01": {}},
+		},
+	}
+	wf := newWorkflowV2(v1alpha2.WorkflowStateRunning, v1alpha2.ActionStateRunning)
+	wf.Spec.Paused = true
+
+	server := newWorkflowV2Server(wf, hw)
+	ctx := context.Background()
+	stream := &getWorkflowsStream{ctx: ctx}
+	dispatched := map[string]*dispatchedWorkflow{}
+
+	got, err := server.getHardwareForAgent(ctx, "00:00:00:00:00:01")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A Running workflow is already executing on the agent, for example after the agent
+	// restarted, so it isn't re-sent but its pause configuration is.
+	if err := server.dispatchWorkflows(ctx, server.logger, got, stream, dispatched); err != nil {
+		t.Fatal(err)
+	}
+	want := []*workflowproto.GetWorkflowsResponse{
+		{
+			Cmd: &workflowproto.GetWorkflowsResponse_PauseWorkflow_{
+				PauseWorkflow: &workflowproto.GetWorkflowsResponse_PauseWorkflow{
+					WorkflowId: "default/workflow",
+					Pause:      &workflowproto.Workflow_Pause{Paused: true},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, stream.sent, protocmp.Transform()); diff != "" {
+		t.Fatalf("unexpected difference:\n%v", diff)
+	}
+
+	// Cancelling the workflow should stop it on the agent.
+	var stored v1alpha2.Workflow
+	if err := server.ClientFunc().Get(ctx, client.ObjectKeyFromObject(wf), &stored); err != nil {
+		t.Fatal(err)
+	}
+	stored.Status.State = v1alpha2.WorkflowStateCancelling
+	if err := server.ClientFunc().Status().Update(ctx, &stored); err != nil {
+		t.Fatal(err)
+	}
+	if err := server.dispatchWorkflows(ctx, server.logger, got, stream, dispatched); err != nil {
+		t.Fatal(err)
+	}
+	want = append(want, &workflowproto.GetWorkflowsResponse{
+		Cmd: &workflowproto.GetWorkflowsResponse_StopWorkflow_{
+			StopWorkflow: &workflowproto.GetWorkflowsResponse_StopWorkflow{WorkflowId: "default/workflow"},
+		},
+	})
+	if diff := cmp.Diff(want, stream.sent, protocmp.Transform()); diff != "" {
+		t.Fatalf("unexpected difference:\n%v", diff)
+	}
+}
+
+func TestDispatchWorkflowsAfterReboot(t *testing.T) {
+	hw := &v1alpha2.Hardware{
+		ObjectMeta: metav1.ObjectMeta{Name: "hardware", Namespace: "default"},
+		Spec: v1alpha2.HardwareSpec{
+			NetworkInterfaces: v1alpha2.NetworkInterfaces{"00:00:00:00:00:01": {}},
+		},
+	}
+
+	cases := []struct {
+		Name     string
+		Workflow *v1alpha2.Workflow
+
+		// Results are the results expected to be dispatched. When nil, the Workflow isn't expected
+		// to be dispatched.
+		Results map[string]*workflowproto.Workflow_ActionResult
+		State   v1alpha2.WorkflowState
+	}{
+		{
+			Name: "ContinuesWithNextAction",
+			Workflow: func() *v1alpha2.Workflow {
+				wf := newWorkflowV2(v1alpha2.WorkflowStateRunning,
+					v1alpha2.ActionStateSucceeded, v1alpha2.ActionStateRunning, v1alpha2.ActionStatePending)
+				wf.Status.Actions[0].Outputs = map[string]string{"disk": "/dev/sda"}
+				wf.Status.Actions[1].Rendered = v1alpha2.Action{
+					Name:  "kexec",
+					Type:  v1alpha2.ActionTypeKexec,
+					Kexec: &v1alpha2.Kexec{BlockDevice: "/dev/sda1", FSType: "ext4", KernelPath: "/boot/vmlinuz"},
+				}
+				return wf
+			}(),
+			Results: map[string]*workflowproto.Workflow_ActionResult{
+				"action-0": {Succeeded: true, Outputs: map[string]string{"disk": "/dev/sda"}},
+				"action-1": {Succeeded: true},
+			},
+			State: v1alpha2.WorkflowStateRunning,
+		},
+		{
+			Name: "LastAction",
+			Workflow: func() *v1alpha2.Workflow {
+				wf := newWorkflowV2(v1alpha2.WorkflowStateRunning, v1alpha2.ActionStateSucceeded, v1alpha2.ActionStateRunning)
+				wf.Status.Actions[1].Rendered = v1alpha2.Action{Name: "reboot", Type: v1alpha2.ActionTypeReboot}
+				return wf
+			}(),
+			State: v1alpha2.WorkflowStateSucceeded,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			server := newWorkflowV2Server(tc.Workflow, hw)
+			ctx := context.Background()
+			stream := &getWorkflowsStream{ctx: ctx}
+
+			got, err := server.getHardwareForAgent(ctx, "00:00:00:00:00:01")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// The agent reconnecting on a new stream indicates the machine restarted.
+			if err := server.dispatchWorkflows(ctx, server.logger, got, stream, map[string]*dispatchedWorkflow{}); err != nil {
+				t.Fatal(err)
+			}
+
+			var stored v1alpha2.Workflow
+			if err := server.ClientFunc().Get(ctx, client.ObjectKeyFromObject(tc.Workflow), &stored); err != nil {
+				t.Fatal(err)
+			}
+			if stored.Status.State != tc.State {
+				t.Fatalf("Expected state %v; received %v", tc.State, stored.Status.State)
+			}
+			if state := stored.Status.Actions[1].State; state != v1alpha2.ActionStateSucceeded {
+				t.Fatalf("Expected reboot action to succeed; received %v", state)
+			}
+
+			if tc.Results == nil {
+				if len(stream.sent) != 0 {
+					t.Fatalf("Expected no messages; received %v", stream.sent)
+				}
+				return
+			}
+
+			if len(stream.sent) != 1 {
+				t.Fatalf("Expected 1 message; received %v", len(stream.sent))
+			}
+			wf := stream.sent[0].GetStartWorkflow().GetWorkflow()
+			if diff := cmp.Diff(tc.Results, wf.GetResults(), protocmp.Transform()); diff != "" {
+				t.Fatalf("unexpected difference:\n%v", diff)
+			}
+			expect := &workflowproto.Workflow_Action{
+				Id:    "action-1",
+				Name:  "kexec",
+				Type:  workflowproto.Workflow_Action_TYPE_KEXEC,
+				Kexec: &workflowproto.Workflow_Kexec{BlockDevice: "/dev/sda1", FsType: "ext4", KernelPath: "/boot/vmlinuz"},
+			}
+			if diff := cmp.Diff(expect, wf.GetActions()[1], protocmp.Transform()); diff != "" {
+				t.Fatalf("unexpected difference:\n%v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package server
+
+import "sync"
+
+// notifier fans out change notifications to subscribers keyed by an arbitrary string. Multiple
+// notifications for the same key are coalesced until the subscriber reads from its channel.
+//
+// A nil *notifier never notifies.
+type notifier struct {
+	mtx  sync.Mutex
+	subs map[string]map[chan struct{}]struct{}
+}
+
+func newNotifier() *notifier {
+	return &notifier{
+		subs: make(map[string]map[chan struct{}]struct{}),
+	}
+}
+
+// Subscribe registers interest in key. The returned channel receives a value when Notify is
+// called for key. Callers must call the returned func to unsubscribe.
+func (n *notifier) Subscribe(key string) (<-chan struct{}, func()) {
+	if n == nil {
+		return nil, func() {}
+	}
+
+	ch := make(chan struct{}, 1)
+
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	if n.subs[key] == nil {
+		n.subs[key] = make(map[chan struct{}]struct{})
+	}
+	n.subs[key][ch] = struct{}{}
+
+	return ch, func() {
+		n.mtx.Lock()
+		defer n.mtx.Unlock()
+		delete(n.subs[key], ch)
+		if len(n.subs[key]) == 0 {
+			delete(n.subs, key)
+		}
+	}
+}
+
+// Notify notifies all subscribers of key. It never blocks.
+func (n *notifier) Notify(key string) {
+	if n == nil {
+		return
+	}
+
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	for ch := range n.subs[key] {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
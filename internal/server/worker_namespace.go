@@ -0,0 +1,104 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/tinkerbell/tink/api/v1alpha1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+)
+
+// setupWorkerNamespaces configures the index used to resolve the namespace workers belong to when
+// Workflows are served from more than one namespace.
+func (s *KubernetesBackedServer) setupWorkerNamespaces(clstr cluster.Cluster) error {
+	err := clstr.GetFieldIndexer().IndexField(
+		context.Background(),
+		&v1alpha1.Hardware{},
+		hardwareV1ByMACAddr,
+		hardwareV1ByMACAddrFunc,
+	)
+	if err != nil {
+		return fmt.Errorf("setup %s index: %w", hardwareV1ByMACAddr, err)
+	}
+	s.resolveWorkerNamespace = true
+	return nil
+}
+
+// workerNamespace returns the namespace that owns the worker identified by workerID: the namespace
+// of the Hardware with an interface matching workerID. It returns an empty string if no Hardware
+// matches and a FailedPrecondition error if Hardware in more than one namespace matches.
+func (s *KubernetesBackedServer) workerNamespace(ctx context.Context, workerID string) (string, error) {
+	var hw v1alpha1.HardwareList
+	err := s.ClientFunc().List(ctx, &hw, client.MatchingFields{
+		hardwareV1ByMACAddr: strings.ToLower(workerID),
+	})
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "list hardware: %v", err)
+	}
+
+	var namespaces []string
+	for _, h := range hw.Items {
+		if !slices.Contains(namespaces, h.Namespace) {
+			namespaces = append(namespaces, h.Namespace)
+		}
+	}
+
+	switch len(namespaces) {
+	case 0:
+		return "", nil
+	case 1:
+		return namespaces[0], nil
+	default:
+		slices.Sort(namespaces)
+		return "", status.Errorf(codes.FailedPrecondition,
+			"worker %v matches hardware in multiple namespaces: %v", workerID, strings.Join(namespaces, ", "))
+	}
+}
+
+// ownedWorkflows filters wfs, the Workflows assigned to the worker identified by workerID, to
+// those in the namespace that owns the worker. When no Hardware identifies the worker's namespace
+// the Workflows must all be in one namespace. Assignments that can't be resolved to a single
+// namespace are rejected with a FailedPrecondition error so a worker is never served Workflows
+// from more than one tenant.
+func (s *KubernetesBackedServer) ownedWorkflows(ctx context.Context, workerID string, wfs []v1alpha1.Workflow) ([]v1alpha1.Workflow, error) {
+	if !s.resolveWorkerNamespace || len(wfs) == 0 {
+		return wfs, nil
+	}
+
+	namespace, err := s.workerNamespace(ctx, workerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if namespace != "" {
+		owned := wfs[:0]
+		for _, wf := range wfs {
+			if wf.Namespace == namespace {
+				owned = append(owned, wf)
+				continue
+			}
+			s.logger.Info("ignoring workflow assigned to worker owned by another namespace",
+				"workflow", wf.Namespace+"/"+wf.Name, "workerID", workerID, "workerNamespace", namespace)
+		}
+		return owned, nil
+	}
+
+	var namespaces []string
+	for _, wf := range wfs {
+		if !slices.Contains(namespaces, wf.Namespace) {
+			namespaces = append(namespaces, wf.Namespace)
+		}
+	}
+	if len(namespaces) > 1 {
+		slices.Sort(namespaces)
+		return nil, status.Errorf(codes.FailedPrecondition,
+			"worker %v is assigned workflows in multiple namespaces (%v) and no hardware identifies the namespace it belongs to",
+			workerID, strings.Join(namespaces, ", "))
+	}
+	return wfs, nil
+}
@@ -0,0 +1,168 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/zapr"
+	"github.com/google/go-cmp/cmp"
+	"github.com/tinkerbell/tink/api/v1alpha1"
+	"github.com/tinkerbell/tink/internal/proto"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const workerMAC = "00:00:00:00:00:01"
+
+func newNamespacedWorkflow(namespace string) *v1alpha1.Workflow {
+	return &v1alpha1.Workflow{
+		ObjectMeta: metav1.ObjectMeta{Name: "workflow", Namespace: namespace},
+		Status: v1alpha1.WorkflowStatus{
+			State: v1alpha1.WorkflowStatePending,
+			Tasks: []v1alpha1.Task{
+				{
+					Name:       "provision",
+					WorkerAddr: workerMAC,
+					Actions:    []v1alpha1.Action{{Name: "stream", Status: v1alpha1.WorkflowStatePending}},
+				},
+			},
+		},
+	}
+}
+
+func newNamespacedHardware(namespace string) *v1alpha1.Hardware {
+	return &v1alpha1.Hardware{
+		ObjectMeta: metav1.ObjectMeta{Name: "hardware", Namespace: namespace},
+		Spec: v1alpha1.HardwareSpec{
+			Interfaces: []v1alpha1.Interface{{DHCP: &v1alpha1.DHCP{MAC: workerMAC}}},
+		},
+	}
+}
+
+func newMultiNamespaceServer(resolve bool, objs ...client.Object) *KubernetesBackedServer {
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+
+	clnt := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithIndex(&v1alpha1.Workflow{}, workflowByNonTerminalState, workflowByNonTerminalStateFunc).
+		WithIndex(&v1alpha1.Hardware{}, hardwareV1ByMACAddr, hardwareV1ByMACAddrFunc).
+		Build()
+
+	return &KubernetesBackedServer{
+		logger:                 zapr.NewLogger(zap.Must(zap.NewDevelopment())),
+		ClientFunc:             func() client.Client { return clnt },
+		nowFunc:                TestTime.Now,
+		resolveWorkerNamespace: resolve,
+	}
+}
+
+func TestGetWorkflowContextsMultiNamespace(t *testing.T) {
+	cases := []struct {
+		name          string
+		resolve       bool
+		objs          []client.Object
+		wantWorkflows []string
+		wantCode      codes.Code
+	}{
+		{
+			name:    "SingleNamespace",
+			resolve: false,
+			objs: []client.Object{
+				newNamespacedWorkflow("a"),
+				newNamespacedWorkflow("b"),
+			},
+			wantWorkflows: []string{"a/workflow", "b/workflow"},
+		},
+		{
+			name:    "HardwareOwnsWorker",
+			resolve: true,
+			objs: []client.Object{
+				newNamespacedHardware("b"),
+				newNamespacedWorkflow("a"),
+				newNamespacedWorkflow("b"),
+			},
+			wantWorkflows: []string{"b/workflow"},
+		},
+		{
+			name:    "HardwareOwnsWorkerWithoutWorkflows",
+			resolve: true,
+			objs: []client.Object{
+				newNamespacedHardware("b"),
+				newNamespacedWorkflow("a"),
+			},
+		},
+		{
+			name:          "NoHardwareOneNamespace",
+			resolve:       true,
+			objs:          []client.Object{newNamespacedWorkflow("a")},
+			wantWorkflows: []string{"a/workflow"},
+		},
+		{
+			name:    "NoHardwareManyNamespaces",
+			resolve: true,
+			objs: []client.Object{
+				newNamespacedWorkflow("a"),
+				newNamespacedWorkflow("b"),
+			},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name:    "HardwareInManyNamespaces",
+			resolve: true,
+			objs: []client.Object{
+				newNamespacedHardware("a"),
+				newNamespacedHardware("b"),
+				newNamespacedWorkflow("a"),
+			},
+			wantCode: codes.FailedPrecondition,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newMultiNamespaceServer(tc.resolve, tc.objs...)
+
+			stream := &getWorkflowContextsStream{}
+			err := srv.GetWorkflowContexts(&proto.WorkflowContextRequest{WorkerId: workerMAC}, stream)
+			if got := status.Code(err); got != tc.wantCode {
+				t.Fatalf("Unexpected code: got %v, want %v (%v)", got, tc.wantCode, err)
+			}
+
+			var got []string
+			for _, c := range stream.sent {
+				got = append(got, c.GetWorkflowId())
+			}
+			if diff := cmp.Diff(tc.wantWorkflows, got); diff != "" {
+				t.Fatalf("Unexpected workflows served:\n%v", diff)
+			}
+		})
+	}
+}
+
+func TestAuthorizeMultiNamespace(t *testing.T) {
+	srv := newMultiNamespaceServer(true,
+		newNamespacedHardware("b"),
+		newNamespacedWorkflow("a"),
+		newNamespacedWorkflow("b"),
+	)
+
+	cases := map[string]codes.Code{
+		"a/workflow": codes.PermissionDenied,
+		"b/workflow": codes.OK,
+	}
+	for workflowID, wantCode := range cases {
+		t.Run(workflowID, func(t *testing.T) {
+			err := srv.Authorize(context.Background(), workerMAC, &proto.WorkflowActionsRequest{WorkflowId: workflowID})
+			if got := status.Code(err); got != wantCode {
+				t.Fatalf("Unexpected code: got %v, want %v (%v)", got, wantCode, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+// Package tlsconfig builds TLS configurations for servers from certificate files that are reloaded
+// when they change so certificates can be rotated without restarting the server.
+package tlsconfig
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+)
+
+// DefaultReloadInterval is the default interval files are re-read at in addition to being
+// reloaded when a change is observed.
+const DefaultReloadInterval = 10 * time.Second
+
+// Option configures optional behavior of a Reloader.
+type Option func(*Reloader)
+
+// WithClientCAFile requires clients to present a certificate that can be verified against the
+// PEM encoded CA certificates in path.
+func WithClientCAFile(path string) Option {
+	return func(r *Reloader) {
+		r.clientCAFile = path
+	}
+}
+
+// WithLogger configures the logger used to report reloads.
+func WithLogger(l logr.Logger) Option {
+	return func(r *Reloader) {
+		r.log = l
+	}
+}
+
+// WithReloadInterval configures the interval files are re-read at.
+func WithReloadInterval(d time.Duration) Option {
+	return func(r *Reloader) {
+		if d > 0 {
+			r.interval = d
+		}
+	}
+}
+
+// Reloader serves a certificate and key, and optionally verifies client certificates, from files
+// that are reloaded when they change. Connections established after a reload use the reloaded
+// files; established connections are unaffected.
+type Reloader struct {
+	log          logr.Logger
+	interval     time.Duration
+	clientCAFile string
+
+	cert *certwatcher.CertWatcher
+
+	mtx         sync.RWMutex
+	clientCAPEM []byte
+	clientCAs   *x509.CertPool
+}
+
+// New creates a Reloader serving the certificate and key in certFile and keyFile. The files are
+// read immediately so misconfiguration is surfaced before serving; they're reloaded once Start is
+// called.
+func New(certFile, keyFile string, opts ...Option) (*Reloader, error) {
+	r := &Reloader{
+		log:      logr.Discard(),
+		interval: DefaultReloadInterval,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(r)
+		}
+	}
+
+	cert, err := certwatcher.New(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load certificate: %w", err)
+	}
+	r.cert = cert.WithWatchInterval(r.interval)
+
+	if err := r.loadClientCAs(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// ClientAuth reports whether clients are required to present a verified certificate.
+func (r *Reloader) ClientAuth() bool {
+	return r.clientCAFile != ""
+}
+
+// Start reloads files as they change until ctx is cancelled.
+func (r *Reloader) Start(ctx context.Context) error {
+	if r.clientCAFile != "" {
+		go r.watchClientCAs(ctx)
+	}
+	return r.cert.Start(ctx)
+}
+
+// nextProtos are the application protocols, in order of preference, negotiated with clients.
+// Servers consuming the configuration serve HTTP/2 (gRPC) or HTTP/1.1.
+var nextProtos = []string{"h2", "http/1.1"}
+
+// Config returns a TLS configuration that serves the current certificate and verifies clients
+// against the current client CAs.
+func (r *Reloader) Config() *tls.Config {
+	return &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: r.cert.GetCertificate,
+		// The configuration returned for each client replaces this one so it must negotiate
+		// application protocols itself.
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cfg := &tls.Config{
+				MinVersion:     tls.VersionTLS12,
+				NextProtos:     nextProtos,
+				GetCertificate: r.cert.GetCertificate,
+			}
+			if r.clientCAFile != "" {
+				r.mtx.RLock()
+				cfg.ClientCAs = r.clientCAs
+				r.mtx.RUnlock()
+				cfg.ClientAuth = tls.RequireAndVerifyClientCert
+			}
+			return cfg, nil
+		},
+	}
+}
+
+func (r *Reloader) watchClientCAs(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.loadClientCAs(); err != nil {
+				r.log.Error(err, "Reloading client CAs; continuing to use previous client CAs")
+			}
+		}
+	}
+}
+
+// loadClientCAs reads the client CA file, if any, replacing the current client CAs if it changed.
+func (r *Reloader) loadClientCAs() error {
+	if r.clientCAFile == "" {
+		return nil
+	}
+
+	pem, err := os.ReadFile(r.clientCAFile)
+	if err != nil {
+		return fmt.Errorf("read client CA file: %w", err)
+	}
+
+	r.mtx.RLock()
+	unchanged := bytes.Equal(pem, r.clientCAPEM)
+	r.mtx.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return fmt.Errorf("no certificates found in client CA file: %v", r.clientCAFile)
+	}
+
+	r.mtx.Lock()
+	loaded := r.clientCAPEM != nil
+	r.clientCAPEM = pem
+	r.clientCAs = pool
+	r.mtx.Unlock()
+
+	if loaded {
+		r.log.Info("Reloaded client CAs", "file", r.clientCAFile)
+	}
+	return nil
+}
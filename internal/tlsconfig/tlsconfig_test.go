@@ -0,0 +1,285 @@
+package tlsconfig_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/tinkerbell/tink/internal/tlsconfig"
+)
+
+type keyPair struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+// issue creates a certificate for cn signed by parent, or self-signed when parent is nil.
+func issue(t *testing.T, cn string, parent *keyPair) *keyPair {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: cn},
+		DNSNames:     []string{cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	signer, signerKey := tmpl, key
+	if parent == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &keyPair{cert: cert, key: key}
+}
+
+func (k *keyPair) CertPEM() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: k.cert.Raw})
+}
+
+func (k *keyPair) KeyPEM(t *testing.T) []byte {
+	t.Helper()
+	der, err := x509.MarshalECPrivateKey(k.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})
+}
+
+func (k *keyPair) TLS(t *testing.T) tls.Certificate {
+	t.Helper()
+	cert, err := tls.X509KeyPair(k.CertPEM(), k.KeyPEM(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+func writeFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	// Write then rename, as certificate rotation tooling does, so readers never observe a
+	// partially written file.
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// serve accepts TLS connections configured by cfg, completing each handshake.
+func serve(t *testing.T, cfg *tls.Config) string {
+	t.Helper()
+	lis, err := tls.Listen("tcp", "127.0.0.1:0", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { lis.Close() })
+
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_ = conn.(*tls.Conn).Handshake()
+				_, _ = conn.Read(make([]byte, 1))
+			}()
+		}
+	}()
+	return lis.Addr().String()
+}
+
+// dial connects to addr and returns the certificate the server presented.
+func dial(addr string, roots *x509.CertPool, certs ...tls.Certificate) (*x509.Certificate, error) {
+	conn, err := tls.Dial("tcp", addr, &tls.Config{
+		ServerName:   "server",
+		RootCAs:      roots,
+		Certificates: certs,
+		NextProtos:   []string{"h2"},
+		MinVersion:   tls.VersionTLS12,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	// TLS 1.3 servers reject client certificates after the client considers the handshake
+	// complete so wait for the server's verdict.
+	_ = conn.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+	if _, err := conn.Read(make([]byte, 1)); err != nil {
+		var netErr net.Error
+		if !errors.As(err, &netErr) || !netErr.Timeout() {
+			return nil, err
+		}
+	}
+
+	state := conn.ConnectionState()
+	if state.NegotiatedProtocol != "h2" {
+		return nil, &protocolError{state.NegotiatedProtocol}
+	}
+	return state.PeerCertificates[0], nil
+}
+
+type protocolError struct{ proto string }
+
+func (e *protocolError) Error() string { return "negotiated protocol " + e.proto }
+
+func TestReloader(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+	caFile := filepath.Join(dir, "ca.crt")
+
+	serverCA := issue(t, "server-ca", nil)
+	clientCA := issue(t, "client-ca", nil)
+	server := issue(t, "server", serverCA)
+
+	writeFile(t, certFile, server.CertPEM())
+	writeFile(t, keyFile, server.KeyPEM(t))
+	writeFile(t, caFile, clientCA.CertPEM())
+
+	r, err := tlsconfig.New(certFile, keyFile,
+		tlsconfig.WithClientCAFile(caFile),
+		tlsconfig.WithReloadInterval(10*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.ClientAuth() {
+		t.Fatal("Expected client authentication")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = r.Start(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	addr := serve(t, r.Config())
+	roots := x509.NewCertPool()
+	roots.AddCert(serverCA.cert)
+	worker := issue(t, "worker", clientCA)
+
+	got, err := dial(addr, roots, worker.TLS(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(server.cert) {
+		t.Fatal("Unexpected server certificate")
+	}
+
+	if _, err := dial(addr, roots); err == nil {
+		t.Fatal("Expected error connecting without a client certificate")
+	}
+
+	// Rotate the server certificate and the client CA.
+	rotated := issue(t, "server", serverCA)
+	writeFile(t, keyFile, rotated.KeyPEM(t))
+	writeFile(t, certFile, rotated.CertPEM())
+	newClientCA := issue(t, "client-ca", nil)
+	writeFile(t, caFile, newClientCA.CertPEM())
+	newWorker := issue(t, "worker", newClientCA)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		got, err := dial(addr, roots, newWorker.TLS(t))
+		if err == nil && got.Equal(rotated.cert) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for reload: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if _, err := dial(addr, roots, worker.TLS(t)); err == nil {
+		t.Fatal("Expected error connecting with a certificate issued by the replaced client CA")
+	}
+}
+
+func TestReloaderWithoutClientCA(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+
+	ca := issue(t, "ca", nil)
+	server := issue(t, "server", ca)
+	writeFile(t, certFile, server.CertPEM())
+	writeFile(t, keyFile, server.KeyPEM(t))
+
+	r, err := tlsconfig.New(certFile, keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.ClientAuth() {
+		t.Fatal("Unexpected client authentication")
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	if _, err := dial(serve(t, r.Config()), roots); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewInvalidFiles(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+	caFile := filepath.Join(dir, "ca.crt")
+
+	if _, err := tlsconfig.New(certFile, keyFile); err == nil {
+		t.Fatal("Expected error for missing certificate")
+	}
+
+	ca := issue(t, "ca", nil)
+	server := issue(t, "server", ca)
+	writeFile(t, certFile, server.CertPEM())
+	writeFile(t, keyFile, server.KeyPEM(t))
+	writeFile(t, caFile, []byte("not a certificate"))
+
+	if _, err := tlsconfig.New(certFile, keyFile, tlsconfig.WithClientCAFile(caFile)); err == nil {
+		t.Fatal("Expected error for client CA file without certificates")
+	}
+}
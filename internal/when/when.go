@@ -0,0 +1,51 @@
+// Package when evaluates the conditions that determine whether workflow actions run.
+//
+// A condition is a Go template pipeline without the enclosing delimiters, for example:
+//
+//	eq .Hardware.metadata.instance.operating_system.distro "ubuntu"
+//
+// Omitting the delimiters ensures conditions are left intact when templates are rendered so they
+// can be evaluated when the action is about to run. Conditions have access to Sprig's hermetic
+// functions and must evaluate to a boolean.
+package when
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+)
+
+// Evaluate evaluates the condition expr against data. An empty condition is always true.
+func Evaluate(expr string, data any) (bool, error) {
+	if strings.TrimSpace(expr) == "" {
+		return true, nil
+	}
+
+	if strings.Contains(expr, "{{") || strings.Contains(expr, "}}") {
+		return false, errors.New("condition must not contain template delimiters")
+	}
+
+	tmpl, err := template.New("when").
+		Option("missingkey=error").
+		Funcs(sprig.HermeticTxtFuncMap()).
+		Parse("{{ " + expr + " }}")
+	if err != nil {
+		return false, fmt.Errorf("parse condition: %w", err)
+	}
+
+	var result bytes.Buffer
+	if err := tmpl.Execute(&result, data); err != nil {
+		return false, fmt.Errorf("evaluate condition: %w", err)
+	}
+
+	ok, err := strconv.ParseBool(strings.TrimSpace(result.String()))
+	if err != nil {
+		return false, fmt.Errorf("condition must evaluate to a boolean: got %q", result.String())
+	}
+	return ok, nil
+}
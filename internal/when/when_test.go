@@ -0,0 +1,46 @@
+package when_test
+
+import (
+	"testing"
+
+	"github.com/tinkerbell/tink/internal/when"
+)
+
+func TestEvaluate(t *testing.T) {
+	data := map[string]any{
+		"Hardware": map[string]any{
+			"disks": []any{"/dev/sda", "/dev/sdb"},
+			"arch":  "x86_64",
+		},
+		"Param": map[string]any{"wipe": "true"},
+	}
+
+	cases := []struct {
+		name    string
+		expr    string
+		want    bool
+		wantErr bool
+	}{
+		{name: "empty", expr: "", want: true},
+		{name: "true", expr: `eq .Hardware.arch "x86_64"`, want: true},
+		{name: "false", expr: `eq .Hardware.arch "aarch64"`, want: false},
+		{name: "sprig function", expr: `gt (len .Hardware.disks) 1`, want: true},
+		{name: "string boolean", expr: `.Param.wipe`, want: true},
+		{name: "missing key", expr: `.Param.missing`, wantErr: true},
+		{name: "not a boolean", expr: `.Hardware.arch`, wantErr: true},
+		{name: "delimiters", expr: `{{ true }}`, wantErr: true},
+		{name: "invalid syntax", expr: `eq (`, wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := when.Evaluate(tc.expr, data)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Evaluate() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Fatalf("Evaluate() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
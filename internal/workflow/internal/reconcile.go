@@ -3,18 +3,28 @@ package internal
 import (
 	"bytes"
 	"context"
+	"fmt"
+	"slices"
 	"text/template"
 	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/google/uuid"
 	tinkv1 "github.com/tinkerbell/tink/api/v1alpha2"
-	"gopkg.in/yaml.v3"
+	"github.com/tinkerbell/tink/internal/dag"
+	"github.com/tinkerbell/tink/internal/ptr"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/yaml"
 )
 
+// CancellationGracePeriod is the time a Workflow may remain in the Cancelling state, giving the
+// agent an opportunity to stop the running action, before it's forcibly transitioned to a
+// terminal state.
+const CancellationGracePeriod = 30 * time.Second
+
 // ReconciliationContext reconciles Workflow resources when created or updated.
 type ReconciliationContext struct {
 	// Workflow is the Workflow instance we're reconciling.
@@ -23,12 +33,37 @@ type ReconciliationContext struct {
 	// NewActionID generated unique IDs for actions. Defaults to generating UUIDv4s.
 	NewActionID func() string
 
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+
+	// PinImage resolves an action image to a reference pinned by digest. When set, rendered
+	// actions reference images by digest so every action runs the image that existed at render
+	// time.
+	PinImage func(_ context.Context, image string) (string, error)
+
 	Log    logr.Logger
 	Client client.Client
 }
 
-// Reconcile reconciles the Workflow.
+// Reconcile reconciles the Workflow. It renders the Workflow's Template on first reconciliation
+// and subsequently drives the Workflow through its states. Transitions resulting from agent
+// activity (Scheduled, Running, Succeeded and Failed) are recorded by the server; Reconcile
+// enforces the Workflow timeout and completes cancellations.
 func (rc ReconciliationContext) Reconcile(ctx context.Context) (reconcile.Result, error) {
+	switch rc.Workflow.Status.State {
+	case "":
+		return rc.render(ctx)
+	case tinkv1.WorkflowStateScheduled, tinkv1.WorkflowStateRunning:
+		return rc.enforceTimeout(), nil
+	case tinkv1.WorkflowStateCancelling:
+		return rc.completeCancellation(), nil
+	default:
+		// Pending Workflows are awaiting dispatch by the server and all other states are terminal.
+		return reconcile.Result{}, nil
+	}
+}
+
+func (rc ReconciliationContext) render(ctx context.Context) (reconcile.Result, error) {
 	tmplRef := client.ObjectKey{
 		Name:      rc.Workflow.Spec.TemplateRef.Name,
 		Namespace: rc.Workflow.Namespace,
@@ -38,6 +73,8 @@ func (rc ReconciliationContext) Reconcile(ctx context.Context) (reconcile.Result
 		if errors.IsNotFound(err) {
 			// The Template may yet to be submitted to the cluster so just requeue.
 			rc.Log.Info("Template not found; requeue in 5 seconds", "ref", tmplRef)
+			rc.setCondition(tinkv1.WorkflowConditionTemplateRendered, tinkv1.ConditionStatusFalse,
+				"TemplateNotFound", fmt.Sprintf("template %v not found", tmplRef))
 			return reconcile.Result{RequeueAfter: 5 * time.Second}, nil
 		}
 		return reconcile.Result{}, err
@@ -51,7 +88,9 @@ func (rc ReconciliationContext) Reconcile(ctx context.Context) (reconcile.Result
 	if err := rc.Client.Get(ctx, hwRef, &hw); err != nil {
 		if errors.IsNotFound(err) {
 			// The Hardware may yet to be submitted to the cluster so just requeue.
-			rc.Log.Info("Hardware not found; requeue in 5 seconds", "ref", tmplRef)
+			rc.Log.Info("Hardware not found; requeue in 5 seconds", "ref", hwRef)
+			rc.setCondition(tinkv1.WorkflowConditionTemplateRendered, tinkv1.ConditionStatusFalse,
+				"HardwareNotFound", fmt.Sprintf("hardware %v not found", hwRef))
 			return reconcile.Result{RequeueAfter: 5 * time.Second}, nil
 		}
 		return reconcile.Result{}, err
@@ -61,15 +100,153 @@ func (rc ReconciliationContext) Reconcile(ctx context.Context) (reconcile.Result
 	if len(rc.Workflow.Status.Actions) == 0 {
 		tmpl, err := rc.renderTemplate(tmpl, &hw)
 		if err != nil {
+			rc.setCondition(tinkv1.WorkflowConditionTemplateRendered, tinkv1.ConditionStatusFalse,
+				"RenderFailed", err.Error())
+			return reconcile.Result{}, err
+		}
+
+		// A Workflow without actions would never be dispatched so it would remain Pending.
+		if len(tmpl.Spec.Actions) == 0 {
+			rc.Log.Info("Template has no actions")
+			rc.setCondition(tinkv1.WorkflowConditionTemplateRendered, tinkv1.ConditionStatusFalse,
+				"NoActions", "template has no actions")
+			rc.setState(tinkv1.WorkflowStateFailed)
+			return reconcile.Result{}, nil
+		}
+
+		// An invalid action graph can't be executed and won't be fixed by re-rendering.
+		if err := validateDependencies(tmpl.Spec.Actions); err != nil {
+			rc.Log.Info("Template has invalid action dependencies", "error", err)
+			rc.setCondition(tinkv1.WorkflowConditionTemplateRendered, tinkv1.ConditionStatusFalse,
+				"InvalidDependencies", err.Error())
+			rc.setState(tinkv1.WorkflowStateFailed)
+			return reconcile.Result{}, nil
+		}
+
+		if err := validateHandlers(tmpl.Spec); err != nil {
+			rc.Log.Info("Template has invalid handler actions", "error", err)
+			rc.setCondition(tinkv1.WorkflowConditionTemplateRendered, tinkv1.ConditionStatusFalse,
+				"InvalidHandlers", err.Error())
+			rc.setState(tinkv1.WorkflowStateFailed)
+			return reconcile.Result{}, nil
+		}
+
+		if err := validateKexec(tmpl.Spec); err != nil {
+			rc.Log.Info("Template has invalid kexec actions", "error", err)
+			rc.setCondition(tinkv1.WorkflowConditionTemplateRendered, tinkv1.ConditionStatusFalse,
+				"InvalidKexec", err.Error())
+			rc.setState(tinkv1.WorkflowStateFailed)
+			return reconcile.Result{}, nil
+		}
+
+		if err := validateNamespaces(tmpl.Spec); err != nil {
+			rc.Log.Info("Template has unsupported namespaces", "error", err)
+			rc.setCondition(tinkv1.WorkflowConditionTemplateRendered, tinkv1.ConditionStatusFalse,
+				"UnsupportedNamespace", err.Error())
+			rc.setState(tinkv1.WorkflowStateFailed)
+			return reconcile.Result{}, nil
+		}
+
+		applyResourceDefaults(&tmpl.Spec)
+
+		// Registries may be temporarily unavailable so pinning failures are retried.
+		if err := rc.pinImages(ctx, &tmpl.Spec); err != nil {
+			rc.setCondition(tinkv1.WorkflowConditionTemplateRendered, tinkv1.ConditionStatusFalse,
+				"ImagePinFailed", err.Error())
 			return reconcile.Result{}, err
 		}
 
 		rc.Workflow.Status.Actions = rc.toActionStatus(tmpl.Spec.Actions)
+		rc.Workflow.Status.OnFailure = rc.toActionStatus(tmpl.Spec.OnFailure)
+		rc.Workflow.Status.Finally = rc.toActionStatus(tmpl.Spec.Finally)
 	}
 
+	rc.setCondition(tinkv1.WorkflowConditionTemplateRendered, tinkv1.ConditionStatusTrue, "", "")
+	rc.setState(tinkv1.WorkflowStatePending)
+
 	return reconcile.Result{}, nil
 }
 
+// enforceTimeout transitions the Workflow to Cancelling if it has exceeded Spec.TimeoutSeconds.
+// When the Workflow is still within its deadline the returned result requeues for the deadline.
+func (rc ReconciliationContext) enforceTimeout() reconcile.Result {
+	if rc.Workflow.Spec.TimeoutSeconds == 0 || rc.Workflow.Status.StartedAt == nil {
+		return reconcile.Result{}
+	}
+
+	timeout := time.Duration(rc.Workflow.Spec.TimeoutSeconds) * time.Second
+	deadline := rc.Workflow.Status.StartedAt.Add(timeout)
+	if remaining := deadline.Sub(rc.now()); remaining > 0 {
+		return reconcile.Result{RequeueAfter: remaining}
+	}
+
+	rc.Log.Info("Workflow timed out; cancelling", "timeout", timeout)
+	rc.setCondition(tinkv1.WorkflowConditionTimedOut, tinkv1.ConditionStatusTrue,
+		"Timeout", fmt.Sprintf("workflow exceeded timeout of %v", timeout))
+	rc.setState(tinkv1.WorkflowStateCancelling)
+
+	return reconcile.Result{RequeueAfter: CancellationGracePeriod}
+}
+
+// completeCancellation transitions a Cancelling Workflow to a terminal state once the
+// CancellationGracePeriod has elapsed. Timed out Workflows are Failed, all others are Canceled.
+func (rc ReconciliationContext) completeCancellation() reconcile.Result {
+	deadline := rc.Workflow.Status.LastTransition.Add(CancellationGracePeriod)
+	if remaining := deadline.Sub(rc.now()); remaining > 0 {
+		return reconcile.Result{RequeueAfter: remaining}
+	}
+
+	state, reason, message := tinkv1.WorkflowStateCanceled, "Canceled", "workflow canceled"
+	if rc.Workflow.Status.Conditions.IsTrue(tinkv1.WorkflowConditionTimedOut) {
+		state, reason, message = tinkv1.WorkflowStateFailed, "Timeout", "workflow timed out"
+	}
+
+	now := metav1.NewTime(rc.now())
+	for _, actions := range [][]tinkv1.ActionStatus{
+		rc.Workflow.Status.Actions,
+		rc.Workflow.Status.OnFailure,
+		rc.Workflow.Status.Finally,
+	} {
+		for i := range actions {
+			action := &actions[i]
+			if action.State != tinkv1.ActionStateRunning {
+				continue
+			}
+			action.State = tinkv1.ActionStateFailed
+			action.LastTransition = &now
+			action.FailureReason = reason
+			action.FailureMessage = message
+		}
+	}
+
+	rc.setState(state)
+
+	return reconcile.Result{}
+}
+
+func (rc ReconciliationContext) setState(state tinkv1.WorkflowState) {
+	if rc.Workflow.Status.State == state {
+		return
+	}
+	rc.Workflow.Status.State = state
+	rc.Workflow.Status.LastTransition = metav1.NewTime(rc.now())
+}
+
+func (rc ReconciliationContext) setCondition(typ tinkv1.ConditionType, status tinkv1.ConditionStatus, reason, message string) {
+	cond := tinkv1.Condition{
+		Type:           typ,
+		Status:         status,
+		LastTransition: metav1.NewTime(rc.now()),
+	}
+	if reason != "" {
+		cond.Reason = ptr.String(reason)
+	}
+	if message != "" {
+		cond.Message = ptr.String(message)
+	}
+	rc.Workflow.Status.Conditions.Set(cond)
+}
+
 func (rc ReconciliationContext) renderTemplate(tpl tinkv1.Template, hw *tinkv1.Hardware) (tinkv1.Template, error) {
 	tplYAML, err := yaml.Marshal(tpl)
 	if err != nil {
@@ -101,6 +278,113 @@ func (rc ReconciliationContext) renderTemplate(tpl tinkv1.Template, hw *tinkv1.H
 	return tpl, nil
 }
 
+// validateDependencies ensures the actions form an acyclic graph where dependencies reference
+// other actions by name.
+func validateDependencies(actions []tinkv1.Action) error {
+	names := make([]string, 0, len(actions))
+	deps := make(map[string][]string, len(actions))
+	for _, action := range actions {
+		names = append(names, action.Name)
+		deps[action.Name] = action.DependsOn
+	}
+	_, err := dag.Sort(names, deps)
+	return err
+}
+
+// validateHandlers ensures the OnFailure and Finally actions of spec don't declare dependencies
+// and that action names are unique across all action lists.
+func validateHandlers(spec tinkv1.TemplateSpec) error {
+	names := map[string]bool{}
+	for _, action := range spec.Actions {
+		names[action.Name] = true
+	}
+	for _, action := range slices.Concat(spec.OnFailure, spec.Finally) {
+		if names[action.Name] {
+			return fmt.Errorf("duplicate action name: %v", action.Name)
+		}
+		if len(action.DependsOn) > 0 {
+			return fmt.Errorf("%v: onFailure and finally actions cannot depend on other actions", action.Name)
+		}
+		names[action.Name] = true
+	}
+	return nil
+}
+
+// validateKexec ensures the Kexec actions of spec define the kernel to boot.
+func validateKexec(spec tinkv1.TemplateSpec) error {
+	for _, action := range slices.Concat(spec.Actions, spec.OnFailure, spec.Finally) {
+		if action.Type == tinkv1.ActionTypeKexec && (action.Kexec == nil || action.Kexec.KernelPath == "") {
+			return fmt.Errorf("%v: kexec actions must specify a kernel path", action.Name)
+		}
+	}
+	return nil
+}
+
+// validateNamespaces ensures the actions of spec only join the host PID namespace. Runtimes can't
+// join the PID namespace of other host processes.
+func validateNamespaces(spec tinkv1.TemplateSpec) error {
+	for _, action := range slices.Concat(spec.Actions, spec.OnFailure, spec.Finally) {
+		if ns := action.Namespace; ns != nil && ns.PID != nil && *ns.PID != 1 {
+			return fmt.Errorf("%v: unsupported pid namespace %v; only 1, the host pid namespace, is supported", action.Name, *ns.PID)
+		}
+	}
+	return nil
+}
+
+// pinImages replaces the images of spec's actions with references pinned by digest. It's a no-op
+// when PinImage isn't set.
+func (rc ReconciliationContext) pinImages(ctx context.Context, spec *tinkv1.TemplateSpec) error {
+	if rc.PinImage == nil {
+		return nil
+	}
+
+	pinned := map[string]string{}
+	for _, actions := range [][]tinkv1.Action{spec.Actions, spec.OnFailure, spec.Finally} {
+		for i := range actions {
+			// Reboot and kexec actions are performed by the agent without an image.
+			if actions[i].Type.IsReboot() {
+				continue
+			}
+			image := actions[i].Image
+			if _, ok := pinned[image]; !ok {
+				ref, err := rc.PinImage(ctx, image)
+				if err != nil {
+					return fmt.Errorf("action %v: %w", actions[i].Name, err)
+				}
+				pinned[image] = ref
+			}
+			actions[i].Image = pinned[image]
+		}
+	}
+	return nil
+}
+
+// applyResourceDefaults applies the template level resource limits of spec to its actions. Limits
+// specified by an action take precedence.
+func applyResourceDefaults(spec *tinkv1.TemplateSpec) {
+	defaults := spec.Resources
+	if defaults == nil {
+		return
+	}
+	for _, actions := range [][]tinkv1.Action{spec.Actions, spec.OnFailure, spec.Finally} {
+		for i := range actions {
+			limits := defaults.DeepCopy()
+			if r := actions[i].Resources; r != nil {
+				if r.Memory != nil {
+					limits.Memory = r.Memory
+				}
+				if r.CPU != nil {
+					limits.CPU = r.CPU
+				}
+				if r.PIDs != nil {
+					limits.PIDs = r.PIDs
+				}
+			}
+			actions[i].Resources = limits
+		}
+	}
+}
+
 func (rc ReconciliationContext) toActionStatus(actions []tinkv1.Action) []tinkv1.ActionStatus {
 	var status []tinkv1.ActionStatus
 	for _, action := range actions {
@@ -119,3 +403,10 @@ func (rc ReconciliationContext) newActionID() string {
 	}
 	return uuid.New().String()
 }
+
+func (rc ReconciliationContext) now() time.Time {
+	if rc.Now != nil {
+		return rc.Now()
+	}
+	return time.Now()
+}
@@ -2,24 +2,31 @@ package internal_test
 
 import (
 	"context"
+	"errors"
 	"os"
 	"testing"
+	"time"
 
+	"github.com/go-logr/logr"
 	"github.com/go-logr/zerologr"
 	"github.com/google/go-cmp/cmp"
 	"github.com/rs/zerolog"
 	tinkv1 "github.com/tinkerbell/tink/api/v1alpha2"
 	"github.com/tinkerbell/tink/internal/ptr"
+	"github.com/tinkerbell/tink/internal/testtime"
 	. "github.com/tinkerbell/tink/internal/workflow/internal" //nolint:revive // Dot imports should not be used. Problem for another time though.
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	machineryruntimeutil "k8s.io/apimachinery/pkg/util/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 func TestReconcileContext(t *testing.T) {
 	ctx := context.Background()
+	clock := testtime.NewFrozenTimeUnix(1637361793)
 
 	hw := newHardware(func(*tinkv1.Hardware) {})
 	tmpl := newTemplate(func(t *tinkv1.Template) {
@@ -49,6 +56,15 @@ func TestReconcileContext(t *testing.T) {
 			ID:    newActionID(),
 		},
 	}
+	expectWrkflw.Status.State = tinkv1.WorkflowStatePending
+	expectWrkflw.Status.LastTransition = *clock.MetaV1Now()
+	expectWrkflw.Status.Conditions = tinkv1.Conditions{
+		{
+			Type:           tinkv1.WorkflowConditionTemplateRendered,
+			Status:         tinkv1.ConditionStatusTrue,
+			LastTransition: *clock.MetaV1Now(),
+		},
+	}
 
 	zl := zerolog.New(os.Stdout)
 	logger := zerologr.New(&zl)
@@ -66,6 +82,7 @@ func TestReconcileContext(t *testing.T) {
 		Log:         logger,
 		Workflow:    wrkflw,
 		NewActionID: newActionID,
+		Now:         clock.Now,
 	}
 	_, err := reconcileCtx.Reconcile(ctx)
 	if err != nil {
@@ -77,6 +94,652 @@ func TestReconcileContext(t *testing.T) {
 	}
 }
 
+func TestReconcileContextMissingTemplate(t *testing.T) {
+	clock := testtime.NewFrozenTimeUnix(1637361793)
+
+	wrkflw := newWorkflow(func(w *tinkv1.Workflow) {
+		w.Spec.TemplateRef = corev1.LocalObjectReference{Name: "missing"}
+	})
+
+	scheme := runtime.NewScheme()
+	machineryruntimeutil.Must(tinkv1.AddToScheme(scheme))
+
+	reconcileCtx := ReconciliationContext{
+		Client:   fake.NewClientBuilder().WithScheme(scheme).Build(),
+		Log:      logr.Discard(),
+		Workflow: wrkflw,
+		Now:      clock.Now,
+	}
+	result, err := reconcileCtx.Reconcile(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.RequeueAfter == 0 {
+		t.Fatal("expected requeue")
+	}
+
+	expect := tinkv1.Conditions{
+		{
+			Type:           tinkv1.WorkflowConditionTemplateRendered,
+			Status:         tinkv1.ConditionStatusFalse,
+			LastTransition: *clock.MetaV1Now(),
+			Reason:         ptr.String("TemplateNotFound"),
+			Message:        ptr.String("template /missing not found"),
+		},
+	}
+	if diff := cmp.Diff(expect, wrkflw.Status.Conditions); diff != "" {
+		t.Fatal(diff)
+	}
+	if wrkflw.Status.State != "" {
+		t.Fatalf("expected empty state, got %v", wrkflw.Status.State)
+	}
+}
+
+func TestReconcileContextInvalidDependencies(t *testing.T) {
+	clock := testtime.NewFrozenTimeUnix(1637361793)
+
+	hw := newHardware(func(*tinkv1.Hardware) {})
+	tmpl := newTemplate(func(t *tinkv1.Template) {
+		t.Spec.Actions = []tinkv1.Action{
+			{Name: "first", Image: "image", DependsOn: []string{"second"}},
+			{Name: "second", Image: "image", DependsOn: []string{"first"}},
+		}
+	})
+	wrkflw := newWorkflow(func(w *tinkv1.Workflow) {
+		w.Spec.HardwareRef = corev1.LocalObjectReference{Name: hw.Name}
+		w.Spec.TemplateRef = corev1.LocalObjectReference{Name: tmpl.Name}
+	})
+
+	scheme := runtime.NewScheme()
+	machineryruntimeutil.Must(tinkv1.AddToScheme(scheme))
+
+	reconcileCtx := ReconciliationContext{
+		Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(hw, tmpl).Build(),
+		Log:      logr.Discard(),
+		Workflow: wrkflw,
+		Now:      clock.Now,
+	}
+	if _, err := reconcileCtx.Reconcile(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := tinkv1.Conditions{
+		{
+			Type:           tinkv1.WorkflowConditionTemplateRendered,
+			Status:         tinkv1.ConditionStatusFalse,
+			LastTransition: *clock.MetaV1Now(),
+			Reason:         ptr.String("InvalidDependencies"),
+			Message:        ptr.String("dependency cycle between: first, second"),
+		},
+	}
+	if diff := cmp.Diff(expect, wrkflw.Status.Conditions); diff != "" {
+		t.Fatal(diff)
+	}
+	if wrkflw.Status.State != tinkv1.WorkflowStateFailed {
+		t.Fatalf("expected Failed state, got %v", wrkflw.Status.State)
+	}
+	if len(wrkflw.Status.Actions) != 0 {
+		t.Fatalf("expected no actions, got %v", wrkflw.Status.Actions)
+	}
+}
+
+func TestReconcileContextHandlers(t *testing.T) {
+	clock := testtime.NewFrozenTimeUnix(1637361793)
+
+	hw := newHardware(func(*tinkv1.Hardware) {})
+	tmpl := newTemplate(func(t *tinkv1.Template) {
+		t.Spec.Actions = []tinkv1.Action{{Name: "action", Image: "image"}}
+		t.Spec.OnFailure = []tinkv1.Action{{Name: "diagnostics", Image: "image"}}
+		t.Spec.Finally = []tinkv1.Action{{Name: "reset", Image: "{{ .Param.Image }}"}}
+	})
+	wrkflw := newWorkflow(func(w *tinkv1.Workflow) {
+		w.Spec.HardwareRef = corev1.LocalObjectReference{Name: hw.Name}
+		w.Spec.TemplateRef = corev1.LocalObjectReference{Name: tmpl.Name}
+		w.Spec.TemplateParams = map[string]string{"Image": "reset-image"}
+	})
+
+	scheme := runtime.NewScheme()
+	machineryruntimeutil.Must(tinkv1.AddToScheme(scheme))
+
+	reconcileCtx := ReconciliationContext{
+		Client:      fake.NewClientBuilder().WithScheme(scheme).WithObjects(hw, tmpl).Build(),
+		Log:         logr.Discard(),
+		Workflow:    wrkflw,
+		NewActionID: newActionID,
+		Now:         clock.Now,
+	}
+	if _, err := reconcileCtx.Reconcile(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	expectOnFailure := []tinkv1.ActionStatus{
+		{
+			Rendered: newAction(func(a *tinkv1.Action) {
+				a.Name = "diagnostics"
+				a.Image = "image"
+			}),
+			State: tinkv1.ActionStatePending,
+			ID:    newActionID(),
+		},
+	}
+	if diff := cmp.Diff(expectOnFailure, wrkflw.Status.OnFailure); diff != "" {
+		t.Fatal(diff)
+	}
+
+	expectFinally := []tinkv1.ActionStatus{
+		{
+			Rendered: newAction(func(a *tinkv1.Action) {
+				a.Name = "reset"
+				a.Image = "reset-image"
+			}),
+			State: tinkv1.ActionStatePending,
+			ID:    newActionID(),
+		},
+	}
+	if diff := cmp.Diff(expectFinally, wrkflw.Status.Finally); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestReconcileContextInvalidHandlers(t *testing.T) {
+	clock := testtime.NewFrozenTimeUnix(1637361793)
+
+	hw := newHardware(func(*tinkv1.Hardware) {})
+	tmpl := newTemplate(func(t *tinkv1.Template) {
+		t.Spec.Actions = []tinkv1.Action{{Name: "action", Image: "image"}}
+		t.Spec.Finally = []tinkv1.Action{{Name: "action", Image: "image"}}
+	})
+	wrkflw := newWorkflow(func(w *tinkv1.Workflow) {
+		w.Spec.HardwareRef = corev1.LocalObjectReference{Name: hw.Name}
+		w.Spec.TemplateRef = corev1.LocalObjectReference{Name: tmpl.Name}
+	})
+
+	scheme := runtime.NewScheme()
+	machineryruntimeutil.Must(tinkv1.AddToScheme(scheme))
+
+	reconcileCtx := ReconciliationContext{
+		Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(hw, tmpl).Build(),
+		Log:      logr.Discard(),
+		Workflow: wrkflw,
+		Now:      clock.Now,
+	}
+	if _, err := reconcileCtx.Reconcile(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := tinkv1.Conditions{
+		{
+			Type:           tinkv1.WorkflowConditionTemplateRendered,
+			Status:         tinkv1.ConditionStatusFalse,
+			LastTransition: *clock.MetaV1Now(),
+			Reason:         ptr.String("InvalidHandlers"),
+			Message:        ptr.String("duplicate action name: action"),
+		},
+	}
+	if diff := cmp.Diff(expect, wrkflw.Status.Conditions); diff != "" {
+		t.Fatal(diff)
+	}
+	if wrkflw.Status.State != tinkv1.WorkflowStateFailed {
+		t.Fatalf("expected Failed state, got %v", wrkflw.Status.State)
+	}
+}
+
+func TestReconcileContextNoActions(t *testing.T) {
+	clock := testtime.NewFrozenTimeUnix(1637361793)
+
+	hw := newHardware(func(*tinkv1.Hardware) {})
+	tmpl := newTemplate(func(t *tinkv1.Template) {
+		t.Spec.Actions = nil
+	})
+	wrkflw := newWorkflow(func(w *tinkv1.Workflow) {
+		w.Spec.HardwareRef = corev1.LocalObjectReference{Name: hw.Name}
+		w.Spec.TemplateRef = corev1.LocalObjectReference{Name: tmpl.Name}
+	})
+
+	scheme := runtime.NewScheme()
+	machineryruntimeutil.Must(tinkv1.AddToScheme(scheme))
+
+	reconcileCtx := ReconciliationContext{
+		Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(hw, tmpl).Build(),
+		Log:      logr.Discard(),
+		Workflow: wrkflw,
+		Now:      clock.Now,
+	}
+	if _, err := reconcileCtx.Reconcile(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := tinkv1.Conditions{
+		{
+			Type:           tinkv1.WorkflowConditionTemplateRendered,
+			Status:         tinkv1.ConditionStatusFalse,
+			LastTransition: *clock.MetaV1Now(),
+			Reason:         ptr.String("NoActions"),
+			Message:        ptr.String("template has no actions"),
+		},
+	}
+	if diff := cmp.Diff(expect, wrkflw.Status.Conditions); diff != "" {
+		t.Fatal(diff)
+	}
+	if wrkflw.Status.State != tinkv1.WorkflowStateFailed {
+		t.Fatalf("expected Failed state, got %v", wrkflw.Status.State)
+	}
+}
+
+func TestReconcileContextInvalidKexec(t *testing.T) {
+	clock := testtime.NewFrozenTimeUnix(1637361793)
+
+	hw := newHardware(func(*tinkv1.Hardware) {})
+	tmpl := newTemplate(func(t *tinkv1.Template) {
+		t.Spec.Actions = []tinkv1.Action{
+			{Name: "install", Image: "image"},
+			{Name: "kexec", Type: tinkv1.ActionTypeKexec, Kexec: &tinkv1.Kexec{BlockDevice: "/dev/sda1"}},
+		}
+	})
+	wrkflw := newWorkflow(func(w *tinkv1.Workflow) {
+		w.Spec.HardwareRef = corev1.LocalObjectReference{Name: hw.Name}
+		w.Spec.TemplateRef = corev1.LocalObjectReference{Name: tmpl.Name}
+	})
+
+	scheme := runtime.NewScheme()
+	machineryruntimeutil.Must(tinkv1.AddToScheme(scheme))
+
+	reconcileCtx := ReconciliationContext{
+		Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(hw, tmpl).Build(),
+		Log:      logr.Discard(),
+		Workflow: wrkflw,
+		Now:      clock.Now,
+	}
+	if _, err := reconcileCtx.Reconcile(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := tinkv1.Conditions{
+		{
+			Type:           tinkv1.WorkflowConditionTemplateRendered,
+			Status:         tinkv1.ConditionStatusFalse,
+			LastTransition: *clock.MetaV1Now(),
+			Reason:         ptr.String("InvalidKexec"),
+			Message:        ptr.String("kexec: kexec actions must specify a kernel path"),
+		},
+	}
+	if diff := cmp.Diff(expect, wrkflw.Status.Conditions); diff != "" {
+		t.Fatal(diff)
+	}
+	if wrkflw.Status.State != tinkv1.WorkflowStateFailed {
+		t.Fatalf("expected Failed state, got %v", wrkflw.Status.State)
+	}
+}
+
+func TestReconcileContextUnsupportedPIDNamespace(t *testing.T) {
+	clock := testtime.NewFrozenTimeUnix(1637361793)
+
+	hw := newHardware(func(*tinkv1.Hardware) {})
+	tmpl := newTemplate(func(t *tinkv1.Template) {
+		t.Spec.Actions = []tinkv1.Action{
+			{Name: "host", Image: "image", Namespace: &tinkv1.Namespace{PID: ptr.Int(1)}},
+			{Name: "process", Image: "image", Namespace: &tinkv1.Namespace{PID: ptr.Int(10)}},
+		}
+	})
+	wrkflw := newWorkflow(func(w *tinkv1.Workflow) {
+		w.Spec.HardwareRef = corev1.LocalObjectReference{Name: hw.Name}
+		w.Spec.TemplateRef = corev1.LocalObjectReference{Name: tmpl.Name}
+	})
+
+	scheme := runtime.NewScheme()
+	machineryruntimeutil.Must(tinkv1.AddToScheme(scheme))
+
+	reconcileCtx := ReconciliationContext{
+		Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(hw, tmpl).Build(),
+		Log:      logr.Discard(),
+		Workflow: wrkflw,
+		Now:      clock.Now,
+	}
+	if _, err := reconcileCtx.Reconcile(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := tinkv1.Conditions{
+		{
+			Type:           tinkv1.WorkflowConditionTemplateRendered,
+			Status:         tinkv1.ConditionStatusFalse,
+			LastTransition: *clock.MetaV1Now(),
+			Reason:         ptr.String("UnsupportedNamespace"),
+			Message:        ptr.String("process: unsupported pid namespace 10; only 1, the host pid namespace, is supported"),
+		},
+	}
+	if diff := cmp.Diff(expect, wrkflw.Status.Conditions); diff != "" {
+		t.Fatal(diff)
+	}
+	if wrkflw.Status.State != tinkv1.WorkflowStateFailed {
+		t.Fatalf("expected Failed state, got %v", wrkflw.Status.State)
+	}
+	// Actions aren't rendered so the workflow is never dispatched to an agent.
+	if len(wrkflw.Status.Actions) != 0 {
+		t.Fatalf("expected no actions, got %v", len(wrkflw.Status.Actions))
+	}
+}
+
+func TestReconcileContextPinImages(t *testing.T) {
+	clock := testtime.NewFrozenTimeUnix(1637361793)
+
+	hw := newHardware(func(*tinkv1.Hardware) {})
+	tmpl := newTemplate(func(t *tinkv1.Template) {
+		t.Spec.Actions = []tinkv1.Action{
+			{Name: "first", Image: "image:1"},
+			{Name: "second", Image: "image:1"},
+			{Name: "reboot", Type: tinkv1.ActionTypeReboot},
+		}
+		t.Spec.Finally = []tinkv1.Action{{Name: "reset", Image: "reset:1"}}
+	})
+	wrkflw := newWorkflow(func(w *tinkv1.Workflow) {
+		w.Spec.HardwareRef = corev1.LocalObjectReference{Name: hw.Name}
+		w.Spec.TemplateRef = corev1.LocalObjectReference{Name: tmpl.Name}
+	})
+
+	scheme := runtime.NewScheme()
+	machineryruntimeutil.Must(tinkv1.AddToScheme(scheme))
+
+	var pinned []string
+	reconcileCtx := ReconciliationContext{
+		Client:      fake.NewClientBuilder().WithScheme(scheme).WithObjects(hw, tmpl).Build(),
+		Log:         logr.Discard(),
+		Workflow:    wrkflw,
+		NewActionID: newActionID,
+		Now:         clock.Now,
+		PinImage: func(_ context.Context, image string) (string, error) {
+			pinned = append(pinned, image)
+			return image + "@sha256:digest", nil
+		},
+	}
+	if _, err := reconcileCtx.Reconcile(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Each distinct image is only resolved once.
+	if diff := cmp.Diff([]string{"image:1", "reset:1"}, pinned); diff != "" {
+		t.Fatal(diff)
+	}
+
+	var images []string
+	for _, actions := range [][]tinkv1.ActionStatus{wrkflw.Status.Actions, wrkflw.Status.Finally} {
+		for _, action := range actions {
+			images = append(images, action.Rendered.Image)
+		}
+	}
+	// Reboot actions don't have an image to pin.
+	expect := []string{"image:1@sha256:digest", "image:1@sha256:digest", "", "reset:1@sha256:digest"}
+	if diff := cmp.Diff(expect, images); diff != "" {
+		t.Fatal(diff)
+	}
+	if wrkflw.Status.State != tinkv1.WorkflowStatePending {
+		t.Fatalf("expected Pending state, got %v", wrkflw.Status.State)
+	}
+}
+
+func TestReconcileContextPinImagesFailure(t *testing.T) {
+	clock := testtime.NewFrozenTimeUnix(1637361793)
+
+	hw := newHardware(func(*tinkv1.Hardware) {})
+	tmpl := newTemplate(func(t *tinkv1.Template) {
+		t.Spec.Actions = []tinkv1.Action{{Name: "action", Image: "image:1"}}
+	})
+	wrkflw := newWorkflow(func(w *tinkv1.Workflow) {
+		w.Spec.HardwareRef = corev1.LocalObjectReference{Name: hw.Name}
+		w.Spec.TemplateRef = corev1.LocalObjectReference{Name: tmpl.Name}
+	})
+
+	scheme := runtime.NewScheme()
+	machineryruntimeutil.Must(tinkv1.AddToScheme(scheme))
+
+	reconcileCtx := ReconciliationContext{
+		Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(hw, tmpl).Build(),
+		Log:      logr.Discard(),
+		Workflow: wrkflw,
+		Now:      clock.Now,
+		PinImage: func(context.Context, string) (string, error) {
+			return "", errors.New("registry unavailable")
+		},
+	}
+	if _, err := reconcileCtx.Reconcile(context.Background()); err == nil {
+		t.Fatal("expected error")
+	}
+
+	expect := tinkv1.Conditions{
+		{
+			Type:           tinkv1.WorkflowConditionTemplateRendered,
+			Status:         tinkv1.ConditionStatusFalse,
+			LastTransition: *clock.MetaV1Now(),
+			Reason:         ptr.String("ImagePinFailed"),
+			Message:        ptr.String("action action: registry unavailable"),
+		},
+	}
+	if diff := cmp.Diff(expect, wrkflw.Status.Conditions); diff != "" {
+		t.Fatal(diff)
+	}
+	// The render is retried so the Workflow remains unrendered.
+	if wrkflw.Status.State != "" || len(wrkflw.Status.Actions) != 0 {
+		t.Fatalf("expected unrendered workflow, got state %q with %v actions", wrkflw.Status.State, len(wrkflw.Status.Actions))
+	}
+}
+
+func TestReconcileContextResourceDefaults(t *testing.T) {
+	clock := testtime.NewFrozenTimeUnix(1637361793)
+
+	hw := newHardware(func(*tinkv1.Hardware) {})
+	tmpl := newTemplate(func(t *tinkv1.Template) {
+		t.Spec.Resources = &tinkv1.ResourceLimits{Memory: quantity("1Gi"), PIDs: ptr.Int64(1000)}
+		t.Spec.Actions = []tinkv1.Action{
+			{Name: "default", Image: "image"},
+			{Name: "override", Image: "image", Resources: &tinkv1.ResourceLimits{Memory: quantity("2Gi"), CPU: quantity("2")}},
+		}
+		t.Spec.Finally = []tinkv1.Action{{Name: "reset", Image: "image"}}
+	})
+	wrkflw := newWorkflow(func(w *tinkv1.Workflow) {
+		w.Spec.HardwareRef = corev1.LocalObjectReference{Name: hw.Name}
+		w.Spec.TemplateRef = corev1.LocalObjectReference{Name: tmpl.Name}
+	})
+
+	scheme := runtime.NewScheme()
+	machineryruntimeutil.Must(tinkv1.AddToScheme(scheme))
+
+	reconcileCtx := ReconciliationContext{
+		Client:      fake.NewClientBuilder().WithScheme(scheme).WithObjects(hw, tmpl).Build(),
+		Log:         logr.Discard(),
+		Workflow:    wrkflw,
+		NewActionID: newActionID,
+		Now:         clock.Now,
+	}
+	if _, err := reconcileCtx.Reconcile(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := []*tinkv1.ResourceLimits{
+		{Memory: quantity("1Gi"), PIDs: ptr.Int64(1000)},
+		{Memory: quantity("2Gi"), CPU: quantity("2"), PIDs: ptr.Int64(1000)},
+		{Memory: quantity("1Gi"), PIDs: ptr.Int64(1000)},
+	}
+	var got []*tinkv1.ResourceLimits
+	for _, actions := range [][]tinkv1.ActionStatus{wrkflw.Status.Actions, wrkflw.Status.Finally} {
+		for _, action := range actions {
+			got = append(got, action.Rendered.Resources)
+		}
+	}
+	equal := cmp.Comparer(func(a, b resource.Quantity) bool { return a.Cmp(b) == 0 })
+	if diff := cmp.Diff(expect, got, equal); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestReconcileContextStateTransitions(t *testing.T) {
+	clock := testtime.NewFrozenTimeUnix(1637361793)
+	timedOut := tinkv1.Condition{
+		Type:           tinkv1.WorkflowConditionTimedOut,
+		Status:         tinkv1.ConditionStatusTrue,
+		LastTransition: *clock.MetaV1Now(),
+		Reason:         ptr.String("Timeout"),
+		Message:        ptr.String("workflow exceeded timeout of 1m0s"),
+	}
+
+	cases := []struct {
+		Name         string
+		Status       tinkv1.WorkflowStatus
+		Timeout      int64
+		ExpectStatus tinkv1.WorkflowStatus
+		ExpectResult reconcile.Result
+	}{
+		{
+			Name:         "PendingIsUnchanged",
+			Status:       tinkv1.WorkflowStatus{State: tinkv1.WorkflowStatePending},
+			Timeout:      60,
+			ExpectStatus: tinkv1.WorkflowStatus{State: tinkv1.WorkflowStatePending},
+		},
+		{
+			Name: "RunningWithoutTimeout",
+			Status: tinkv1.WorkflowStatus{
+				State:     tinkv1.WorkflowStateRunning,
+				StartedAt: clock.MetaV1Before(time.Hour),
+			},
+			ExpectStatus: tinkv1.WorkflowStatus{
+				State:     tinkv1.WorkflowStateRunning,
+				StartedAt: clock.MetaV1Before(time.Hour),
+			},
+		},
+		{
+			Name: "RunningWithinTimeout",
+			Status: tinkv1.WorkflowStatus{
+				State:     tinkv1.WorkflowStateRunning,
+				StartedAt: clock.MetaV1Before(20 * time.Second),
+			},
+			Timeout: 60,
+			ExpectStatus: tinkv1.WorkflowStatus{
+				State:     tinkv1.WorkflowStateRunning,
+				StartedAt: clock.MetaV1Before(20 * time.Second),
+			},
+			ExpectResult: reconcile.Result{RequeueAfter: 40 * time.Second},
+		},
+		{
+			Name: "RunningTimedOut",
+			Status: tinkv1.WorkflowStatus{
+				State:     tinkv1.WorkflowStateRunning,
+				StartedAt: clock.MetaV1Before(2 * time.Minute),
+			},
+			Timeout: 60,
+			ExpectStatus: tinkv1.WorkflowStatus{
+				State:          tinkv1.WorkflowStateCancelling,
+				StartedAt:      clock.MetaV1Before(2 * time.Minute),
+				LastTransition: *clock.MetaV1Now(),
+				Conditions:     tinkv1.Conditions{timedOut},
+			},
+			ExpectResult: reconcile.Result{RequeueAfter: CancellationGracePeriod},
+		},
+		{
+			Name: "CancellingWithinGracePeriod",
+			Status: tinkv1.WorkflowStatus{
+				State:          tinkv1.WorkflowStateCancelling,
+				LastTransition: *clock.MetaV1Before(10 * time.Second),
+				Conditions:     tinkv1.Conditions{timedOut},
+			},
+			ExpectStatus: tinkv1.WorkflowStatus{
+				State:          tinkv1.WorkflowStateCancelling,
+				LastTransition: *clock.MetaV1Before(10 * time.Second),
+				Conditions:     tinkv1.Conditions{timedOut},
+			},
+			ExpectResult: reconcile.Result{RequeueAfter: CancellationGracePeriod - 10*time.Second},
+		},
+		{
+			Name: "CancellingTimedOut",
+			Status: tinkv1.WorkflowStatus{
+				State:          tinkv1.WorkflowStateCancelling,
+				LastTransition: *clock.MetaV1Before(time.Minute),
+				Conditions:     tinkv1.Conditions{timedOut},
+				Actions: []tinkv1.ActionStatus{
+					{ID: "action-0", State: tinkv1.ActionStateSucceeded},
+					{ID: "action-1", State: tinkv1.ActionStateRunning},
+					{ID: "action-2", State: tinkv1.ActionStatePending},
+				},
+				Finally: []tinkv1.ActionStatus{
+					{ID: "finally-0", State: tinkv1.ActionStateRunning},
+				},
+			},
+			ExpectStatus: tinkv1.WorkflowStatus{
+				State:          tinkv1.WorkflowStateFailed,
+				LastTransition: *clock.MetaV1Now(),
+				Conditions:     tinkv1.Conditions{timedOut},
+				Actions: []tinkv1.ActionStatus{
+					{ID: "action-0", State: tinkv1.ActionStateSucceeded},
+					{
+						ID:             "action-1",
+						State:          tinkv1.ActionStateFailed,
+						LastTransition: clock.MetaV1Now(),
+						FailureReason:  "Timeout",
+						FailureMessage: "workflow timed out",
+					},
+					{ID: "action-2", State: tinkv1.ActionStatePending},
+				},
+				Finally: []tinkv1.ActionStatus{
+					{
+						ID:             "finally-0",
+						State:          tinkv1.ActionStateFailed,
+						LastTransition: clock.MetaV1Now(),
+						FailureReason:  "Timeout",
+						FailureMessage: "workflow timed out",
+					},
+				},
+			},
+		},
+		{
+			Name: "Cancelling",
+			Status: tinkv1.WorkflowStatus{
+				State:          tinkv1.WorkflowStateCancelling,
+				LastTransition: *clock.MetaV1Before(time.Minute),
+			},
+			ExpectStatus: tinkv1.WorkflowStatus{
+				State:          tinkv1.WorkflowStateCanceled,
+				LastTransition: *clock.MetaV1Now(),
+			},
+		},
+		{
+			Name: "TerminalIsUnchanged",
+			Status: tinkv1.WorkflowStatus{
+				State:     tinkv1.WorkflowStateSucceeded,
+				StartedAt: clock.MetaV1Before(2 * time.Minute),
+			},
+			Timeout: 60,
+			ExpectStatus: tinkv1.WorkflowStatus{
+				State:     tinkv1.WorkflowStateSucceeded,
+				StartedAt: clock.MetaV1Before(2 * time.Minute),
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			wrkflw := newWorkflow(func(w *tinkv1.Workflow) {
+				w.Spec.TimeoutSeconds = tc.Timeout
+				w.Status = tc.Status
+			})
+
+			reconcileCtx := ReconciliationContext{
+				Log:      logr.Discard(),
+				Workflow: wrkflw,
+				Now:      clock.Now,
+			}
+			result, err := reconcileCtx.Reconcile(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(tc.ExpectResult, result); diff != "" {
+				t.Errorf("result: %v", diff)
+			}
+			if diff := cmp.Diff(tc.ExpectStatus, wrkflw.Status); diff != "" {
+				t.Errorf("status: %v", diff)
+			}
+		})
+	}
+}
+
 func newWorkflow(fn func(*tinkv1.Workflow)) *tinkv1.Workflow {
 	w := &tinkv1.Workflow{
 		TypeMeta: v1.TypeMeta{
@@ -121,15 +784,16 @@ func newHardware(fn func(*tinkv1.Hardware)) *tinkv1.Hardware {
 }
 
 func newAction(fn func(*tinkv1.Action)) tinkv1.Action {
-	a := tinkv1.Action{
-		Args:    []string{},
-		Env:     map[string]string{},
-		Volumes: []tinkv1.Volume{},
-	}
+	var a tinkv1.Action
 	fn(&a)
 	return a
 }
 
+func quantity(s string) *resource.Quantity {
+	q := resource.MustParse(s)
+	return &q
+}
+
 func newActionID() string {
 	return "8659e46f-00ff-40e4-a19b-c8661ca81167"
 }
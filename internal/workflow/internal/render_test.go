@@ -0,0 +1,96 @@
+package internal_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	tinkv1 "github.com/tinkerbell/tink/api/v1alpha2"
+	"github.com/tinkerbell/tink/internal/ptr"
+	"github.com/tinkerbell/tink/internal/testtime"
+	. "github.com/tinkerbell/tink/internal/workflow/internal" //nolint:revive // Dot imports should not be used. Problem for another time though.
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	machineryruntimeutil "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// TestReconcileContextRenderFields ensures every action field survives rendering and that
+// rendered values are decoded by their API field names and types.
+func TestReconcileContextRenderFields(t *testing.T) {
+	clock := testtime.NewFrozenTimeUnix(1637361793)
+
+	hw := newHardware(func(hw *tinkv1.Hardware) {
+		hw.Spec.NetworkInterfaces = tinkv1.NetworkInterfaces{"00:00:00:00:00:01": {}}
+	})
+	action := func(name, env string) tinkv1.Action {
+		return tinkv1.Action{
+			Name:       name,
+			Image:      "registry/image:{{ .Param.tag }}",
+			Cmd:        ptr.String("/bin/{{ .Param.cmd }}"),
+			Args:       []string{"--count", "{{ .Param.count }}", "--force={{ .Param.force }}"},
+			Env:        map[string]string{"COUNT": env, "EMPTY": ""},
+			Volumes:    []tinkv1.Volume{"/dev:/dev"},
+			Namespace:  &tinkv1.Namespace{Network: ptr.String("host"), PID: ptr.Int(1)},
+			Privileged: true,
+			Capabilities: &tinkv1.Capabilities{
+				Add:  []string{"SYS_ADMIN"},
+				Drop: []string{"NET_RAW"},
+			},
+			Devices:        []tinkv1.Device{"/dev/sda:/dev/disk:rw"},
+			Retry:          &tinkv1.RetryPolicy{Retries: 3, BackoffSeconds: 10, Reasons: []string{"Timeout"}},
+			TimeoutSeconds: 600,
+			When:           "Hardware.spec.disks.size() > 0",
+		}
+	}
+	tmpl := newTemplate(func(t *tinkv1.Template) {
+		t.Spec.Actions = []tinkv1.Action{
+			action("first", "{{ .Param.count }}"),
+			action("second", "true"),
+		}
+		t.Spec.Actions[1].DependsOn = []string{"first"}
+	})
+	wrkflw := newWorkflow(func(w *tinkv1.Workflow) {
+		w.Spec.HardwareRef = corev1.LocalObjectReference{Name: hw.Name}
+		w.Spec.TemplateRef = corev1.LocalObjectReference{Name: tmpl.Name}
+		w.Spec.TemplateParams = map[string]string{
+			"tag":   "1.0",
+			"cmd":   "sh",
+			"count": "3",
+			"force": "yes",
+		}
+	})
+
+	scheme := runtime.NewScheme()
+	machineryruntimeutil.Must(tinkv1.AddToScheme(scheme))
+
+	reconcileCtx := ReconciliationContext{
+		Client:      fake.NewClientBuilder().WithScheme(scheme).WithObjects(hw, tmpl).Build(),
+		Log:         logr.Discard(),
+		Workflow:    wrkflw,
+		NewActionID: newActionID,
+		Now:         clock.Now,
+	}
+	if _, err := reconcileCtx.Reconcile(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	rendered := func(name, env string) tinkv1.Action {
+		a := action(name, env)
+		a.Image = "registry/image:1.0"
+		a.Cmd = ptr.String("/bin/sh")
+		a.Args = []string{"--count", "3", "--force=yes"}
+		return a
+	}
+	expect := []tinkv1.Action{rendered("first", "3"), rendered("second", "true")}
+	expect[1].DependsOn = []string{"first"}
+
+	var got []tinkv1.Action
+	for _, a := range wrkflw.Status.Actions {
+		got = append(got, a.Rendered)
+	}
+	if diff := cmp.Diff(expect, got); diff != "" {
+		t.Fatal(diff)
+	}
+}
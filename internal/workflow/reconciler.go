@@ -14,18 +14,41 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
+// ImagePinner resolves action images to references pinned by digest.
+type ImagePinner interface {
+	Pin(_ context.Context, image string) (string, error)
+}
+
 // Reconciler reconciles Workflow instances.
 type Reconciler struct {
 	client  client.Client
 	nowFunc func() time.Time
+	pinner  ImagePinner
 }
 
 // NewReconciler creates a Reconciler instance.
-func NewReconciler(clnt client.Client) *Reconciler {
-	return &Reconciler{
+func NewReconciler(clnt client.Client, opts ...ReconcilerOption) *Reconciler {
+	r := &Reconciler{
 		client:  clnt,
 		nowFunc: time.Now,
 	}
+
+	for _, fn := range opts {
+		fn(r)
+	}
+
+	return r
+}
+
+// ReconcilerOption defines optional configuration for a Reconciler instance.
+type ReconcilerOption func(*Reconciler)
+
+// WithImagePinner returns an option that configures the Reconciler to pin action images to
+// digests when rendering Templates.
+func WithImagePinner(p ImagePinner) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.pinner = p
+	}
 }
 
 // +kubebuilder:rbac:groups=tinkerbell.org,resources=hardware;hardware/status,verbs=get;list;watch;update;patch
@@ -54,11 +77,17 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (resu
 		Client:   r.client,
 		Log:      logger,
 		Workflow: wrkflw.DeepCopy(),
+		Now:      r.nowFunc,
+	}
+	if r.pinner != nil {
+		rc.PinImage = r.pinner.Pin
 	}
 
-	// Always attempt to patch.
+	// Always attempt to patch. The server records agent events on the status concurrently so use
+	// an optimistic lock to avoid overwriting them; conflicts result in a requeue.
 	defer func() {
-		if err := r.client.Status().Patch(ctx, rc.Workflow, client.MergeFrom(wrkflw)); err != nil {
+		patch := client.MergeFromWithOptions(wrkflw, client.MergeFromWithOptimisticLock{})
+		if err := r.client.Status().Patch(ctx, rc.Workflow, patch); err != nil {
 			rerr = kerrors.NewAggregate([]error{rerr, err})
 		}
 	}()